@@ -6,13 +6,22 @@ import (
 	"github.com/kelseyhightower/envconfig"
 
 	"library/books/handler"
+	"library/books/metadata"
+	"library/books/purge"
 	"library/books/repository"
 	"library/books/server"
+	"library/pkg/apikey"
 	"library/pkg/config"
+	"library/pkg/jwks"
 	"library/pkg/logger"
+	"library/pkg/middleware"
 	"library/pkg/postgres"
+	"library/pkg/redis"
+	"library/pkg/storage"
 	"library/pkg/utils"
+	"library/shops/service"
 
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -49,12 +58,44 @@ func main() {
 	}
 	defer db.Close()
 
+	redisClient, err := redis.NewRedis(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer redisClient.Close()
+	middleware.UseDenylist(redisClient)
+	middleware.UseSessionTracker(redisClient)
+	middleware.UseAPIKeys(apikey.NewStore(db.GetDB()))
+	middleware.UsePublicKeys(jwks.NewRemote(cfg.JWKSURL, &http.Client{Timeout: 5 * time.Second}, cfg.JWKSCacheTTL))
+
+	var coverStorage storage.Storage
+	if cfg.CoverStorage == "s3" {
+		coverStorage = storage.NewS3Storage(storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKey,
+			SecretAccessKey: cfg.S3SecretKey,
+			PublicURL:       cfg.S3PublicURL,
+		}, &http.Client{Timeout: 30 * time.Second})
+	} else {
+		coverStorage = storage.NewLocalStorage(cfg.CoverDir, cfg.CoverURL)
+	}
+
 	bookRepository := repository.NewBookRepository(ctx, *db)
-	handlerBook := handler.NewBookHandler(ctx, bookRepository)
+	handlerBook := handler.NewBookHandler(ctx, bookRepository, coverStorage, metadata.NewShopProvider(service.GetBookByISBN))
+	authorRepository := repository.NewAuthorRepository(ctx, *db)
+	handlerAuthor := handler.NewAuthorHandler(ctx, authorRepository)
+	shelfRepository := repository.NewShelfRepository(ctx, *db)
+	handlerShelf := handler.NewShelfHandler(ctx, shelfRepository)
 
-	router := server.NewRouter(handlerBook)
+	router := server.NewRouter(handlerBook, handlerAuthor, handlerShelf)
+	if cfg.CoverStorage != "s3" {
+		router.Static(cfg.CoverURL, cfg.CoverDir)
+	}
 
 	go router.Run(":" + cfg.BooksServerPort)
+	go purge.NewPurger(ctx, bookRepository, coverStorage, cfg.TrashRetention, cfg.TrashPurgeInterval).Run()
 
 	select {
 	case sig := <-interrupt:
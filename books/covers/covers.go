@@ -0,0 +1,195 @@
+// Package covers checks uploaded book cover images and renders their thumbnails.
+package covers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	// MaxSize is the largest cover upload accepted, in bytes.
+	MaxSize = 5 << 20
+	// MaxDimension bounds the width and height of a cover, so small files cannot
+	// decode into huge images.
+	MaxDimension = 6000
+
+	Original = "original"
+)
+
+var (
+	ErrUnsupportedType = errors.New("cover must be a JPEG, PNG or WebP image")
+	ErrInvalidImage    = errors.New("cover image is corrupt or too large")
+)
+
+// Size is a thumbnail rendered for every cover, scaled down to the width.
+type Size struct {
+	Name  string
+	Width int
+}
+
+var Sizes = []Size{
+	{Name: "medium", Width: 600},
+	{Name: "small", Width: 200},
+}
+
+// Rendition is one stored image of a cover.
+type Rendition struct {
+	Name        string
+	ContentType string
+	Extension   string
+	Data        []byte
+}
+
+// Render sniffs the type of the upload and returns the original image followed by its
+// thumbnails. The standard library cannot decode WebP, so WebP covers only get their
+// original rendition after their header is checked.
+func Render(data []byte) ([]Rendition, error) {
+	contentType := http.DetectContentType(data)
+
+	switch contentType {
+	case "image/jpeg", "image/png":
+	case "image/webp":
+		if err := checkWebP(data); err != nil {
+			return nil, err
+		}
+
+		return []Rendition{{Name: Original, ContentType: contentType, Extension: "webp", Data: data}}, nil
+	default:
+		return nil, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width > MaxDimension || config.Height > MaxDimension {
+		return nil, ErrInvalidImage
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	renditions := []Rendition{newRendition(Original, contentType, data)}
+	for _, size := range Sizes {
+		thumbnail := img
+		if img.Bounds().Dx() > size.Width {
+			thumbnail = scale(img, size.Width)
+		}
+
+		var encoded bytes.Buffer
+		if contentType == "image/png" {
+			err = png.Encode(&encoded, thumbnail)
+		} else {
+			err = jpeg.Encode(&encoded, thumbnail, &jpeg.Options{Quality: 85})
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		renditions = append(renditions, newRendition(size.Name, contentType, encoded.Bytes()))
+	}
+
+	return renditions, nil
+}
+
+func newRendition(name, contentType string, data []byte) Rendition {
+	extension := "jpg"
+	if contentType == "image/png" {
+		extension = "png"
+	}
+
+	return Rendition{Name: name, ContentType: contentType, Extension: extension, Data: data}
+}
+
+// scale shrinks the image to the width, keeping its aspect ratio, by averaging the
+// source pixels covered by every destination pixel.
+func scale(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*bounds.Dy()/height, (y+1)*bounds.Dy()/height
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+
+		for x := 0; x < width; x++ {
+			x0, x1 := x*bounds.Dx()/width, (x+1)*bounds.Dx()/width
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					r += int(row[sx*4])
+					g += int(row[sx*4+1])
+					b += int(row[sx*4+2])
+					a += int(row[sx*4+3])
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
+
+// checkWebP reads the canvas size from the lossy, lossless or extended WebP header.
+func checkWebP(data []byte) error {
+	if len(data) < 30 {
+		return ErrInvalidImage
+	}
+
+	var width, height int
+	chunk := data[20:]
+
+	switch string(data[12:16]) {
+	case "VP8 ":
+		if chunk[3] != 0x9d || chunk[4] != 0x01 || chunk[5] != 0x2a {
+			return ErrInvalidImage
+		}
+
+		width = int(chunk[6]) | int(chunk[7]&0x3f)<<8
+		height = int(chunk[8]) | int(chunk[9]&0x3f)<<8
+	case "VP8L":
+		if chunk[0] != 0x2f {
+			return ErrInvalidImage
+		}
+
+		bits := uint32(chunk[1]) | uint32(chunk[2])<<8 | uint32(chunk[3])<<16 | uint32(chunk[4])<<24
+		width = int(bits&0x3fff) + 1
+		height = int(bits>>14&0x3fff) + 1
+	case "VP8X":
+		width = (int(chunk[4]) | int(chunk[5])<<8 | int(chunk[6])<<16) + 1
+		height = (int(chunk[7]) | int(chunk[8])<<8 | int(chunk[9])<<16) + 1
+	default:
+		return ErrInvalidImage
+	}
+
+	if width < 1 || height < 1 || width > MaxDimension || height > MaxDimension {
+		return ErrInvalidImage
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package covers_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestCovers(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Covers Suite")
+}
@@ -0,0 +1,109 @@
+package covers_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"library/books/covers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Covers", func() {
+	encode := func(width, height int, encoder func(*bytes.Buffer, image.Image) error) []byte {
+		img := image.NewRGBA(image.Rect(0, 0, width, height))
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+			}
+		}
+
+		var data bytes.Buffer
+		Expect(encoder(&data, img)).To(Succeed())
+
+		return data.Bytes()
+	}
+
+	encodePNG := func(w *bytes.Buffer, img image.Image) error { return png.Encode(w, img) }
+	encodeJPEG := func(w *bytes.Buffer, img image.Image) error { return jpeg.Encode(w, img, nil) }
+
+	decode := func(data []byte) image.Config {
+		config, _, err := image.DecodeConfig(bytes.NewReader(data))
+		Expect(err).To(BeNil())
+
+		return config
+	}
+
+	It("should render the thumbnails of a PNG cover", func() {
+		data := encode(1200, 1800, encodePNG)
+
+		renditions, err := covers.Render(data)
+
+		Expect(err).To(BeNil())
+		Expect(renditions).To(HaveLen(3))
+		Expect(renditions[0].Name).To(Equal(covers.Original))
+		Expect(renditions[0].Data).To(Equal(data))
+
+		for i, size := range covers.Sizes {
+			rendition := renditions[i+1]
+			Expect(rendition.Name).To(Equal(size.Name))
+			Expect(rendition.ContentType).To(Equal("image/png"))
+			Expect(rendition.Extension).To(Equal("png"))
+
+			config := decode(rendition.Data)
+			Expect(config.Width).To(Equal(size.Width))
+			Expect(config.Height).To(Equal(size.Width * 3 / 2))
+		}
+	})
+
+	It("should not enlarge a small JPEG cover", func() {
+		renditions, err := covers.Render(encode(150, 100, encodeJPEG))
+
+		Expect(err).To(BeNil())
+		Expect(renditions).To(HaveLen(3))
+		Expect(renditions[2].Extension).To(Equal("jpg"))
+		Expect(decode(renditions[2].Data).Width).To(Equal(150))
+	})
+
+	It("should keep WebP covers at their original size", func() {
+		// lossless WebP header of a 400x600 image
+		webp := []byte("RIFF\x00\x00\x00\x00WEBPVP8L\x00\x00\x00\x00\x2f")
+		bits := uint32(399) | uint32(599)<<14
+		webp = append(webp, byte(bits), byte(bits>>8), byte(bits>>16), byte(bits>>24))
+		webp = append(webp, make([]byte, 16)...)
+
+		renditions, err := covers.Render(webp)
+
+		Expect(err).To(BeNil())
+		Expect(renditions).To(HaveLen(1))
+		Expect(renditions[0].ContentType).To(Equal("image/webp"))
+		Expect(renditions[0].Extension).To(Equal("webp"))
+	})
+
+	It("should reject other image types", func() {
+		var data bytes.Buffer
+		Expect(gif.Encode(&data, image.NewPaletted(image.Rect(0, 0, 10, 10), color.Palette{color.Black}), nil)).To(Succeed())
+
+		_, err := covers.Render(data.Bytes())
+
+		Expect(err).To(MatchError(covers.ErrUnsupportedType))
+	})
+
+	It("should reject a truncated image", func() {
+		data := encode(300, 300, encodePNG)
+
+		_, err := covers.Render(data[:len(data)/2])
+
+		Expect(err).To(MatchError(covers.ErrInvalidImage))
+	})
+
+	It("should reject an image larger than the maximum dimension", func() {
+		_, err := covers.Render(encode(covers.MaxDimension+1, 1, encodePNG))
+
+		Expect(err).To(MatchError(covers.ErrInvalidImage))
+	})
+})
@@ -0,0 +1,71 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"library/books/models"
+	"strings"
+)
+
+func init() {
+	Register("bibtex", Format{
+		ContentType: "application/x-bibtex",
+		Extension:   "bib",
+		NewWriter:   func(w io.Writer) Writer { return &bibtexWriter{w: w} },
+	})
+}
+
+// bibtexNameFields maps contributor roles onto BibTeX (and biblatex) name fields.
+var bibtexNameFields = []struct {
+	name string
+	role string
+}{
+	{name: "author", role: models.AuthorRoleAuthor},
+	{name: "editor", role: models.AuthorRoleEditor},
+	{name: "translator", role: models.AuthorRoleTranslator},
+	{name: "illustrator", role: models.AuthorRoleIllustrator},
+}
+
+var bibtexEscaper = strings.NewReplacer(`\`, `\textbackslash{}`, "{", `\{`, "}", `\}`)
+
+type bibtexWriter struct {
+	w io.Writer
+}
+
+func (b *bibtexWriter) Begin() error {
+	return nil
+}
+
+func (b *bibtexWriter) Write(book models.BookResponse) error {
+	var entry strings.Builder
+
+	fmt.Fprintf(&entry, "@book{book%d,\n", book.ID)
+	fmt.Fprintf(&entry, "  title = {%s},\n", bibtexEscaper.Replace(book.Name))
+
+	for _, field := range bibtexNameFields {
+		if names := contributors(book, field.role); len(names) > 0 {
+			fmt.Fprintf(&entry, "  %s = {%s},\n", field.name, bibtexEscaper.Replace(strings.Join(names, " and ")))
+		}
+	}
+
+	if date := publishedDate(book); len(date) >= 4 {
+		fmt.Fprintf(&entry, "  year = {%s},\n", date[:4])
+	}
+
+	if book.ISBN != "" {
+		fmt.Fprintf(&entry, "  isbn = {%s},\n", bibtexEscaper.Replace(book.ISBN))
+	}
+
+	if book.PageCount > 0 {
+		fmt.Fprintf(&entry, "  pagetotal = {%d},\n", book.PageCount)
+	}
+
+	entry.WriteString("}\n\n")
+
+	_, err := io.WriteString(b.w, entry.String())
+	return err
+}
+
+func (b *bibtexWriter) End() error {
+	return nil
+}
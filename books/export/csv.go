@@ -0,0 +1,46 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"library/books/models"
+	"strconv"
+)
+
+func init() {
+	Register("csv", Format{
+		ContentType: "text/csv",
+		Extension:   "csv",
+		NewWriter:   func(w io.Writer) Writer { return &csvWriter{w: csv.NewWriter(w)} },
+	})
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Begin() error {
+	return c.w.Write([]string{"id", "name", "authors", "isbn", "date_published", "page_count"})
+}
+
+func (c *csvWriter) Write(book models.BookResponse) error {
+	err := c.w.Write([]string{
+		strconv.Itoa(book.ID),
+		book.Name,
+		creditLine(book),
+		book.ISBN,
+		publishedDate(book),
+		strconv.Itoa(book.PageCount),
+	})
+	if err != nil {
+		return err
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) End() error {
+	c.w.Flush()
+	return c.w.Error()
+}
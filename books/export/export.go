@@ -0,0 +1,107 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"library/books/models"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Writer writes books in a single export format. Begin is called once before
+// the first book and End once after the last one.
+type Writer interface {
+	Begin() error
+	Write(book models.BookResponse) error
+	End() error
+}
+
+// Format describes a registered export format.
+type Format struct {
+	ContentType string
+	Extension   string
+	NewWriter   func(w io.Writer) Writer
+}
+
+var (
+	mu      sync.RWMutex
+	formats = make(map[string]Format)
+)
+
+// Register makes an export format available under the given name, replacing any previous one.
+func Register(name string, format Format) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	formats[name] = format
+}
+
+// Unregister removes the export format registered under the given name.
+func Unregister(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	delete(formats, name)
+}
+
+// Lookup returns the export format registered under the given name.
+func Lookup(name string) (Format, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	format, ok := formats[name]
+	return format, ok
+}
+
+// Names returns the names of all registered export formats.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// publishedDate trims the time part the database driver adds to dates.
+func publishedDate(book models.BookResponse) string {
+	date, err := time.Parse(time.RFC3339, book.DatePublished)
+	if err != nil {
+		return book.DatePublished
+	}
+
+	return date.Format(time.DateOnly)
+}
+
+// contributors returns the names of the book contributors with the given role.
+func contributors(book models.BookResponse, role string) []string {
+	var names []string
+	for _, author := range book.Authors {
+		if author.Role == role || (author.Role == "" && role == models.AuthorRoleAuthor) {
+			names = append(names, author.Name)
+		}
+	}
+
+	return names
+}
+
+// creditLine lists all contributors, naming the role of everyone who is not an author.
+func creditLine(book models.BookResponse) string {
+	credits := make([]string, 0, len(book.Authors))
+	for _, author := range book.Authors {
+		if author.Role == "" || author.Role == models.AuthorRoleAuthor {
+			credits = append(credits, author.Name)
+			continue
+		}
+
+		credits = append(credits, fmt.Sprintf("%s (%s)", author.Name, author.Role))
+	}
+
+	return strings.Join(credits, "; ")
+}
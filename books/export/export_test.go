@@ -0,0 +1,111 @@
+package export_test
+
+import (
+	"bytes"
+	"io"
+	"library/books/export"
+	"library/books/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Export", func() {
+	var books []models.BookResponse
+
+	BeforeEach(func() {
+		books = []models.BookResponse{
+			{ID: 1, Name: "The Hobbit", DatePublished: "1937-09-21T00:00:00Z", ISBN: "9780261102217", PageCount: 310, Authors: []models.AuthorResponse{{ID: 1, Name: "J. R. R. Tolkien", Role: models.AuthorRoleAuthor}}},
+			{ID: 2, Name: "Sets {and} Maps", DatePublished: "2001-01-01", Authors: []models.AuthorResponse{
+				{ID: 2, Name: "Jane Doe", Role: models.AuthorRoleAuthor},
+				{ID: 3, Name: "John Roe", Role: models.AuthorRoleAuthor},
+				{ID: 4, Name: "Ann Poe", Role: models.AuthorRoleTranslator},
+			}},
+		}
+	})
+
+	write := func(name string) string {
+		format, ok := export.Lookup(name)
+		Expect(ok).To(BeTrue())
+
+		var buf bytes.Buffer
+		writer := format.NewWriter(&buf)
+
+		Expect(writer.Begin()).To(Succeed())
+		for _, book := range books {
+			Expect(writer.Write(book)).To(Succeed())
+		}
+		Expect(writer.End()).To(Succeed())
+
+		return buf.String()
+	}
+
+	It("should register the built-in formats", func() {
+		Expect(export.Names()).To(Equal([]string{"bibtex", "csv", "jsonl", "marcxml"}))
+	})
+
+	It("should accept new formats", func() {
+		export.Register("titles", export.Format{
+			ContentType: "text/plain",
+			Extension:   "txt",
+			NewWriter:   func(w io.Writer) export.Writer { return &titleWriter{w: w} },
+		})
+		DeferCleanup(export.Unregister, "titles")
+
+		Expect(write("titles")).To(Equal("The Hobbit\nSets {and} Maps\n"))
+	})
+
+	It("should write CSV", func() {
+		Expect(write("csv")).To(Equal("id,name,authors,isbn,date_published,page_count\n" +
+			"1,The Hobbit,J. R. R. Tolkien,9780261102217,1937-09-21,310\n" +
+			"2,Sets {and} Maps,Jane Doe; John Roe; Ann Poe (translator),,2001-01-01,0\n"))
+	})
+
+	It("should write JSON Lines", func() {
+		Expect(write("jsonl")).To(Equal(
+			`{"id":1,"name":"The Hobbit","authors":[{"id":1,"name":"J. R. R. Tolkien","role":"author"}],"isbn":"9780261102217","date_published":"1937-09-21","page_count":310}` + "\n" +
+				`{"id":2,"name":"Sets {and} Maps","authors":[{"id":2,"name":"Jane Doe","role":"author"},{"id":3,"name":"John Roe","role":"author"},{"id":4,"name":"Ann Poe","role":"translator"}],"date_published":"2001-01-01"}` + "\n"))
+	})
+
+	It("should write BibTeX", func() {
+		Expect(write("bibtex")).To(Equal("@book{book1,\n" +
+			"  title = {The Hobbit},\n" +
+			"  author = {J. R. R. Tolkien},\n" +
+			"  year = {1937},\n" +
+			"  isbn = {9780261102217},\n" +
+			"  pagetotal = {310},\n" +
+			"}\n\n" +
+			"@book{book2,\n" +
+			"  title = {Sets \\{and\\} Maps},\n" +
+			"  author = {Jane Doe and John Roe},\n" +
+			"  translator = {Ann Poe},\n" +
+			"  year = {2001},\n" +
+			"}\n\n"))
+	})
+
+	It("should write MARCXML", func() {
+		out := write("marcxml")
+
+		Expect(out).To(HavePrefix(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<collection xmlns="http://www.loc.gov/MARC21/slim">`))
+		Expect(out).To(ContainSubstring(`<controlfield tag="001">1</controlfield>`))
+		Expect(out).To(ContainSubstring(`<datafield tag="020" ind1=" " ind2=" "><subfield code="a">9780261102217</subfield></datafield>`))
+		Expect(out).To(ContainSubstring(`<datafield tag="100" ind1="1" ind2=" "><subfield code="a">J. R. R. Tolkien</subfield><subfield code="e">author</subfield></datafield>`))
+		Expect(out).To(ContainSubstring(`<datafield tag="700" ind1="1" ind2=" "><subfield code="a">Ann Poe</subfield><subfield code="e">translator</subfield></datafield>`))
+		Expect(out).To(ContainSubstring(`<datafield tag="245" ind1="1" ind2="0"><subfield code="a">The Hobbit</subfield></datafield>`))
+		Expect(out).To(ContainSubstring(`<datafield tag="300" ind1=" " ind2=" "><subfield code="a">310 pages</subfield></datafield>`))
+		Expect(out).To(HaveSuffix("</collection>\n"))
+	})
+})
+
+type titleWriter struct {
+	w io.Writer
+}
+
+func (t *titleWriter) Begin() error { return nil }
+
+func (t *titleWriter) Write(book models.BookResponse) error {
+	_, err := io.WriteString(t.w, book.Name+"\n")
+	return err
+}
+
+func (t *titleWriter) End() error { return nil }
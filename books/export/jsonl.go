@@ -0,0 +1,47 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"library/books/models"
+)
+
+func init() {
+	Register("jsonl", Format{
+		ContentType: "application/x-ndjson",
+		Extension:   "jsonl",
+		NewWriter:   func(w io.Writer) Writer { return &jsonLinesWriter{encoder: json.NewEncoder(w)} },
+	})
+}
+
+type jsonBook struct {
+	ID            int                     `json:"id"`
+	Name          string                  `json:"name"`
+	Authors       []models.AuthorResponse `json:"authors"`
+	ISBN          string                  `json:"isbn,omitempty"`
+	DatePublished string                  `json:"date_published,omitempty"`
+	PageCount     int                     `json:"page_count,omitempty"`
+}
+
+type jsonLinesWriter struct {
+	encoder *json.Encoder
+}
+
+func (j *jsonLinesWriter) Begin() error {
+	return nil
+}
+
+func (j *jsonLinesWriter) Write(book models.BookResponse) error {
+	return j.encoder.Encode(jsonBook{
+		ID:            book.ID,
+		Name:          book.Name,
+		Authors:       book.Authors,
+		ISBN:          book.ISBN,
+		DatePublished: publishedDate(book),
+		PageCount:     book.PageCount,
+	})
+}
+
+func (j *jsonLinesWriter) End() error {
+	return nil
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"library/books/models"
+	"strconv"
+)
+
+const marcNamespace = "http://www.loc.gov/MARC21/slim"
+
+func init() {
+	Register("marcxml", Format{
+		ContentType: "application/marcxml+xml",
+		Extension:   "xml",
+		NewWriter:   func(w io.Writer) Writer { return &marcWriter{w: w, encoder: xml.NewEncoder(w)} },
+	})
+}
+
+type marcRecord struct {
+	XMLName       xml.Name           `xml:"record"`
+	Leader        string             `xml:"leader"`
+	ControlFields []marcControlField `xml:"controlfield"`
+	DataFields    []marcDataField    `xml:"datafield"`
+}
+
+type marcControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type marcDataField struct {
+	Tag       string         `xml:"tag,attr"`
+	Ind1      string         `xml:"ind1,attr"`
+	Ind2      string         `xml:"ind2,attr"`
+	Subfields []marcSubfield `xml:"subfield"`
+}
+
+type marcSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+type marcWriter struct {
+	w       io.Writer
+	encoder *xml.Encoder
+}
+
+func (m *marcWriter) Begin() error {
+	_, err := fmt.Fprintf(m.w, "%s<collection xmlns=%q>\n", xml.Header, marcNamespace)
+	return err
+}
+
+func (m *marcWriter) Write(book models.BookResponse) error {
+	record := marcRecord{
+		Leader:        "00000nam a2200000 a 4500",
+		ControlFields: []marcControlField{{Tag: "001", Value: strconv.Itoa(book.ID)}},
+	}
+
+	if book.ISBN != "" {
+		record.DataFields = append(record.DataFields, marcField("020", " ", " ", "a", book.ISBN))
+	}
+
+	// the first contributor is the main entry, everyone else is an added entry with a relator term
+	for i, author := range book.Authors {
+		tag := "700"
+		if i == 0 {
+			tag = "100"
+		}
+
+		field := marcField(tag, "1", " ", "a", author.Name)
+		if author.Role != "" {
+			field.Subfields = append(field.Subfields, marcSubfield{Code: "e", Value: author.Role})
+		}
+
+		record.DataFields = append(record.DataFields, field)
+	}
+
+	record.DataFields = append(record.DataFields, marcField("245", "1", "0", "a", book.Name))
+
+	if date := publishedDate(book); len(date) >= 4 {
+		record.DataFields = append(record.DataFields, marcField("264", " ", "1", "c", date[:4]))
+	}
+
+	if book.PageCount > 0 {
+		record.DataFields = append(record.DataFields, marcField("300", " ", " ", "a", fmt.Sprintf("%d pages", book.PageCount)))
+	}
+
+	if err := m.encoder.Encode(record); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(m.w, "\n")
+	return err
+}
+
+func (m *marcWriter) End() error {
+	_, err := io.WriteString(m.w, "</collection>\n")
+	return err
+}
+
+func marcField(tag, ind1, ind2, code, value string) marcDataField {
+	return marcDataField{
+		Tag:       tag,
+		Ind1:      ind1,
+		Ind2:      ind2,
+		Subfields: []marcSubfield{{Code: code, Value: value}},
+	}
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"library/books/models"
+	"library/books/repository"
+	"library/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxDuplicateSuggestions = 10
+
+type AuthorerHandler interface {
+	ListAuthors(c *gin.Context)
+	GetAuthor(c *gin.Context)
+	UpdateAuthor(c *gin.Context)
+	SuggestDuplicates(c *gin.Context)
+	MergeAuthors(c *gin.Context)
+}
+
+type AuthorHandler struct {
+	ctx              context.Context
+	authorRepository repository.AuthorerRepository
+}
+
+func NewAuthorHandler(ctx context.Context, authorer repository.AuthorerRepository) AuthorerHandler {
+	return &AuthorHandler{
+		ctx:              ctx,
+		authorRepository: authorer,
+	}
+}
+
+// ListAuthors retrieves a page of authors, optionally filtered by name.
+//
+//	@Summary		List authors
+//	@Description	Retrieves a page of authors whose name contains the query.
+//	@Tags			authors
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"JWT Token"
+//	@Param			q				query		string	false	"Part of the author name"
+//	@Param			limit			query		int		false	"Page size (default 20, max 100)"
+//	@Param			offset			query		int		false	"Number of authors to skip"
+//	@Success		200
+//	@Failure		400
+//	@Failure		500
+//	@Router			/v1/books/authors [get]
+func (a *AuthorHandler) ListAuthors(c *gin.Context) {
+	var filter models.AuthorFilter
+
+	log := utils.GetLogger(a.ctx)
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		log.Errorf("Query binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := filter.Validate(); err != nil {
+		log.Warningf("Invalid author filter: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authors, err := a.authorRepository.ListAuthors(&filter)
+	if err != nil {
+		log.Errorf("List authors repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, authors)
+}
+
+// GetAuthor retrieves an author by ID.
+//
+//	@Summary		Retrieve an author by ID
+//	@Description	Retrieves an author with the biography and the number of books crediting them.
+//	@Tags			authors
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"JWT Token"
+//	@Param			author_id		path		int		true	"Author ID"
+//	@Success		200
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/books/authors/{author_id} [get]
+func (a *AuthorHandler) GetAuthor(c *gin.Context) {
+	log := utils.GetLogger(a.ctx)
+
+	author, err := a.authorRepository.GetAuthor(c.GetInt("authorID"))
+	if err != nil {
+		log.Errorf("Get author repository error: %v", err)
+		c.JSON(authorErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"author": author})
+}
+
+// UpdateAuthor renames an author or changes the biography.
+//
+//	@Summary		Update an author
+//	@Description	Renames an author or changes the biography. Authors are shared by every library and the shop catalog, so it requires the authors:write permission. Renaming to the name of another author is rejected, merge them instead.
+//	@Tags			authors
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"JWT Token"
+//	@Param			author_id		path		int					true	"Author ID"
+//	@Param			author			body		models.AuthorUpdate	true	"New name and biography"
+//	@Success		200
+//	@Failure		400
+//	@Failure		403
+//	@Failure		404
+//	@Failure		409
+//	@Failure		500
+//	@Router			/v1/books/authors/{author_id} [put]
+func (a *AuthorHandler) UpdateAuthor(c *gin.Context) {
+	var update models.AuthorUpdate
+
+	log := utils.GetLogger(a.ctx)
+
+	if err := c.ShouldBindJSON(&update); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := update.Validate(); err != nil {
+		log.Warningf("Invalid author update: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	author, err := a.authorRepository.UpdateAuthor(c.GetInt("authorID"), &update)
+	if err != nil {
+		log.Errorf("Update author repository error: %v", err)
+		c.JSON(authorErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infof("Author updated successfully: %v", author.ID)
+	c.JSON(http.StatusOK, gin.H{"author": author})
+}
+
+// SuggestDuplicates lists authors that are likely the same person.
+//
+//	@Summary		Suggest duplicate authors
+//	@Description	Lists authors whose name matches once case and punctuation are ignored or is similar enough to be a likely duplicate.
+//	@Tags			authors
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"JWT Token"
+//	@Param			author_id		path		int		true	"Author ID"
+//	@Param			limit			query		int		false	"Maximum number of suggestions (default 10)"
+//	@Success		200
+//	@Failure		400
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/books/authors/{author_id}/duplicates [get]
+func (a *AuthorHandler) SuggestDuplicates(c *gin.Context) {
+	log := utils.GetLogger(a.ctx)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(maxDuplicateSuggestions)))
+	if err != nil || limit < 1 || limit > models.MaxPageSize {
+		log.Warningf("Invalid suggestion limit: %v", c.Query("limit"))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number between 1 and 100"})
+		return
+	}
+
+	suggestions, err := a.authorRepository.SuggestDuplicates(c.GetInt("authorID"), limit)
+	if err != nil {
+		log.Errorf("Suggest duplicates repository error: %v", err)
+		c.JSON(authorErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"duplicates": suggestions})
+}
+
+// MergeAuthors merges duplicate authors into one. The route requires the authors:merge permission.
+//
+//	@Summary		Merge duplicate authors
+//	@Description	Moves all personal and shop book credits of the duplicates to the author and deletes the duplicates in one transaction.
+//	@Tags			authors
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string						true	"JWT Token"
+//	@Param			author_id		path		int							true	"ID of the author that is kept"
+//	@Param			merge			body		models.MergeAuthorsRequest	true	"IDs of the duplicates"
+//	@Success		200
+//	@Failure		400
+//	@Failure		403
+//	@Failure		500
+//	@Router			/v1/books/authors/{author_id}/merge [post]
+func (a *AuthorHandler) MergeAuthors(c *gin.Context) {
+	var request models.MergeAuthorsRequest
+
+	log := utils.GetLogger(a.ctx)
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	merge, err := a.authorRepository.MergeAuthors(c.GetInt("authorID"), request.DuplicateIDs)
+	if err != nil {
+		log.Errorf("Merge authors repository error: %v", err)
+		c.JSON(authorErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, merge)
+}
+
+func authorErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrAuthorNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrAuthorNameTaken):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrInvalidMerge):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,193 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"library/books/handler"
+	"library/books/metadata/metadatafakes"
+	"library/books/models"
+	"library/books/repository"
+	"library/books/repository/repositoryfakes"
+	"library/books/server"
+	"library/pkg/authz"
+	"library/pkg/logger"
+	"library/pkg/middleware"
+	"library/pkg/storage/storagefakes"
+	userModel "library/users/models"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Author API Test", func() {
+	var (
+		fakeAuthorer *repositoryfakes.FakeAuthorerRepository
+		w            *httptest.ResponseRecorder
+		permissions  []string
+		request      func(method, path string, body interface{}) *http.Request
+		serve        func(req *http.Request)
+	)
+
+	BeforeEach(func() {
+		permissions = nil
+	})
+
+	JustBeforeEach(func() {
+		w = httptest.NewRecorder()
+
+		ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+		fakeAuthorer = &repositoryfakes.FakeAuthorerRepository{}
+		router := server.NewRouter(
+			handler.NewBookHandler(ctx, &repositoryfakes.FakeBookerRepository{}, &storagefakes.FakeStorage{}, &metadatafakes.FakeProvider{}),
+			handler.NewAuthorHandler(ctx, fakeAuthorer),
+			handler.NewShelfHandler(ctx, &repositoryfakes.FakeShelferRepository{}),
+		)
+
+		token, _ := middleware.GenerateJWT(userModel.User{ID: 1, Email: "tmostowashere@tmostowashere.com", Role: "user", Permissions: permissions})
+
+		request = func(method, path string, body interface{}) *http.Request {
+			var payload bytes.Buffer
+			if body != nil {
+				Expect(json.NewEncoder(&payload).Encode(body)).To(Succeed())
+			}
+
+			req, err := http.NewRequest(method, path, &payload)
+			Expect(err).To(BeNil())
+			req.AddCookie(&http.Cookie{Name: "token", Value: token, Expires: time.Now().Add(time.Hour)})
+			req.Header.Set("Content-Type", "application/json")
+
+			return req
+		}
+
+		serve = func(req *http.Request) {
+			router.ServeHTTP(w, req)
+		}
+	})
+
+	Describe("ListAuthors", func() {
+		It("should search authors by name", func() {
+			fakeAuthorer.ListAuthorsReturns(&models.AuthorListResponse{
+				Authors: []models.Author{{ID: 1, Name: "J. R. R. Tolkien", BookCount: 3}},
+				Total:   1,
+			}, nil)
+
+			serve(request("GET", "/v1/books/authors?q=tolkien&limit=5", nil))
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			Expect(w.Body.String()).To(ContainSubstring(`"book_count":3`))
+
+			filter := fakeAuthorer.ListAuthorsArgsForCall(0)
+			Expect(filter.Query).To(Equal("tolkien"))
+			Expect(filter.Limit).To(Equal(5))
+		})
+
+		It("should reject a negative offset", func() {
+			serve(request("GET", "/v1/books/authors?offset=-1", nil))
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest), "Expected HTTP status Bad Request")
+			Expect(fakeAuthorer.ListAuthorsCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("GetAuthor", func() {
+		It("should return not found for an unknown author", func() {
+			fakeAuthorer.GetAuthorReturns(&models.Author{}, repository.ErrAuthorNotFound)
+
+			serve(request("GET", "/v1/books/authors/42", nil))
+
+			Expect(w.Code).To(Equal(http.StatusNotFound), "Expected HTTP status Not Found")
+			Expect(fakeAuthorer.GetAuthorArgsForCall(0)).To(Equal(42))
+		})
+	})
+
+	Describe("UpdateAuthor", func() {
+		It("should be forbidden without the authors:write permission", func() {
+			serve(request("PUT", "/v1/books/authors/1", models.AuthorUpdate{Name: "J. R. R. Tolkien"}))
+
+			Expect(w.Code).To(Equal(http.StatusForbidden), "Expected HTTP status Forbidden")
+			Expect(fakeAuthorer.UpdateAuthorCallCount()).To(Equal(0))
+		})
+
+		Context("with the authors:write permission", func() {
+			BeforeEach(func() {
+				permissions = []string{authz.AuthorsWrite}
+			})
+
+			It("should rename the author", func() {
+				biography := "English writer and philologist."
+				fakeAuthorer.UpdateAuthorReturns(&models.Author{ID: 1, Name: "J. R. R. Tolkien", Biography: biography}, nil)
+
+				serve(request("PUT", "/v1/books/authors/1", models.AuthorUpdate{Name: "  J. R. R.   Tolkien ", Biography: &biography}))
+
+				Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+
+				id, update := fakeAuthorer.UpdateAuthorArgsForCall(0)
+				Expect(id).To(Equal(1))
+				Expect(update.Name).To(Equal("J. R. R. Tolkien"))
+			})
+
+			It("should report a conflict when the name belongs to another author", func() {
+				fakeAuthorer.UpdateAuthorReturns(&models.Author{}, repository.ErrAuthorNameTaken)
+
+				serve(request("PUT", "/v1/books/authors/1", models.AuthorUpdate{Name: "J.R.R. Tolkien"}))
+
+				Expect(w.Code).To(Equal(http.StatusConflict), "Expected HTTP status Conflict")
+			})
+		})
+	})
+
+	Describe("SuggestDuplicates", func() {
+		It("should list likely duplicates", func() {
+			fakeAuthorer.SuggestDuplicatesReturns([]models.AuthorSuggestion{{ID: 2, Name: "J.R.R. Tolkien", Similarity: 0.8}}, nil)
+
+			serve(request("GET", "/v1/books/authors/1/duplicates", nil))
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			Expect(w.Body.String()).To(ContainSubstring(`"name":"J.R.R. Tolkien"`))
+
+			id, limit := fakeAuthorer.SuggestDuplicatesArgsForCall(0)
+			Expect(id).To(Equal(1))
+			Expect(limit).To(Equal(10))
+		})
+	})
+
+	Describe("MergeAuthors", func() {
+		It("should be forbidden for regular users", func() {
+			serve(request("POST", "/v1/books/authors/1/merge", models.MergeAuthorsRequest{DuplicateIDs: []int{2}}))
+
+			Expect(w.Code).To(Equal(http.StatusForbidden), "Expected HTTP status Forbidden")
+			Expect(fakeAuthorer.MergeAuthorsCallCount()).To(Equal(0))
+		})
+
+		Context("with the authors:merge permission", func() {
+			BeforeEach(func() {
+				permissions = []string{authz.AuthorsMerge}
+			})
+
+			It("should merge the duplicates", func() {
+				fakeAuthorer.MergeAuthorsReturns(&models.MergeAuthorsResponse{AuthorID: 1, MergedIDs: []int{2, 3}, UserBookCredits: 4}, nil)
+
+				serve(request("POST", "/v1/books/authors/1/merge", models.MergeAuthorsRequest{DuplicateIDs: []int{2, 3}}))
+
+				Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+
+				authorID, duplicateIDs := fakeAuthorer.MergeAuthorsArgsForCall(0)
+				Expect(authorID).To(Equal(1))
+				Expect(duplicateIDs).To(Equal([]int{2, 3}))
+			})
+
+			It("should reject merging an author into itself", func() {
+				fakeAuthorer.MergeAuthorsReturns(&models.MergeAuthorsResponse{}, repository.ErrInvalidMerge)
+
+				serve(request("POST", "/v1/books/authors/1/merge", models.MergeAuthorsRequest{DuplicateIDs: []int{1}}))
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest), "Expected HTTP status Bad Request")
+			})
+		})
+	})
+})
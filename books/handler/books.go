@@ -2,46 +2,70 @@ package handler
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"library/books/metadata"
 	"library/books/models"
 	"library/books/repository"
-	"library/pkg"
+	"library/pkg/storage"
 	"library/pkg/utils"
 	"net/http"
+	"strconv"
 )
 
 type BookerHandler interface {
 	AddBook(c *gin.Context)
 	UpdateBook(c *gin.Context)
+	PatchBook(c *gin.Context)
 	GetBook(c *gin.Context)
 	GetAllBooks(c *gin.Context)
 	DeleteBook(c *gin.Context)
+	SearchBooks(c *gin.Context)
+	ImportBooks(c *gin.Context)
+	ExportBooks(c *gin.Context)
+	ListTrash(c *gin.Context)
+	RestoreBook(c *gin.Context)
+	ListHistory(c *gin.Context)
+	RevertBook(c *gin.Context)
+	GetReading(c *gin.Context)
+	UpdateReading(c *gin.Context)
+	UploadCover(c *gin.Context)
+	DeleteCover(c *gin.Context)
+	LookupBook(c *gin.Context)
 }
 
 type BookHandler struct {
 	ctx            context.Context
 	bookRepository repository.BookerRepository
+	coverStorage   storage.Storage
+	metadata       metadata.Provider
 }
 
-func NewBookHandler(ctx context.Context, booker repository.BookerRepository) BookerHandler {
+func NewBookHandler(ctx context.Context, booker repository.BookerRepository, coverStorage storage.Storage, provider metadata.Provider) BookerHandler {
 	return &BookHandler{
 		ctx:            ctx,
 		bookRepository: booker,
+		coverStorage:   coverStorage,
+		metadata:       provider,
 	}
 }
 
 // AddBook adds a new book to the database.
 //
 //	@Summary		Add a new book
-//	@Description	Adds a new book to the database.
+//	@Description	Adds a new book to the database. With autofill, the fields left empty are filled in from the ISBN lookup.
 //	@Tags			books
 //	@Accept			json
 //	@Produce		json
 //	@Param			Authorization	header		string										true	"JWT Token"
+//	@Param			autofill		query		bool										false	"Fill in empty fields from the ISBN lookup"
 //	@Param			book			body		models.BookRequest									true	"Book object to be added"
 //	@Success		201
 //	@Failure		400
 //	@Failure		401
+//	@Failure		422
 //	@Failure		500
 //	@Router			/v1/books [post]
 func (b *BookHandler) AddBook(c *gin.Context) {
@@ -50,23 +74,37 @@ func (b *BookHandler) AddBook(c *gin.Context) {
 
 	log := utils.GetLogger(b.ctx)
 
-	book.UserID.ID = c.GetInt("userID")
-
 	if err = c.ShouldBindJSON(&book); err != nil {
 		log.Errorf("JSON binding error: %v", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	err = pkg.CheckPublishedDate(log, &book)
-	if err != nil {
-		log.Errorf("checking published date error: %v", err)
-		errorMessage := "Cannot add a book with a future publication date"
-		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage})
+	book.UserID.ID = c.GetInt("userID")
+
+	if c.Query("autofill") != "" {
+		autofill, err := strconv.ParseBool(c.Query("autofill"))
+		if err != nil {
+			log.Warningf("Invalid autofill: %v", c.Query("autofill"))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "autofill must be true or false"})
+			return
+		}
+
+		if autofill {
+			if err = b.autofill(log, &book); err != nil {
+				log.Errorf("Autofill ISBN error: %v", err)
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
+		}
+	}
+
+	if status, err := validateBook(log, &book); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
-	bookID, err := b.bookRepository.AddBook(&book)
+	bookID, err := b.bookRepository.AddBook(&book, c.GetInt("actorID"))
 	if err != nil {
 		log.Errorf("Add Book repository error: %v", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -85,15 +123,17 @@ func (b *BookHandler) AddBook(c *gin.Context) {
 //	@Accept			json
 //	@Produce		json
 //	@Param			Authorization	header		string													true	"JWT Token"
+//	@Param			If-Match		header		string													false	"ETag returned by GetBook, the update fails when the book has changed since"
 //	@Param			book			body		models.BookRequest												true	"Updated book object"
 //	@Success		201
 //	@Failure		400
 //	@Failure		401
+//	@Failure		412
+//	@Failure		422
 //	@Failure		500
 //	@Router			/v1/books [put]
 func (b *BookHandler) UpdateBook(c *gin.Context) {
 	var book models.BookRequest
-	var bookResponse *models.BookResponse
 
 	log := utils.GetLogger(b.ctx)
 
@@ -103,24 +143,37 @@ func (b *BookHandler) UpdateBook(c *gin.Context) {
 		return
 	}
 
-	err := pkg.CheckPublishedDate(log, &book)
-	if err != nil {
-		log.Errorf("checking published date error: %v", err)
-		errorMessage := "Cannot add a book with a future publication date"
-		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage})
+	if status, err := validateBook(log, &book); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	version, ok := ifMatch(c)
+	if !ok {
+		log.Warningf("Unusable If-Match header: %v", c.GetHeader("If-Match"))
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": repository.ErrVersionMismatch.Error()})
 		return
 	}
 
 	book.ID = c.GetInt("bookID")
 	book.UserID.ID = c.GetInt("userID")
+	book.Version = version
+
+	bookResponse, err := b.bookRepository.UpdateBook(&book, c.GetInt("actorID"))
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		log.Warningf("Update book repository error: %v", err)
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return
+	}
 
-	bookResponse, err = b.bookRepository.UpdateBook(&book)
 	if err != nil {
 		log.Errorf("Update book repository error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	c.Header("ETag", bookETag(bookResponse.Version))
+
 	log.Infof("Book updated successfully: %v", &book)
 	c.JSON(http.StatusCreated, gin.H{"Book updated successfully": bookResponse})
 }
@@ -128,15 +181,18 @@ func (b *BookHandler) UpdateBook(c *gin.Context) {
 // GetBook retrieves a book by its ID from the database.
 //
 //	@Summary		Retrieve a book by ID
-//	@Description	Retrieves a book by its ID from the database.
+//	@Description	Retrieves a book of the user by its ID. The books of other users require the books:read:any permission.
 //	@Tags			books
 //	@Accept			json
 //	@Produce		json
 //	@Param			Authorization	header		string								true	"JWT Token"
+//	@Param			user_id			path		int									true	"User ID"
 //	@Param			book_id			path		int									true	"Book ID"
 //	@Success		200
+//	@Failure		403
+//	@Failure		404
 //	@Failure		500
-//	@Router			/v1/books/{book_id} [get]
+//	@Router			/v1/books/{user_id}/books/{book_id} [get]
 func (b *BookHandler) GetBook(c *gin.Context) {
 	var book *models.BookResponse
 	var err error
@@ -145,46 +201,81 @@ func (b *BookHandler) GetBook(c *gin.Context) {
 
 	bookID := c.GetInt("bookID")
 
-	book, err = b.bookRepository.GetBook(bookID)
+	book, err = b.bookRepository.GetBook(bookID, c.GetInt("userID"))
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("Book not found: %v", bookID)
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Book ID doesn't exists: %v", bookID)})
+		return
+	}
+
 	if err != nil {
 		log.Errorf("Get Book repository error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	c.Header("ETag", bookETag(book.Version))
+
 	log.Infof("Book: %v", book)
 	c.JSON(http.StatusOK, gin.H{"book": book})
 }
 
-// GetAllBooks retrieves all books from the database.
+// GetAllBooks retrieves a page of the user's books from the database.
 //
-//	@Summary		Retrieve all books
-//	@Description	Retrieves all books from the database.
+//	@Summary		Retrieve books
+//	@Description	Retrieves a page of the user's books, filtered and sorted by the query parameters.
 //	@Tags			books
 //	@Accept			json
 //	@Produce		json
 //	@Param			Authorization	header		string					true	"JWT Token"
+//	@Param			cursor			query		string					false	"Cursor returned as next_cursor by the previous page"
+//	@Param			limit			query		int						false	"Page size, 20 by default and 100 at most"
+//	@Param			author			query		string					false	"Author name"
+//	@Param			isbn_prefix		query		string					false	"ISBN prefix"
+//	@Param			published_from	query		string					false	"Published on or after (YYYY-MM-DD)"
+//	@Param			published_to	query		string					false	"Published on or before (YYYY-MM-DD)"
+//	@Param			min_pages		query		int						false	"Minimum page count"
+//	@Param			max_pages		query		int						false	"Maximum page count"
+//	@Param			status			query		string					false	"Reading status: want_to_read, reading, finished or abandoned"
+//	@Param			sort			query		string					false	"Sort key: id, name, date_published or page_count"
+//	@Param			order			query		string					false	"Sort order: asc or desc"
 //	@Success		200
 //	@Failure		400
-//	@Router			/v1/books [get]
+//	@Router			/v1/books/{user_id}/books [get]
 func (b *BookHandler) GetAllBooks(c *gin.Context) {
+	var filter models.BookFilter
+
 	log := utils.GetLogger(b.ctx)
 
-	books, err := b.bookRepository.GetAllBooks()
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		log.Errorf("Query binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := filter.Validate(); err != nil {
+		log.Warningf("Book filter validation error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	books, err := b.bookRepository.ListBooks(userID, &filter)
 	if err != nil {
-		log.Errorf("Get All Books repository error: %v", err)
+		log.Errorf("List Books repository error: %v", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	log.Infof("Books: %v", books)
-	c.JSON(http.StatusOK, gin.H{"books": books})
+	log.Infof("Books: %v", books.Books)
+	c.JSON(http.StatusOK, books)
 }
 
-// DeleteBook deletes a book from the database.
+// DeleteBook moves a book to the trash.
 //
 //	@Summary		Delete a book
-//	@Description	Deletes a book from the database.
+//	@Description	Moves a book to the trash, where it can be restored until the retention period is over.
 //	@Tags			books
 //	@Accept			json
 //	@Produce		json
@@ -200,7 +291,7 @@ func (b *BookHandler) DeleteBook(c *gin.Context) {
 	bookID := c.GetInt("bookID")
 	userID := c.GetInt("userID")
 
-	deletedID, err := b.bookRepository.DeleteBook(bookID, userID)
+	deletedID, err := b.bookRepository.DeleteBook(bookID, userID, c.GetInt("actorID"))
 	if err != nil {
 		log.Errorf("Delete Book repository error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -210,3 +301,57 @@ func (b *BookHandler) DeleteBook(c *gin.Context) {
 	log.Infof("Book deleted successfully, id: %v", deletedID)
 	c.JSON(http.StatusOK, gin.H{"message": "Book deleted successfully"})
 }
+
+// SearchBooks runs a full-text search over the user's books and the shop catalog.
+//
+//	@Summary		Search books
+//	@Description	Searches titles and authors of the user's books and the shop catalog, best matches first.
+//	@Tags			books
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string						true	"JWT Token"
+//	@Param			q				query		string						true	"Search terms, matched as prefixes"
+//	@Param			limit			query		int							false	"Maximum number of results, 20 by default and 100 at most"
+//	@Success		200
+//	@Failure		400
+//	@Failure		500
+//	@Router			/v1/books/search [get]
+func (b *BookHandler) SearchBooks(c *gin.Context) {
+	var search models.SearchRequest
+
+	log := utils.GetLogger(b.ctx)
+
+	if err := c.ShouldBindQuery(&search); err != nil {
+		log.Errorf("Query binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if search.Limit == 0 {
+		search.Limit = models.DefaultPageSize
+	}
+
+	if search.Limit < 0 || search.Limit > models.MaxPageSize {
+		log.Warningf("Invalid search limit: %v", search.Limit)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit is out of range"})
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	results, err := b.bookRepository.SearchBooks(userID, &search)
+	if errors.Is(err, repository.ErrEmptySearch) {
+		log.Warningf("Search Books repository error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("Search Books repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infof("Search results: %v", results)
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
@@ -4,28 +4,51 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	imagepkg "image"
+	"image/png"
 	"io"
+	"library/books/covers"
 	"library/books/handler"
+	"library/books/metadata"
+	"library/books/metadata/metadatafakes"
 	"library/books/models"
+	"library/books/repository"
 	"library/books/repository/repositoryfakes"
 	"library/books/server"
+	"library/pkg/authz"
 	"library/pkg/logger"
 	"library/pkg/middleware"
+	"library/pkg/storage/storagefakes"
 	userModel "library/users/models"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"time"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
+type staticAPIKeys struct {
+	claims *userModel.Claims
+}
+
+func (s staticAPIKeys) ResolveAPIKey(context.Context, string) (*userModel.Claims, error) {
+	return s.claims, nil
+}
+
 var _ = Describe("Book API Test", func() {
 	var (
 		fakeBooker      *repositoryfakes.FakeBookerRepository
 		fakeBookHandler handler.BookerHandler
+		fakeAuthorer    *repositoryfakes.FakeAuthorerRepository
+		fakeStorage     *storagefakes.FakeStorage
+		fakeMetadata    *metadatafakes.FakeProvider
 		w               *httptest.ResponseRecorder
 		ginCtx          *gin.Context
 		router          *gin.Engine
@@ -42,9 +65,18 @@ var _ = Describe("Book API Test", func() {
 		ctx = context.WithValue(ctx, "logger", logger.NewLogger(2))
 
 		fakeBooker = &repositoryfakes.FakeBookerRepository{}
-		fakeBookHandler = handler.NewBookHandler(ctx, fakeBooker)
+		fakeStorage = &storagefakes.FakeStorage{}
+		fakeStorage.URLStub = func(key string) string { return "/v1/books/covers/" + key }
+		fakeMetadata = &metadatafakes.FakeProvider{}
+		fakeBookHandler = handler.NewBookHandler(ctx, fakeBooker, fakeStorage, fakeMetadata)
 
-		router = server.NewRouter(fakeBookHandler)
+		fakeAuthorer = &repositoryfakes.FakeAuthorerRepository{}
+
+		router = server.NewRouter(
+			fakeBookHandler,
+			handler.NewAuthorHandler(ctx, fakeAuthorer),
+			handler.NewShelfHandler(ctx, &repositoryfakes.FakeShelferRepository{}),
+		)
 
 		user = &userModel.User{
 			ID:    1,
@@ -76,18 +108,17 @@ var _ = Describe("Book API Test", func() {
 				ID:            1,
 				Name:          "tmostowashere",
 				DatePublished: "2022-01-01",
-				ISBN:          "12345679",
+				ISBN:          "0-261-10221-4",
 				PageCount:     123,
-				Author: models.AuthorRequest{
-					ID:   1,
-					Name: "tmostowashere",
+				Authors: []models.AuthorRequest{
+					{ID: 1, Name: "tmostowashere"},
 				},
 			}
 
 			body, err := json.Marshal(request)
 			Expect(err).To(BeNil())
 
-			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/:user_id/books", bytes.NewBuffer(body))
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books", bytes.NewBuffer(body))
 			Expect(err).To(BeNil())
 			ginCtx.Request.AddCookie(cookie)
 			ginCtx.Request = enricher(ginCtx.Request)
@@ -97,8 +128,234 @@ var _ = Describe("Book API Test", func() {
 			router.ServeHTTP(w, ginCtx.Request)
 
 			Expect(w.Code).To(Equal(http.StatusCreated), "Expected HTTP status Created")
-			args := fakeBooker.AddBookArgsForCall(0)
+			args, actorID := fakeBooker.AddBookArgsForCall(0)
+			Expect(actorID).To(Equal(1))
 			Expect(args.ID).To(Equal(1), "Expected AddBOok to be called with the correct arguments")
+			Expect(args.ISBN).To(Equal("9780261102217"), "Expected the ISBN to be stored as ISBN-13")
+		})
+
+		It("should add the book for the path user whatever user the body names", func() {
+			body := []byte(`{"name": "tmostowashere", "date_published": "2022-01-01", "authors": [{"name": "tmostowashere"}], "user": {"id": 2}}`)
+
+			var err error
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books", bytes.NewBuffer(body))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.AddBookReturns(1, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusCreated), "Expected HTTP status Created")
+			args, _ := fakeBooker.AddBookArgsForCall(0)
+			Expect(args.UserID.ID).To(Equal(1))
+		})
+
+		It("should keep the contributors in order and default their role", func() {
+			var request = &models.BookRequest{
+				Name:          "Good Omens",
+				DatePublished: "1990-05-01",
+				Authors: []models.AuthorRequest{
+					{Name: "Terry Pratchett"},
+					{Name: " Neil Gaiman ", Role: models.AuthorRoleAuthor},
+					{Name: "Paul Kidby", Role: models.AuthorRoleIllustrator},
+				},
+			}
+
+			body, err := json.Marshal(request)
+			Expect(err).To(BeNil())
+
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books", bytes.NewBuffer(body))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.AddBookReturns(1, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusCreated), "Expected HTTP status Created")
+			args, _ := fakeBooker.AddBookArgsForCall(0)
+			Expect(args.Authors).To(Equal([]models.AuthorRequest{
+				{Name: "Terry Pratchett", Role: models.AuthorRoleAuthor},
+				{Name: "Neil Gaiman", Role: models.AuthorRoleAuthor},
+				{Name: "Paul Kidby", Role: models.AuthorRoleIllustrator},
+			}))
+		})
+
+		It("should reject an unknown contributor role", func() {
+			var request = &models.BookRequest{
+				Name:          "Good Omens",
+				DatePublished: "1990-05-01",
+				Authors:       []models.AuthorRequest{{Name: "Terry Pratchett", Role: "narrator"}},
+			}
+
+			body, err := json.Marshal(request)
+			Expect(err).To(BeNil())
+
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books", bytes.NewBuffer(body))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest), "Expected HTTP status Bad Request")
+			Expect(w.Body.String()).To(ContainSubstring("author 1: role must be one of: author, editor, translator, illustrator"))
+			Expect(fakeBooker.AddBookCallCount()).To(Equal(0))
+		})
+
+		It("should reject an invalid ISBN", func() {
+			var request = &models.BookRequest{
+				Name:          "tmostowashere",
+				DatePublished: "2022-01-01",
+				ISBN:          "12345679",
+				Authors:       []models.AuthorRequest{{Name: "tmostowashere"}},
+			}
+
+			body, err := json.Marshal(request)
+			Expect(err).To(BeNil())
+
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books", bytes.NewBuffer(body))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusUnprocessableEntity), "Expected HTTP status Unprocessable Entity")
+			Expect(w.Body.String()).To(ContainSubstring("must have 10 or 13 digits, got 8"))
+			Expect(fakeBooker.AddBookCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("Lookup", func() {
+		var hobbit *models.BookRequest
+
+		BeforeEach(func() {
+			hobbit = &models.BookRequest{
+				Name:          "The Hobbit",
+				DatePublished: "1991-07-01",
+				ISBN:          "9780261103344",
+				PageCount:     310,
+				Authors:       []models.AuthorRequest{{Name: "J. R. R. Tolkien", Role: models.AuthorRoleAuthor}},
+			}
+		})
+
+		lookup := func(isbn string) {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books/lookup?isbn="+isbn, nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			router.ServeHTTP(w, ginCtx.Request)
+		}
+
+		It("should return the catalog book first", func() {
+			fakeBooker.LookupISBNReturns(hobbit, nil)
+
+			lookup("0-261-10334-2")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(fakeBooker.LookupISBNArgsForCall(0)).To(Equal("9780261103344"))
+			Expect(fakeMetadata.LookupISBNCallCount()).To(Equal(0))
+
+			var response struct {
+				Book   models.BookRequest `json:"book"`
+				Source string             `json:"source"`
+			}
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Source).To(Equal(handler.LookupSourceCatalog))
+			Expect(response.Book).To(Equal(*hobbit))
+		})
+
+		It("should fall back to the metadata provider", func() {
+			fakeBooker.LookupISBNReturns(nil, repository.ErrISBNNotInCatalog)
+			fakeMetadata.LookupISBNReturns(hobbit, nil)
+
+			lookup("9780261103344")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(fakeMetadata.LookupISBNArgsForCall(0)).To(Equal("9780261103344"))
+			Expect(w.Body.String()).To(ContainSubstring(`"source":"metadata"`))
+		})
+
+		It("should answer not found when no source knows the ISBN", func() {
+			fakeBooker.LookupISBNReturns(nil, repository.ErrISBNNotInCatalog)
+			fakeMetadata.LookupISBNReturns(nil, metadata.ErrNotFound)
+
+			lookup("9780261103344")
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should answer bad gateway when the provider fails", func() {
+			fakeBooker.LookupISBNReturns(nil, repository.ErrISBNNotInCatalog)
+			fakeMetadata.LookupISBNReturns(nil, errors.New("connection refused"))
+
+			lookup("9780261103344")
+
+			Expect(w.Code).To(Equal(http.StatusBadGateway))
+		})
+
+		It("should reject an invalid ISBN", func() {
+			lookup("9780261103345")
+
+			Expect(w.Code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(fakeBooker.LookupISBNCallCount()).To(Equal(0))
+		})
+
+		It("should autofill the fields left empty when adding a book", func() {
+			body, err := json.Marshal(&models.BookRequest{Name: "Hobbit", ISBN: "0261103342"})
+			Expect(err).To(BeNil())
+
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books?autofill=true", bytes.NewBuffer(body))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.LookupISBNReturns(hobbit, nil)
+			fakeBooker.AddBookReturns(1, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusCreated))
+			args, _ := fakeBooker.AddBookArgsForCall(0)
+			Expect(args.Name).To(Equal("Hobbit"))
+			Expect(args.ISBN).To(Equal("9780261103344"))
+			Expect(args.DatePublished).To(Equal("1991-07-01"))
+			Expect(args.PageCount).To(Equal(310))
+			Expect(args.Authors).To(Equal(hobbit.Authors))
+		})
+
+		It("should add the book as sent when the lookup finds nothing", func() {
+			body, err := json.Marshal(&models.BookRequest{
+				Name:          "Hobbit",
+				DatePublished: "1937-09-21",
+				ISBN:          "0261103342",
+				Authors:       []models.AuthorRequest{{Name: "Tolkien"}},
+			})
+			Expect(err).To(BeNil())
+
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books?autofill=true", bytes.NewBuffer(body))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.LookupISBNReturns(nil, repository.ErrISBNNotInCatalog)
+			fakeMetadata.LookupISBNReturns(nil, metadata.ErrNotFound)
+			fakeBooker.AddBookReturns(1, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusCreated))
+			args, _ := fakeBooker.AddBookArgsForCall(0)
+			Expect(args.DatePublished).To(Equal("1937-09-21"))
+			Expect(args.PageCount).To(Equal(0))
+			Expect(args.Authors).To(Equal([]models.AuthorRequest{{Name: "Tolkien", Role: models.AuthorRoleAuthor}}))
 		})
 	})
 
@@ -108,18 +365,17 @@ var _ = Describe("Book API Test", func() {
 				ID:            1,
 				Name:          "tmostowashere",
 				DatePublished: "2022-01-01",
-				ISBN:          "12345679",
+				ISBN:          "0-261-10221-4",
 				PageCount:     123,
-				Author: models.AuthorRequest{
-					ID:   1,
-					Name: "tmostowashere",
+				Authors: []models.AuthorRequest{
+					{ID: 1, Name: "tmostowashere"},
 				},
 			}
 
 			body, err := json.Marshal(request)
 			Expect(err).To(BeNil())
 
-			ginCtx.Request, err = http.NewRequest("PUT", "/v1/books/:user_id/books/1", bytes.NewBuffer(body))
+			ginCtx.Request, err = http.NewRequest("PUT", "/v1/books/1/books/1", bytes.NewBuffer(body))
 			Expect(err).To(BeNil())
 			ginCtx.Request.AddCookie(cookie)
 			ginCtx.Request = enricher(ginCtx.Request)
@@ -130,9 +386,8 @@ var _ = Describe("Book API Test", func() {
 				DatePublished: "2022-01-01",
 				ISBN:          "12345679",
 				PageCount:     123,
-				Author: models.AuthorResponse{
-					ID:   1,
-					Name: "tmostowashere",
+				Authors: []models.AuthorResponse{
+					{ID: 1, Name: "tmostowashere"},
 				},
 			}
 
@@ -144,13 +399,139 @@ var _ = Describe("Book API Test", func() {
 			actualBody, _ := io.ReadAll(w.Result().Body)
 			Expect(w.Body.String()).To(Equal(string(actualBody)), "Unexpected response body: %s", w.Body.String())
 		})
+
+		It("should update the book of another user as the admin with the books:write:any permission", func() {
+			user.Permissions = []string{authz.BooksWriteAny}
+			token, _ := middleware.GenerateJWT(*user)
+
+			body := []byte(`{"name": "tmostowashere", "date_published": "2022-01-01", "authors": [{"name": "tmostowashere"}]}`)
+
+			var err error
+			ginCtx.Request, err = http.NewRequest("PUT", "/v1/books/2/books/1", bytes.NewBuffer(body))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(&http.Cookie{Name: "token", Value: token})
+
+			fakeBooker.UpdateBookReturns(&models.BookResponse{ID: 1, Version: 2}, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusCreated), "Expected HTTP status Created")
+			args, actorID := fakeBooker.UpdateBookArgsForCall(0)
+			Expect(args.UserID.ID).To(Equal(2), "Expected the book to stay with its owner")
+			Expect(actorID).To(Equal(1), "Expected the admin to be recorded as the actor")
+		})
+
+		It("should refuse a stale write", func() {
+			var request = &models.BookRequest{
+				Name:          "tmostowashere",
+				DatePublished: "2022-01-01",
+				Authors:       []models.AuthorRequest{{Name: "tmostowashere"}},
+			}
+
+			body, err := json.Marshal(request)
+			Expect(err).To(BeNil())
+
+			ginCtx.Request, err = http.NewRequest("PUT", "/v1/books/1/books/1", bytes.NewBuffer(body))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+			ginCtx.Request.Header.Set("If-Match", `"3"`)
+
+			fakeBooker.UpdateBookReturns(nil, repository.ErrVersionMismatch)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusPreconditionFailed), "Expected HTTP status Precondition Failed")
+			args, _ := fakeBooker.UpdateBookArgsForCall(0)
+			Expect(args.Version).To(Equal(3))
+		})
+	})
+
+	Describe("PatchBook", func() {
+		var current *models.BookResponse
+
+		BeforeEach(func() {
+			current = &models.BookResponse{
+				ID:            1,
+				Name:          "The Hobbit",
+				DatePublished: "1937-09-21T00:00:00Z",
+				ISBN:          "9780261102217",
+				PageCount:     310,
+				Authors: []models.AuthorResponse{
+					{ID: 1, Name: "J. R. R. Tolkien", Role: models.AuthorRoleAuthor},
+				},
+				Version: 3,
+			}
+		})
+
+		It("should change only the patched fields", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("PATCH", "/v1/books/1/books/1", bytes.NewBufferString(`{"page_count": 320, "isbn": null}`))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+			ginCtx.Request.Header.Set("Content-Type", "application/merge-patch+json")
+			ginCtx.Request.Header.Set("If-Match", `"3"`)
+
+			fakeBooker.GetBookReturns(current, nil)
+			fakeBooker.UpdateBookReturns(&models.BookResponse{ID: 1, Name: "The Hobbit", Version: 4}, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			Expect(w.Header().Get("ETag")).To(Equal(`"4"`))
+
+			args, _ := fakeBooker.UpdateBookArgsForCall(0)
+			Expect(args.ID).To(Equal(1))
+			Expect(args.UserID.ID).To(Equal(1))
+			Expect(args.Name).To(Equal("The Hobbit"))
+			Expect(args.DatePublished).To(Equal("1937-09-21"))
+			Expect(args.ISBN).To(BeEmpty())
+			Expect(args.PageCount).To(Equal(320))
+			Expect(args.Authors).To(Equal([]models.AuthorRequest{{ID: 1, Name: "J. R. R. Tolkien", Role: models.AuthorRoleAuthor}}))
+			Expect(args.Version).To(Equal(3))
+		})
+
+		It("should fail the precondition when the book has changed", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("PATCH", "/v1/books/1/books/1", bytes.NewBufferString(`{"page_count": 320}`))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+			ginCtx.Request.Header.Set("If-Match", `"2"`)
+
+			fakeBooker.GetBookReturns(current, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusPreconditionFailed), "Expected HTTP status Precondition Failed")
+			Expect(fakeBooker.UpdateBookCallCount()).To(Equal(0))
+		})
+
+		It("should reject a patch that is not an object", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("PATCH", "/v1/books/1/books/1", bytes.NewBufferString(`["page_count"]`))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.GetBookReturns(current, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest), "Expected HTTP status Bad Request")
+			Expect(fakeBooker.UpdateBookCallCount()).To(Equal(0))
+		})
 	})
 
 	Describe("DeleteBoook", func() {
 		It("should delete a book", func() {
 			var err error
 
-			ginCtx.Request, err = http.NewRequest("DELETE", "/v1/books/:user_id/books/2", nil)
+			ginCtx.Request, err = http.NewRequest("DELETE", "/v1/books/1/books/2", nil)
 			Expect(err).To(BeNil())
 			ginCtx.Request.AddCookie(cookie)
 			ginCtx.Request = enricher(ginCtx.Request)
@@ -164,85 +545,716 @@ var _ = Describe("Book API Test", func() {
 		})
 	})
 
-	Describe("GetAllBooks", func() {
-		It("should retrieve all books", func() {
+	Describe("Trash", func() {
+		It("should list the deleted books", func() {
 			var err error
 
-			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/:user_id/books", nil)
+			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/1/books/trash", nil)
 			Expect(err).To(BeNil())
 			ginCtx.Request.AddCookie(cookie)
 			ginCtx.Request = enricher(ginCtx.Request)
 
-			fakeBooker.GetAllBooksReturns([]models.BookResponse{
-				{
-					ID:            1,
-					Name:          "Book1",
-					DatePublished: "Book1",
-					ISBN:          "1234679",
-					PageCount:     123,
-					UserID: userModel.User{
-						ID: 1,
-					},
-					Author: models.AuthorResponse{
-						ID:   1,
-						Name: "Author1",
-					},
-				},
-				{
-					ID:            2,
-					Name:          "Book2",
-					DatePublished: "Book2",
-					ISBN:          "987654321",
-					PageCount:     321,
-					UserID: userModel.User{
-						ID: 2,
-					},
-					Author: models.AuthorResponse{
-						ID:   2,
-						Name: "Author2",
-					},
-				},
-			}, nil)
+			deletedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+			fakeBooker.ListTrashReturns([]models.BookResponse{{ID: 7, Name: "Dune", DeletedAt: &deletedAt}}, nil)
 
 			router.ServeHTTP(w, ginCtx.Request)
 
-			actualBody, _ := io.ReadAll(w.Result().Body)
 			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
-			Expect(w.Body.String()).To(Equal(string(actualBody)), "Unexpected response body: %s", w.Body.String())
+			Expect(w.Body.String()).To(ContainSubstring(`"deleted_at":"2024-03-01T12:00:00Z"`))
+			Expect(fakeBooker.ListTrashArgsForCall(0)).To(Equal(1))
+			Expect(fakeBooker.GetBookCallCount()).To(Equal(0))
 		})
-	})
 
-	Describe("GetBook", func() {
-		It("should retrieve a book", func() {
+		It("should restore a deleted book", func() {
 			var err error
 
-			response := &models.BookResponse{
-				ID:            1,
-				Name:          "Book1",
-				DatePublished: "Book1",
-				ISBN:          "1234679",
-				PageCount:     123,
-				UserID: userModel.User{
-					ID: 1,
-				},
-				Author: models.AuthorResponse{
-					ID:   1,
-					Name: "Author1",
-				},
-			}
-
-			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/:user_id/books/1", nil)
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books/7/restore", nil)
 			Expect(err).To(BeNil())
 			ginCtx.Request.AddCookie(cookie)
 			ginCtx.Request = enricher(ginCtx.Request)
 
-			fakeBooker.GetBookReturns(response, nil)
+			fakeBooker.RestoreBookReturns(7, nil)
 
 			router.ServeHTTP(w, ginCtx.Request)
 
-			actualBody, _ := io.ReadAll(w.Result().Body)
 			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
-			Expect(w.Body.String()).To(Equal(string(actualBody)), "Unexpected response body: %s", w.Body.String())
+			bookID, userID, actorID := fakeBooker.RestoreBookArgsForCall(0)
+			Expect(bookID).To(Equal(7))
+			Expect(userID).To(Equal(1))
+			Expect(actorID).To(Equal(1))
+		})
+
+		It("should answer conflict when another book took the ISBN", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books/7/restore", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.RestoreBookReturns(0, repository.ErrISBNTaken)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusConflict), "Expected HTTP status Conflict")
+		})
+
+		It("should answer not found when the book is not in the trash", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books/7/restore", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.RestoreBookReturns(0, repository.ErrNotInTrash)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound), "Expected HTTP status Not Found")
+		})
+	})
+
+	Describe("Reading", func() {
+		It("should update the reading progress", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("PUT", "/v1/books/1/books/7/reading", bytes.NewBufferString(`{"status": "reading", "current_page": 120}`))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.UpdateReadingReturns(&models.ReadingSession{ID: 1, Status: models.ReadingStatusReading, CurrentPage: 120}, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+
+			bookID, userID, progress := fakeBooker.UpdateReadingArgsForCall(0)
+			Expect(bookID).To(Equal(7))
+			Expect(userID).To(Equal(1))
+			Expect(progress.Status).To(Equal(models.ReadingStatusReading))
+			Expect(*progress.CurrentPage).To(Equal(120))
+		})
+
+		It("should reject an unknown status", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("PUT", "/v1/books/1/books/7/reading", bytes.NewBufferString(`{"status": "skimmed"}`))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest), "Expected HTTP status Bad Request")
+			Expect(fakeBooker.UpdateReadingCallCount()).To(Equal(0))
+		})
+
+		It("should reject a page past the end of the book", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("PUT", "/v1/books/1/books/7/reading", bytes.NewBufferString(`{"current_page": 500}`))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.UpdateReadingReturns(nil, fmt.Errorf("%w: current page must be between 0 and 412", repository.ErrInvalidProgress))
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusUnprocessableEntity), "Expected HTTP status Unprocessable Entity")
+		})
+
+		It("should filter the listing by reading status", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/1/books?status=finished", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.ListBooksReturns(&models.BookListResponse{}, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			_, filter := fakeBooker.ListBooksArgsForCall(0)
+			Expect(filter.Status).To(Equal(models.ReadingStatusFinished))
+		})
+	})
+
+	Describe("Cover", func() {
+		var upload func(data []byte) *http.Request
+
+		BeforeEach(func() {
+			upload = func(data []byte) *http.Request {
+				var body bytes.Buffer
+
+				form := multipart.NewWriter(&body)
+				part, err := form.CreateFormFile("cover", "cover.png")
+				Expect(err).To(BeNil())
+				_, err = part.Write(data)
+				Expect(err).To(BeNil())
+				Expect(form.Close()).To(Succeed())
+
+				req, err := http.NewRequest("PUT", "/v1/books/1/books/7/cover", &body)
+				Expect(err).To(BeNil())
+				req.AddCookie(cookie)
+				req = enricher(req)
+				req.Header.Set("Content-Type", form.FormDataContentType())
+
+				return req
+			}
+		})
+
+		It("should store the cover with its thumbnails and delete the previous one", func() {
+			var image bytes.Buffer
+			Expect(png.Encode(&image, imagepkg.NewRGBA(imagepkg.Rect(0, 0, 900, 1200)))).To(Succeed())
+
+			fakeBooker.SetCoverReturns([]string{"7/0123456789abcdef/original.jpg"}, nil)
+
+			router.ServeHTTP(w, upload(image.Bytes()))
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			Expect(fakeStorage.PutCallCount()).To(Equal(3))
+
+			_, key, contentType, data := fakeStorage.PutArgsForCall(0)
+			Expect(key).To(MatchRegexp(`^7/[0-9a-f]{16}/original\.png$`))
+			Expect(contentType).To(Equal("image/png"))
+			Expect(data).To(Equal(image.Bytes()))
+
+			bookID, userID, cover, keys := fakeBooker.SetCoverArgsForCall(0)
+			Expect(bookID).To(Equal(7))
+			Expect(userID).To(Equal(1))
+			Expect(cover).To(HaveKey("small"))
+			Expect(keys).To(HaveLen(3))
+			Expect(w.Body.String()).To(ContainSubstring(`"original":"/v1/books/covers/` + key + `"`))
+
+			Expect(fakeStorage.DeleteCallCount()).To(Equal(1))
+			_, deleted := fakeStorage.DeleteArgsForCall(0)
+			Expect(deleted).To(Equal("7/0123456789abcdef/original.jpg"))
+		})
+
+		It("should reject files that are not images", func() {
+			router.ServeHTTP(w, upload([]byte("GIF89a not a cover")))
+
+			Expect(w.Code).To(Equal(http.StatusUnsupportedMediaType), "Expected HTTP status Unsupported Media Type")
+			Expect(fakeStorage.PutCallCount()).To(Equal(0))
+		})
+
+		It("should reject covers larger than the limit", func() {
+			router.ServeHTTP(w, upload(make([]byte, covers.MaxSize+1)))
+
+			Expect(w.Code).To(Equal(http.StatusRequestEntityTooLarge), "Expected HTTP status Request Entity Too Large")
+			Expect(fakeStorage.PutCallCount()).To(Equal(0))
+		})
+
+		It("should not store anything for the book of another user", func() {
+			var image bytes.Buffer
+			Expect(png.Encode(&image, imagepkg.NewRGBA(imagepkg.Rect(0, 0, 10, 10)))).To(Succeed())
+
+			fakeBooker.CheckCoverReturns(repository.ErrBookNotFound)
+
+			router.ServeHTTP(w, upload(image.Bytes()))
+
+			Expect(w.Code).To(Equal(http.StatusNotFound), "Expected HTTP status Not Found")
+			Expect(fakeStorage.PutCallCount()).To(Equal(0))
+			Expect(fakeBooker.SetCoverCallCount()).To(Equal(0))
+
+			bookID, userID := fakeBooker.CheckCoverArgsForCall(0)
+			Expect(bookID).To(Equal(7))
+			Expect(userID).To(Equal(1))
+		})
+
+		It("should delete the stored files when the book is gone before the cover is set", func() {
+			var image bytes.Buffer
+			Expect(png.Encode(&image, imagepkg.NewRGBA(imagepkg.Rect(0, 0, 10, 10)))).To(Succeed())
+
+			fakeBooker.SetCoverReturns(nil, repository.ErrBookNotFound)
+
+			router.ServeHTTP(w, upload(image.Bytes()))
+
+			Expect(w.Code).To(Equal(http.StatusNotFound), "Expected HTTP status Not Found")
+			Expect(fakeStorage.DeleteCallCount()).To(Equal(3))
+		})
+
+		It("should remove the cover", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("DELETE", "/v1/books/1/books/7/cover", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.SetCoverReturns([]string{"7/0123456789abcdef/original.webp"}, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+
+			_, _, cover, keys := fakeBooker.SetCoverArgsForCall(0)
+			Expect(cover).To(BeNil())
+			Expect(keys).To(BeNil())
+			Expect(fakeStorage.DeleteCallCount()).To(Equal(1))
+		})
+	})
+
+	Describe("History", func() {
+		It("should list the revisions of a book", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/1/books/7/history", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.ListHistoryReturns([]models.BookRevision{{
+				ID:      11,
+				BookID:  7,
+				Version: 1,
+				Action:  models.HistoryActionCreate,
+				ActorID: 1,
+				After:   &models.BookSnapshot{Name: "Dune"},
+				Changes: map[string]models.FieldChange{"name": {After: "Dune"}},
+			}}, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			Expect(w.Body.String()).To(ContainSubstring(`"changes":{"name":{"before":null,"after":"Dune"}}`))
+
+			bookID, userID := fakeBooker.ListHistoryArgsForCall(0)
+			Expect(bookID).To(Equal(7))
+			Expect(userID).To(Equal(1))
+		})
+
+		It("should revert a book to a revision", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books/7/history/11/revert", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.RevertBookReturns(&models.BookResponse{ID: 7, Name: "Dune", Version: 5}, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			Expect(w.Header().Get("ETag")).To(Equal(`"5"`))
+
+			bookID, userID, revisionID, _ := fakeBooker.RevertBookArgsForCall(0)
+			Expect(bookID).To(Equal(7))
+			Expect(userID).To(Equal(1))
+			Expect(revisionID).To(Equal(11))
+		})
+
+		It("should answer not found for a revision of another book", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books/7/history/99/revert", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.RevertBookReturns(nil, repository.ErrRevisionNotFound)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound), "Expected HTTP status Not Found")
+		})
+	})
+
+	Describe("GetAllBooks", func() {
+		It("should retrieve a page of books", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/1/books?limit=2&sort=name&author=Author", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.ListBooksReturns(&models.BookListResponse{
+				Books: []models.BookResponse{
+					{
+						ID:            1,
+						Name:          "Book1",
+						DatePublished: "Book1",
+						ISBN:          "1234679",
+						PageCount:     123,
+						UserID: userModel.User{
+							ID: 1,
+						},
+						Authors: []models.AuthorResponse{
+							{ID: 1, Name: "Author1"},
+						},
+					},
+					{
+						ID:            2,
+						Name:          "Book2",
+						DatePublished: "Book2",
+						ISBN:          "987654321",
+						PageCount:     321,
+						UserID: userModel.User{
+							ID: 2,
+						},
+						Authors: []models.AuthorResponse{
+							{ID: 2, Name: "Author2"},
+						},
+					},
+				},
+				NextCursor: "next",
+				Total:      3,
+			}, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			Expect(w.Body.String()).To(ContainSubstring(`"next_cursor":"next"`))
+			Expect(w.Body.String()).To(ContainSubstring(`"total":3`))
+
+			userID, filter := fakeBooker.ListBooksArgsForCall(0)
+			Expect(userID).To(Equal(1))
+			Expect(filter.Limit).To(Equal(2))
+			Expect(filter.Sort).To(Equal("name"))
+			Expect(filter.Order).To(Equal("asc"))
+			Expect(filter.Author).To(Equal("Author"))
+		})
+
+		It("should reject an unsupported sort key", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/1/books?sort=isbn", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest), "Expected HTTP status Bad Request")
+			Expect(fakeBooker.ListBooksCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("GetBook", func() {
+		It("should retrieve a book", func() {
+			var err error
+
+			response := &models.BookResponse{
+				ID:            1,
+				Name:          "Book1",
+				DatePublished: "Book1",
+				ISBN:          "1234679",
+				PageCount:     123,
+				UserID: userModel.User{
+					ID: 1,
+				},
+				Authors: []models.AuthorResponse{
+					{ID: 1, Name: "Author1"},
+				},
+				Version: 2,
+			}
+
+			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/1/books/1", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.GetBookReturns(response, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			actualBody, _ := io.ReadAll(w.Result().Body)
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			Expect(w.Header().Get("ETag")).To(Equal(`"2"`))
+			Expect(w.Body.String()).To(Equal(string(actualBody)), "Unexpected response body: %s", w.Body.String())
+		})
+
+		It("should forbid the books of other users", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/2/books/1", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden), "Expected HTTP status Forbidden")
+			Expect(fakeBooker.GetBookCallCount()).To(Equal(0))
+		})
+
+		It("should retrieve the book of another user with the books:read:any permission", func() {
+			var err error
+
+			user.Permissions = []string{authz.BooksReadAny}
+			token, _ := middleware.GenerateJWT(*user)
+
+			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/2/books/1", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(&http.Cookie{Name: "token", Value: token})
+
+			fakeBooker.GetBookReturns(&models.BookResponse{ID: 1, Version: 1}, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			bookID, userID := fakeBooker.GetBookArgsForCall(0)
+			Expect(bookID).To(Equal(1))
+			Expect(userID).To(Equal(2))
+		})
+	})
+	Describe("SearchBooks", func() {
+		It("should return ranked search results", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/search?q=tolkien+hob", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.SearchBooksReturns([]models.SearchResult{
+				{
+					Source:    repository.SearchSourcePersonal,
+					ID:        1,
+					Name:      "The Hobbit",
+					Authors:   "J. R. R. Tolkien",
+					Rank:      0.6,
+					Highlight: "The <mark>Hobbit</mark> J. R. R. <mark>Tolkien</mark>",
+				},
+				{
+					Source:    repository.SearchSourceShop,
+					ID:        7,
+					Name:      "The Hobbit",
+					Authors:   "J. R. R. Tolkien",
+					Rank:      0.5,
+					Highlight: "The <mark>Hobbit</mark> J. R. R. <mark>Tolkien</mark>",
+				},
+			}, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			Expect(w.Body.String()).To(ContainSubstring(`"source":"personal"`))
+			Expect(w.Body.String()).To(ContainSubstring(`"source":"shop"`))
+
+			userID, search := fakeBooker.SearchBooksArgsForCall(0)
+			Expect(userID).To(Equal(1))
+			Expect(search.Query).To(Equal("tolkien hob"))
+			Expect(search.Limit).To(Equal(models.DefaultPageSize))
+		})
+
+		It("should reject a query without search terms", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/search?q=%21%21", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.SearchBooksReturns(nil, repository.ErrEmptySearch)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest), "Expected HTTP status Bad Request")
+		})
+	})
+	Describe("ImportBooks", func() {
+		It("should import a Goodreads CSV export", func() {
+			var err error
+
+			body := "Book Id,Title,Author,Additional Authors,ISBN,ISBN13,Number of Pages,Year Published,Original Publication Year\n" +
+				"1,The Hobbit,J.R.R. Tolkien,\"Alan Lee, Christopher Tolkien\",\"=\"\"0261102214\"\"\",\"=\"\"9780261102217\"\"\",310,1995,1937\n" +
+				"2,Dune,Frank Herbert,,,,many,,1965\n"
+
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books/import?mode=best_effort", bytes.NewBufferString(body))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+			ginCtx.Request.Header.Set("Content-Type", "text/csv")
+
+			fakeBooker.ImportBooksReturns(&models.ImportReport{Mode: models.ImportModeBestEffort, Created: 1, Failed: 1}, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusCreated), "Expected HTTP status Created")
+
+			userID, _, rows, mode := fakeBooker.ImportBooksArgsForCall(0)
+			Expect(userID).To(Equal(1))
+			Expect(mode).To(Equal(models.ImportModeBestEffort))
+			Expect(rows).To(HaveLen(2))
+			Expect(rows[0].Book.Name).To(Equal("The Hobbit"))
+			Expect(rows[0].Book.Authors).To(Equal([]models.AuthorRequest{{Name: "J.R.R. Tolkien"}, {Name: "Alan Lee"}, {Name: "Christopher Tolkien"}}))
+			Expect(rows[0].Book.ISBN).To(Equal("9780261102217"))
+			Expect(rows[0].Book.PageCount).To(Equal(310))
+			Expect(rows[0].Book.DatePublished).To(Equal("1995-01-01"))
+			Expect(rows[1].Error).To(Equal("invalid page count: many"))
+		})
+
+		It("should import a JSON array", func() {
+			books := []models.BookRequest{
+				{Name: "Dune", DatePublished: "1965-08-01", Authors: []models.AuthorRequest{{Name: "Frank Herbert"}}},
+			}
+
+			body, err := json.Marshal(books)
+			Expect(err).To(BeNil())
+
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books/import", bytes.NewBuffer(body))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.ImportBooksReturns(&models.ImportReport{Mode: models.ImportModeAtomic, Failed: 1, RolledBack: true}, nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusUnprocessableEntity), "Expected HTTP status Unprocessable Entity")
+
+			_, _, rows, mode := fakeBooker.ImportBooksArgsForCall(0)
+			Expect(mode).To(Equal(models.ImportModeAtomic))
+			Expect(rows).To(HaveLen(1))
+			Expect(rows[0].Row).To(Equal(1))
+		})
+
+		It("should reject an unsupported mode", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/books/1/books/import?mode=sometimes", bytes.NewBufferString("[]"))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest), "Expected HTTP status Bad Request")
+			Expect(fakeBooker.ImportBooksCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("ExportBooks", func() {
+		It("should stream the books as BibTeX", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/1/books/export?format=bibtex", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.ExportBooksStub = func(userID, chunkSize int, write func([]models.BookResponse) error) error {
+				Expect(write([]models.BookResponse{{ID: 1, Name: "The Hobbit", DatePublished: "1937-09-21T00:00:00Z", Authors: []models.AuthorResponse{{Name: "J. R. R. Tolkien", Role: models.AuthorRoleAuthor}}}})).To(Succeed())
+				return write([]models.BookResponse{{ID: 2, Name: "Dune", Authors: []models.AuthorResponse{{Name: "Frank Herbert"}}}})
+			}
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			Expect(w.Header().Get("Content-Type")).To(Equal("application/x-bibtex"))
+			Expect(w.Header().Get("Content-Disposition")).To(ContainSubstring("books.bib"))
+			Expect(w.Body.String()).To(ContainSubstring("@book{book1,\n  title = {The Hobbit},\n  author = {J. R. R. Tolkien},\n  year = {1937},\n}"))
+			Expect(w.Body.String()).To(ContainSubstring("@book{book2,"))
+
+			userID, _, _ := fakeBooker.ExportBooksArgsForCall(0)
+			Expect(userID).To(Equal(1))
+		})
+
+		It("should export an empty library as a CSV header", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/1/books/export", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.ExportBooksReturns(nil)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			Expect(w.Header().Get("Content-Type")).To(Equal("text/csv"))
+			Expect(w.Body.String()).To(Equal("id,name,authors,isbn,date_published,page_count\n"))
+		})
+
+		It("should reject an unknown format", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/1/books/export?format=pdf", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest), "Expected HTTP status Bad Request")
+			Expect(fakeBooker.ExportBooksCallCount()).To(Equal(0))
+		})
+
+		It("should return an error when the first chunk cannot be read", func() {
+			var err error
+
+			ginCtx.Request, err = http.NewRequest("GET", "/v1/books/1/books/export?format=jsonl", nil)
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			fakeBooker.ExportBooksReturns(errors.New("database error"))
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError), "Expected HTTP status Internal Server Error")
+		})
+	})
+
+	Describe("Scopes", func() {
+		useKey := func(permissions ...string) {
+			middleware.UseAPIKeys(staticAPIKeys{claims: &userModel.Claims{
+				UserID:         "1",
+				Permissions:    permissions,
+				APIKeyID:       3,
+				StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Hour).Unix()},
+			}})
+		}
+
+		send := func(path string) {
+			req, err := http.NewRequest("GET", path, nil)
+			Expect(err).To(BeNil())
+			req.Header.Set("Authorization", "Bearer lib_key")
+
+			router.ServeHTTP(w, req)
+		}
+
+		AfterEach(func() {
+			middleware.UseAPIKeys(nil)
+		})
+
+		It("should refuse the catalog to an API key without the books:read scope", func() {
+			useKey(authz.ReviewsWrite)
+
+			for _, path := range []string{
+				"/v1/books/search?q=hobbit",
+				"/v1/books/authors",
+				"/v1/books/authors/1",
+				"/v1/books/authors/1/duplicates",
+			} {
+				w = httptest.NewRecorder()
+				send(path)
+
+				Expect(w.Code).To(Equal(http.StatusForbidden), "Expected HTTP status Forbidden for %s", path)
+			}
+
+			Expect(fakeBooker.SearchBooksCallCount()).To(Equal(0))
+			Expect(fakeAuthorer.ListAuthorsCallCount()).To(Equal(0))
+			Expect(fakeAuthorer.GetAuthorCallCount()).To(Equal(0))
+			Expect(fakeAuthorer.SuggestDuplicatesCallCount()).To(Equal(0))
+		})
+
+		It("should search with an API key of the books:read scope", func() {
+			useKey(authz.BooksRead)
+
+			send("/v1/books/search?q=hobbit")
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			Expect(fakeBooker.SearchBooksCallCount()).To(Equal(1))
 		})
 	})
 })
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"library/books/covers"
+	"library/books/models"
+	"library/books/repository"
+	"library/pkg/logger"
+	"library/pkg/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCoverRequestSize leaves room for the multipart headers around the image.
+const maxCoverRequestSize = covers.MaxSize + 64<<10
+
+// UploadCover stores a new cover image for a book.
+//
+//	@Summary		Upload a book cover
+//	@Description	Stores a JPEG, PNG or WebP cover sent as the cover field of a multipart form, with its thumbnails, and replaces the previous cover.
+//	@Tags			books
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"JWT Token"
+//	@Param			book_id			path		int		true	"Book ID"
+//	@Param			cover			formData	file	true	"Cover image, 5 MiB at most"
+//	@Success		200
+//	@Failure		400
+//	@Failure		404
+//	@Failure		413
+//	@Failure		415
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/books/{book_id}/cover [put]
+func (b *BookHandler) UploadCover(c *gin.Context) {
+	log := utils.GetLogger(b.ctx)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCoverRequestSize)
+
+	file, _, err := c.Request.FormFile("cover")
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		log.Warningf("Cover upload too large: %v", err)
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Warningf("Cover form error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, covers.MaxSize+1))
+	if err != nil {
+		log.Errorf("Failed to read cover: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(data) > covers.MaxSize {
+		log.Warningf("Cover image too large: %d bytes", len(data))
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("cover cannot be larger than %d bytes", covers.MaxSize)})
+		return
+	}
+
+	renditions, err := covers.Render(data)
+	if errors.Is(err, covers.ErrUnsupportedType) {
+		log.Warningf("Unsupported cover: %v", err)
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Warningf("Invalid cover: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": covers.ErrInvalidImage.Error()})
+		return
+	}
+
+	bookID := c.GetInt("bookID")
+	userID := c.GetInt("userID")
+
+	// SetCover checks the book again, this only keeps files of someone else's book out of the storage
+	if err = b.bookRepository.CheckCover(bookID, userID); err != nil {
+		log.Errorf("Check cover repository error: %v", err)
+		c.JSON(coverErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// the content hash in the keys gives every cover new URLs, so caches never serve a stale one
+	sum := sha256.Sum256(data)
+	prefix := fmt.Sprintf("%d/%x", bookID, sum[:8])
+
+	cover := models.Cover{}
+	keys := make([]string, 0, len(renditions))
+	for _, rendition := range renditions {
+		key := prefix + "/" + rendition.Name + "." + rendition.Extension
+
+		if err = b.coverStorage.Put(c.Request.Context(), key, rendition.ContentType, rendition.Data); err != nil {
+			log.Errorf("Failed to store cover %v: %v", key, err)
+			b.deleteCoverFiles(c, log, keys, nil)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store the cover"})
+			return
+		}
+
+		cover[rendition.Name] = b.coverStorage.URL(key)
+		keys = append(keys, key)
+	}
+
+	previous, err := b.bookRepository.SetCover(bookID, userID, cover, keys)
+	if err != nil {
+		log.Errorf("Set cover repository error: %v", err)
+		b.deleteCoverFiles(c, log, keys, previous)
+		c.JSON(coverErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	b.deleteCoverFiles(c, log, previous, keys)
+
+	log.Infof("Cover of book %v stored under %v", bookID, prefix)
+	c.JSON(http.StatusOK, gin.H{"cover": cover})
+}
+
+// DeleteCover removes the cover of a book.
+//
+//	@Summary		Delete a book cover
+//	@Description	Removes the cover of a book and its stored images.
+//	@Tags			books
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"JWT Token"
+//	@Param			book_id			path		int		true	"Book ID"
+//	@Success		200
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/books/{book_id}/cover [delete]
+func (b *BookHandler) DeleteCover(c *gin.Context) {
+	log := utils.GetLogger(b.ctx)
+
+	previous, err := b.bookRepository.SetCover(c.GetInt("bookID"), c.GetInt("userID"), nil, nil)
+	if err != nil {
+		log.Errorf("Set cover repository error: %v", err)
+		c.JSON(coverErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	b.deleteCoverFiles(c, log, previous, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cover deleted successfully"})
+}
+
+// deleteCoverFiles removes the stored images, except the ones still in use. Failures
+// only leave unreferenced files behind, so they are logged and not reported.
+func (b *BookHandler) deleteCoverFiles(c *gin.Context, log logger.Logger, keys, keep []string) {
+	kept := make(map[string]bool, len(keep))
+	for _, key := range keep {
+		kept[key] = true
+	}
+
+	for _, key := range keys {
+		if kept[key] {
+			continue
+		}
+
+		if err := b.coverStorage.Delete(c.Request.Context(), key); err != nil {
+			log.Errorf("Failed to delete cover %v: %v", key, err)
+		}
+	}
+}
+
+func coverErrorStatus(err error) int {
+	if errors.Is(err, repository.ErrBookNotFound) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
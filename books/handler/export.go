@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"fmt"
+	"library/books/export"
+	"library/books/models"
+	"library/pkg/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const exportChunkSize = 500
+
+// ExportBooks streams the user's library in the requested format.
+//
+//	@Summary		Export books
+//	@Description	Streams all books of the user as CSV, JSON Lines, BibTeX or MARCXML.
+//	@Tags			books
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		application/x-bibtex
+//	@Produce		application/marcxml+xml
+//	@Param			Authorization	header	string	true	"JWT Token"
+//	@Param			format			query	string	false	"csv (default), jsonl, bibtex or marcxml"
+//	@Success		200
+//	@Failure		400
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/books/export [get]
+func (b *BookHandler) ExportBooks(c *gin.Context) {
+	log := utils.GetLogger(b.ctx)
+
+	name := c.DefaultQuery("format", "csv")
+
+	format, ok := export.Lookup(name)
+	if !ok {
+		log.Warningf("Unsupported export format: %v", name)
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("format must be one of: %s", strings.Join(export.Names(), ", "))})
+		return
+	}
+
+	writer := format.NewWriter(c.Writer)
+	started := false
+
+	// headers go out with the first chunk, so a failing first query still gets a proper status code
+	begin := func() error {
+		started = true
+
+		c.Header("Content-Type", format.ContentType)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"books.%s\"", format.Extension))
+		c.Status(http.StatusOK)
+
+		return writer.Begin()
+	}
+
+	err := b.bookRepository.ExportBooks(c.GetInt("userID"), exportChunkSize, func(books []models.BookResponse) error {
+		if !started {
+			if err := begin(); err != nil {
+				return err
+			}
+		}
+
+		for _, book := range books {
+			if err := writer.Write(book); err != nil {
+				return err
+			}
+		}
+
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Failed to export books: %v", err)
+		if !started {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		// the body is already streaming, the client gets a truncated export
+		c.Abort()
+		return
+	}
+
+	if !started {
+		err = begin()
+	}
+	if err == nil {
+		err = writer.End()
+	}
+	if err != nil {
+		log.Errorf("Failed to write export: %v", err)
+		return
+	}
+
+	c.Writer.Flush()
+}
@@ -1,6 +1,9 @@
 package handler_test
 
 import (
+	"library/pkg/jwks"
+	"library/pkg/middleware"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -11,3 +14,10 @@ func TestHandler(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Handler Suite")
 }
+
+var _ = BeforeSuite(func() {
+	keys, err := jwks.GenerateKeySet()
+	Expect(err).To(BeNil())
+
+	middleware.UseSigningKeys(keys)
+})
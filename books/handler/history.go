@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"library/books/repository"
+	"library/pkg/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListHistory retrieves the change history of a book.
+//
+//	@Summary		List book revisions
+//	@Description	Retrieves every recorded change of a book, newest first, with the state before and after it.
+//	@Tags			books
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string						true	"JWT Token"
+//	@Param			book_id			path		int							true	"Book ID"
+//	@Success		200
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/books/{book_id}/history [get]
+func (b *BookHandler) ListHistory(c *gin.Context) {
+	log := utils.GetLogger(b.ctx)
+
+	bookID := c.GetInt("bookID")
+	userID := c.GetInt("userID")
+
+	revisions, err := b.bookRepository.ListHistory(bookID, userID)
+	if errors.Is(err, repository.ErrBookNotFound) {
+		log.Warningf("List History repository error: %v", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("List History repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infof("Book %v history: %d revisions", bookID, len(revisions))
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// RevertBook puts a book back in the state of one of its revisions.
+//
+//	@Summary		Revert a book
+//	@Description	Restores the book fields and contributors recorded by the revision, the revert is recorded as a new revision.
+//	@Tags			books
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string						true	"JWT Token"
+//	@Param			book_id			path		int							true	"Book ID"
+//	@Param			revision_id		path		int							true	"Revision ID"
+//	@Success		200
+//	@Failure		400
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/books/{book_id}/history/{revision_id}/revert [post]
+func (b *BookHandler) RevertBook(c *gin.Context) {
+	log := utils.GetLogger(b.ctx)
+
+	bookID := c.GetInt("bookID")
+	userID := c.GetInt("userID")
+	revisionID := c.GetInt("revisionID")
+
+	book, err := b.bookRepository.RevertBook(bookID, userID, revisionID, c.GetInt("actorID"))
+	switch {
+	case errors.Is(err, repository.ErrBookNotFound), errors.Is(err, repository.ErrRevisionNotFound):
+		log.Warningf("Revert Book repository error: %v", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, repository.ErrInvalidRevert):
+		log.Warningf("Revert Book repository error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		log.Errorf("Revert Book repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("ETag", bookETag(book.Version))
+
+	log.Infof("Book %v reverted to revision %v", bookID, revisionID)
+	c.JSON(http.StatusOK, gin.H{"book": book})
+}
@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"library/books/models"
+	"library/pkg/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxImportSize = 10 << 20
+	maxImportRows = 5000
+)
+
+// importColumns maps the accepted CSV headers, including the Goodreads export ones, onto book fields.
+var importColumns = map[string]string{
+	"name":                      "name",
+	"title":                     "name",
+	"author":                    "author",
+	"authors":                   "authors",
+	"additional authors":        "additional_authors",
+	"isbn":                      "isbn",
+	"isbn13":                    "isbn13",
+	"page_count":                "page_count",
+	"number of pages":           "page_count",
+	"date_published":            "date_published",
+	"year published":            "year_published",
+	"original publication year": "original_year",
+}
+
+// ImportBooks adds many books to the database at once.
+//
+//	@Summary		Import books
+//	@Description	Imports books from a CSV file (Goodreads export columns are supported) or a JSON array and reports the outcome of every row.
+//	@Tags			books
+//	@Accept			json
+//	@Accept			text/csv
+//	@Produce		json
+//	@Param			Authorization	header		string						true	"JWT Token"
+//	@Param			mode			query		string						false	"atomic (default) rolls back everything when a row fails, best_effort keeps the rows that succeeded"
+//	@Success		200
+//	@Success		201
+//	@Failure		400
+//	@Failure		413
+//	@Failure		415
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/books/import [post]
+func (b *BookHandler) ImportBooks(c *gin.Context) {
+	var rows []models.ImportRow
+	var err error
+
+	log := utils.GetLogger(b.ctx)
+
+	mode := c.DefaultQuery("mode", models.ImportModeAtomic)
+	if mode != models.ImportModeAtomic && mode != models.ImportModeBestEffort {
+		log.Warningf("Unsupported import mode: %v", mode)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be either atomic or best_effort"})
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	switch c.ContentType() {
+	case "text/csv":
+		rows, err = parseImportCSV(body)
+	case "application/json":
+		rows, err = parseImportJSON(body)
+	default:
+		log.Warningf("Unsupported import content type: %v", c.ContentType())
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "content type must be text/csv or application/json"})
+		return
+	}
+
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		log.Warningf("Import body too large: %v", err)
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("Import parsing error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(rows) == 0 || len(rows) > maxImportRows {
+		log.Warningf("Invalid number of import rows: %v", len(rows))
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("import must contain between 1 and %d rows", maxImportRows)})
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	report, err := b.bookRepository.ImportBooks(userID, c.GetInt("actorID"), rows, mode)
+	if err != nil {
+		log.Errorf("Import Books repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if report.RolledBack {
+		status = http.StatusUnprocessableEntity
+	} else if report.Created > 0 {
+		status = http.StatusCreated
+	}
+
+	log.Infof("Books imported, created: %d, skipped: %d, failed: %d", report.Created, report.Skipped, report.Failed)
+	c.JSON(status, report)
+}
+
+func parseImportJSON(body io.Reader) ([]models.ImportRow, error) {
+	var books []models.BookRequest
+
+	if err := json.NewDecoder(body).Decode(&books); err != nil {
+		return nil, err
+	}
+
+	rows := make([]models.ImportRow, len(books))
+	for i, book := range books {
+		rows[i] = models.ImportRow{Row: i + 1, Book: book}
+	}
+
+	return rows, nil
+}
+
+func parseImportCSV(body io.Reader) ([]models.ImportRow, error) {
+	var rows []models.ImportRow
+
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := importColumns[name]; ok {
+			columns[field] = i
+		}
+	}
+
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("CSV header must contain a name or title column")
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var parseError *csv.ParseError
+		if errors.As(err, &parseError) {
+			rows = append(rows, models.ImportRow{Row: len(rows) + 1, Error: err.Error()})
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		rows = append(rows, parseImportRecord(len(rows)+1, record, columns))
+	}
+
+	return rows, nil
+}
+
+func parseImportRecord(index int, record []string, columns map[string]int) models.ImportRow {
+	row := models.ImportRow{Row: index}
+
+	value := func(field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		// Goodreads writes ISBNs as ="0439023483" so spreadsheets keep the leading zeros
+		return strings.Trim(strings.TrimSpace(record[i]), `="`)
+	}
+
+	row.Book.Name = value("name")
+
+	if author := value("author"); author != "" {
+		row.Book.Authors = append(row.Book.Authors, models.AuthorRequest{Name: author})
+	}
+
+	for _, author := range strings.Split(value("additional_authors"), ",") {
+		if author = strings.TrimSpace(author); author != "" {
+			row.Book.Authors = append(row.Book.Authors, models.AuthorRequest{Name: author})
+		}
+	}
+
+	row.Book.Authors = append(row.Book.Authors, parseCredits(value("authors"))...)
+
+	row.Book.ISBN = value("isbn13")
+	if row.Book.ISBN == "" {
+		row.Book.ISBN = value("isbn")
+	}
+
+	if pages := value("page_count"); pages != "" {
+		pageCount, err := strconv.Atoi(pages)
+		if err != nil {
+			row.Error = fmt.Sprintf("invalid page count: %s", pages)
+			return row
+		}
+
+		row.Book.PageCount = pageCount
+	}
+
+	row.Book.DatePublished = value("date_published")
+	for _, field := range []string{"year_published", "original_year"} {
+		if row.Book.DatePublished != "" {
+			break
+		}
+
+		if year := value(field); year != "" {
+			row.Book.DatePublished = year + "-01-01"
+		}
+	}
+
+	return row
+}
+
+// parseCredits reads the authors column written by the CSV export, e.g. "Frank Herbert; Jane Doe (translator)".
+func parseCredits(credits string) []models.AuthorRequest {
+	var authors []models.AuthorRequest
+
+	for _, credit := range strings.Split(credits, ";") {
+		credit = strings.TrimSpace(credit)
+		if credit == "" {
+			continue
+		}
+
+		author := models.AuthorRequest{Name: credit}
+		if open := strings.LastIndex(credit, " ("); open > 0 && strings.HasSuffix(credit, ")") {
+			author.Name = credit[:open]
+			author.Role = credit[open+2 : len(credit)-1]
+		}
+
+		authors = append(authors, author)
+	}
+
+	return authors
+}
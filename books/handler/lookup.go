@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"library/books/metadata"
+	"library/books/models"
+	"library/books/repository"
+	"library/pkg/isbn"
+	"library/pkg/logger"
+	"library/pkg/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Sources of a looked up book.
+const (
+	LookupSourceCatalog  = "catalog"
+	LookupSourceMetadata = "metadata"
+)
+
+// LookupBook finds the details of a book by its ISBN.
+//
+//	@Summary		Look up a book by ISBN
+//	@Description	Looks the ISBN up in the shop catalog, then with the metadata provider, and returns a pre-filled book the user can confirm with AddBook.
+//	@Tags			books
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"JWT Token"
+//	@Param			isbn			query		string	true	"ISBN-10 or ISBN-13"
+//	@Success		200
+//	@Failure		404
+//	@Failure		422
+//	@Failure		502
+//	@Router			/v1/books/{user_id}/books/lookup [post]
+func (b *BookHandler) LookupBook(c *gin.Context) {
+	log := utils.GetLogger(b.ctx)
+
+	canonical, err := isbn.Normalize(c.Query("isbn"))
+	if err != nil {
+		log.Warningf("Invalid lookup ISBN: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	book, source, err := b.lookupISBN(log, canonical)
+	if errors.Is(err, metadata.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"book": book, "source": source})
+}
+
+// lookupISBN returns the catalog book with the canonical ISBN, or asks the metadata
+// provider when the catalog does not have it.
+func (b *BookHandler) lookupISBN(log logger.Logger, canonical string) (*models.BookRequest, string, error) {
+	book, err := b.bookRepository.LookupISBN(canonical)
+	if err == nil {
+		return book, LookupSourceCatalog, nil
+	}
+
+	if !errors.Is(err, repository.ErrISBNNotInCatalog) {
+		log.Errorf("Catalog lookup error: %v", err)
+	}
+
+	book, err = b.metadata.LookupISBN(canonical)
+	if errors.Is(err, metadata.ErrNotFound) {
+		log.Warningf("No metadata for ISBN %v", canonical)
+		return nil, "", err
+	}
+
+	if err != nil {
+		log.Errorf("Metadata lookup error: %v", err)
+		return nil, "", err
+	}
+
+	return book, LookupSourceMetadata, nil
+}
+
+// autofill completes the fields the user left empty with the looked up details of
+// the ISBN. The book is added as sent when nothing is found.
+func (b *BookHandler) autofill(log logger.Logger, book *models.BookRequest) error {
+	canonical, err := isbn.Normalize(book.ISBN)
+	if err != nil {
+		return err
+	}
+
+	found, _, err := b.lookupISBN(log, canonical)
+	if err != nil {
+		log.Warningf("Autofill skipped for ISBN %v: %v", canonical, err)
+		return nil
+	}
+
+	book.ISBN = canonical
+
+	if book.Name == "" {
+		book.Name = found.Name
+	}
+
+	if book.DatePublished == "" {
+		book.DatePublished = found.DatePublished
+	}
+
+	if book.PageCount == 0 {
+		book.PageCount = found.PageCount
+	}
+
+	if len(book.Authors) == 0 {
+		book.Authors = found.Authors
+	}
+
+	return nil
+}
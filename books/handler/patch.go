@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"library/books/models"
+	"library/books/repository"
+	"library/pkg"
+	"library/pkg/logger"
+	"library/pkg/mergepatch"
+	"library/pkg/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxPatchSize = 1 << 20
+
+// PatchBook applies a JSON Merge Patch to an existing book.
+//
+//	@Summary		Partially update a book
+//	@Description	Applies a JSON Merge Patch (RFC 7386) to a book, fields missing from the patch keep their value.
+//	@Tags			books
+//	@Accept			application/merge-patch+json
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string						true	"JWT Token"
+//	@Param			If-Match		header		string						false	"ETag returned by GetBook, the patch fails when the book has changed since"
+//	@Param			book_id			path		int							true	"Book ID"
+//	@Param			patch			body		models.BookRequest			true	"Fields to change, null removes a field"
+//	@Success		200
+//	@Failure		400
+//	@Failure		401
+//	@Failure		404
+//	@Failure		412
+//	@Failure		413
+//	@Failure		415
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/books/{book_id} [patch]
+func (b *BookHandler) PatchBook(c *gin.Context) {
+	var book models.BookRequest
+
+	log := utils.GetLogger(b.ctx)
+
+	if contentType := c.ContentType(); contentType != mergepatch.ContentType && contentType != "application/json" {
+		log.Warningf("Unsupported patch content type: %v", contentType)
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "content type must be " + mergepatch.ContentType})
+		return
+	}
+
+	patch, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPatchSize))
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		log.Warningf("Patch body too large: %v", err)
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("Failed to read patch: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	version, ok := ifMatch(c)
+	if !ok {
+		log.Warningf("Unusable If-Match header: %v", c.GetHeader("If-Match"))
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": repository.ErrVersionMismatch.Error()})
+		return
+	}
+
+	bookID := c.GetInt("bookID")
+
+	current, err := b.bookRepository.GetBook(bookID, c.GetInt("userID"))
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("Book not found: %v", bookID)
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Book ID doesn't exists: %v", bookID)})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("Get Book repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if version != 0 && version != current.Version {
+		log.Warningf("Book %v is at version %v, If-Match expects %v", bookID, current.Version, version)
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": repository.ErrVersionMismatch.Error()})
+		return
+	}
+
+	current.DatePublished = dateOnly(current.DatePublished)
+
+	document, err := json.Marshal(current)
+	if err != nil {
+		log.Errorf("Failed to encode book: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	patched, err := mergepatch.Apply(document, patch)
+	if err == nil {
+		err = json.Unmarshal(patched, &book)
+	}
+
+	if err != nil {
+		log.Errorf("Failed to apply patch: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if status, err := validateBook(log, &book); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	// the patch cannot move the book to another user or id, and a write that
+	// happened since GetBook must not be overwritten even without If-Match
+	book.ID = bookID
+	book.UserID.ID = c.GetInt("userID")
+	book.Version = current.Version
+
+	bookResponse, err := b.bookRepository.UpdateBook(&book, c.GetInt("actorID"))
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		log.Warningf("Update book repository error: %v", err)
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("Update book repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("ETag", bookETag(bookResponse.Version))
+
+	log.Infof("Book patched successfully: %v", bookResponse)
+	c.JSON(http.StatusOK, gin.H{"book": bookResponse})
+}
+
+// validateBook runs the checks shared by every book write and returns the status to answer with.
+func validateBook(log logger.Logger, book *models.BookRequest) (int, error) {
+	if err := pkg.CheckPublishedDate(log, book); err != nil {
+		log.Errorf("checking published date error: %v", err)
+		return http.StatusBadRequest, errors.New("Cannot add a book with a future publication date")
+	}
+
+	if err := pkg.CheckISBN(log, book); err != nil {
+		log.Errorf("checking ISBN error: %v", err)
+		return http.StatusUnprocessableEntity, err
+	}
+
+	if err := book.ValidateAuthors(); err != nil {
+		log.Errorf("checking authors error: %v", err)
+		return http.StatusBadRequest, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// bookETag formats the book version as a strong entity tag.
+func bookETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// ifMatch reads the version expected by the If-Match header, 0 when any version is fine.
+// It is not ok when the header holds something other than a single book ETag.
+func ifMatch(c *gin.Context) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	tag, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+
+	return version, true
+}
+
+// dateOnly trims the time the database driver adds to dates.
+func dateOnly(date string) string {
+	if parsed, err := time.Parse(time.RFC3339, date); err == nil {
+		return parsed.Format(time.DateOnly)
+	}
+
+	return date
+}
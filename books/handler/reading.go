@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"errors"
+	"library/books/models"
+	"library/books/repository"
+	"library/pkg/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetReading retrieves the reading status and the reading sessions of a book.
+//
+//	@Summary		Retrieve reading progress
+//	@Description	Retrieves the current reading session of a book, every previous read and how many times it was finished.
+//	@Tags			books
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string						true	"JWT Token"
+//	@Param			book_id			path		int							true	"Book ID"
+//	@Success		200
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/books/{book_id}/reading [get]
+func (b *BookHandler) GetReading(c *gin.Context) {
+	log := utils.GetLogger(b.ctx)
+
+	bookID := c.GetInt("bookID")
+	userID := c.GetInt("userID")
+
+	reading, err := b.bookRepository.GetReading(bookID, userID)
+	if errors.Is(err, repository.ErrBookNotFound) {
+		log.Warningf("Get Reading repository error: %v", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("Get Reading repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infof("Book %v reading: %v", bookID, reading.Current)
+	c.JSON(http.StatusOK, reading)
+}
+
+// UpdateReading updates the reading status and progress of a book.
+//
+//	@Summary		Update reading progress
+//	@Description	Updates the status, current page and dates of the current read. Moving a finished or abandoned book back to reading or want_to_read starts a re-read.
+//	@Tags			books
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string							true	"JWT Token"
+//	@Param			book_id			path		int								true	"Book ID"
+//	@Param			progress		body		models.ReadingProgressRequest	true	"Reading progress"
+//	@Success		200
+//	@Failure		400
+//	@Failure		404
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/books/{book_id}/reading [put]
+func (b *BookHandler) UpdateReading(c *gin.Context) {
+	var progress models.ReadingProgressRequest
+
+	log := utils.GetLogger(b.ctx)
+
+	if err := c.ShouldBindJSON(&progress); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := progress.Validate(); err != nil {
+		log.Warningf("Reading progress validation error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	bookID := c.GetInt("bookID")
+	userID := c.GetInt("userID")
+
+	session, err := b.bookRepository.UpdateReading(bookID, userID, &progress)
+	switch {
+	case errors.Is(err, repository.ErrBookNotFound):
+		log.Warningf("Update Reading repository error: %v", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, repository.ErrInvalidProgress):
+		log.Warningf("Update Reading repository error: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		log.Errorf("Update Reading repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infof("Book %v reading updated: %v", bookID, session)
+	c.JSON(http.StatusOK, gin.H{"reading": session})
+}
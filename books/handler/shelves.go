@@ -0,0 +1,332 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"library/books/models"
+	"library/books/repository"
+	"library/pkg/authz"
+	"library/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShelferHandler interface {
+	ListShelves(c *gin.Context)
+	CreateShelf(c *gin.Context)
+	GetShelf(c *gin.Context)
+	UpdateShelf(c *gin.Context)
+	DeleteShelf(c *gin.Context)
+	AddShelfBook(c *gin.Context)
+	RemoveShelfBook(c *gin.Context)
+	ReorderShelf(c *gin.Context)
+}
+
+type ShelfHandler struct {
+	ctx             context.Context
+	shelfRepository repository.ShelferRepository
+}
+
+func NewShelfHandler(ctx context.Context, shelfer repository.ShelferRepository) ShelferHandler {
+	return &ShelfHandler{
+		ctx:             ctx,
+		shelfRepository: shelfer,
+	}
+}
+
+// ListShelves retrieves the shelves of the user.
+//
+//	@Summary		List shelves
+//	@Description	Retrieves the user's shelves sorted by name, with the number of books on each.
+//	@Tags			shelves
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"JWT Token"
+//	@Success		200
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/shelves [get]
+func (s *ShelfHandler) ListShelves(c *gin.Context) {
+	log := utils.GetLogger(s.ctx)
+
+	shelves, err := s.shelfRepository.ListShelves(c.GetInt("userID"))
+	if err != nil {
+		log.Errorf("List shelves repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"shelves": shelves})
+}
+
+// CreateShelf adds a new shelf for the user.
+//
+//	@Summary		Create a shelf
+//	@Description	Creates a shelf, names must be unique among the user's shelves.
+//	@Tags			shelves
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"JWT Token"
+//	@Param			shelf			body		models.ShelfRequest	true	"Name, description and visibility"
+//	@Success		201
+//	@Failure		400
+//	@Failure		409
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/shelves [post]
+func (s *ShelfHandler) CreateShelf(c *gin.Context) {
+	var request models.ShelfRequest
+
+	log := utils.GetLogger(s.ctx)
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		log.Warningf("Invalid shelf: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	shelf, err := s.shelfRepository.CreateShelf(c.GetInt("userID"), &request)
+	if err != nil {
+		log.Errorf("Create shelf repository error: %v", err)
+		c.JSON(shelfErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infof("Shelf created successfully, id: %v", shelf.ID)
+	c.JSON(http.StatusCreated, gin.H{"shelf": shelf})
+}
+
+// GetShelf retrieves a shelf with its books.
+//
+//	@Summary		Retrieve a shelf by ID
+//	@Description	Retrieves a shelf of the user with its books in shelf order. Public shelves can be read by every authenticated user, private ones by their owner and users with the books:read:any permission.
+//	@Tags			shelves
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"JWT Token"
+//	@Param			user_id			path		int		true	"Owner ID"
+//	@Param			shelf_id		path		int		true	"Shelf ID"
+//	@Success		200
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/shelves/{shelf_id} [get]
+func (s *ShelfHandler) GetShelf(c *gin.Context) {
+	log := utils.GetLogger(s.ctx)
+
+	shelf, err := s.shelfRepository.GetShelf(c.GetInt("shelfID"), c.GetInt("ownerID"), c.GetInt("userID"), authz.Can(c, authz.BooksReadAny))
+	if err != nil {
+		log.Errorf("Get shelf repository error: %v", err)
+		c.JSON(shelfErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"shelf": shelf})
+}
+
+// UpdateShelf renames a shelf or changes its description and visibility.
+//
+//	@Summary		Update a shelf
+//	@Description	Replaces the name, description and visibility of one of the user's shelves.
+//	@Tags			shelves
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"JWT Token"
+//	@Param			shelf_id		path		int					true	"Shelf ID"
+//	@Param			shelf			body		models.ShelfRequest	true	"Name, description and visibility"
+//	@Success		200
+//	@Failure		400
+//	@Failure		404
+//	@Failure		409
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/shelves/{shelf_id} [put]
+func (s *ShelfHandler) UpdateShelf(c *gin.Context) {
+	var request models.ShelfRequest
+
+	log := utils.GetLogger(s.ctx)
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		log.Warningf("Invalid shelf: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	shelf, err := s.shelfRepository.UpdateShelf(c.GetInt("shelfID"), c.GetInt("userID"), &request)
+	if err != nil {
+		log.Errorf("Update shelf repository error: %v", err)
+		c.JSON(shelfErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"shelf": shelf})
+}
+
+// DeleteShelf removes a shelf, the books on it are kept.
+//
+//	@Summary		Delete a shelf
+//	@Description	Deletes one of the user's shelves. The books on it stay in the library.
+//	@Tags			shelves
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"JWT Token"
+//	@Param			shelf_id		path		int		true	"Shelf ID"
+//	@Success		200
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/shelves/{shelf_id} [delete]
+func (s *ShelfHandler) DeleteShelf(c *gin.Context) {
+	log := utils.GetLogger(s.ctx)
+
+	shelfID := c.GetInt("shelfID")
+
+	if err := s.shelfRepository.DeleteShelf(shelfID, c.GetInt("userID")); err != nil {
+		log.Errorf("Delete shelf repository error: %v", err)
+		c.JSON(shelfErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infof("Shelf deleted successfully, id: %v", shelfID)
+	c.JSON(http.StatusOK, gin.H{"message": "Shelf deleted successfully"})
+}
+
+// AddShelfBook puts one of the user's books on a shelf.
+//
+//	@Summary		Add a book to a shelf
+//	@Description	Puts a book on the shelf at the given 1-based position, or last when no position is given.
+//	@Tags			shelves
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string					true	"JWT Token"
+//	@Param			shelf_id		path		int						true	"Shelf ID"
+//	@Param			book			body		models.ShelfBookRequest	true	"Book ID and position"
+//	@Success		201
+//	@Failure		400
+//	@Failure		404
+//	@Failure		409
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/shelves/{shelf_id}/books [post]
+func (s *ShelfHandler) AddShelfBook(c *gin.Context) {
+	var request models.ShelfBookRequest
+
+	log := utils.GetLogger(s.ctx)
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		log.Warningf("Invalid shelf book: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := s.shelfRepository.AddShelfBook(c.GetInt("shelfID"), c.GetInt("userID"), &request)
+	if err != nil {
+		log.Errorf("Add shelf book repository error: %v", err)
+		c.JSON(shelfErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Book added to the shelf"})
+}
+
+// RemoveShelfBook takes a book off a shelf.
+//
+//	@Summary		Remove a book from a shelf
+//	@Description	Takes a book off the shelf, the books after it move up one position.
+//	@Tags			shelves
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"JWT Token"
+//	@Param			shelf_id		path		int		true	"Shelf ID"
+//	@Param			book_id			path		int		true	"Book ID"
+//	@Success		200
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/shelves/{shelf_id}/books/{book_id} [delete]
+func (s *ShelfHandler) RemoveShelfBook(c *gin.Context) {
+	log := utils.GetLogger(s.ctx)
+
+	bookID, err := strconv.Atoi(c.Param("book_id"))
+	if err != nil {
+		log.Warningf("Invalid book id: %v", c.Param("book_id"))
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = s.shelfRepository.RemoveShelfBook(c.GetInt("shelfID"), c.GetInt("userID"), bookID)
+	if err != nil {
+		log.Errorf("Remove shelf book repository error: %v", err)
+		c.JSON(shelfErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Book removed from the shelf"})
+}
+
+// ReorderShelf changes the order of the books on a shelf.
+//
+//	@Summary		Reorder a shelf
+//	@Description	Gives the books on the shelf the order of the list, which must contain every book on the shelf once.
+//	@Tags			shelves
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string						true	"JWT Token"
+//	@Param			shelf_id		path		int							true	"Shelf ID"
+//	@Param			order			body		models.ShelfOrderRequest	true	"Book IDs in the new order"
+//	@Success		200
+//	@Failure		400
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/shelves/{shelf_id}/books/order [put]
+func (s *ShelfHandler) ReorderShelf(c *gin.Context) {
+	var request models.ShelfOrderRequest
+
+	log := utils.GetLogger(s.ctx)
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		log.Warningf("Invalid shelf order: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := s.shelfRepository.ReorderShelf(c.GetInt("shelfID"), c.GetInt("userID"), request.BookIDs)
+	if err != nil {
+		log.Errorf("Reorder shelf repository error: %v", err)
+		c.JSON(shelfErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Shelf reordered successfully"})
+}
+
+func shelfErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrShelfNotFound), errors.Is(err, repository.ErrBookNotOnShelf):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrShelfNameTaken), errors.Is(err, repository.ErrBookOnShelf):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrBookNotShelvable):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, repository.ErrInvalidOrder):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,176 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"library/books/handler"
+	"library/books/metadata/metadatafakes"
+	"library/books/models"
+	"library/books/repository"
+	"library/books/repository/repositoryfakes"
+	"library/books/server"
+	"library/pkg/logger"
+	"library/pkg/middleware"
+	"library/pkg/storage/storagefakes"
+	userModel "library/users/models"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shelf API Test", func() {
+	var (
+		fakeShelfer *repositoryfakes.FakeShelferRepository
+		w           *httptest.ResponseRecorder
+		request     func(method, path string, body interface{}) *http.Request
+		serve       func(req *http.Request)
+	)
+
+	JustBeforeEach(func() {
+		w = httptest.NewRecorder()
+
+		ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+		fakeShelfer = &repositoryfakes.FakeShelferRepository{}
+		router := server.NewRouter(
+			handler.NewBookHandler(ctx, &repositoryfakes.FakeBookerRepository{}, &storagefakes.FakeStorage{}, &metadatafakes.FakeProvider{}),
+			handler.NewAuthorHandler(ctx, &repositoryfakes.FakeAuthorerRepository{}),
+			handler.NewShelfHandler(ctx, fakeShelfer),
+		)
+
+		token, _ := middleware.GenerateJWT(userModel.User{ID: 1, Email: "tmostowashere@tmostowashere.com", Role: "user"})
+
+		request = func(method, path string, body interface{}) *http.Request {
+			var payload bytes.Buffer
+			if body != nil {
+				Expect(json.NewEncoder(&payload).Encode(body)).To(Succeed())
+			}
+
+			req, err := http.NewRequest(method, path, &payload)
+			Expect(err).To(BeNil())
+			req.AddCookie(&http.Cookie{Name: "token", Value: token, Expires: time.Now().Add(time.Hour)})
+			req.Header.Set("Content-Type", "application/json")
+
+			return req
+		}
+
+		serve = func(req *http.Request) {
+			router.ServeHTTP(w, req)
+		}
+	})
+
+	Describe("CreateShelf", func() {
+		It("should create a shelf with a trimmed name", func() {
+			fakeShelfer.CreateShelfReturns(&models.Shelf{ID: 3, UserID: 1, Name: "Sci-fi"}, nil)
+
+			serve(request("POST", "/v1/books/1/shelves", models.ShelfRequest{Name: "  Sci-fi ", Public: true}))
+
+			Expect(w.Code).To(Equal(http.StatusCreated), "Expected HTTP status Created")
+
+			userID, shelf := fakeShelfer.CreateShelfArgsForCall(0)
+			Expect(userID).To(Equal(1))
+			Expect(shelf.Name).To(Equal("Sci-fi"))
+			Expect(shelf.Public).To(BeTrue())
+		})
+
+		It("should reject a shelf without a name", func() {
+			serve(request("POST", "/v1/books/1/shelves", models.ShelfRequest{Name: "   "}))
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest), "Expected HTTP status Bad Request")
+			Expect(fakeShelfer.CreateShelfCallCount()).To(Equal(0))
+		})
+
+		It("should report a conflict when the name is taken", func() {
+			fakeShelfer.CreateShelfReturns(nil, repository.ErrShelfNameTaken)
+
+			serve(request("POST", "/v1/books/1/shelves", models.ShelfRequest{Name: "Favourites"}))
+
+			Expect(w.Code).To(Equal(http.StatusConflict), "Expected HTTP status Conflict")
+		})
+	})
+
+	Describe("GetShelf", func() {
+		It("should return the shelf with its books", func() {
+			fakeShelfer.GetShelfReturns(&models.ShelfResponse{
+				Shelf: models.Shelf{ID: 3, UserID: 2, Name: "Lend-able", Public: true, BookCount: 1},
+				Books: []models.BookResponse{{ID: 7, Name: "Dune"}},
+			}, nil)
+
+			serve(request("GET", "/v1/books/2/shelves/3", nil))
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			Expect(w.Body.String()).To(ContainSubstring(`"name":"Dune"`))
+
+			shelfID, ownerID, readerID, readAny := fakeShelfer.GetShelfArgsForCall(0)
+			Expect(shelfID).To(Equal(3))
+			Expect(ownerID).To(Equal(2))
+			Expect(readerID).To(Equal(1))
+			Expect(readAny).To(BeFalse())
+		})
+
+		It("should hide a private shelf of another user", func() {
+			fakeShelfer.GetShelfReturns(nil, repository.ErrShelfNotFound)
+
+			serve(request("GET", "/v1/books/2/shelves/3", nil))
+
+			Expect(w.Code).To(Equal(http.StatusNotFound), "Expected HTTP status Not Found")
+		})
+	})
+
+	Describe("AddShelfBook", func() {
+		It("should put the book at the requested position", func() {
+			serve(request("POST", "/v1/books/1/shelves/3/books", models.ShelfBookRequest{BookID: 7, Position: 2}))
+
+			Expect(w.Code).To(Equal(http.StatusCreated), "Expected HTTP status Created")
+
+			shelfID, userID, book := fakeShelfer.AddShelfBookArgsForCall(0)
+			Expect(shelfID).To(Equal(3))
+			Expect(userID).To(Equal(1))
+			Expect(book.BookID).To(Equal(7))
+			Expect(book.Position).To(Equal(2))
+		})
+
+		It("should report a conflict when the book is already on the shelf", func() {
+			fakeShelfer.AddShelfBookReturns(repository.ErrBookOnShelf)
+
+			serve(request("POST", "/v1/books/1/shelves/3/books", models.ShelfBookRequest{BookID: 7}))
+
+			Expect(w.Code).To(Equal(http.StatusConflict), "Expected HTTP status Conflict")
+		})
+	})
+
+	Describe("RemoveShelfBook", func() {
+		It("should take the book off the shelf", func() {
+			serve(request("DELETE", "/v1/books/1/shelves/3/books/7", nil))
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+
+			shelfID, userID, bookID := fakeShelfer.RemoveShelfBookArgsForCall(0)
+			Expect([]int{shelfID, userID, bookID}).To(Equal([]int{3, 1, 7}))
+		})
+	})
+
+	Describe("ReorderShelf", func() {
+		It("should reject a book listed twice", func() {
+			serve(request("PUT", "/v1/books/1/shelves/3/books/order", models.ShelfOrderRequest{BookIDs: []int{7, 8, 7}}))
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest), "Expected HTTP status Bad Request")
+			Expect(fakeShelfer.ReorderShelfCallCount()).To(Equal(0))
+		})
+
+		It("should report an order that does not match the shelf", func() {
+			fakeShelfer.ReorderShelfReturns(repository.ErrInvalidOrder)
+
+			serve(request("PUT", "/v1/books/1/shelves/3/books/order", models.ShelfOrderRequest{BookIDs: []int{8, 7}}))
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest), "Expected HTTP status Bad Request")
+
+			_, _, bookIDs := fakeShelfer.ReorderShelfArgsForCall(0)
+			Expect(bookIDs).To(Equal([]int{8, 7}))
+		})
+	})
+})
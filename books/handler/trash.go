@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"library/books/repository"
+	"library/pkg/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListTrash retrieves the deleted books of the user that were not purged yet.
+//
+//	@Summary		List deleted books
+//	@Description	Retrieves the user's books in the trash, most recently deleted first.
+//	@Tags			books
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string						true	"JWT Token"
+//	@Success		200
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/books/trash [get]
+func (b *BookHandler) ListTrash(c *gin.Context) {
+	log := utils.GetLogger(b.ctx)
+
+	userID := c.GetInt("userID")
+
+	books, err := b.bookRepository.ListTrash(userID)
+	if err != nil {
+		log.Errorf("List Trash repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infof("Trash: %v", books)
+	c.JSON(http.StatusOK, gin.H{"books": books})
+}
+
+// RestoreBook moves a deleted book out of the trash.
+//
+//	@Summary		Restore a deleted book
+//	@Description	Moves a book from the trash back to the user's library.
+//	@Tags			books
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string						true	"JWT Token"
+//	@Param			book_id			path		int							true	"Book ID"
+//	@Success		200
+//	@Failure		404
+//	@Failure		409
+//	@Failure		500
+//	@Router			/v1/books/{user_id}/books/{book_id}/restore [post]
+func (b *BookHandler) RestoreBook(c *gin.Context) {
+	log := utils.GetLogger(b.ctx)
+
+	bookID := c.GetInt("bookID")
+	userID := c.GetInt("userID")
+
+	restoredID, err := b.bookRepository.RestoreBook(bookID, userID, c.GetInt("actorID"))
+	if errors.Is(err, repository.ErrNotInTrash) {
+		log.Warningf("Restore Book repository error: %v", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, repository.ErrISBNTaken) {
+		log.Warningf("Restore Book repository error: %v", err)
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("Restore Book repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infof("Book restored successfully, id: %v", restoredID)
+	c.JSON(http.StatusOK, gin.H{"message": "Book restored successfully"})
+}
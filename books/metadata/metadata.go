@@ -0,0 +1,86 @@
+// Package metadata looks up book details by ISBN outside the library catalog.
+package metadata
+
+import (
+	"errors"
+	"library/books/models"
+	"library/pkg/isbn"
+	shopModels "library/shops/models"
+	"strings"
+	"time"
+)
+
+var ErrNotFound = errors.New("no book metadata found for this ISBN")
+
+// Provider looks up the details of a book by its canonical ISBN-13.
+type Provider interface {
+	LookupISBN(isbn string) (*models.BookRequest, error)
+}
+
+// SearchFunc searches volumes by ISBN, see service.GetBookByISBN.
+type SearchFunc func(isbn string) (shopModels.BooksRequest, error)
+
+type ShopProvider struct {
+	search SearchFunc
+}
+
+// NewShopProvider returns a Provider backed by the shop metadata service.
+func NewShopProvider(search SearchFunc) Provider {
+	return &ShopProvider{search: search}
+}
+
+// LookupISBN returns the first volume whose industry identifiers match the ISBN,
+// as the search may also return volumes that only mention it.
+func (s *ShopProvider) LookupISBN(value string) (*models.BookRequest, error) {
+	volumes, err := s.search(value)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range volumes.Items {
+		info := item.VolumeInfo
+
+		if !hasISBN(info.ISBN, value) {
+			continue
+		}
+
+		book := &models.BookRequest{
+			Name:          strings.TrimSpace(info.Name),
+			DatePublished: parseDate(info.DatePublished),
+			ISBN:          value,
+			PageCount:     info.PageCount,
+		}
+
+		for _, name := range info.Authors {
+			if name = strings.TrimSpace(name); name != "" {
+				book.Authors = append(book.Authors, models.AuthorRequest{Name: name, Role: models.AuthorRoleAuthor})
+			}
+		}
+
+		return book, nil
+	}
+
+	return nil, ErrNotFound
+}
+
+func hasISBN(identifiers []shopModels.ISBN, value string) bool {
+	for _, id := range identifiers {
+		if canonical, ok := isbn.FromIndustryIdentifier(id.Type, id.Identifier); ok && canonical == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseDate turns the published date, which may only hold a year or a month,
+// into a full date. Unknown formats are dropped.
+func parseDate(value string) string {
+	for _, format := range []string{"2006-01-02", "2006-01", "2006"} {
+		if date, err := time.Parse(format, value); err == nil {
+			return date.Format("2006-01-02")
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,89 @@
+package metadata_test
+
+import (
+	"encoding/json"
+	"errors"
+	"library/books/metadata"
+	"library/books/models"
+	shopModels "library/shops/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const hobbitISBN = "9780261103344"
+
+func volumes(body string) shopModels.BooksRequest {
+	var result shopModels.BooksRequest
+	Expect(json.Unmarshal([]byte(body), &result)).To(Succeed())
+	return result
+}
+
+var _ = Describe("ShopProvider", func() {
+	var (
+		response   shopModels.BooksRequest
+		searchErr  error
+		searchedBy string
+		provider   metadata.Provider
+	)
+
+	BeforeEach(func() {
+		response = shopModels.BooksRequest{}
+		searchErr = nil
+		searchedBy = ""
+		provider = metadata.NewShopProvider(func(isbn string) (shopModels.BooksRequest, error) {
+			searchedBy = isbn
+			return response, searchErr
+		})
+	})
+
+	It("returns the volume matching the ISBN", func() {
+		response = volumes(`{"items": [
+			{"volumeInfo": {"title": "Unrelated", "industryIdentifiers": [{"type": "ISBN_13", "identifier": "9780306406157"}]}},
+			{"volumeInfo": {
+				"title": " The Hobbit ",
+				"publishedDate": "1991-07",
+				"pageCount": 310,
+				"authors": ["J. R. R. Tolkien", " "],
+				"industryIdentifiers": [{"type": "OTHER", "identifier": "x"}, {"type": "ISBN_10", "identifier": "0261103342"}]
+			}}
+		]}`)
+
+		book, err := provider.LookupISBN(hobbitISBN)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(searchedBy).To(Equal(hobbitISBN))
+		Expect(book).To(Equal(&models.BookRequest{
+			Name:          "The Hobbit",
+			DatePublished: "1991-07-01",
+			ISBN:          hobbitISBN,
+			PageCount:     310,
+			Authors:       []models.AuthorRequest{{Name: "J. R. R. Tolkien", Role: models.AuthorRoleAuthor}},
+		}))
+	})
+
+	It("drops a published date it cannot parse", func() {
+		response = volumes(`{"items": [{"volumeInfo": {
+			"title": "The Hobbit",
+			"publishedDate": "circa 1937",
+			"industryIdentifiers": [{"type": "ISBN_13", "identifier": "978-0-261-10334-4"}]
+		}}]}`)
+
+		book, err := provider.LookupISBN(hobbitISBN)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(book.DatePublished).To(BeEmpty())
+	})
+
+	It("returns ErrNotFound when no volume has the ISBN", func() {
+		response = volumes(`{"items": [{"volumeInfo": {"title": "Unrelated", "industryIdentifiers": [{"type": "ISBN_13", "identifier": "9780306406157"}]}}]}`)
+
+		_, err := provider.LookupISBN(hobbitISBN)
+		Expect(err).To(MatchError(metadata.ErrNotFound))
+	})
+
+	It("returns the search error", func() {
+		searchErr = errors.New("connection refused")
+
+		_, err := provider.LookupISBN(hobbitISBN)
+		Expect(err).To(MatchError("connection refused"))
+	})
+})
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Contributor roles of a personal book.
+const (
+	AuthorRoleAuthor      = "author"
+	AuthorRoleEditor      = "editor"
+	AuthorRoleTranslator  = "translator"
+	AuthorRoleIllustrator = "illustrator"
+)
+
+var AuthorRoles = []string{AuthorRoleAuthor, AuthorRoleEditor, AuthorRoleTranslator, AuthorRoleIllustrator}
+
+// ValidateAuthors trims the contributor names, defaults missing roles to author
+// and checks that the book has at least one named contributor with a known role.
+func (b *BookRequest) ValidateAuthors() error {
+	if len(b.Authors) == 0 {
+		return errors.New("at least one author is required")
+	}
+
+	for i := range b.Authors {
+		author := &b.Authors[i]
+
+		author.Name = strings.TrimSpace(author.Name)
+		if author.Name == "" {
+			return fmt.Errorf("author %d: name is required", i+1)
+		}
+
+		if author.Role == "" {
+			author.Role = AuthorRoleAuthor
+		}
+
+		if !isAuthorRole(author.Role) {
+			return fmt.Errorf("author %d: role must be one of: %s", i+1, strings.Join(AuthorRoles, ", "))
+		}
+	}
+
+	return nil
+}
+
+func isAuthorRole(role string) bool {
+	for _, r := range AuthorRoles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Author represents an author of the shared catalog together with the number of books crediting them.
+type Author struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Biography string `json:"biography,omitempty"`
+	BookCount int    `json:"book_count"`
+}
+
+// AuthorUpdate represents the request body for renaming an author or changing the biography.
+type AuthorUpdate struct {
+	Name      string  `json:"name"`
+	Biography *string `json:"biography,omitempty"`
+}
+
+// AuthorFilter represents the query parameters for listing and searching authors.
+type AuthorFilter struct {
+	Query  string `form:"q"`
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+}
+
+// AuthorListResponse represents a page of authors.
+type AuthorListResponse struct {
+	Authors []Author `json:"authors"`
+	Total   int      `json:"total"`
+}
+
+// AuthorSuggestion is an author that is likely a duplicate of another one.
+type AuthorSuggestion struct {
+	ID         int     `json:"id"`
+	Name       string  `json:"name"`
+	Similarity float64 `json:"similarity"`
+}
+
+// MergeAuthorsRequest represents the request body for merging duplicates into an author.
+type MergeAuthorsRequest struct {
+	DuplicateIDs []int `json:"duplicate_ids"`
+}
+
+// MergeAuthorsResponse reports what a merge changed.
+type MergeAuthorsResponse struct {
+	AuthorID        int   `json:"author_id"`
+	MergedIDs       []int `json:"merged_ids"`
+	UserBookCredits int64 `json:"user_book_credits"`
+	ShopBookCredits int64 `json:"shop_book_credits"`
+}
+
+// Validate fills in the default page size and checks the paging parameters.
+func (f *AuthorFilter) Validate() error {
+	if f.Limit == 0 {
+		f.Limit = DefaultPageSize
+	}
+
+	if f.Limit < 1 || f.Limit > MaxPageSize {
+		return fmt.Errorf("limit must be between 1 and %d", MaxPageSize)
+	}
+
+	if f.Offset < 0 {
+		return errors.New("offset cannot be negative")
+	}
+
+	return nil
+}
+
+// Validate trims the new name and checks that it is not empty.
+func (u *AuthorUpdate) Validate() error {
+	u.Name = strings.Join(strings.Fields(u.Name), " ")
+	if u.Name == "" {
+		return errors.New("author name is required")
+	}
+
+	return nil
+}
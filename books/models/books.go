@@ -2,38 +2,85 @@ package models
 
 import (
 	"library/users/models"
+	"time"
 )
 
 // AuthorRequest represents the request body for creating or updating an author.
 type AuthorRequest struct {
 	ID   int    `json:"id,omitempty" form:"id"`
 	Name string `json:"name,omitempty" form:"name"`
+	Role string `json:"role,omitempty" form:"role"`
 }
 
 // AuthorResponse represents the response body for retrieving author information.
 type AuthorResponse struct {
 	ID   int    `json:"id,omitempty" form:"id"`
 	Name string `json:"name,omitempty" form:"name"`
+	Role string `json:"role,omitempty" form:"role"`
 }
 
 // BookRequest represents the request body for creating or updating a book.
 type BookRequest struct {
-	ID            int           `json:"id,omitempty" form:"id"`
-	Name          string        `json:"name,omitempty" form:"name"`
-	DatePublished string        `json:"date_published,omitempty" form:"date_published"`
-	ISBN          string        `json:"isbn,omitempty" form:"isbn"`
-	PageCount     int           `json:"page_count,omitempty" form:"page_count"`
-	UserID        models.User   `json:"user,omitempty"`
-	Author        AuthorRequest `json:"author,omitempty"`
+	ID            int             `json:"id,omitempty" form:"id"`
+	Name          string          `json:"name,omitempty" form:"name"`
+	DatePublished string          `json:"date_published,omitempty" form:"date_published"`
+	ISBN          string          `json:"isbn,omitempty" form:"isbn"`
+	PageCount     int             `json:"page_count,omitempty" form:"page_count"`
+	UserID        models.User     `json:"user,omitempty"`
+	Authors       []AuthorRequest `json:"authors,omitempty"`
+	// Version is the version the client last saw, 0 skips the concurrency check.
+	Version int `json:"-"`
 }
 
 // BookResponse represents the response body for retrieving book information.
 type BookResponse struct {
-	ID            int            `json:"id,omitempty" form:"id"`
-	Name          string         `json:"name,omitempty" form:"name"`
-	DatePublished string         `json:"date_published,omitempty" form:"date_published"`
-	ISBN          string         `json:"isbn,omitempty" form:"isbn"`
-	PageCount     int            `json:"page_count,omitempty" form:"page_count"`
-	UserID        models.User    `json:"user,omitempty"`
-	Author        AuthorResponse `json:"author,omitempty"`
+	ID            int              `json:"id,omitempty" form:"id"`
+	Name          string           `json:"name,omitempty" form:"name"`
+	DatePublished string           `json:"date_published,omitempty" form:"date_published"`
+	ISBN          string           `json:"isbn,omitempty" form:"isbn"`
+	PageCount     int              `json:"page_count,omitempty" form:"page_count"`
+	UserID        models.User      `json:"user,omitempty"`
+	Authors       []AuthorResponse `json:"authors,omitempty"`
+	Version       int              `json:"version,omitempty"`
+	DeletedAt     *time.Time       `json:"deleted_at,omitempty"`
+	Cover         Cover            `json:"cover,omitempty"`
+}
+
+// BookFilter represents the query parameters for listing books.
+type BookFilter struct {
+	Cursor        string `form:"cursor"`
+	Limit         int    `form:"limit"`
+	Author        string `form:"author"`
+	ISBNPrefix    string `form:"isbn_prefix"`
+	PublishedFrom string `form:"published_from"`
+	PublishedTo   string `form:"published_to"`
+	MinPages      int    `form:"min_pages"`
+	MaxPages      int    `form:"max_pages"`
+	Sort          string `form:"sort"`
+	Order         string `form:"order"`
+	Status        string `form:"status"`
+}
+
+// BookListResponse represents a single page of books.
+type BookListResponse struct {
+	Books      []BookResponse `json:"books"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	Total      int            `json:"total"`
+}
+
+// SearchRequest represents the query parameters of the full-text book search.
+type SearchRequest struct {
+	Query string `form:"q"`
+	Limit int    `form:"limit"`
+}
+
+// SearchResult represents a single match of the full-text book search.
+type SearchResult struct {
+	Source    string  `json:"source"`
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	ISBN      string  `json:"isbn,omitempty"`
+	Authors   string  `json:"authors,omitempty"`
+	Rank      float64 `json:"rank"`
+	Highlight string  `json:"highlight"`
 }
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Cover maps the rendition names of a book cover, such as original or small, to the
+// URLs they are downloaded from.
+type Cover map[string]string
+
+// Scan reads the cover stored as JSON, a NULL cover is nil.
+func (c *Cover) Scan(src interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, c)
+	case string:
+		return json.Unmarshal([]byte(data), c)
+	default:
+		return fmt.Errorf("cannot scan %T into a cover", src)
+	}
+}
+
+func (c Cover) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(c)
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// SortKeys lists the fields a book listing can be sorted by.
+var SortKeys = []string{"id", "name", "date_published", "page_count"}
+
+// Validate fills in the listing defaults and checks the filter values.
+func (f *BookFilter) Validate() error {
+	if f.Limit == 0 {
+		f.Limit = DefaultPageSize
+	}
+
+	if f.Limit < 0 || f.Limit > MaxPageSize {
+		return fmt.Errorf("limit must be between 1 and %d", MaxPageSize)
+	}
+
+	if f.Sort == "" {
+		f.Sort = "id"
+	}
+
+	if !isSortKey(f.Sort) {
+		return fmt.Errorf("unsupported sort key: %s", f.Sort)
+	}
+
+	if f.Order == "" {
+		f.Order = "asc"
+	}
+
+	if f.Order != "asc" && f.Order != "desc" {
+		return errors.New("order must be either asc or desc")
+	}
+
+	for _, date := range []string{f.PublishedFrom, f.PublishedTo} {
+		if date == "" {
+			continue
+		}
+
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return fmt.Errorf("invalid published date: %s", date)
+		}
+	}
+
+	if f.MinPages < 0 || f.MaxPages < 0 {
+		return errors.New("page count filters cannot be negative")
+	}
+
+	if f.MaxPages != 0 && f.MinPages > f.MaxPages {
+		return errors.New("min_pages cannot be greater than max_pages")
+	}
+
+	if f.Status != "" && !IsReadingStatus(f.Status) {
+		return fmt.Errorf("unsupported reading status: %s", f.Status)
+	}
+
+	return nil
+}
+
+func isSortKey(key string) bool {
+	for _, sortKey := range SortKeys {
+		if sortKey == key {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"reflect"
+	"time"
+)
+
+// Actions recorded in the history of a personal book.
+const (
+	HistoryActionCreate  = "create"
+	HistoryActionUpdate  = "update"
+	HistoryActionDelete  = "delete"
+	HistoryActionRestore = "restore"
+	HistoryActionRevert  = "revert"
+)
+
+// BookSnapshot is the state of a personal book before or after a change.
+type BookSnapshot struct {
+	Name          string           `json:"name"`
+	DatePublished string           `json:"date_published"`
+	ISBN          string           `json:"isbn"`
+	PageCount     int              `json:"page_count"`
+	Authors       []AuthorResponse `json:"authors"`
+}
+
+// FieldChange holds the value of a book field before and after a change.
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// BookRevision represents a single entry of the change history of a personal book.
+type BookRevision struct {
+	ID        int                    `json:"id"`
+	BookID    int                    `json:"book_id"`
+	Version   int                    `json:"version"`
+	Action    string                 `json:"action"`
+	ActorID   int                    `json:"actor_id,omitempty"`
+	ChangedAt time.Time              `json:"changed_at"`
+	Before    *BookSnapshot          `json:"before,omitempty"`
+	After     *BookSnapshot          `json:"after,omitempty"`
+	Changes   map[string]FieldChange `json:"changes"`
+}
+
+// DiffSnapshots returns the fields that differ between two snapshots, either of
+// which may be nil when the book did not exist before or after the change.
+func DiffSnapshots(before, after *BookSnapshot) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+
+	old, current := before.fields(), after.fields()
+	for name := range current {
+		if !reflect.DeepEqual(old[name], current[name]) {
+			changes[name] = FieldChange{Before: old[name], After: current[name]}
+		}
+	}
+
+	return changes
+}
+
+func (s *BookSnapshot) fields() map[string]interface{} {
+	if s == nil {
+		return map[string]interface{}{
+			"name":           nil,
+			"date_published": nil,
+			"isbn":           nil,
+			"page_count":     nil,
+			"authors":        nil,
+		}
+	}
+
+	return map[string]interface{}{
+		"name":           s.Name,
+		"date_published": s.DatePublished,
+		"isbn":           s.ISBN,
+		"page_count":     s.PageCount,
+		"authors":        s.Authors,
+	}
+}
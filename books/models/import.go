@@ -0,0 +1,72 @@
+package models
+
+const (
+	ImportModeAtomic     = "atomic"
+	ImportModeBestEffort = "best_effort"
+
+	ImportStatusCreated = "created"
+	ImportStatusSkipped = "skipped"
+	ImportStatusFailed  = "failed"
+)
+
+// ImportRow represents a single parsed record of a bulk import.
+// Error is set when the record could not be parsed into a book.
+type ImportRow struct {
+	Row   int
+	Book  BookRequest
+	Error string
+}
+
+// ImportRowResult represents the outcome of importing a single record.
+type ImportRowResult struct {
+	Row    int    `json:"row"`
+	Status string `json:"status"`
+	BookID int    `json:"book_id,omitempty"`
+	ISBN   string `json:"isbn,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ImportReport represents the response body of a bulk import.
+type ImportReport struct {
+	Mode       string            `json:"mode"`
+	Created    int               `json:"created"`
+	Skipped    int               `json:"skipped"`
+	Failed     int               `json:"failed"`
+	RolledBack bool              `json:"rolled_back"`
+	Rows       []ImportRowResult `json:"rows"`
+}
+
+// Add records the outcome of a single record and updates the totals.
+func (r *ImportReport) Add(row ImportRow, status, reason string, bookID int) {
+	switch status {
+	case ImportStatusCreated:
+		r.Created++
+	case ImportStatusSkipped:
+		r.Skipped++
+	case ImportStatusFailed:
+		r.Failed++
+	}
+
+	r.Rows = append(r.Rows, ImportRowResult{
+		Row:    row.Row,
+		Status: status,
+		BookID: bookID,
+		ISBN:   row.Book.ISBN,
+		Reason: reason,
+	})
+}
+
+// RollBack marks every created record as skipped once the import transaction has been rolled back.
+func (r *ImportReport) RollBack(reason string) {
+	for i := range r.Rows {
+		if r.Rows[i].Status == ImportStatusCreated {
+			r.Rows[i].Status = ImportStatusSkipped
+			r.Rows[i].BookID = 0
+			r.Rows[i].Reason = reason
+			r.Created--
+			r.Skipped++
+		}
+	}
+
+	r.RolledBack = true
+}
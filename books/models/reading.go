@@ -0,0 +1,88 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Reading statuses of a personal book.
+const (
+	ReadingStatusWantToRead = "want_to_read"
+	ReadingStatusReading    = "reading"
+	ReadingStatusFinished   = "finished"
+	ReadingStatusAbandoned  = "abandoned"
+)
+
+var ReadingStatuses = []string{ReadingStatusWantToRead, ReadingStatusReading, ReadingStatusFinished, ReadingStatusAbandoned}
+
+// ReadingSession represents one read of a personal book, a re-read starts a new session.
+type ReadingSession struct {
+	ID          int       `json:"id"`
+	Status      string    `json:"status"`
+	CurrentPage int       `json:"current_page"`
+	StartedAt   string    `json:"started_at,omitempty"`
+	FinishedAt  string    `json:"finished_at,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ReadingResponse represents the reading state of a personal book.
+type ReadingResponse struct {
+	Current   *ReadingSession  `json:"current"`
+	Sessions  []ReadingSession `json:"sessions"`
+	ReadCount int              `json:"read_count"`
+}
+
+// ReadingProgressRequest represents the request body for updating the reading progress.
+// Fields left out keep their current value.
+type ReadingProgressRequest struct {
+	Status      string `json:"status,omitempty"`
+	CurrentPage *int   `json:"current_page,omitempty"`
+	StartedAt   string `json:"started_at,omitempty"`
+	FinishedAt  string `json:"finished_at,omitempty"`
+}
+
+// Validate checks the values that do not depend on the stored reading session.
+func (r *ReadingProgressRequest) Validate() error {
+	if r.Status != "" && !IsReadingStatus(r.Status) {
+		return fmt.Errorf("status must be one of: %s", strings.Join(ReadingStatuses, ", "))
+	}
+
+	if r.CurrentPage != nil && *r.CurrentPage < 0 {
+		return errors.New("current page cannot be negative")
+	}
+
+	today := time.Now().Format(time.DateOnly)
+	for _, date := range []string{r.StartedAt, r.FinishedAt} {
+		if date == "" {
+			continue
+		}
+
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return fmt.Errorf("invalid reading date: %s", date)
+		}
+
+		if date > today {
+			return fmt.Errorf("reading date cannot be in the future: %s", date)
+		}
+	}
+
+	return nil
+}
+
+// IsReadingStatus tells whether the status is one of the known reading statuses.
+func IsReadingStatus(status string) bool {
+	for _, readingStatus := range ReadingStatuses {
+		if readingStatus == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Done tells whether the session is over, so that reading the book again starts a new one.
+func (s *ReadingSession) Done() bool {
+	return s.Status == ReadingStatusFinished || s.Status == ReadingStatusAbandoned
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const maxShelfNameLength = 100
+
+// Shelf represents a named collection of a user's books.
+type Shelf struct {
+	ID          int       `json:"id"`
+	UserID      int       `json:"user_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Public      bool      `json:"public"`
+	BookCount   int       `json:"book_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ShelfRequest represents the request body for creating or updating a shelf.
+type ShelfRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Public      bool   `json:"public"`
+}
+
+// ShelfResponse represents a shelf with its books in shelf order.
+type ShelfResponse struct {
+	Shelf
+	Books []BookResponse `json:"books"`
+}
+
+// ShelfBookRequest represents the request body for putting a book on a shelf.
+type ShelfBookRequest struct {
+	BookID int `json:"book_id"`
+	// Position is 1-based, 0 puts the book at the end of the shelf.
+	Position int `json:"position,omitempty"`
+}
+
+// ShelfOrderRequest represents the new order of every book on a shelf.
+type ShelfOrderRequest struct {
+	BookIDs []int `json:"book_ids"`
+}
+
+// Validate trims the shelf name and checks its length.
+func (s *ShelfRequest) Validate() error {
+	s.Name = strings.TrimSpace(s.Name)
+	s.Description = strings.TrimSpace(s.Description)
+
+	if s.Name == "" {
+		return errors.New("shelf name is required")
+	}
+
+	if len(s.Name) > maxShelfNameLength {
+		return fmt.Errorf("shelf name cannot be longer than %d characters", maxShelfNameLength)
+	}
+
+	return nil
+}
+
+// Validate checks that the book ID and position can be used.
+func (s *ShelfBookRequest) Validate() error {
+	if s.BookID < 1 {
+		return errors.New("book_id is required")
+	}
+
+	if s.Position < 0 {
+		return errors.New("position cannot be negative")
+	}
+
+	return nil
+}
+
+// Validate checks that every book appears once in the new order.
+func (s *ShelfOrderRequest) Validate() error {
+	seen := make(map[int]bool, len(s.BookIDs))
+	for _, id := range s.BookIDs {
+		if seen[id] {
+			return fmt.Errorf("book %d appears more than once", id)
+		}
+
+		seen[id] = true
+	}
+
+	return nil
+}
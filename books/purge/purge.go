@@ -0,0 +1,78 @@
+// Package purge permanently deletes the books that stayed in the trash longer than the retention period.
+package purge
+
+import (
+	"context"
+	"library/books/repository"
+	"library/pkg/storage"
+	"library/pkg/utils"
+	"time"
+)
+
+type Purger struct {
+	ctx            context.Context
+	bookRepository repository.BookerRepository
+	coverStorage   storage.Storage
+	retention      time.Duration
+	interval       time.Duration
+}
+
+func NewPurger(ctx context.Context, booker repository.BookerRepository, coverStorage storage.Storage, retention, interval time.Duration) *Purger {
+	return &Purger{
+		ctx:            ctx,
+		bookRepository: booker,
+		coverStorage:   coverStorage,
+		retention:      retention,
+		interval:       interval,
+	}
+}
+
+// Run purges the trash right away and then once every interval until the context is done.
+// An interval that is not positive disables the purge.
+func (p *Purger) Run() {
+	log := utils.GetLogger(p.ctx)
+
+	if p.interval <= 0 {
+		log.Warningf("Trash purge disabled, the interval %v is not positive", p.interval)
+		return
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Purge(); err != nil {
+			log.Errorf("Trash purge failed: %v", err)
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge deletes the books moved to the trash more than the retention period ago, along
+// with their stored covers.
+func (p *Purger) Purge() (int64, error) {
+	log := utils.GetLogger(p.ctx)
+
+	purged, keys, err := p.bookRepository.PurgeTrash(time.Now().Add(-p.retention))
+	if err != nil {
+		return 0, err
+	}
+
+	// the books are already gone, a cover that fails to delete is only left behind in the storage
+	for _, key := range keys {
+		if err := p.coverStorage.Delete(p.ctx, key); err != nil {
+			log.Errorf("Failed to delete cover %s of a purged book: %v", key, err)
+		}
+	}
+
+	if purged > 0 {
+		log.Infof("Purged %d books from the trash", purged)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,13 @@
+package purge_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestPurge(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Purge Suite")
+}
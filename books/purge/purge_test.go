@@ -0,0 +1,83 @@
+package purge_test
+
+import (
+	"context"
+	"errors"
+	"library/books/purge"
+	"library/books/repository/repositoryfakes"
+	"library/pkg/logger"
+	"library/pkg/storage/storagefakes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Purger", func() {
+	var (
+		ctx         context.Context
+		cancel      context.CancelFunc
+		fakeBooker  *repositoryfakes.FakeBookerRepository
+		fakeStorage *storagefakes.FakeStorage
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.WithValue(context.Background(), "logger", logger.NewLogger(2)))
+		DeferCleanup(cancel)
+
+		fakeBooker = &repositoryfakes.FakeBookerRepository{}
+		fakeStorage = &storagefakes.FakeStorage{}
+	})
+
+	It("should purge the books deleted before the retention period", func() {
+		fakeBooker.PurgeTrashReturns(3, nil, nil)
+
+		start := time.Now()
+		purged, err := purge.NewPurger(ctx, fakeBooker, fakeStorage, 30*24*time.Hour, time.Hour).Purge()
+
+		Expect(err).To(BeNil())
+		Expect(purged).To(Equal(int64(3)))
+		Expect(fakeBooker.PurgeTrashArgsForCall(0)).To(BeTemporally("~", start.Add(-30*24*time.Hour), time.Second))
+		Expect(fakeStorage.DeleteCallCount()).To(Equal(0))
+	})
+
+	It("should delete the covers of the purged books", func() {
+		fakeBooker.PurgeTrashReturns(2, []string{"7/cd/original.jpg", "7/cd/small.jpg", "9/ef/original.png"}, nil)
+		fakeStorage.DeleteReturnsOnCall(0, errors.New("access denied"))
+
+		purged, err := purge.NewPurger(ctx, fakeBooker, fakeStorage, time.Hour, time.Hour).Purge()
+
+		Expect(err).To(BeNil())
+		Expect(purged).To(Equal(int64(2)))
+		Expect(fakeStorage.DeleteCallCount()).To(Equal(3))
+
+		_, key := fakeStorage.DeleteArgsForCall(2)
+		Expect(key).To(Equal("9/ef/original.png"))
+	})
+
+	It("should keep running after a failed purge until the context is done", func() {
+		fakeBooker.PurgeTrashReturns(0, nil, errors.New("connection refused"))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			purge.NewPurger(ctx, fakeBooker, fakeStorage, time.Hour, 10*time.Millisecond).Run()
+		}()
+
+		Eventually(fakeBooker.PurgeTrashCallCount).Should(BeNumerically(">=", 2))
+
+		cancel()
+		Eventually(done).Should(BeClosed())
+	})
+
+	It("should not run without a positive interval", func() {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			purge.NewPurger(ctx, fakeBooker, fakeStorage, time.Hour, 0).Run()
+		}()
+
+		Eventually(done).Should(BeClosed())
+		Expect(fakeBooker.PurgeTrashCallCount()).To(Equal(0))
+	})
+})
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"library/books/models"
+	"library/pkg/postgres"
+	"library/pkg/utils"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrAuthorNotFound  = errors.New("author not found")
+	ErrAuthorNameTaken = errors.New("another author already has this name, merge the authors instead")
+	ErrInvalidMerge    = errors.New("duplicate ids must be existing authors other than the one merged into")
+)
+
+type AuthorerRepository interface {
+	ListAuthors(filter *models.AuthorFilter) (*models.AuthorListResponse, error)
+	GetAuthor(id int) (*models.Author, error)
+	UpdateAuthor(id int, update *models.AuthorUpdate) (*models.Author, error)
+	SuggestDuplicates(id, limit int) ([]models.AuthorSuggestion, error)
+	MergeAuthors(authorID int, duplicateIDs []int) (*models.MergeAuthorsResponse, error)
+}
+
+type AuthorRepository struct {
+	ctx context.Context
+	DB  postgres.DB
+}
+
+func NewAuthorRepository(ctx context.Context, db postgres.DB) AuthorerRepository {
+	return &AuthorRepository{
+		ctx: ctx,
+		DB:  db,
+	}
+}
+
+func (a *AuthorRepository) ListAuthors(filter *models.AuthorFilter) (*models.AuthorListResponse, error) {
+	authorList := &models.AuthorListResponse{Authors: []models.Author{}}
+
+	log := utils.GetLogger(a.ctx)
+
+	pattern := "%" + escapeLike(filter.Query) + "%"
+
+	err := a.DB.DB.QueryRow(CountAuthors, pattern).Scan(&authorList.Total)
+	if err != nil {
+		log.Errorf("Failed to count authors: %v", err)
+		return authorList, err
+	}
+
+	rows, err := a.DB.DB.Query(ListAuthors, pattern, filter.Limit, filter.Offset)
+	if err != nil {
+		log.Errorf("Failed to perform a query on author table: %v", err)
+		return authorList, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var author models.Author
+
+		if err = rows.Scan(&author.ID, &author.Name, &author.Biography, &author.BookCount); err != nil {
+			log.Errorf("Failed to scan rows: %v", err)
+			return authorList, err
+		}
+
+		authorList.Authors = append(authorList.Authors, author)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Errorf("Query failed: %v", err)
+		return authorList, err
+	}
+
+	return authorList, nil
+}
+
+func (a *AuthorRepository) GetAuthor(id int) (*models.Author, error) {
+	author := &models.Author{}
+
+	log := utils.GetLogger(a.ctx)
+
+	err := a.DB.DB.QueryRow(GetAuthor, id).Scan(&author.ID, &author.Name, &author.Biography, &author.BookCount)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("Author not found: %v", id)
+		return author, ErrAuthorNotFound
+	}
+
+	if err != nil {
+		log.Errorf("Query row on author table failed: %v", err)
+		return author, err
+	}
+
+	return author, nil
+}
+
+func (a *AuthorRepository) UpdateAuthor(id int, update *models.AuthorUpdate) (*models.Author, error) {
+	log := utils.GetLogger(a.ctx)
+
+	author, err := a.GetAuthor(id)
+	if err != nil {
+		return author, err
+	}
+
+	var taken bool
+	if err = a.DB.DB.QueryRow(AuthorNameTaken, update.Name, id).Scan(&taken); err != nil {
+		log.Errorf("Failed to check author name: %v", err)
+		return author, err
+	}
+
+	if taken {
+		log.Warningf("Author name already taken: %v", update.Name)
+		return author, ErrAuthorNameTaken
+	}
+
+	author.Name = update.Name
+	if update.Biography != nil {
+		author.Biography = *update.Biography
+	}
+
+	_, err = a.DB.DB.Exec(UpdateAuthor, author.Name, author.Biography, id)
+	if err != nil {
+		log.Errorf("Failed to perform an update query on author table: %v", err)
+		return author, err
+	}
+
+	return author, nil
+}
+
+func (a *AuthorRepository) SuggestDuplicates(id, limit int) ([]models.AuthorSuggestion, error) {
+	suggestions := []models.AuthorSuggestion{}
+
+	log := utils.GetLogger(a.ctx)
+
+	if _, err := a.GetAuthor(id); err != nil {
+		return suggestions, err
+	}
+
+	rows, err := a.DB.DB.Query(SuggestDuplicateAuthors, id, limit)
+	if err != nil {
+		log.Errorf("Failed to look for duplicate authors: %v", err)
+		return suggestions, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var suggestion models.AuthorSuggestion
+
+		if err = rows.Scan(&suggestion.ID, &suggestion.Name, &suggestion.Similarity); err != nil {
+			log.Errorf("Failed to scan rows: %v", err)
+			return suggestions, err
+		}
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Errorf("Query failed: %v", err)
+		return suggestions, err
+	}
+
+	return suggestions, nil
+}
+
+// MergeAuthors moves every personal and shop book credit of the duplicates to the
+// author and deletes the duplicates, all in one transaction.
+func (a *AuthorRepository) MergeAuthors(authorID int, duplicateIDs []int) (*models.MergeAuthorsResponse, error) {
+	merge := &models.MergeAuthorsResponse{AuthorID: authorID, MergedIDs: duplicateIDs}
+
+	log := utils.GetLogger(a.ctx)
+
+	duplicates := make([]int64, 0, len(duplicateIDs))
+	seen := map[int]bool{authorID: true}
+	for _, id := range duplicateIDs {
+		if seen[id] {
+			return merge, ErrInvalidMerge
+		}
+
+		seen[id] = true
+		duplicates = append(duplicates, int64(id))
+	}
+
+	if len(duplicates) == 0 {
+		return merge, ErrInvalidMerge
+	}
+
+	tx, err := a.DB.DB.Begin()
+	if err != nil {
+		log.Errorf("Failed to begin transaction: %v", err)
+		return merge, err
+	}
+	defer tx.Rollback()
+
+	locked, err := lockAuthors(tx, append([]int64{int64(authorID)}, duplicates...))
+	if err != nil {
+		log.Errorf("Failed to lock authors: %v", err)
+		return merge, err
+	}
+
+	if locked != len(duplicates)+1 {
+		log.Warningf("Cannot merge %v into %v, some authors do not exist", duplicateIDs, authorID)
+		return merge, ErrInvalidMerge
+	}
+
+	steps := []struct {
+		query  string
+		result *int64
+	}{
+		{query: DropMergedUserBookCredits},
+		{query: MoveUserBookCredits, result: &merge.UserBookCredits},
+		{query: DropMergedShopCredits},
+		{query: MoveShopCredits, result: &merge.ShopBookCredits},
+		{query: MergeBiography},
+	}
+
+	for _, step := range steps {
+		result, err := tx.Exec(step.query, authorID, pq.Array(duplicates))
+		if err != nil {
+			log.Errorf("Failed to merge authors: %v", err)
+			return merge, err
+		}
+
+		if step.result != nil {
+			if *step.result, err = result.RowsAffected(); err != nil {
+				log.Errorf("Error number of rows affected: %v", err)
+				return merge, err
+			}
+		}
+	}
+
+	if _, err = tx.Exec(DeleteAuthors, pq.Array(duplicates)); err != nil {
+		log.Errorf("Failed to delete merged authors: %v", err)
+		return merge, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Errorf("Failed to commit transaction: %v", err)
+		return merge, err
+	}
+
+	log.Infof("Merged authors %v into %v", duplicateIDs, authorID)
+
+	return merge, nil
+}
+
+func lockAuthors(tx *sql.Tx, ids []int64) (int, error) {
+	rows, err := tx.Query(LockAuthors, pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	locked := 0
+	for rows.Next() {
+		locked++
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to lock authors: %w", err)
+	}
+
+	return locked, nil
+}
@@ -0,0 +1,191 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"library/books/models"
+	"library/books/repository"
+	"library/pkg/logger"
+	"library/pkg/postgres"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Author Repository Test", func() {
+	var (
+		authorRepo repository.AuthorerRepository
+		mock       sqlmock.Sqlmock
+		fakeDB     *postgres.DB
+		authorRow  func(id int, name string) *sqlmock.Rows
+	)
+
+	JustBeforeEach(func() {
+		ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+		fakeDB, _ = postgres.NewFakeDB(ctx)
+		authorRepo = repository.NewAuthorRepository(ctx, *fakeDB)
+		mock = fakeDB.GetMock()
+
+		authorRow = func(id int, name string) *sqlmock.Rows {
+			return sqlmock.NewRows([]string{"id", "name", "biography", "book_count"}).AddRow(id, name, "", 2)
+		}
+	})
+
+	AfterEach(func() {
+		fakeDB.Close()
+	})
+
+	Describe("ListAuthors", func() {
+		It("should escape the search pattern", func() {
+			mock.ExpectQuery(repository.CountAuthors).
+				WithArgs("%100\\%%").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+			mock.ExpectQuery(repository.ListAuthors).
+				WithArgs("%100\\%%", 20, 0).
+				WillReturnRows(authorRow(1, "100% Tolkien"))
+
+			authors, err := authorRepo.ListAuthors(&models.AuthorFilter{Query: "100%", Limit: 20})
+
+			Expect(err).To(BeNil())
+			Expect(authors.Total).To(Equal(1))
+			Expect(authors.Authors).To(HaveLen(1))
+			Expect(authors.Authors[0].BookCount).To(Equal(2))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+
+	Describe("GetAuthor", func() {
+		It("should report a missing author", func() {
+			mock.ExpectQuery(repository.GetAuthor).
+				WithArgs(42).
+				WillReturnError(sql.ErrNoRows)
+
+			_, err := authorRepo.GetAuthor(42)
+
+			Expect(err).To(MatchError(repository.ErrAuthorNotFound))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+
+	Describe("UpdateAuthor", func() {
+		It("should refuse a name used by another author", func() {
+			mock.ExpectQuery(repository.GetAuthor).
+				WithArgs(1).
+				WillReturnRows(authorRow(1, "J. R. R. Tolkien"))
+
+			mock.ExpectQuery(repository.AuthorNameTaken).
+				WithArgs("J.R.R. Tolkien", 1).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+			_, err := authorRepo.UpdateAuthor(1, &models.AuthorUpdate{Name: "J.R.R. Tolkien"})
+
+			Expect(err).To(MatchError(repository.ErrAuthorNameTaken))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("should keep the biography when it is not sent", func() {
+			mock.ExpectQuery(repository.GetAuthor).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "biography", "book_count"}).AddRow(1, "Tolkien", "Philologist.", 2))
+
+			mock.ExpectQuery(repository.AuthorNameTaken).
+				WithArgs("J. R. R. Tolkien", 1).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+			mock.ExpectExec(repository.UpdateAuthor).
+				WithArgs("J. R. R. Tolkien", "Philologist.", 1).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			author, err := authorRepo.UpdateAuthor(1, &models.AuthorUpdate{Name: "J. R. R. Tolkien"})
+
+			Expect(err).To(BeNil())
+			Expect(author.Name).To(Equal("J. R. R. Tolkien"))
+			Expect(author.Biography).To(Equal("Philologist."))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+
+	Describe("SuggestDuplicates", func() {
+		It("should return similar authors", func() {
+			mock.ExpectQuery(repository.GetAuthor).
+				WithArgs(1).
+				WillReturnRows(authorRow(1, "J. R. R. Tolkien"))
+
+			mock.ExpectQuery(repository.SuggestDuplicateAuthors).
+				WithArgs(1, 10).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "score"}).AddRow(2, "J.R.R. Tolkien", 0.8))
+
+			suggestions, err := authorRepo.SuggestDuplicates(1, 10)
+
+			Expect(err).To(BeNil())
+			Expect(suggestions).To(Equal([]models.AuthorSuggestion{{ID: 2, Name: "J.R.R. Tolkien", Similarity: 0.8}}))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+
+	Describe("MergeAuthors", func() {
+		It("should move the credits and delete the duplicates", func() {
+			duplicates := pq.Array([]int64{2, 3})
+
+			mock.ExpectBegin()
+
+			mock.ExpectQuery(repository.LockAuthors).
+				WithArgs(pq.Array([]int64{1, 2, 3})).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3))
+
+			mock.ExpectExec(repository.DropMergedUserBookCredits).
+				WithArgs(1, duplicates).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(repository.MoveUserBookCredits).
+				WithArgs(1, duplicates).
+				WillReturnResult(sqlmock.NewResult(0, 4))
+			mock.ExpectExec(repository.DropMergedShopCredits).
+				WithArgs(1, duplicates).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(repository.MoveShopCredits).
+				WithArgs(1, duplicates).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectExec(repository.MergeBiography).
+				WithArgs(1, duplicates).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(repository.DeleteAuthors).
+				WithArgs(duplicates).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+
+			mock.ExpectCommit()
+
+			merge, err := authorRepo.MergeAuthors(1, []int{2, 3})
+
+			Expect(err).To(BeNil())
+			Expect(merge.UserBookCredits).To(Equal(int64(4)))
+			Expect(merge.ShopBookCredits).To(Equal(int64(2)))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("should roll back when a duplicate does not exist", func() {
+			mock.ExpectBegin()
+
+			mock.ExpectQuery(repository.LockAuthors).
+				WithArgs(pq.Array([]int64{1, 9})).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+			mock.ExpectRollback()
+
+			_, err := authorRepo.MergeAuthors(1, []int{9})
+
+			Expect(err).To(MatchError(repository.ErrInvalidMerge))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("should refuse to merge an author into itself", func() {
+			_, err := authorRepo.MergeAuthors(1, []int{1})
+
+			Expect(err).To(MatchError(repository.ErrInvalidMerge))
+		})
+	})
+})
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"library/books/models"
+	"library/pkg/logger"
+	"library/pkg/postgres"
+
+	"github.com/lib/pq"
+)
+
+// insertBookAuthors stores the contributors of a book in the order they were given.
+func insertBookAuthors(log logger.Logger, bookID int, authors []models.AuthorRequest, db postgres.Queryer) ([]models.AuthorResponse, error) {
+	bookAuthors := make([]models.AuthorResponse, 0, len(authors))
+
+	for i, requested := range authors {
+		author, err := getOrCreateAuthor(log, requested.Name, db)
+		if err != nil {
+			log.Errorf("Failed to get or create author: %v", err)
+			return bookAuthors, err
+		}
+
+		_, err = db.Exec(InsertBookAuthor, bookID, author.ID, requested.Role, i+1)
+		if err != nil {
+			log.Errorf("Failed to perform an insert query on user book authors table: %v", err)
+			return bookAuthors, err
+		}
+
+		author.Role = requested.Role
+		bookAuthors = append(bookAuthors, author)
+	}
+
+	return bookAuthors, nil
+}
+
+// loadBookAuthors fills in the contributors of the given books with a single query.
+func loadBookAuthors(log logger.Logger, books []models.BookResponse, db postgres.Queryer) error {
+	if len(books) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(books))
+	positions := make(map[int]int, len(books))
+	for i, book := range books {
+		ids[i] = int64(book.ID)
+		positions[book.ID] = i
+	}
+
+	rows, err := db.Query(GetBookAuthors, pq.Array(ids))
+	if err != nil {
+		log.Errorf("Failed to perform a query on user book authors table: %v", err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bookID int
+		var author models.AuthorResponse
+
+		if err = rows.Scan(&bookID, &author.ID, &author.Name, &author.Role); err != nil {
+			log.Errorf("Failed to scan rows: %v", err)
+			return err
+		}
+
+		book := &books[positions[bookID]]
+		book.Authors = append(book.Authors, author)
+	}
+
+	return rows.Err()
+}
@@ -6,17 +6,41 @@ import (
 	"errors"
 	"fmt"
 	"library/books/models"
+	"library/pkg/authors"
 	"library/pkg/logger"
 	"library/pkg/postgres"
 	"library/pkg/utils"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrVersionMismatch is returned when the book was changed since the version the client read.
+	ErrVersionMismatch = errors.New("book was modified by another request")
+	ErrNotInTrash      = errors.New("book is not in the trash")
+	ErrISBNTaken       = errors.New("another book with this ISBN was added since, delete it before restoring")
 )
 
 type BookerRepository interface {
-	AddBook(book *models.BookRequest) (int, error)
-	UpdateBook(book *models.BookRequest) (*models.BookResponse, error)
-	GetBook(id int) (*models.BookResponse, error)
-	GetAllBooks() ([]models.BookResponse, error)
-	DeleteBook(bookID, userID int) (int, error)
+	AddBook(book *models.BookRequest, actorID int) (int, error)
+	UpdateBook(book *models.BookRequest, actorID int) (*models.BookResponse, error)
+	GetBook(bookID, userID int) (*models.BookResponse, error)
+	ListBooks(userID int, filter *models.BookFilter) (*models.BookListResponse, error)
+	DeleteBook(bookID, userID, actorID int) (int, error)
+	SearchBooks(userID int, search *models.SearchRequest) ([]models.SearchResult, error)
+	ImportBooks(userID, actorID int, rows []models.ImportRow, mode string) (*models.ImportReport, error)
+	ExportBooks(userID, chunkSize int, write func([]models.BookResponse) error) error
+	ListTrash(userID int) ([]models.BookResponse, error)
+	RestoreBook(bookID, userID, actorID int) (int, error)
+	PurgeTrash(before time.Time) (int64, []string, error)
+	ListHistory(bookID, userID int) ([]models.BookRevision, error)
+	RevertBook(bookID, userID, revisionID, actorID int) (*models.BookResponse, error)
+	GetReading(bookID, userID int) (*models.ReadingResponse, error)
+	UpdateReading(bookID, userID int, progress *models.ReadingProgressRequest) (*models.ReadingSession, error)
+	CheckCover(bookID, userID int) error
+	SetCover(bookID, userID int, cover models.Cover, keys []string) ([]string, error)
+	LookupISBN(isbn string) (*models.BookRequest, error)
 }
 
 type BookRepository struct {
@@ -31,31 +55,9 @@ func NewBookRepository(ctx context.Context, db postgres.DB) BookerRepository {
 	}
 }
 
-func (b *BookRepository) GetOrCreateAuthor(authorName string) (models.AuthorResponse, error) {
-	var author models.AuthorResponse
-
-	log := utils.GetLogger(b.ctx)
-
-	err := b.DB.DB.QueryRow(SelectAuthor, authorName).Scan(&author.ID)
-	if err != nil {
-		var lastInsertedID int
-		err = b.DB.DB.QueryRow(InsertAuthor, authorName).Scan(&lastInsertedID)
-		if err != nil {
-			log.Errorf("Failed to perform an insert query on author table: %v", err)
-			return author, err
-		}
-
-		author.ID = lastInsertedID
-	}
-
-	author.Name = authorName
-
-	return author, nil
-}
-
-func (b *BookRepository) AddBook(book *models.BookRequest) (int, error) {
-	var bookResponse models.BookResponse
-
+// AddBook adds the book to the library of book.UserID. The actor is the user making the
+// request, recorded in the history of the book.
+func (b *BookRepository) AddBook(book *models.BookRequest, actorID int) (int, error) {
 	log := utils.GetLogger(b.ctx)
 
 	isExisting, err := validateISBNExists(log, book.ISBN, b.DB.GetDB())
@@ -70,47 +72,32 @@ func (b *BookRepository) AddBook(book *models.BookRequest) (int, error) {
 		return 0, errors.New(errorMessage)
 	}
 
-	author, err := b.GetOrCreateAuthor(book.Author.Name)
+	tx, err := b.DB.DB.Begin()
 	if err != nil {
-		log.Errorf("Failed to get or create author: %v", err)
+		log.Errorf("Failed to begin transaction: %v", err)
 		return 0, err
 	}
 
-	bookResponse = models.BookResponse{
-		Name:          book.Name,
-		DatePublished: book.DatePublished,
-		ISBN:          book.ISBN,
-		PageCount:     book.PageCount,
-		UserID:        book.UserID,
-		Author:        author,
+	bookID, err := insertBook(log, book, actorID, tx)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
 	}
 
-	bookResponse.Author.ID = author.ID
-
-	var lastInsertedID int
-	err = b.DB.DB.QueryRow(
-		InsertBook,
-		bookResponse.Name,
-		bookResponse.DatePublished,
-		bookResponse.ISBN,
-		bookResponse.PageCount,
-		bookResponse.UserID.ID,
-		author.ID,
-	).Scan(&lastInsertedID)
-	if err != nil {
-		log.Errorf("Failed to perform an insert query on user book table: %d", err)
+	if err = tx.Commit(); err != nil {
+		log.Errorf("Failed to commit transaction: %v", err)
 		return 0, err
 	}
 
-	return lastInsertedID, nil
+	return bookID, nil
 }
 
-func (b *BookRepository) UpdateBook(book *models.BookRequest) (*models.BookResponse, error) {
+func (b *BookRepository) UpdateBook(book *models.BookRequest, actorID int) (*models.BookResponse, error) {
 	var bookResponse *models.BookResponse
 
 	log := utils.GetLogger(b.ctx)
 
-	exists, err := postgres.CheckIDExists("books", book.ID, b.DB.GetDB())
+	exists, err := bookExists(log, book.ID, b.DB.GetDB())
 	if err != nil {
 		log.Errorf("Checking book ID error: %v", book.ID)
 		return bookResponse, err
@@ -134,103 +121,178 @@ func (b *BookRepository) UpdateBook(book *models.BookRequest) (*models.BookRespo
 		return bookResponse, errors.New(errorMessage)
 	}
 
-	author, err := b.GetOrCreateAuthor(book.Author.Name)
+	tx, err := b.DB.DB.Begin()
 	if err != nil {
-		log.Errorf("Failed to get or create author: %v", err)
+		log.Errorf("Failed to begin transaction: %v", err)
 		return bookResponse, err
 	}
+	defer tx.Rollback()
 
-	bookResponse = &models.BookResponse{
-		ID:            book.ID,
-		Name:          book.Name,
-		DatePublished: book.DatePublished,
-		ISBN:          book.ISBN,
-		PageCount:     book.PageCount,
-		Author: models.AuthorResponse{
-			ID:   author.ID,
-			Name: book.Author.Name,
-		},
+	before, _, err := loadSnapshot(log, book.ID, tx)
+	if err != nil {
+		return bookResponse, err
 	}
 
-	result, err := b.DB.DB.Exec(
+	var version int
+	err = tx.QueryRow(
 		UpdateBook,
-		bookResponse.Name,
-		bookResponse.DatePublished,
-		bookResponse.ISBN,
-		bookResponse.PageCount,
-		bookResponse.Author.ID,
-		bookResponse.ID,
-	)
+		book.Name,
+		book.DatePublished,
+		book.ISBN,
+		book.PageCount,
+		book.ID,
+		book.Version,
+	).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("Book %v is no longer at version %v", book.ID, book.Version)
+		return bookResponse, ErrVersionMismatch
+	}
+
 	if err != nil {
-		log.Errorf("Failed to perform an update query in user book table: %d", err)
+		log.Errorf("Failed to perform an update query in user book table: %v", err)
+		return bookResponse, err
+	}
+
+	// the contributor list is replaced as a whole so that order and roles follow the request
+	if _, err = tx.Exec(DeleteBookAuthors, book.ID); err != nil {
+		log.Errorf("Failed to perform a delete query on user book authors table: %v", err)
 		return bookResponse, err
 	}
 
-	affectedRows, err := result.RowsAffected()
+	authors, err := insertBookAuthors(log, book.ID, book.Authors, tx)
 	if err != nil {
-		log.Errorf("Error number of rows affected: %v", err)
 		return bookResponse, err
 	}
 
-	if affectedRows == 0 {
-		log.Errorf("No rows were affected: %d", err)
+	err = recordHistory(log, tx, &models.BookRevision{
+		BookID:  book.ID,
+		Version: version,
+		Action:  models.HistoryActionUpdate,
+		ActorID: actorID,
+		Before:  before,
+		After:   snapshotOf(book, authors),
+	})
+	if err != nil {
 		return bookResponse, err
 	}
 
+	if err = tx.Commit(); err != nil {
+		log.Errorf("Failed to commit transaction: %v", err)
+		return bookResponse, err
+	}
+
+	bookResponse = &models.BookResponse{
+		ID:            book.ID,
+		Name:          book.Name,
+		DatePublished: book.DatePublished,
+		ISBN:          book.ISBN,
+		PageCount:     book.PageCount,
+		Authors:       authors,
+		Version:       version,
+	}
+
 	return bookResponse, nil
 }
 
-func (b *BookRepository) GetBook(id int) (*models.BookResponse, error) {
+// GetBook returns the book when it belongs to the user.
+func (b *BookRepository) GetBook(bookID, userID int) (*models.BookResponse, error) {
 	bookResponse := &models.BookResponse{}
 
 	log := utils.GetLogger(b.ctx)
 
-	err := b.DB.DB.QueryRow(GetBook, id).Scan(
+	err := b.DB.DB.QueryRow(GetBook, bookID, userID).Scan(
+		&bookResponse.ID,
 		&bookResponse.Name,
 		&bookResponse.DatePublished,
 		&bookResponse.ISBN,
 		&bookResponse.PageCount,
-		&bookResponse.Author.Name,
+		&bookResponse.Version,
+		&bookResponse.Cover,
 	)
 	if err != nil {
 		log.Errorf("Query row on user book table failed: %v", err)
 		return bookResponse, err
 	}
 
-	return bookResponse, nil
+	books := []models.BookResponse{*bookResponse}
+	if err = loadBookAuthors(log, books, b.DB.GetDB()); err != nil {
+		return bookResponse, err
+	}
+
+	return &books[0], nil
 }
 
-func (b *BookRepository) GetAllBooks() ([]models.BookResponse, error) {
-	var books []models.BookResponse
+func (b *BookRepository) ListBooks(userID int, filter *models.BookFilter) (*models.BookListResponse, error) {
+	bookList := &models.BookListResponse{Books: []models.BookResponse{}}
 
 	log := utils.GetLogger(b.ctx)
 
-	rows, err := b.DB.DB.Query(GetAllBooks)
+	query := newBookQuery(userID, filter)
+
+	err := b.DB.DB.QueryRow(CountBooks+query.whereClause(), query.args...).Scan(&bookList.Total)
 	if err != nil {
-		log.Errorf("Failed to perform a query on user book table: %d", err)
-		return books, err
+		log.Errorf("Failed to count books in user book table: %v", err)
+		return bookList, err
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := decodeCursor(filter)
+		if err != nil {
+			log.Errorf("Failed to decode cursor: %v", err)
+			return bookList, err
+		}
+
+		query.after(filter, cursor)
+	}
+
+	// one extra row tells whether there is a next page
+	limit := query.limit(filter.Limit + 1)
+
+	rows, err := b.DB.DB.Query(ListBooks+query.whereClause()+query.orderBy(filter)+limit, query.args...)
+	if err != nil {
+		log.Errorf("Failed to perform a query on user book table: %v", err)
+		return bookList, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var book models.BookResponse
 
-		err := rows.Scan(&book.Name, &book.DatePublished, &book.ISBN, &book.PageCount, &book.Author.Name)
+		err = rows.Scan(&book.ID, &book.Name, &book.DatePublished, &book.ISBN, &book.PageCount, &book.Cover)
 		if err != nil {
-			log.Errorf("Failed to scan rows: %d", err)
-			return books, err
+			log.Errorf("Failed to scan rows: %v", err)
+			return bookList, err
 		}
 
-		books = append(books, book)
+		bookList.Books = append(bookList.Books, book)
 	}
 
-	return books, nil
+	if err = rows.Err(); err != nil {
+		log.Errorf("Query failed: %v", err)
+		return bookList, err
+	}
+
+	if len(bookList.Books) > filter.Limit {
+		bookList.Books = bookList.Books[:filter.Limit]
+
+		bookList.NextCursor, err = encodeCursor(filter, bookList.Books[filter.Limit-1])
+		if err != nil {
+			log.Errorf("Failed to encode cursor: %v", err)
+			return bookList, err
+		}
+	}
+
+	if err = loadBookAuthors(log, bookList.Books, b.DB.GetDB()); err != nil {
+		return bookList, err
+	}
+
+	return bookList, nil
 }
 
-func (b *BookRepository) DeleteBook(bookID, userID int) (int, error) {
+func (b *BookRepository) DeleteBook(bookID, userID, actorID int) (int, error) {
 	log := utils.GetLogger(b.ctx)
 
-	exists, err := postgres.CheckIDExists("user_book", bookID, b.DB.GetDB())
+	exists, err := bookExists(log, bookID, b.DB.GetDB())
 	if err != nil {
 		log.Errorf("Checking book ID error: %v", bookID)
 		return 0, err
@@ -254,15 +316,238 @@ func (b *BookRepository) DeleteBook(bookID, userID int) (int, error) {
 		return 0, errors.New(errorMessage)
 	}
 
-	_, err = b.DB.DB.Exec(DeleteBook, bookID)
+	tx, err := b.DB.DB.Begin()
+	if err != nil {
+		log.Errorf("Failed to begin transaction: %v", err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	before, version, err := loadSnapshot(log, bookID, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	// the book only moves to the trash, PurgeTrash deletes it once the retention period is over
+	_, err = tx.Exec(DeleteBook, bookID)
 	if err != nil {
 		log.Errorf("Failed to perform delete on a user book table: %d", err)
 		return 0, err
 	}
 
+	err = recordHistory(log, tx, &models.BookRevision{
+		BookID:  bookID,
+		Version: version + 1,
+		Action:  models.HistoryActionDelete,
+		ActorID: actorID,
+		Before:  before,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Errorf("Failed to commit transaction: %v", err)
+		return 0, err
+	}
+
 	return bookID, nil
 }
 
+func (b *BookRepository) ListTrash(userID int) ([]models.BookResponse, error) {
+	books := []models.BookResponse{}
+
+	log := utils.GetLogger(b.ctx)
+
+	rows, err := b.DB.DB.Query(ListTrash, userID)
+	if err != nil {
+		log.Errorf("Failed to perform a query on user book table: %v", err)
+		return books, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var book models.BookResponse
+
+		err = rows.Scan(&book.ID, &book.Name, &book.DatePublished, &book.ISBN, &book.PageCount, &book.DeletedAt)
+		if err != nil {
+			log.Errorf("Failed to scan rows: %v", err)
+			return books, err
+		}
+
+		books = append(books, book)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Errorf("Query failed: %v", err)
+		return books, err
+	}
+
+	if err = loadBookAuthors(log, books, b.DB.GetDB()); err != nil {
+		return books, err
+	}
+
+	return books, nil
+}
+
+func (b *BookRepository) RestoreBook(bookID, userID, actorID int) (int, error) {
+	log := utils.GetLogger(b.ctx)
+
+	tx, err := b.DB.DB.Begin()
+	if err != nil {
+		log.Errorf("Failed to begin transaction: %v", err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(RestoreBook, bookID, userID)
+	if err != nil {
+		log.Errorf("Failed to restore user book: %v", err)
+		return 0, err
+	}
+
+	restored, err := result.RowsAffected()
+	if err != nil {
+		log.Errorf("Error number of rows affected: %v", err)
+		return 0, err
+	}
+
+	if restored == 0 {
+		log.Warningf("Book %v of user %v is not in the trash", bookID, userID)
+		return 0, ErrNotInTrash
+	}
+
+	// the ISBN is only checked against live books, another one may have taken it meanwhile
+	var taken bool
+	if err = tx.QueryRow(CheckRestoredISBN, bookID).Scan(&taken); err != nil {
+		log.Errorf("error validating ISBN of book %v: %v", bookID, err)
+		return 0, err
+	}
+
+	if taken {
+		log.Warningf("ISBN of book %v is taken by another book", bookID)
+		return 0, ErrISBNTaken
+	}
+
+	after, version, err := loadSnapshot(log, bookID, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	err = recordHistory(log, tx, &models.BookRevision{
+		BookID:  bookID,
+		Version: version,
+		Action:  models.HistoryActionRestore,
+		ActorID: actorID,
+		After:   after,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Errorf("Failed to commit transaction: %v", err)
+		return 0, err
+	}
+
+	return bookID, nil
+}
+
+// PurgeTrash permanently deletes the books moved to the trash before the given time. It
+// returns the number of purged books and the storage keys of their covers, so the caller
+// can delete those files.
+func (b *BookRepository) PurgeTrash(before time.Time) (int64, []string, error) {
+	var (
+		purged int64
+		keys   []string
+	)
+
+	log := utils.GetLogger(b.ctx)
+
+	rows, err := b.DB.DB.Query(PurgeTrash, before)
+	if err != nil {
+		log.Errorf("Failed to purge the trash: %v", err)
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var coverKeys []string
+		if err = rows.Scan(pq.Array(&coverKeys)); err != nil {
+			log.Errorf("Failed to scan the purged book: %v", err)
+			return 0, nil, err
+		}
+
+		purged++
+		keys = append(keys, coverKeys...)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Errorf("Failed to purge the trash: %v", err)
+		return 0, nil, err
+	}
+
+	return purged, keys, nil
+}
+
+// insertBook adds the book and its contributors, the caller owns the transaction.
+func insertBook(log logger.Logger, book *models.BookRequest, actorID int, db postgres.Queryer) (int, error) {
+	var bookID int
+	err := db.QueryRow(
+		InsertBook,
+		book.Name,
+		book.DatePublished,
+		book.ISBN,
+		book.PageCount,
+		book.UserID.ID,
+	).Scan(&bookID)
+	if err != nil {
+		log.Errorf("Failed to perform an insert query on user book table: %v", err)
+		return 0, err
+	}
+
+	authors, err := insertBookAuthors(log, bookID, book.Authors, db)
+	if err != nil {
+		return 0, err
+	}
+
+	// new rows start at version 1
+	err = recordHistory(log, db, &models.BookRevision{
+		BookID:  bookID,
+		Version: 1,
+		Action:  models.HistoryActionCreate,
+		ActorID: actorID,
+		After:   snapshotOf(book, authors),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return bookID, nil
+}
+
+// snapshotOf describes the book as it is stored once the request is applied.
+func snapshotOf(book *models.BookRequest, authors []models.AuthorResponse) *models.BookSnapshot {
+	return &models.BookSnapshot{
+		Name:          book.Name,
+		DatePublished: book.DatePublished,
+		ISBN:          book.ISBN,
+		PageCount:     book.PageCount,
+		Authors:       authors,
+	}
+}
+
+func bookExists(log logger.Logger, bookID int, db *sql.DB) (bool, error) {
+	var exists bool
+	err := db.QueryRow(BookExists, bookID).Scan(&exists)
+	if err != nil {
+		log.Errorf("error checking book existence: %v", err)
+		return false, err
+	}
+
+	return exists, nil
+}
+
 func isAssigned(log logger.Logger, bookID, userID int, db *sql.DB) (bool, error) {
 	var exists bool
 	err := db.QueryRow(IsAssigned, bookID, userID).Scan(&exists)
@@ -274,7 +559,20 @@ func isAssigned(log logger.Logger, bookID, userID int, db *sql.DB) (bool, error)
 	return exists, nil
 }
 
-func validateISBNExists(log logger.Logger, isbn string, db *sql.DB) (bool, error) {
+func getOrCreateAuthor(log logger.Logger, authorName string, db postgres.Queryer) (models.AuthorResponse, error) {
+	var author models.AuthorResponse
+	var err error
+
+	author.ID, author.Name, err = authors.GetOrCreate(db, authorName)
+	if err != nil {
+		log.Errorf("Failed to get or create author %q: %v", authorName, err)
+		return author, err
+	}
+
+	return author, nil
+}
+
+func validateISBNExists(log logger.Logger, isbn string, db postgres.Queryer) (bool, error) {
 	var exists bool
 	err := db.QueryRow(CheckISBN, isbn).Scan(&exists)
 	if err != nil {
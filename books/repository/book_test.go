@@ -6,11 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"library/books/models"
 	"library/books/repository"
+	"library/pkg/authors"
 	"library/pkg/logger"
 	"library/pkg/postgres"
 	userModel "library/users/models"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -24,6 +27,8 @@ var _ = Describe("API Test", func() {
 		mock   sqlmock.Sqlmock
 		fakeDB *postgres.DB
 		//err          error
+		expectSnapshot func(bookID, version int)
+		expectHistory  func(bookID, version int, action string, actorID int)
 	)
 
 	JustBeforeEach(func() {
@@ -45,13 +50,30 @@ var _ = Describe("API Test", func() {
 			UserID: userModel.User{
 				ID: 1,
 			},
-			Author: models.AuthorRequest{
-				ID:   1,
-				Name: "tmosto",
+			Authors: []models.AuthorRequest{
+				{ID: 1, Name: "tmosto", Role: models.AuthorRoleAuthor},
 			},
 		}
 
 		mock = fakeDB.GetMock()
+
+		expectSnapshot = func(bookID, version int) {
+			mock.ExpectQuery(repository.GetBookSnapshot).
+				WithArgs(bookID).
+				WillReturnRows(sqlmock.NewRows([]string{"name", "date_published", "isbn", "page_count", "version"}).
+					AddRow("Old Book", "2020-01-01", "9780261102217", 100, version))
+
+			mock.ExpectQuery(repository.GetBookAuthors).
+				WithArgs(pq.Array([]int64{int64(bookID)})).
+				WillReturnRows(sqlmock.NewRows([]string{"user_book_id", "id", "name", "role"}).
+					AddRow(bookID, 1, "Author Name", models.AuthorRoleAuthor))
+		}
+
+		expectHistory = func(bookID, version int, action string, actorID int) {
+			mock.ExpectExec(repository.InsertBookHistory).
+				WithArgs(bookID, version, action, actorID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
 	})
 
 	AfterEach(func() {
@@ -64,21 +86,74 @@ var _ = Describe("API Test", func() {
 				WithArgs(bookRequest.ISBN).
 				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
-			mock.ExpectQuery(repository.SelectAuthor).
-				WithArgs(bookRequest.Author.Name).
+			mock.ExpectBegin()
+
+			mock.ExpectQuery(repository.InsertBook).
+				WithArgs(bookRequest.Name, bookRequest.DatePublished, bookRequest.ISBN, bookRequest.PageCount, bookRequest.UserID.ID).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+			mock.ExpectQuery(authors.SelectAuthor).
+				WithArgs(bookRequest.Authors[0].Name).
 				WillReturnError(sql.ErrNoRows)
 
-			mock.ExpectExec(repository.InsertAuthor).
-				WithArgs(bookRequest.Author.Name).
-				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectQuery(authors.InsertAuthor).
+				WithArgs(bookRequest.Authors[0].Name).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+			mock.ExpectExec(repository.InsertBookAuthor).
+				WithArgs(1, 1, models.AuthorRoleAuthor, 1).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			after := `{"name":"tmosto","date_published":"tmosto","isbn":"1234679","page_count":123,"authors":[{"id":1,"name":"tmosto","role":"author"}]}`
+			changes := `{"authors":{"before":null,"after":[{"id":1,"name":"tmosto","role":"author"}]},"date_published":{"before":null,"after":"tmosto"},"isbn":{"before":null,"after":"1234679"},"name":{"before":null,"after":"tmosto"},"page_count":{"before":null,"after":123}}`
 
-			mock.ExpectExec(repository.InsertBook).
-				WithArgs(bookRequest.Name, bookRequest.DatePublished, bookRequest.ISBN, bookRequest.PageCount, bookRequest.UserID.ID, 1).
-				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectExec(repository.InsertBookHistory).
+				WithArgs(1, 1, models.HistoryActionCreate, 1, nil, []byte(after), []byte(changes)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
 
-			bookID, err := bookRepo.AddBook(bookRequest)
+			mock.ExpectCommit()
+
+			bookID, err := bookRepo.AddBook(bookRequest, 1)
 			Expect(err).To(BeNil())
 			Expect(bookID).To(Equal(1), "Expected AddUser to be called with the correct arguments")
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("should store every contributor with its position and role", func() {
+			bookRequest.Authors = []models.AuthorRequest{
+				{Name: "Terry Pratchett", Role: models.AuthorRoleAuthor},
+				{Name: "Neil Gaiman", Role: models.AuthorRoleAuthor},
+				{Name: "Paul Kidby", Role: models.AuthorRoleIllustrator},
+			}
+
+			mock.ExpectQuery(repository.CheckISBN).
+				WithArgs(bookRequest.ISBN).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+			mock.ExpectBegin()
+
+			mock.ExpectQuery(repository.InsertBook).
+				WithArgs(bookRequest.Name, bookRequest.DatePublished, bookRequest.ISBN, bookRequest.PageCount, bookRequest.UserID.ID).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+			for i, author := range bookRequest.Authors {
+				mock.ExpectQuery(authors.SelectAuthor).
+					WithArgs(author.Name).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(i+10, author.Name))
+
+				mock.ExpectExec(repository.InsertBookAuthor).
+					WithArgs(7, i+10, author.Role, i+1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			expectHistory(7, 1, models.HistoryActionCreate, 1)
+
+			mock.ExpectCommit()
+
+			bookID, err := bookRepo.AddBook(bookRequest, 1)
+			Expect(err).To(BeNil())
+			Expect(bookID).To(Equal(7))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
 		})
 
 		It("should return an error if ISBN already exists", func() {
@@ -86,7 +161,7 @@ var _ = Describe("API Test", func() {
 				WithArgs(bookRequest.ISBN).
 				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 
-			_, err := bookRepo.AddBook(bookRequest)
+			_, err := bookRepo.AddBook(bookRequest, 1)
 
 			Expect(err).NotTo(BeNil())
 			Expect(err).To(MatchError("book with this ISBN already exists"))
@@ -97,11 +172,23 @@ var _ = Describe("API Test", func() {
 				WithArgs(bookRequest.ISBN).
 				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
-			mock.ExpectExec(repository.InsertAuthor).
-				WithArgs(bookRequest.Author.Name).
+			mock.ExpectBegin()
+
+			mock.ExpectQuery(repository.InsertBook).
+				WithArgs(bookRequest.Name, bookRequest.DatePublished, bookRequest.ISBN, bookRequest.PageCount, bookRequest.UserID.ID).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+			mock.ExpectQuery(authors.SelectAuthor).
+				WithArgs(bookRequest.Authors[0].Name).
+				WillReturnError(sql.ErrNoRows)
+
+			mock.ExpectQuery(authors.InsertAuthor).
+				WithArgs(bookRequest.Authors[0].Name).
 				WillReturnError(errors.New("author error"))
 
-			_, err := bookRepo.AddBook(bookRequest)
+			mock.ExpectRollback()
+
+			_, err := bookRepo.AddBook(bookRequest, 1)
 
 			Expect(err).NotTo(BeNil())
 			Expect(err).To(MatchError("author error"))
@@ -112,30 +199,37 @@ var _ = Describe("API Test", func() {
 				WithArgs(bookRequest.ISBN).
 				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
-			mock.ExpectQuery(repository.SelectAuthor).
-				WithArgs(bookRequest.Author.Name).
-				WillReturnError(sql.ErrNoRows)
+			mock.ExpectBegin()
+
+			mock.ExpectQuery(repository.InsertBook).
+				WithArgs(bookRequest.Name, bookRequest.DatePublished, bookRequest.ISBN, bookRequest.PageCount, bookRequest.UserID.ID).
+				WillReturnError(errors.New("insert error"))
 
-			mock.ExpectExec(repository.InsertBook).
-				WithArgs(bookRequest)
+			mock.ExpectRollback()
+
+			_, err := bookRepo.AddBook(bookRequest, 1)
+
+			Expect(err).To(MatchError("insert error"))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
 		})
 
 		Describe("UpdateBook", func() {
 			Context("when book exists and user is assigned", func() {
-				It("should update the book", func() {
+				It("should update the book and replace its contributors", func() {
 					book := &models.BookRequest{
 						ID:            1,
 						Name:          "Updated Book",
 						DatePublished: "2022-01-01",
 						ISBN:          "1234567890",
 						PageCount:     200,
-						Author: models.AuthorRequest{
-							Name: "Author Name",
+						Authors: []models.AuthorRequest{
+							{Name: "Author Name", Role: models.AuthorRoleAuthor},
+							{Name: "Editor Name", Role: models.AuthorRoleEditor},
 						},
 						UserID: userModel.User{ID: 1},
 					}
 
-					query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM books WHERE id=$1)")
+					query := repository.BookExists
 					mock.ExpectQuery(query).
 						WithArgs(book.ID).
 						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
@@ -144,17 +238,132 @@ var _ = Describe("API Test", func() {
 						WithArgs(book.ID, book.UserID.ID).
 						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 
-					mock.ExpectQuery(repository.SelectAuthor).
-						WithArgs(book.Author.Name).
-						WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+					mock.ExpectBegin()
+
+					expectSnapshot(book.ID, 3)
+
+					mock.ExpectQuery(repository.UpdateBook).
+						WithArgs(book.Name, book.DatePublished, book.ISBN, book.PageCount, book.ID, 0).
+						WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
 
-					mock.ExpectExec(repository.UpdateBook).
-						WithArgs(book.Name, book.DatePublished, book.ISBN, book.PageCount, 1, book.ID).
-						WillReturnResult(sqlmock.NewResult(1, 1))
+					mock.ExpectExec(repository.DeleteBookAuthors).
+						WithArgs(book.ID).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+
+					mock.ExpectQuery(authors.SelectAuthor).
+						WithArgs("Author Name").
+						WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Author Name"))
+
+					mock.ExpectExec(repository.InsertBookAuthor).
+						WithArgs(book.ID, 1, models.AuthorRoleAuthor, 1).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+
+					mock.ExpectQuery(authors.SelectAuthor).
+						WithArgs("Editor Name").
+						WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "Editor Name"))
+
+					mock.ExpectExec(repository.InsertBookAuthor).
+						WithArgs(book.ID, 2, models.AuthorRoleEditor, 2).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+
+					expectHistory(book.ID, 4, models.HistoryActionUpdate, book.UserID.ID)
 
-					bookResponse, err := bookRepo.UpdateBook(book)
+					mock.ExpectCommit()
+
+					bookResponse, err := bookRepo.UpdateBook(book, 1)
 					Expect(err).NotTo(HaveOccurred())
-					Expect(bookResponse).NotTo(BeNil())
+					Expect(mock.ExpectationsWereMet()).To(Succeed())
+					Expect(bookResponse.Authors).To(Equal([]models.AuthorResponse{
+						{ID: 1, Name: "Author Name", Role: models.AuthorRoleAuthor},
+						{ID: 2, Name: "Editor Name", Role: models.AuthorRoleEditor},
+					}))
+					Expect(bookResponse.Version).To(Equal(4))
+				})
+			})
+
+			Context("when an admin updates the book of another user", func() {
+				It("should record the admin as the actor", func() {
+					book := &models.BookRequest{
+						ID:            1,
+						Name:          "Updated Book",
+						DatePublished: "2022-01-01",
+						PageCount:     200,
+						Authors:       []models.AuthorRequest{{Name: "Author Name", Role: models.AuthorRoleAuthor}},
+						UserID:        userModel.User{ID: 1},
+					}
+
+					mock.ExpectQuery(repository.BookExists).
+						WithArgs(book.ID).
+						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+					// the book stays assigned to its owner
+					mock.ExpectQuery(repository.IsAssigned).
+						WithArgs(book.ID, 1).
+						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+					mock.ExpectBegin()
+
+					expectSnapshot(book.ID, 3)
+
+					mock.ExpectQuery(repository.UpdateBook).
+						WithArgs(book.Name, book.DatePublished, book.ISBN, book.PageCount, book.ID, 0).
+						WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+
+					mock.ExpectExec(repository.DeleteBookAuthors).
+						WithArgs(book.ID).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+
+					mock.ExpectQuery(authors.SelectAuthor).
+						WithArgs("Author Name").
+						WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Author Name"))
+
+					mock.ExpectExec(repository.InsertBookAuthor).
+						WithArgs(book.ID, 1, models.AuthorRoleAuthor, 1).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+
+					expectHistory(book.ID, 4, models.HistoryActionUpdate, 9)
+
+					mock.ExpectCommit()
+
+					_, err := bookRepo.UpdateBook(book, 9)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(mock.ExpectationsWereMet()).To(Succeed())
+				})
+			})
+
+			Context("when the book was changed since it was read", func() {
+				It("should return a version mismatch", func() {
+					book := &models.BookRequest{
+						ID:            1,
+						Name:          "Updated Book",
+						DatePublished: "2022-01-01",
+						PageCount:     200,
+						Authors:       []models.AuthorRequest{{Name: "Author Name", Role: models.AuthorRoleAuthor}},
+						UserID:        userModel.User{ID: 1},
+						Version:       3,
+					}
+
+					mock.ExpectQuery(repository.BookExists).
+						WithArgs(book.ID).
+						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+					mock.ExpectQuery(repository.IsAssigned).
+						WithArgs(book.ID, book.UserID.ID).
+						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+					mock.ExpectBegin()
+
+					expectSnapshot(book.ID, 4)
+
+					mock.ExpectQuery(repository.UpdateBook).
+						WithArgs(book.Name, book.DatePublished, book.ISBN, book.PageCount, book.ID, book.Version).
+						WillReturnRows(sqlmock.NewRows([]string{"version"}))
+
+					mock.ExpectRollback()
+
+					_, err := bookRepo.UpdateBook(book, 1)
+					Expect(err).To(MatchError(repository.ErrVersionMismatch))
+					Expect(mock.ExpectationsWereMet()).To(Succeed())
 				})
 			})
 
@@ -166,18 +375,18 @@ var _ = Describe("API Test", func() {
 						DatePublished: "2022-01-01",
 						ISBN:          "1234567890",
 						PageCount:     200,
-						Author: models.AuthorRequest{
-							Name: "Author Name",
+						Authors: []models.AuthorRequest{
+							{Name: "Author Name"},
 						},
 						UserID: userModel.User{ID: 1},
 					}
 
-					query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM books WHERE id=$1)")
+					query := repository.BookExists
 					mock.ExpectQuery(query).
 						WithArgs(book.ID).
 						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
-					_, err := bookRepo.UpdateBook(book)
+					_, err := bookRepo.UpdateBook(book, 1)
 					Expect(err).To(HaveOccurred())
 					expectedErrorMessage := fmt.Sprintf("Book ID doesn't exists: %d", book.ID)
 					Expect(err.Error()).To(Equal(expectedErrorMessage))
@@ -192,13 +401,13 @@ var _ = Describe("API Test", func() {
 						DatePublished: "2022-01-01",
 						ISBN:          "1234567890",
 						PageCount:     200,
-						Author: models.AuthorRequest{
-							Name: "Author Name",
+						Authors: []models.AuthorRequest{
+							{Name: "Author Name"},
 						},
 						UserID: userModel.User{ID: 2},
 					}
 
-					query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM books WHERE id=$1)")
+					query := repository.BookExists
 					mock.ExpectQuery(query).
 						WithArgs(book.ID).
 						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
@@ -207,7 +416,7 @@ var _ = Describe("API Test", func() {
 						WithArgs(book.ID, book.UserID.ID).
 						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
-					_, err := bookRepo.UpdateBook(book)
+					_, err := bookRepo.UpdateBook(book, 1)
 					Expect(err).To(HaveOccurred())
 					expectedErrorMessage := "user is not the owner of the book"
 					Expect(err.Error()).To(Equal(expectedErrorMessage))
@@ -218,23 +427,32 @@ var _ = Describe("API Test", func() {
 		Describe("GetBook", func() {
 			Context("when the book exists", func() {
 				It("should return the book details", func() {
+					bookID := 123
 					expectedBook := &models.BookResponse{
+						ID:            bookID,
 						Name:          "Test Book",
 						DatePublished: "2022-01-01",
 						ISBN:          "1234567890",
 						PageCount:     200,
-						Author: models.AuthorResponse{
-							Name: "Author Name",
+						Authors: []models.AuthorResponse{
+							{ID: 1, Name: "Author Name", Role: models.AuthorRoleAuthor},
+							{ID: 2, Name: "Translator Name", Role: models.AuthorRoleTranslator},
 						},
+						Version: 2,
 					}
-					bookID := 123
 
 					mock.ExpectQuery(repository.GetBook).
-						WithArgs(bookID).
-						WillReturnRows(sqlmock.NewRows([]string{"name", "date_published", "isbn", "page_count", "author_name"}).
-							AddRow(expectedBook.Name, expectedBook.DatePublished, expectedBook.ISBN, expectedBook.PageCount, expectedBook.Author.Name))
+						WithArgs(bookID, 1).
+						WillReturnRows(sqlmock.NewRows([]string{"id", "name", "date_published", "isbn", "page_count", "version", "cover"}).
+							AddRow(bookID, expectedBook.Name, expectedBook.DatePublished, expectedBook.ISBN, expectedBook.PageCount, expectedBook.Version, nil))
 
-					bookResponse, err := bookRepo.GetBook(bookID)
+					mock.ExpectQuery(repository.GetBookAuthors).
+						WithArgs(pq.Array([]int64{int64(bookID)})).
+						WillReturnRows(sqlmock.NewRows([]string{"user_book_id", "id", "name", "role"}).
+							AddRow(bookID, 1, "Author Name", models.AuthorRoleAuthor).
+							AddRow(bookID, 2, "Translator Name", models.AuthorRoleTranslator))
+
+					bookResponse, err := bookRepo.GetBook(bookID, 1)
 
 					Expect(err).ToNot(HaveOccurred())
 					Expect(bookResponse).To(Equal(expectedBook))
@@ -247,10 +465,10 @@ var _ = Describe("API Test", func() {
 					bookID := 456
 
 					mock.ExpectQuery(repository.GetBook).
-						WithArgs(bookID).
+						WithArgs(bookID, 1).
 						WillReturnError(sql.ErrNoRows)
 
-					bookResponse, err := bookRepo.GetBook(bookID)
+					bookResponse, err := bookRepo.GetBook(bookID, 1)
 
 					Expect(err).To(HaveOccurred())
 					Expect(bookResponse).To(Equal(expectedBook))
@@ -258,51 +476,287 @@ var _ = Describe("API Test", func() {
 			})
 		})
 
-		Describe("GetAllBooks", func() {
-			Context("when books exist", func() {
-				It("should return a list of books", func() {
-					expectedBooks := []models.BookResponse{
-						{Name: "Book1", DatePublished: "2022-01-01", ISBN: "1234567890", PageCount: 200, Author: models.AuthorResponse{Name: "Author1"}},
-						{Name: "Book2", DatePublished: "2022-02-01", ISBN: "0987654321", PageCount: 250, Author: models.AuthorResponse{Name: "Author2"}},
-					}
+		Describe("ListBooks", func() {
+			var columns = []string{"id", "name", "date_published", "isbn", "page_count", "cover"}
 
-					rows := sqlmock.NewRows([]string{"name", "date_published", "isbn", "page_count", "author_name"})
-					for _, book := range expectedBooks {
-						rows.AddRow(book.Name, book.DatePublished, book.ISBN, book.PageCount, book.Author.Name)
-					}
+			Context("when there are more books than the page size", func() {
+				It("should return the first page and a cursor", func() {
+					filter := &models.BookFilter{Limit: 2, Sort: "name", Order: "asc", Author: "Tolkien"}
 
-					mock.ExpectQuery(repository.GetAllBooks).
-						WillReturnRows(rows)
+					mock.ExpectQuery(repository.CountBooks+" WHERE b.user_id = $1 AND b.deleted_at IS NULL AND EXISTS (SELECT 1 FROM user_book_authors AS ba JOIN author AS a ON a.id = ba.author_id WHERE ba.user_book_id = b.id AND a.name ILIKE $2)").
+						WithArgs(1, "%Tolkien%").
+						WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
 
-					books, err := bookRepo.GetAllBooks()
+					mock.ExpectQuery(repository.ListBooks+" WHERE b.user_id = $1 AND b.deleted_at IS NULL AND EXISTS (SELECT 1 FROM user_book_authors AS ba JOIN author AS a ON a.id = ba.author_id WHERE ba.user_book_id = b.id AND a.name ILIKE $2) ORDER BY b.name ASC, b.id ASC LIMIT $3").
+						WithArgs(1, "%Tolkien%", 3).
+						WillReturnRows(sqlmock.NewRows(columns).
+							AddRow(1, "Book1", "2022-01-01", "1234567890", 200, nil).
+							AddRow(2, "Book2", "2022-02-01", "0987654321", 250, nil).
+							AddRow(3, "Book3", "2022-03-01", "1122334455", 300, nil))
+
+					mock.ExpectQuery(repository.GetBookAuthors).
+						WithArgs(pq.Array([]int64{1, 2})).
+						WillReturnRows(sqlmock.NewRows([]string{"user_book_id", "id", "name", "role"}).
+							AddRow(1, 1, "Tolkien", models.AuthorRoleAuthor).
+							AddRow(2, 1, "Tolkien", models.AuthorRoleAuthor).
+							AddRow(2, 5, "Alan Lee", models.AuthorRoleIllustrator))
+
+					books, err := bookRepo.ListBooks(1, filter)
 
 					Expect(err).NotTo(HaveOccurred())
+					Expect(books.Total).To(Equal(3))
+					Expect(books.Books).To(HaveLen(2))
+					Expect(books.Books[1].Name).To(Equal("Book2"))
+					Expect(books.Books[1].Authors).To(HaveLen(2))
+					Expect(books.Books[1].Authors[1].Role).To(Equal(models.AuthorRoleIllustrator))
+					Expect(books.NextCursor).NotTo(BeEmpty())
+
+					filter.Cursor = books.NextCursor
+
+					mock.ExpectQuery(repository.CountBooks+" WHERE b.user_id = $1 AND b.deleted_at IS NULL AND EXISTS (SELECT 1 FROM user_book_authors AS ba JOIN author AS a ON a.id = ba.author_id WHERE ba.user_book_id = b.id AND a.name ILIKE $2)").
+						WithArgs(1, "%Tolkien%").
+						WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
 
-					Expect(books).To(Equal(expectedBooks))
+					mock.ExpectQuery(repository.ListBooks+" WHERE b.user_id = $1 AND b.deleted_at IS NULL AND EXISTS (SELECT 1 FROM user_book_authors AS ba JOIN author AS a ON a.id = ba.author_id WHERE ba.user_book_id = b.id AND a.name ILIKE $2) AND (b.name, b.id) > ($3, $4) ORDER BY b.name ASC, b.id ASC LIMIT $5").
+						WithArgs(1, "%Tolkien%", "Book2", 2, 3).
+						WillReturnRows(sqlmock.NewRows(columns).
+							AddRow(3, "Book3", "2022-03-01", "1122334455", 300, nil))
+
+					mock.ExpectQuery(repository.GetBookAuthors).
+						WithArgs(pq.Array([]int64{3})).
+						WillReturnRows(sqlmock.NewRows([]string{"user_book_id", "id", "name", "role"}).
+							AddRow(3, 1, "Tolkien", models.AuthorRoleAuthor))
+
+					books, err = bookRepo.ListBooks(1, filter)
+
+					Expect(err).NotTo(HaveOccurred())
+					Expect(books.Books).To(HaveLen(1))
+					Expect(books.NextCursor).To(BeEmpty())
 				})
 			})
 
 			Context("when no books exist", func() {
 				It("should return an empty list", func() {
-					mock.ExpectQuery(repository.GetAllBooks).
-						WillReturnRows(sqlmock.NewRows([]string{}))
+					filter := &models.BookFilter{Limit: 20, Sort: "id", Order: "desc", MinPages: 100}
+
+					mock.ExpectQuery(repository.CountBooks+" WHERE b.user_id = $1 AND b.deleted_at IS NULL AND b.page_count >= $2").
+						WithArgs(1, 100).
+						WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+					mock.ExpectQuery(repository.ListBooks+" WHERE b.user_id = $1 AND b.deleted_at IS NULL AND b.page_count >= $2 ORDER BY b.id DESC LIMIT $3").
+						WithArgs(1, 100, 21).
+						WillReturnRows(sqlmock.NewRows(columns))
+
+					books, err := bookRepo.ListBooks(1, filter)
+
+					Expect(err).NotTo(HaveOccurred())
+					Expect(books.Books).To(BeEmpty())
+					Expect(books.Total).To(Equal(0))
+				})
+			})
+
+			Context("when filtering by reading status", func() {
+				It("should match the status of the latest reading session", func() {
+					filter := &models.BookFilter{Limit: 20, Sort: "id", Order: "asc", Status: models.ReadingStatusReading}
+					condition := " WHERE b.user_id = $1 AND b.deleted_at IS NULL AND (SELECT rs.status FROM reading_sessions AS rs WHERE rs.user_book_id = b.id ORDER BY rs.id DESC LIMIT 1) = $2"
+
+					mock.ExpectQuery(repository.CountBooks+condition).
+						WithArgs(1, models.ReadingStatusReading).
+						WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+					mock.ExpectQuery(repository.ListBooks+condition+" ORDER BY b.id ASC LIMIT $3").
+						WithArgs(1, models.ReadingStatusReading, 21).
+						WillReturnRows(sqlmock.NewRows(columns))
+
+					_, err := bookRepo.ListBooks(1, filter)
+
+					Expect(err).NotTo(HaveOccurred())
+					Expect(mock.ExpectationsWereMet()).To(Succeed())
+				})
+			})
+
+			Context("when the cursor was issued for another sort order", func() {
+				It("should return an error", func() {
+					filter := &models.BookFilter{Limit: 2, Sort: "id", Order: "asc", Cursor: "bm90LWEtY3Vyc29y"}
+
+					mock.ExpectQuery(repository.CountBooks + " WHERE b.user_id = $1 AND b.deleted_at IS NULL").
+						WithArgs(1).
+						WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+					_, err := bookRepo.ListBooks(1, filter)
+
+					Expect(err).To(MatchError(repository.ErrInvalidCursor))
+				})
+			})
+		})
+
+		Describe("SearchBooks", func() {
+			Context("when the query has search terms", func() {
+				It("should return personal and shop matches", func() {
+					search := &models.SearchRequest{Query: "Tolkien, hob", Limit: 20}
+
+					mock.ExpectQuery(repository.SearchBooks).
+						WithArgs("tolkien:* & hob:*", 1, 20).
+						WillReturnRows(sqlmock.NewRows([]string{"source", "id", "name", "isbn", "authors", "rank", "highlight"}).
+							AddRow("personal", 1, "The Hobbit", "9780261102217", "J. R. R. Tolkien", 0.6, "The \uE000Hobbit\uE001").
+							AddRow("shop", 7, "The Hobbit <script>", "", "J. R. R. Tolkien", 0.5, "The \uE000Hobbit\uE001 <script>"))
+
+					results, err := bookRepo.SearchBooks(1, search)
+
+					Expect(err).NotTo(HaveOccurred())
+					Expect(results).To(HaveLen(2))
+					Expect(results[0].Source).To(Equal(repository.SearchSourcePersonal))
+					Expect(results[1].Source).To(Equal(repository.SearchSourceShop))
+					Expect(results[0].Highlight).To(Equal("The <mark>Hobbit</mark>"))
+					Expect(results[1].Highlight).To(Equal("The <mark>Hobbit</mark> &lt;script&gt;"))
+				})
+			})
 
-					books, err := bookRepo.GetAllBooks()
+			Context("when the query has no search terms", func() {
+				It("should return an error", func() {
+					_, err := bookRepo.SearchBooks(1, &models.SearchRequest{Query: " & | !", Limit: 20})
+
+					Expect(err).To(MatchError(repository.ErrEmptySearch))
+				})
+			})
+		})
+
+		Describe("ImportBooks", func() {
+			var rows []models.ImportRow
+
+			JustBeforeEach(func() {
+				rows = []models.ImportRow{
+					{Row: 1, Book: models.BookRequest{Name: "The Hobbit", DatePublished: "1937-09-21", ISBN: "9780261102217", PageCount: 310, Authors: []models.AuthorRequest{{Name: "J. R. R. Tolkien"}}}},
+					{Row: 2, Book: models.BookRequest{Name: "Dune", DatePublished: "1965-08-01", ISBN: "9780441172719", PageCount: 412, Authors: []models.AuthorRequest{{Name: "Frank Herbert"}}}},
+					{Row: 3, Book: models.BookRequest{Name: "The Hobbit", DatePublished: "1937-09-21", ISBN: "9780261102217", Authors: []models.AuthorRequest{{Name: "J. R. R. Tolkien"}}}},
+					{Row: 4, Error: "invalid page count: many"},
+				}
+
+				mock.ExpectBegin()
+
+				mock.ExpectExec(repository.SavepointImportRow).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(repository.CheckISBN).
+					WithArgs("9780261102217").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectQuery(repository.InsertBook).
+					WithArgs("The Hobbit", "1937-09-21", "9780261102217", 310, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+				mock.ExpectQuery(authors.SelectAuthor).
+					WithArgs("J. R. R. Tolkien").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "J. R. R. Tolkien"))
+				mock.ExpectExec(repository.InsertBookAuthor).
+					WithArgs(10, 1, models.AuthorRoleAuthor, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectHistory(10, 1, models.HistoryActionCreate, 1)
+				mock.ExpectExec(repository.ReleaseImportRow).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectExec(repository.SavepointImportRow).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(repository.CheckISBN).
+					WithArgs("9780441172719").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectExec(repository.ReleaseImportRow).WillReturnResult(sqlmock.NewResult(0, 0))
+			})
+
+			Context("in best effort mode", func() {
+				It("should commit the rows that succeeded", func() {
+					mock.ExpectCommit()
+
+					report, err := bookRepo.ImportBooks(1, 1, rows, models.ImportModeBestEffort)
 
 					Expect(err).NotTo(HaveOccurred())
+					Expect(mock.ExpectationsWereMet()).To(Succeed())
+					Expect(report.Created).To(Equal(1))
+					Expect(report.Skipped).To(Equal(2))
+					Expect(report.Failed).To(Equal(1))
+					Expect(report.RolledBack).To(BeFalse())
+					Expect(report.Rows[0].BookID).To(Equal(10))
+					Expect(report.Rows[1].Reason).To(Equal("book with this ISBN already exists"))
+					Expect(report.Rows[2].Reason).To(Equal("duplicate ISBN in the import"))
+					Expect(report.Rows[3].Reason).To(Equal("invalid page count: many"))
+				})
+			})
+
+			Context("in atomic mode", func() {
+				It("should roll back everything when a row fails", func() {
+					mock.ExpectRollback()
 
-					Expect(books).To(BeEmpty())
+					report, err := bookRepo.ImportBooks(1, 1, rows, models.ImportModeAtomic)
+
+					Expect(err).NotTo(HaveOccurred())
+					Expect(mock.ExpectationsWereMet()).To(Succeed())
+					Expect(report.Created).To(Equal(0))
+					Expect(report.Skipped).To(Equal(3))
+					Expect(report.Failed).To(Equal(1))
+					Expect(report.RolledBack).To(BeTrue())
+					Expect(report.Rows[0].Status).To(Equal(models.ImportStatusSkipped))
+					Expect(report.Rows[0].BookID).To(BeZero())
 				})
 			})
 		})
 
+		Describe("ExportBooks", func() {
+			It("should read the books in chunks until the last one", func() {
+				columns := []string{"id", "name", "date_published", "isbn", "page_count", "cover"}
+				authorColumns := []string{"user_book_id", "id", "name", "role"}
+
+				mock.ExpectQuery(repository.ExportBooks).
+					WithArgs(1, 0, 2).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, "The Hobbit", "1937-09-21", "9780261102217", 310, nil).
+						AddRow(4, "Dune", "1965-08-01", "9780441172719", 412, nil))
+				mock.ExpectQuery(repository.GetBookAuthors).
+					WithArgs(pq.Array([]int64{1, 4})).
+					WillReturnRows(sqlmock.NewRows(authorColumns).
+						AddRow(1, 1, "J. R. R. Tolkien", models.AuthorRoleAuthor).
+						AddRow(4, 2, "Frank Herbert", models.AuthorRoleAuthor))
+				mock.ExpectQuery(repository.ExportBooks).
+					WithArgs(1, 4, 2).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(7, "Emma", "1815-12-23", "9780141439587", 474, nil))
+				mock.ExpectQuery(repository.GetBookAuthors).
+					WithArgs(pq.Array([]int64{7})).
+					WillReturnRows(sqlmock.NewRows(authorColumns).
+						AddRow(7, 3, "Jane Austen", models.AuthorRoleAuthor))
+
+				var chunks [][]models.BookResponse
+				err := bookRepo.ExportBooks(1, 2, func(books []models.BookResponse) error {
+					chunks = append(chunks, books)
+					return nil
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+				Expect(chunks).To(HaveLen(2))
+				Expect(chunks[0][1].Authors[0].Name).To(Equal("Frank Herbert"))
+				Expect(chunks[1][0].Name).To(Equal("Emma"))
+			})
+
+			It("should stop when writing a chunk fails", func() {
+				mock.ExpectQuery(repository.ExportBooks).
+					WithArgs(1, 0, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "date_published", "isbn", "page_count", "cover"}).
+						AddRow(1, "The Hobbit", "1937-09-21", "9780261102217", 310, nil).
+						AddRow(4, "Dune", "1965-08-01", "9780441172719", 412, nil))
+				mock.ExpectQuery(repository.GetBookAuthors).
+					WithArgs(pq.Array([]int64{1, 4})).
+					WillReturnRows(sqlmock.NewRows([]string{"user_book_id", "id", "name", "role"}))
+
+				err := bookRepo.ExportBooks(1, 2, func(books []models.BookResponse) error {
+					return errors.New("connection reset")
+				})
+
+				Expect(err).To(MatchError("connection reset"))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+		})
+
 		Describe("DeleteBook", func() {
 			Context("when the book exists and the user is the owner", func() {
 				It("should delete the book", func() {
 					bookID := 123
 					userID := 456
 
-					query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM user_book WHERE id=$1)")
+					query := repository.BookExists
 					mock.ExpectQuery(query).
 						WithArgs(bookID).
 						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
@@ -311,29 +765,67 @@ var _ = Describe("API Test", func() {
 						WithArgs(bookID, userID).
 						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 
+					mock.ExpectBegin()
+
+					expectSnapshot(bookID, 2)
+
 					mock.ExpectExec(repository.DeleteBook).
 						WithArgs(bookID).
 						WillReturnResult(sqlmock.NewResult(0, 1))
 
-					deletedBookID, err := bookRepo.DeleteBook(bookID, userID)
+					expectHistory(bookID, 3, models.HistoryActionDelete, userID)
+
+					mock.ExpectCommit()
+
+					deletedBookID, err := bookRepo.DeleteBook(bookID, userID, userID)
 
 					Expect(err).NotTo(HaveOccurred())
+					Expect(mock.ExpectationsWereMet()).To(Succeed())
 
 					Expect(deletedBookID).To(Equal(bookID))
 				})
 			})
 
+			Context("when an admin deletes the book of another user", func() {
+				It("should record the admin as the actor", func() {
+					mock.ExpectQuery(repository.BookExists).
+						WithArgs(123).
+						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+					mock.ExpectQuery(repository.IsAssigned).
+						WithArgs(123, 456).
+						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+					mock.ExpectBegin()
+
+					expectSnapshot(123, 2)
+
+					mock.ExpectExec(repository.DeleteBook).
+						WithArgs(123).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+
+					expectHistory(123, 3, models.HistoryActionDelete, 9)
+
+					mock.ExpectCommit()
+
+					_, err := bookRepo.DeleteBook(123, 456, 9)
+
+					Expect(err).NotTo(HaveOccurred())
+					Expect(mock.ExpectationsWereMet()).To(Succeed())
+				})
+			})
+
 			Context("when the book does not exist", func() {
 				It("should return an error", func() {
 					bookID := 123
 					userID := 456
 
-					query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM user_book WHERE id=$1)")
+					query := repository.BookExists
 					mock.ExpectQuery(query).
 						WithArgs(bookID).
 						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
-					_, err := bookRepo.DeleteBook(bookID, userID)
+					_, err := bookRepo.DeleteBook(bookID, userID, userID)
 
 					Expect(err).To(HaveOccurred())
 					Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("Book ID doesn't exists: %v", bookID)))
@@ -345,7 +837,7 @@ var _ = Describe("API Test", func() {
 					bookID := 123
 					userID := 456
 
-					query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM user_book WHERE id=$1)")
+					query := repository.BookExists
 					mock.ExpectQuery(query).
 						WithArgs(bookID).
 						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
@@ -354,12 +846,447 @@ var _ = Describe("API Test", func() {
 						WithArgs(bookID, userID).
 						WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
-					_, err := bookRepo.DeleteBook(bookID, userID)
+					_, err := bookRepo.DeleteBook(bookID, userID, userID)
 
 					Expect(err).To(HaveOccurred())
 					Expect(err.Error()).To(ContainSubstring("user is not the owner of the book"))
 				})
 			})
 		})
+
+		Describe("Trash", func() {
+			It("should list the deleted books with their authors", func() {
+				deletedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+				mock.ExpectQuery(repository.ListTrash).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "date_published", "isbn", "page_count", "deleted_at"}).
+						AddRow(7, "Dune", "1965-08-01", "9780441172719", 412, deletedAt))
+
+				mock.ExpectQuery(repository.GetBookAuthors).
+					WithArgs(pq.Array([]int64{7})).
+					WillReturnRows(sqlmock.NewRows([]string{"user_book_id", "id", "name", "role"}).
+						AddRow(7, 3, "Frank Herbert", models.AuthorRoleAuthor))
+
+				books, err := bookRepo.ListTrash(1)
+
+				Expect(err).To(BeNil())
+				Expect(books).To(HaveLen(1))
+				Expect(*books[0].DeletedAt).To(Equal(deletedAt))
+				Expect(books[0].Authors).To(Equal([]models.AuthorResponse{{ID: 3, Name: "Frank Herbert", Role: models.AuthorRoleAuthor}}))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should restore a deleted book", func() {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(repository.RestoreBook).
+					WithArgs(7, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectQuery(repository.CheckRestoredISBN).
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+				expectSnapshot(7, 4)
+				expectHistory(7, 4, models.HistoryActionRestore, 1)
+
+				mock.ExpectCommit()
+
+				restoredID, err := bookRepo.RestoreBook(7, 1, 1)
+
+				Expect(err).To(BeNil())
+				Expect(restoredID).To(Equal(7))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should record the admin restoring the book of another user", func() {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(repository.RestoreBook).
+					WithArgs(7, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectQuery(repository.CheckRestoredISBN).
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+				expectSnapshot(7, 4)
+				expectHistory(7, 4, models.HistoryActionRestore, 9)
+
+				mock.ExpectCommit()
+
+				_, err := bookRepo.RestoreBook(7, 1, 9)
+
+				Expect(err).To(BeNil())
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should not restore a book whose ISBN was taken since it was deleted", func() {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(repository.RestoreBook).
+					WithArgs(7, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectQuery(repository.CheckRestoredISBN).
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+				mock.ExpectRollback()
+
+				_, err := bookRepo.RestoreBook(7, 1, 1)
+
+				Expect(err).To(MatchError(repository.ErrISBNTaken))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should not restore a book that is not in the user's trash", func() {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(repository.RestoreBook).
+					WithArgs(7, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectRollback()
+
+				_, err := bookRepo.RestoreBook(7, 2, 2)
+
+				Expect(err).To(MatchError(repository.ErrNotInTrash))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should purge the books deleted before the given time", func() {
+				before := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+				mock.ExpectQuery(repository.PurgeTrash).
+					WithArgs(before).
+					WillReturnRows(sqlmock.NewRows([]string{"cover_keys"}).
+						AddRow("{7/cd/original.jpg,7/cd/small.jpg}").
+						AddRow("{}").
+						AddRow("{9/ef/original.png}").
+						AddRow("{}"))
+
+				purged, keys, err := bookRepo.PurgeTrash(before)
+
+				Expect(err).To(BeNil())
+				Expect(purged).To(Equal(int64(4)))
+				Expect(keys).To(Equal([]string{"7/cd/original.jpg", "7/cd/small.jpg", "9/ef/original.png"}))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+		})
+
+		Describe("Reading", func() {
+			var (
+				sessionColumns = []string{"id", "status", "current_page", "started_at", "finished_at", "updated_at"}
+				updatedAt      = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+				page           = func(page int) *int { return &page }
+			)
+
+			It("should count the finished reads", func() {
+				mock.ExpectQuery(repository.IsAssigned).
+					WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+				mock.ExpectQuery(repository.ListReadingSessions).
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows(sessionColumns).
+						AddRow(3, models.ReadingStatusReading, 50, "2024-02-01", "", updatedAt).
+						AddRow(2, models.ReadingStatusFinished, 412, "2023-01-01", "2023-02-01", updatedAt).
+						AddRow(1, models.ReadingStatusAbandoned, 80, "2022-01-01", "", updatedAt))
+
+				reading, err := bookRepo.GetReading(7, 1)
+
+				Expect(err).To(BeNil())
+				Expect(reading.Current.ID).To(Equal(3))
+				Expect(reading.Sessions).To(HaveLen(3))
+				Expect(reading.ReadCount).To(Equal(1))
+			})
+
+			It("should start the first session when the book is started", func() {
+				today := time.Now().Format(time.DateOnly)
+
+				mock.ExpectBegin()
+				mock.ExpectQuery(repository.LockReadingBook).
+					WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"page_count"}).AddRow(412))
+				mock.ExpectQuery(repository.LatestReadingSession).
+					WithArgs(7).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(repository.InsertReadingSession).
+					WithArgs(7, models.ReadingStatusReading, 12, today, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "updated_at"}).AddRow(1, updatedAt))
+				mock.ExpectCommit()
+
+				session, err := bookRepo.UpdateReading(7, 1, &models.ReadingProgressRequest{CurrentPage: page(12)})
+
+				Expect(err).To(BeNil())
+				Expect(session.ID).To(Equal(1))
+				Expect(session.StartedAt).To(Equal(today))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should finish the current session on the last page", func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(repository.LockReadingBook).
+					WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"page_count"}).AddRow(412))
+				mock.ExpectQuery(repository.LatestReadingSession).
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows(sessionColumns).AddRow(3, models.ReadingStatusReading, 50, "2024-02-01", "", updatedAt))
+				mock.ExpectQuery(repository.UpdateReadingSession).
+					WithArgs(models.ReadingStatusFinished, 412, "2024-02-01", "2024-02-20", 3).
+					WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(updatedAt))
+				mock.ExpectCommit()
+
+				session, err := bookRepo.UpdateReading(7, 1, &models.ReadingProgressRequest{Status: models.ReadingStatusFinished, FinishedAt: "2024-02-20"})
+
+				Expect(err).To(BeNil())
+				Expect(session.CurrentPage).To(Equal(412))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should start a new session when a finished book is read again", func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(repository.LockReadingBook).
+					WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"page_count"}).AddRow(412))
+				mock.ExpectQuery(repository.LatestReadingSession).
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows(sessionColumns).AddRow(2, models.ReadingStatusFinished, 412, "2023-01-01", "2023-02-01", updatedAt))
+				mock.ExpectQuery(repository.InsertReadingSession).
+					WithArgs(7, models.ReadingStatusReading, 0, "2024-03-01", nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "updated_at"}).AddRow(4, updatedAt))
+				mock.ExpectCommit()
+
+				session, err := bookRepo.UpdateReading(7, 1, &models.ReadingProgressRequest{Status: models.ReadingStatusReading, StartedAt: "2024-03-01"})
+
+				Expect(err).To(BeNil())
+				Expect(session.ID).To(Equal(4))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should reject a page past the end of the book", func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(repository.LockReadingBook).
+					WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"page_count"}).AddRow(412))
+				mock.ExpectQuery(repository.LatestReadingSession).
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows(sessionColumns).AddRow(3, models.ReadingStatusReading, 50, "2024-02-01", "", updatedAt))
+				mock.ExpectRollback()
+
+				_, err := bookRepo.UpdateReading(7, 1, &models.ReadingProgressRequest{CurrentPage: page(500)})
+
+				Expect(err).To(MatchError(repository.ErrInvalidProgress))
+				Expect(err).To(MatchError(ContainSubstring("current page must be between 0 and 412")))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should not update the progress of another user's book", func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(repository.LockReadingBook).
+					WithArgs(7, 2).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				_, err := bookRepo.UpdateReading(7, 2, &models.ReadingProgressRequest{CurrentPage: page(1)})
+
+				Expect(err).To(MatchError(repository.ErrBookNotFound))
+			})
+		})
+
+		Describe("History", func() {
+			var columns = []string{"id", "user_book_id", "version", "action", "actor_id", "changed_at", "before", "after", "changes"}
+
+			It("should list the revisions of the owner's book", func() {
+				changedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+				mock.ExpectQuery(repository.IsAssigned).
+					WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+				mock.ExpectQuery(repository.ListBookHistory).
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(12, 7, 2, models.HistoryActionUpdate, 1, changedAt,
+							[]byte(`{"name":"Dune","page_count":400}`),
+							[]byte(`{"name":"Dune","page_count":412}`),
+							[]byte(`{"page_count":{"before":400,"after":412}}`)).
+						AddRow(11, 7, 1, models.HistoryActionCreate, 1, changedAt,
+							nil,
+							[]byte(`{"name":"Dune","page_count":400}`),
+							[]byte(`{"name":{"before":null,"after":"Dune"}}`)))
+
+				revisions, err := bookRepo.ListHistory(7, 1)
+
+				Expect(err).To(BeNil())
+				Expect(revisions).To(HaveLen(2))
+				Expect(revisions[0].Before.PageCount).To(Equal(400))
+				Expect(revisions[0].After.PageCount).To(Equal(412))
+				Expect(revisions[0].Changes).To(HaveKeyWithValue("page_count", models.FieldChange{Before: 400.0, After: 412.0}))
+				Expect(revisions[1].Before).To(BeNil())
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should hide the history of other users' books", func() {
+				mock.ExpectQuery(repository.IsAssigned).
+					WithArgs(7, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+				_, err := bookRepo.ListHistory(7, 2)
+
+				Expect(err).To(MatchError(repository.ErrBookNotFound))
+			})
+
+			It("should revert the book to a revision and record the revert", func() {
+				mock.ExpectQuery(repository.BookExists).
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(repository.IsAssigned).
+					WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(repository.GetBookRevision).
+					WithArgs(11, 7).
+					WillReturnRows(sqlmock.NewRows([]string{"after"}).
+						AddRow([]byte(`{"name":"Dune","date_published":"1965-08-01","isbn":"9780441172719","page_count":400,"authors":[{"id":3,"name":"Frank Herbert","role":"author"}]}`)))
+
+				expectSnapshot(7, 2)
+
+				mock.ExpectQuery(repository.UpdateBook).
+					WithArgs("Dune", "1965-08-01", "9780441172719", 400, 7, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+				mock.ExpectExec(repository.DeleteBookAuthors).
+					WithArgs(7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(authors.SelectAuthor).
+					WithArgs("Frank Herbert").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "Frank Herbert"))
+				mock.ExpectExec(repository.InsertBookAuthor).
+					WithArgs(7, 3, models.AuthorRoleAuthor, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				expectHistory(7, 3, models.HistoryActionRevert, 1)
+
+				mock.ExpectCommit()
+
+				book, err := bookRepo.RevertBook(7, 1, 11, 1)
+
+				Expect(err).To(BeNil())
+				Expect(book.PageCount).To(Equal(400))
+				Expect(book.Version).To(Equal(3))
+				Expect(book.Authors).To(Equal([]models.AuthorResponse{{ID: 3, Name: "Frank Herbert", Role: models.AuthorRoleAuthor}}))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should refuse to revert to a deletion", func() {
+				mock.ExpectQuery(repository.BookExists).
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(repository.IsAssigned).
+					WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(repository.GetBookRevision).
+					WithArgs(12, 7).
+					WillReturnRows(sqlmock.NewRows([]string{"after"}).AddRow(nil))
+
+				mock.ExpectRollback()
+
+				_, err := bookRepo.RevertBook(7, 1, 12, 1)
+
+				Expect(err).To(MatchError(repository.ErrInvalidRevert))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+		})
+
+		Describe("Cover", func() {
+			It("should accept the book of the user", func() {
+				mock.ExpectQuery(repository.CheckBookCover).
+					WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+				Expect(bookRepo.CheckCover(7, 1)).To(Succeed())
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should refuse to check the book of another user", func() {
+				mock.ExpectQuery(repository.CheckBookCover).
+					WithArgs(7, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+				Expect(bookRepo.CheckCover(7, 2)).To(MatchError(repository.ErrBookNotFound))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should replace the cover and return the previous keys", func() {
+				cover := models.Cover{"original": "/v1/books/covers/7/ab/original.png"}
+
+				mock.ExpectBegin()
+				mock.ExpectQuery(repository.LockBookCover).
+					WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"cover_keys"}).AddRow("{7/cd/original.jpg,7/cd/small.jpg}"))
+				mock.ExpectExec(repository.UpdateBookCover).
+					WithArgs(sqlmock.AnyArg(), pq.Array([]string{"7/ab/original.png"}), 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				previous, err := bookRepo.SetCover(7, 1, cover, []string{"7/ab/original.png"})
+
+				Expect(err).To(BeNil())
+				Expect(previous).To(Equal([]string{"7/cd/original.jpg", "7/cd/small.jpg"}))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should refuse the book of another user", func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(repository.LockBookCover).
+					WithArgs(7, 2).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				_, err := bookRepo.SetCover(7, 2, nil, nil)
+
+				Expect(err).To(MatchError(repository.ErrBookNotFound))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+		})
+
+		Describe("LookupISBN", func() {
+			It("should return the catalog book with its authors", func() {
+				mock.ExpectQuery(repository.LookupCatalogISBN).
+					WithArgs("9780261103344").
+					WillReturnRows(sqlmock.NewRows([]string{"name", "date_published", "page_count", "authors"}).
+						AddRow("The Hobbit", "1991-07-01", 310, "{\"J. R. R. Tolkien\"}"))
+
+				book, err := bookRepo.LookupISBN("9780261103344")
+
+				Expect(err).To(BeNil())
+				Expect(book).To(Equal(&models.BookRequest{
+					Name:          "The Hobbit",
+					DatePublished: "1991-07-01",
+					ISBN:          "9780261103344",
+					PageCount:     310,
+					Authors:       []models.AuthorRequest{{Name: "J. R. R. Tolkien", Role: models.AuthorRoleAuthor}},
+				}))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+
+			It("should report an ISBN missing from the catalog", func() {
+				mock.ExpectQuery(repository.LookupCatalogISBN).
+					WithArgs("9780306406157").
+					WillReturnError(sql.ErrNoRows)
+
+				_, err := bookRepo.LookupISBN("9780306406157")
+
+				Expect(err).To(MatchError(repository.ErrISBNNotInCatalog))
+				Expect(mock.ExpectationsWereMet()).To(Succeed())
+			})
+		})
 	})
 })
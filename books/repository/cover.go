@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"library/books/models"
+	"library/pkg/utils"
+
+	"github.com/lib/pq"
+)
+
+// CheckCover returns ErrBookNotFound unless the book belongs to the user, so the cover is
+// only uploaded for a book the user can set it on.
+func (b *BookRepository) CheckCover(bookID, userID int) error {
+	var exists bool
+
+	log := utils.GetLogger(b.ctx)
+
+	if err := b.DB.DB.QueryRow(CheckBookCover, bookID, userID).Scan(&exists); err != nil {
+		log.Errorf("Query row on user book table failed: %v", err)
+		return err
+	}
+
+	if !exists {
+		log.Warningf("Book %v does not belong to user %v", bookID, userID)
+		return ErrBookNotFound
+	}
+
+	return nil
+}
+
+// SetCover replaces the cover of the book, or removes it when cover is nil. It returns
+// the storage keys of the replaced cover, so the caller can delete those files.
+func (b *BookRepository) SetCover(bookID, userID int, cover models.Cover, keys []string) ([]string, error) {
+	var previous []string
+
+	log := utils.GetLogger(b.ctx)
+
+	tx, err := b.DB.DB.Begin()
+	if err != nil {
+		log.Errorf("Failed to begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(LockBookCover, bookID, userID).Scan(pq.Array(&previous))
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("Book %v does not belong to user %v", bookID, userID)
+		return nil, ErrBookNotFound
+	}
+
+	if err != nil {
+		log.Errorf("Query row on user book table failed: %v", err)
+		return nil, err
+	}
+
+	var storedKeys interface{}
+	if cover != nil {
+		storedKeys = pq.Array(keys)
+	}
+
+	if _, err = tx.Exec(UpdateBookCover, cover, storedKeys, bookID); err != nil {
+		log.Errorf("Failed to update book cover: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Errorf("Failed to commit transaction: %v", err)
+		return nil, err
+	}
+
+	return previous, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"library/books/models"
+	"library/pkg/utils"
+)
+
+// ExportBooks pages through all books of the user by ID, chunkSize rows at a
+// time, and passes every chunk to write so the whole library is never held in memory.
+func (b *BookRepository) ExportBooks(userID, chunkSize int, write func([]models.BookResponse) error) error {
+	log := utils.GetLogger(b.ctx)
+
+	lastID := 0
+
+	for {
+		books, err := b.exportChunk(userID, lastID, chunkSize)
+		if err != nil {
+			log.Errorf("Failed to export books: %v", err)
+			return err
+		}
+
+		if err = loadBookAuthors(log, books, b.DB.GetDB()); err != nil {
+			return err
+		}
+
+		if len(books) == 0 {
+			return nil
+		}
+
+		if err = write(books); err != nil {
+			log.Errorf("Failed to write exported books: %v", err)
+			return err
+		}
+
+		if len(books) < chunkSize {
+			return nil
+		}
+
+		lastID = books[len(books)-1].ID
+	}
+}
+
+func (b *BookRepository) exportChunk(userID, lastID, chunkSize int) ([]models.BookResponse, error) {
+	books := make([]models.BookResponse, 0, chunkSize)
+
+	rows, err := b.DB.DB.Query(ExportBooks, userID, lastID, chunkSize)
+	if err != nil {
+		return books, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var book models.BookResponse
+
+		err = rows.Scan(&book.ID, &book.Name, &book.DatePublished, &book.ISBN, &book.PageCount, &book.Cover)
+		if err != nil {
+			return books, err
+		}
+
+		books = append(books, book)
+	}
+
+	return books, rows.Err()
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"library/books/models"
+	"library/pkg/isbn"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// sortColumns maps the public sort keys onto user_book columns.
+var sortColumns = map[string]string{
+	"id":             "b.id",
+	"name":           "b.name",
+	"date_published": "b.date_published",
+	"page_count":     "b.page_count",
+}
+
+// bookQuery collects the conditions and positional arguments of a book listing.
+type bookQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+// bookCursor is the keyset position of the last book on a page.
+type bookCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v,omitempty"`
+	ID    int    `json:"id"`
+}
+
+func newBookQuery(userID int, filter *models.BookFilter) *bookQuery {
+	query := &bookQuery{}
+
+	query.where("b.user_id = %s", userID)
+	query.where("b.deleted_at IS NULL")
+
+	if filter.Author != "" {
+		query.where(
+			"EXISTS (SELECT 1 FROM user_book_authors AS ba JOIN author AS a ON a.id = ba.author_id WHERE ba.user_book_id = b.id AND a.name ILIKE %s)",
+			"%"+escapeLike(filter.Author)+"%",
+		)
+	}
+
+	if filter.ISBNPrefix != "" {
+		query.where("b.isbn LIKE %s", escapeLike(isbn.Clean(filter.ISBNPrefix))+"%")
+	}
+
+	if filter.PublishedFrom != "" {
+		query.where("b.date_published >= %s", filter.PublishedFrom)
+	}
+
+	if filter.PublishedTo != "" {
+		query.where("b.date_published <= %s", filter.PublishedTo)
+	}
+
+	if filter.MinPages != 0 {
+		query.where("b.page_count >= %s", filter.MinPages)
+	}
+
+	if filter.MaxPages != 0 {
+		query.where("b.page_count <= %s", filter.MaxPages)
+	}
+
+	// the status of a book is the one of its latest reading session
+	if filter.Status != "" {
+		query.where("(SELECT rs.status FROM reading_sessions AS rs WHERE rs.user_book_id = b.id ORDER BY rs.id DESC LIMIT 1) = %s", filter.Status)
+	}
+
+	return query
+}
+
+// where adds a condition, replacing each %s verb with the next positional placeholder.
+func (q *bookQuery) where(condition string, args ...interface{}) {
+	placeholders := make([]interface{}, len(args))
+	for i, arg := range args {
+		placeholders[i] = q.placeholder(arg)
+	}
+
+	q.conditions = append(q.conditions, fmt.Sprintf(condition, placeholders...))
+}
+
+func (q *bookQuery) placeholder(arg interface{}) string {
+	q.args = append(q.args, arg)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// after restricts the listing to the books that follow the cursor position.
+func (q *bookQuery) after(filter *models.BookFilter, cursor *bookCursor) {
+	operator := ">"
+	if filter.Order == "desc" {
+		operator = "<"
+	}
+
+	if filter.Sort == "id" {
+		q.where("b.id "+operator+" %s", cursor.ID)
+		return
+	}
+
+	q.where(fmt.Sprintf("(%s, b.id) %s (%%s, %%s)", sortColumns[filter.Sort], operator), cursor.Value, cursor.ID)
+}
+
+func (q *bookQuery) whereClause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+func (q *bookQuery) orderBy(filter *models.BookFilter) string {
+	direction := strings.ToUpper(filter.Order)
+
+	if filter.Sort == "id" {
+		return " ORDER BY b.id " + direction
+	}
+
+	return fmt.Sprintf(" ORDER BY %s %s, b.id %s", sortColumns[filter.Sort], direction, direction)
+}
+
+func (q *bookQuery) limit(limit int) string {
+	return " LIMIT " + q.placeholder(limit)
+}
+
+func encodeCursor(filter *models.BookFilter, book models.BookResponse) (string, error) {
+	cursor := bookCursor{
+		Sort: filter.Sort + ":" + filter.Order,
+		ID:   book.ID,
+	}
+
+	switch filter.Sort {
+	case "name":
+		cursor.Value = book.Name
+	case "date_published":
+		cursor.Value = book.DatePublished
+	case "page_count":
+		cursor.Value = strconv.Itoa(book.PageCount)
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(filter *models.BookFilter) (*bookCursor, error) {
+	var cursor bookCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(filter.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if err = json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if cursor.Sort != filter.Sort+":"+filter.Order {
+		return nil, errors.New("cursor does not match the requested sort order")
+	}
+
+	return &cursor, nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
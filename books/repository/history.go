@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"library/books/models"
+	"library/pkg/logger"
+	"library/pkg/postgres"
+	"library/pkg/utils"
+)
+
+var (
+	ErrBookNotFound     = errors.New("book not found")
+	ErrRevisionNotFound = errors.New("revision not found")
+	ErrInvalidRevert    = errors.New("cannot revert to a deletion, restore the book instead")
+)
+
+// ListHistory returns the revisions of the book, newest first.
+func (b *BookRepository) ListHistory(bookID, userID int) ([]models.BookRevision, error) {
+	revisions := []models.BookRevision{}
+
+	log := utils.GetLogger(b.ctx)
+
+	isBookAssigned, err := isAssigned(log, bookID, userID, b.DB.GetDB())
+	if err != nil {
+		log.Errorf("Error checking book assignment: %v", err)
+		return revisions, err
+	}
+
+	if !isBookAssigned {
+		log.Warningf("Book %v does not belong to user %v", bookID, userID)
+		return revisions, ErrBookNotFound
+	}
+
+	rows, err := b.DB.DB.Query(ListBookHistory, bookID)
+	if err != nil {
+		log.Errorf("Failed to perform a query on user book history table: %v", err)
+		return revisions, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var revision models.BookRevision
+		var before, after, changes []byte
+
+		err = rows.Scan(
+			&revision.ID,
+			&revision.BookID,
+			&revision.Version,
+			&revision.Action,
+			&revision.ActorID,
+			&revision.ChangedAt,
+			&before,
+			&after,
+			&changes,
+		)
+		if err != nil {
+			log.Errorf("Failed to scan rows: %v", err)
+			return revisions, err
+		}
+
+		if revision.Before, err = decodeSnapshot(before); err == nil {
+			if revision.After, err = decodeSnapshot(after); err == nil {
+				err = json.Unmarshal(changes, &revision.Changes)
+			}
+		}
+
+		if err != nil {
+			log.Errorf("Failed to decode revision %v: %v", revision.ID, err)
+			return revisions, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Errorf("Query failed: %v", err)
+		return revisions, err
+	}
+
+	return revisions, nil
+}
+
+// RevertBook puts the book back in the state recorded by the revision. The revert
+// itself is a new revision, so it can be reverted as well.
+func (b *BookRepository) RevertBook(bookID, userID, revisionID, actorID int) (*models.BookResponse, error) {
+	var bookResponse *models.BookResponse
+
+	log := utils.GetLogger(b.ctx)
+
+	exists, err := bookExists(log, bookID, b.DB.GetDB())
+	if err != nil {
+		log.Errorf("Checking book ID error: %v", bookID)
+		return bookResponse, err
+	}
+
+	isBookAssigned := false
+	if exists {
+		if isBookAssigned, err = isAssigned(log, bookID, userID, b.DB.GetDB()); err != nil {
+			log.Errorf("Error checking book assignment: %v", err)
+			return bookResponse, err
+		}
+	}
+
+	if !isBookAssigned {
+		log.Warningf("Book %v of user %v cannot be reverted", bookID, userID)
+		return bookResponse, ErrBookNotFound
+	}
+
+	tx, err := b.DB.DB.Begin()
+	if err != nil {
+		log.Errorf("Failed to begin transaction: %v", err)
+		return bookResponse, err
+	}
+	defer tx.Rollback()
+
+	var target []byte
+	err = tx.QueryRow(GetBookRevision, revisionID, bookID).Scan(&target)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("Revision %v of book %v not found", revisionID, bookID)
+		return bookResponse, ErrRevisionNotFound
+	}
+
+	if err != nil {
+		log.Errorf("Query row on user book history table failed: %v", err)
+		return bookResponse, err
+	}
+
+	snapshot, err := decodeSnapshot(target)
+	if err != nil {
+		log.Errorf("Failed to decode revision %v: %v", revisionID, err)
+		return bookResponse, err
+	}
+
+	if snapshot == nil {
+		return bookResponse, ErrInvalidRevert
+	}
+
+	before, _, err := loadSnapshot(log, bookID, tx)
+	if err != nil {
+		return bookResponse, err
+	}
+
+	var version int
+	err = tx.QueryRow(
+		UpdateBook,
+		snapshot.Name,
+		snapshot.DatePublished,
+		snapshot.ISBN,
+		snapshot.PageCount,
+		bookID,
+		0,
+	).Scan(&version)
+	if err != nil {
+		log.Errorf("Failed to perform an update query in user book table: %v", err)
+		return bookResponse, err
+	}
+
+	if _, err = tx.Exec(DeleteBookAuthors, bookID); err != nil {
+		log.Errorf("Failed to perform a delete query on user book authors table: %v", err)
+		return bookResponse, err
+	}
+
+	// authors are matched by name, so a revert still works after an author was merged away
+	credits := make([]models.AuthorRequest, len(snapshot.Authors))
+	for i, author := range snapshot.Authors {
+		credits[i] = models.AuthorRequest{Name: author.Name, Role: author.Role}
+	}
+
+	if snapshot.Authors, err = insertBookAuthors(log, bookID, credits, tx); err != nil {
+		return bookResponse, err
+	}
+
+	err = recordHistory(log, tx, &models.BookRevision{
+		BookID:  bookID,
+		Version: version,
+		Action:  models.HistoryActionRevert,
+		ActorID: actorID,
+		Before:  before,
+		After:   snapshot,
+	})
+	if err != nil {
+		return bookResponse, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Errorf("Failed to commit transaction: %v", err)
+		return bookResponse, err
+	}
+
+	bookResponse = &models.BookResponse{
+		ID:            bookID,
+		Name:          snapshot.Name,
+		DatePublished: snapshot.DatePublished,
+		ISBN:          snapshot.ISBN,
+		PageCount:     snapshot.PageCount,
+		Authors:       snapshot.Authors,
+		Version:       version,
+	}
+
+	return bookResponse, nil
+}
+
+// loadSnapshot reads the current state of the book and locks its row until the transaction ends.
+func loadSnapshot(log logger.Logger, bookID int, db postgres.Queryer) (*models.BookSnapshot, int, error) {
+	var version int
+	snapshot := &models.BookSnapshot{}
+
+	err := db.QueryRow(GetBookSnapshot, bookID).Scan(
+		&snapshot.Name,
+		&snapshot.DatePublished,
+		&snapshot.ISBN,
+		&snapshot.PageCount,
+		&version,
+	)
+	if err != nil {
+		log.Errorf("Failed to read user book %v: %v", bookID, err)
+		return nil, 0, err
+	}
+
+	books := []models.BookResponse{{ID: bookID}}
+	if err = loadBookAuthors(log, books, db); err != nil {
+		return nil, 0, err
+	}
+
+	snapshot.Authors = books[0].Authors
+
+	return snapshot, version, nil
+}
+
+// recordHistory stores the revision together with the diff between its snapshots.
+func recordHistory(log logger.Logger, db postgres.Queryer, revision *models.BookRevision) error {
+	before, err := encodeSnapshot(revision.Before)
+	if err != nil {
+		log.Errorf("Failed to encode book snapshot: %v", err)
+		return err
+	}
+
+	after, err := encodeSnapshot(revision.After)
+	if err != nil {
+		log.Errorf("Failed to encode book snapshot: %v", err)
+		return err
+	}
+
+	changes, err := json.Marshal(models.DiffSnapshots(revision.Before, revision.After))
+	if err != nil {
+		log.Errorf("Failed to encode book changes: %v", err)
+		return err
+	}
+
+	_, err = db.Exec(
+		InsertBookHistory,
+		revision.BookID,
+		revision.Version,
+		revision.Action,
+		sql.NullInt64{Int64: int64(revision.ActorID), Valid: revision.ActorID != 0},
+		before,
+		after,
+		changes,
+	)
+	if err != nil {
+		log.Errorf("Failed to perform an insert query on user book history table: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// encodeSnapshot turns a missing snapshot into SQL NULL rather than a JSON null.
+func encodeSnapshot(snapshot *models.BookSnapshot) (interface{}, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(snapshot)
+}
+
+func decodeSnapshot(data []byte) (*models.BookSnapshot, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var snapshot models.BookSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
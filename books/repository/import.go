@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"database/sql"
+	"library/books/models"
+	"library/pkg"
+	"library/pkg/logger"
+	"library/pkg/utils"
+)
+
+const rolledBackReason = "not imported, other rows failed in atomic mode"
+
+// ImportBooks imports the rows in a single transaction. Every row runs inside its own
+// savepoint, so a failing row never poisons the rest of the batch. In atomic mode the
+// whole transaction is rolled back when any row fails, in best effort mode the rows
+// that succeeded are committed.
+func (b *BookRepository) ImportBooks(userID, actorID int, rows []models.ImportRow, mode string) (*models.ImportReport, error) {
+	report := &models.ImportReport{Mode: mode, Rows: []models.ImportRowResult{}}
+
+	log := utils.GetLogger(b.ctx)
+
+	tx, err := b.DB.DB.Begin()
+	if err != nil {
+		log.Errorf("Failed to begin transaction: %v", err)
+		return report, err
+	}
+
+	seenISBNs := make(map[string]bool)
+
+	for _, row := range rows {
+		row.Book.UserID.ID = userID
+
+		if reason := validateImportRow(log, &row); reason != "" {
+			report.Add(row, models.ImportStatusFailed, reason, 0)
+			continue
+		}
+
+		if row.Book.ISBN != "" && seenISBNs[row.Book.ISBN] {
+			report.Add(row, models.ImportStatusSkipped, "duplicate ISBN in the import", 0)
+			continue
+		}
+
+		seenISBNs[row.Book.ISBN] = true
+
+		status, reason, bookID, err := importRow(log, tx, &row, actorID)
+		if err != nil {
+			log.Errorf("Failed to import row %d: %v", row.Row, err)
+			tx.Rollback()
+			return report, err
+		}
+
+		report.Add(row, status, reason, bookID)
+	}
+
+	if mode == models.ImportModeAtomic && report.Failed > 0 {
+		if err = tx.Rollback(); err != nil {
+			log.Errorf("Failed to roll back import: %v", err)
+			return report, err
+		}
+
+		report.RollBack(rolledBackReason)
+		return report, nil
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Errorf("Failed to commit import: %v", err)
+		return report, err
+	}
+
+	return report, nil
+}
+
+func validateImportRow(log logger.Logger, row *models.ImportRow) string {
+	if row.Error != "" {
+		return row.Error
+	}
+
+	if row.Book.Name == "" {
+		return "book name is required"
+	}
+
+	if err := row.Book.ValidateAuthors(); err != nil {
+		return err.Error()
+	}
+
+	if err := pkg.CheckPublishedDate(log, &row.Book); err != nil {
+		return "invalid publication date: " + err.Error()
+	}
+
+	if err := pkg.CheckISBN(log, &row.Book); err != nil {
+		return err.Error()
+	}
+
+	return ""
+}
+
+// importRow inserts a single row inside a savepoint. Row level problems are reported
+// through status and reason, the returned error is reserved for savepoint failures
+// which leave the transaction unusable.
+func importRow(log logger.Logger, tx *sql.Tx, row *models.ImportRow, actorID int) (string, string, int, error) {
+	if _, err := tx.Exec(SavepointImportRow); err != nil {
+		return "", "", 0, err
+	}
+
+	status, reason, bookID := insertImportRow(log, tx, row, actorID)
+
+	release := ReleaseImportRow
+	if status == models.ImportStatusFailed {
+		release = RollbackImportRow
+	}
+
+	if _, err := tx.Exec(release); err != nil {
+		return "", "", 0, err
+	}
+
+	return status, reason, bookID, nil
+}
+
+func insertImportRow(log logger.Logger, tx *sql.Tx, row *models.ImportRow, actorID int) (string, string, int) {
+	if row.Book.ISBN != "" {
+		isExisting, err := validateISBNExists(log, row.Book.ISBN, tx)
+		if err != nil {
+			return models.ImportStatusFailed, err.Error(), 0
+		}
+
+		if isExisting {
+			return models.ImportStatusSkipped, "book with this ISBN already exists", 0
+		}
+	}
+
+	bookID, err := insertBook(log, &row.Book, actorID, tx)
+	if err != nil {
+		return models.ImportStatusFailed, err.Error(), 0
+	}
+
+	return models.ImportStatusCreated, "", bookID
+}
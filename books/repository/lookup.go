@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"library/books/models"
+	"library/pkg/utils"
+
+	"github.com/lib/pq"
+)
+
+var ErrISBNNotInCatalog = errors.New("no catalog book with this ISBN")
+
+// LookupISBN returns the details of the shop catalog book with the canonical ISBN,
+// as a request the user can add to their library.
+func (b *BookRepository) LookupISBN(isbn string) (*models.BookRequest, error) {
+	var authorNames []string
+
+	book := &models.BookRequest{ISBN: isbn}
+
+	log := utils.GetLogger(b.ctx)
+
+	err := b.DB.DB.QueryRow(LookupCatalogISBN, isbn).Scan(
+		&book.Name,
+		&book.DatePublished,
+		&book.PageCount,
+		pq.Array(&authorNames),
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Infof("ISBN %v is not in the catalog", isbn)
+		return nil, ErrISBNNotInCatalog
+	}
+
+	if err != nil {
+		log.Errorf("Failed to look up ISBN in the catalog: %v", err)
+		return nil, err
+	}
+
+	for _, name := range authorNames {
+		book.Authors = append(book.Authors, models.AuthorRequest{Name: name, Role: models.AuthorRoleAuthor})
+	}
+
+	return book, nil
+}
@@ -1,13 +1,215 @@
 package repository
 
 const (
-	SelectAuthor = "SELECT id FROM author WHERE name = $1"
-	InsertAuthor = "INSERT INTO author (name) VALUES ($1)"
-	InsertBook   = "INSERT INTO user_book (name, date_published, isbn, page_count, user_id, author_id) VALUES ($1, $2, $3, $4, $5, $6)"
-	UpdateBook   = "UPDATE user_book SET name = $1, date_published = $2, isbn = $3, page_count = $4, author_id = $5 WHERE id = $6"
-	GetBook      = "SELECT b.name, b.date_published, b.isbn, b.page_count, a.name FROM user_book AS JOIN author AS a ON b.author_id = a.id WHERE b.id = $1"
-	GetAllBooks  = "SELECT b.name, b.date_published, b.isbn, b.page_count, a.name FROM user_book AS JOIN author AS a ON b.author_id = a.id ORDER BY a.id"
-	DeleteBook   = "DELETE FROM user_book WHERE id = $1"
-	IsAssigned   = "SELECT EXISTS (SELECT 1 FROM user_book WHERE id = $1 AND user_id = $2)"
-	CheckISBN    = "SELECT EXISTS (SELECT 1 FROM user_book WHERE isbn = $1)"
+	InsertBook        = "INSERT INTO user_book (name, date_published, isbn, page_count, user_id) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	InsertBookAuthor  = "INSERT INTO user_book_authors (user_book_id, author_id, role, position) VALUES ($1, $2, $3, $4)"
+	DeleteBookAuthors = "DELETE FROM user_book_authors WHERE user_book_id = $1"
+	UpdateBook        = "UPDATE user_book SET name = $1, date_published = $2, isbn = $3, page_count = $4, version = version + 1 WHERE id = $5 AND ($6 = 0 OR version = $6) RETURNING version"
+	GetBook           = "SELECT b.id, b.name, b.date_published, b.isbn, b.page_count, b.version, b.cover FROM user_book AS b WHERE b.id = $1 AND b.user_id = $2 AND b.deleted_at IS NULL"
+	GetBookAuthors    = "SELECT ba.user_book_id, a.id, a.name, ba.role FROM user_book_authors AS ba JOIN author AS a ON a.id = ba.author_id WHERE ba.user_book_id = ANY($1) ORDER BY ba.user_book_id, ba.position"
+	ListBooks         = "SELECT b.id, b.name, b.date_published, b.isbn, b.page_count, b.cover FROM user_book AS b"
+	CountBooks        = "SELECT COUNT(*) FROM user_book AS b"
+	ExportBooks       = ListBooks + " WHERE b.user_id = $1 AND b.deleted_at IS NULL AND b.id > $2 ORDER BY b.id ASC LIMIT $3"
+	BookExists        = "SELECT EXISTS (SELECT 1 FROM user_book WHERE id = $1 AND deleted_at IS NULL)"
+	DeleteBook        = "UPDATE user_book SET deleted_at = now(), version = version + 1 WHERE id = $1"
+	IsAssigned        = "SELECT EXISTS (SELECT 1 FROM user_book WHERE id = $1 AND user_id = $2)"
+	CheckISBN         = "SELECT EXISTS (SELECT 1 FROM user_book WHERE isbn = $1 AND deleted_at IS NULL)"
+
+	GetBookSnapshot   = "SELECT b.name, coalesce(to_char(b.date_published, 'YYYY-MM-DD'), ''), coalesce(b.isbn, ''), coalesce(b.page_count, 0), b.version FROM user_book AS b WHERE b.id = $1 FOR UPDATE"
+	InsertBookHistory = "INSERT INTO user_book_history (user_book_id, version, action, actor_id, before, after, changes) VALUES ($1, $2, $3, $4, $5, $6, $7)"
+	ListBookHistory   = "SELECT h.id, h.user_book_id, h.version, h.action, coalesce(h.actor_id, 0), h.changed_at, h.before, h.after, h.changes FROM user_book_history AS h WHERE h.user_book_id = $1 ORDER BY h.id DESC"
+	GetBookRevision   = "SELECT h.after FROM user_book_history AS h WHERE h.id = $1 AND h.user_book_id = $2"
+
+	LockReadingBook      = "SELECT coalesce(b.page_count, 0) FROM user_book AS b WHERE b.id = $1 AND b.user_id = $2 AND b.deleted_at IS NULL FOR UPDATE"
+	ListReadingSessions  = "SELECT rs.id, rs.status, rs.current_page, coalesce(to_char(rs.started_at, 'YYYY-MM-DD'), ''), coalesce(to_char(rs.finished_at, 'YYYY-MM-DD'), ''), rs.updated_at FROM reading_sessions AS rs WHERE rs.user_book_id = $1 ORDER BY rs.id DESC"
+	LatestReadingSession = ListReadingSessions + " LIMIT 1"
+	InsertReadingSession = "INSERT INTO reading_sessions (user_book_id, status, current_page, started_at, finished_at) VALUES ($1, $2, $3, $4, $5) RETURNING id, updated_at"
+	UpdateReadingSession = "UPDATE reading_sessions SET status = $1, current_page = $2, started_at = $3, finished_at = $4, updated_at = now() WHERE id = $5 RETURNING updated_at"
+
+	CheckBookCover  = "SELECT EXISTS (SELECT 1 FROM user_book WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)"
+	LockBookCover   = "SELECT coalesce(b.cover_keys, '{}') FROM user_book AS b WHERE b.id = $1 AND b.user_id = $2 AND b.deleted_at IS NULL FOR UPDATE"
+	UpdateBookCover = "UPDATE user_book SET cover = $1, cover_keys = $2 WHERE id = $3"
+
+	LookupCatalogISBN = "SELECT bk.name, coalesce(to_char(bk.date_published, 'YYYY-MM-DD'), ''), coalesce(bk.page_count, 0), coalesce(array_agg(a.name ORDER BY a.id) FILTER (WHERE a.id IS NOT NULL), '{}') FROM book AS bk LEFT JOIN book_authors AS ba ON ba.book_id = bk.id LEFT JOIN author AS a ON a.id = ba.author_id WHERE bk.isbn = $1 GROUP BY bk.id ORDER BY bk.id LIMIT 1"
+
+	ListTrash         = "SELECT b.id, b.name, b.date_published, b.isbn, b.page_count, b.deleted_at FROM user_book AS b WHERE b.user_id = $1 AND b.deleted_at IS NOT NULL ORDER BY b.deleted_at DESC, b.id"
+	RestoreBook       = "UPDATE user_book SET deleted_at = NULL, version = version + 1 WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL"
+	CheckRestoredISBN = "SELECT EXISTS (SELECT 1 FROM user_book AS b JOIN user_book AS r ON r.isbn = b.isbn WHERE r.id = $1 AND r.isbn <> '' AND b.id <> r.id AND b.deleted_at IS NULL)"
+	PurgeTrash        = "DELETE FROM user_book WHERE deleted_at < $1 RETURNING coalesce(cover_keys, '{}')"
+
+	SavepointImportRow = "SAVEPOINT import_row"
+	ReleaseImportRow   = "RELEASE SAVEPOINT import_row"
+	RollbackImportRow  = "ROLLBACK TO SAVEPOINT import_row"
+
+	SearchBooks = `
+		SELECT
+			source,
+			id,
+			name,
+			isbn,
+			authors,
+			ts_rank(document, query) AS rank,
+			ts_headline('simple', name || ' ' || authors, query, E'StartSel=\uE000, StopSel=\uE001') AS highlight
+		FROM (
+			SELECT
+				'personal' AS source,
+				b.id,
+				b.name,
+				coalesce(b.isbn, '') AS isbn,
+				coalesce(string_agg(a.name, ', ' ORDER BY ba.position), '') AS authors,
+				b.search_vector || to_tsvector('simple', coalesce(string_agg(a.name, ' '), '')) AS document
+			FROM
+				user_book AS b
+				LEFT JOIN user_book_authors AS ba ON ba.user_book_id = b.id
+				LEFT JOIN author AS a ON a.id = ba.author_id
+			WHERE
+				b.user_id = $2
+				AND b.deleted_at IS NULL
+				AND (
+					b.search_vector @@ to_tsquery('simple', $1)
+					OR b.id IN (
+						SELECT matched.user_book_id
+						FROM user_book_authors AS matched
+						JOIN author AS matched_author ON matched_author.id = matched.author_id
+						WHERE matched_author.search_vector @@ to_tsquery('simple', $1)
+					)
+				)
+			GROUP BY
+				b.id
+			UNION ALL
+			SELECT
+				'shop' AS source,
+				bk.id,
+				bk.name,
+				coalesce(bk.isbn, '') AS isbn,
+				coalesce(string_agg(a.name, ', ' ORDER BY a.name), '') AS authors,
+				bk.search_vector || to_tsvector('simple', coalesce(string_agg(a.name, ' '), '')) AS document
+			FROM
+				book AS bk
+				LEFT JOIN book_authors AS ba ON ba.book_id = bk.id
+				LEFT JOIN author AS a ON a.id = ba.author_id
+			WHERE
+				bk.search_vector @@ to_tsquery('simple', $1)
+				OR bk.id IN (
+					SELECT matched.book_id
+					FROM book_authors AS matched
+					JOIN author AS matched_author ON matched_author.id = matched.author_id
+					WHERE matched_author.search_vector @@ to_tsquery('simple', $1)
+				)
+			GROUP BY
+				bk.id
+		) AS results, to_tsquery('simple', $1) AS query
+		ORDER BY
+			rank DESC, source, id
+		LIMIT $3
+	`
+)
+
+const (
+	selectAuthor = `
+		SELECT
+			a.id,
+			a.name,
+			coalesce(a.biography, ''),
+			(SELECT COUNT(*) FROM user_book_authors AS ba WHERE ba.author_id = a.id)
+				+ (SELECT COUNT(*) FROM book_authors AS sa WHERE sa.author_id = a.id)
+		FROM
+			author AS a`
+
+	ListAuthors     = selectAuthor + " WHERE a.name ILIKE $1 ORDER BY a.name, a.id LIMIT $2 OFFSET $3"
+	CountAuthors    = "SELECT COUNT(*) FROM author AS a WHERE a.name ILIKE $1"
+	GetAuthor       = selectAuthor + " WHERE a.id = $1"
+	UpdateAuthor    = "UPDATE author SET name = $1, biography = $2 WHERE id = $3"
+	AuthorNameTaken = "SELECT EXISTS (SELECT 1 FROM author WHERE name_key = regexp_replace(lower($1), '[^[:alnum:]]+', '', 'g') AND id <> $2)"
+
+	SuggestDuplicateAuthors = `
+		SELECT
+			d.id,
+			d.name,
+			similarity(d.name, a.name) AS score
+		FROM
+			author AS a
+			JOIN author AS d ON d.id <> a.id AND (d.name_key = a.name_key OR d.name % a.name)
+		WHERE
+			a.id = $1
+		ORDER BY
+			score DESC, d.id
+		LIMIT $2
+	`
+
+	LockAuthors = "SELECT id FROM author WHERE id = ANY($1) ORDER BY id FOR UPDATE"
+
+	// a book keeps only its first credit among the merged authors
+	DropMergedUserBookCredits = `
+		DELETE FROM user_book_authors AS d
+		USING user_book_authors AS s
+		WHERE
+			s.user_book_id = d.user_book_id
+			AND s.position < d.position
+			AND (d.author_id = $1 OR d.author_id = ANY($2))
+			AND (s.author_id = $1 OR s.author_id = ANY($2))
+	`
+	MoveUserBookCredits = "UPDATE user_book_authors SET author_id = $1 WHERE author_id = ANY($2)"
+
+	DropMergedShopCredits = `
+		DELETE FROM book_authors AS d
+		USING book_authors AS s
+		WHERE
+			s.book_id = d.book_id
+			AND d.author_id = ANY($2)
+			AND (s.author_id = $1 OR (s.author_id = ANY($2) AND s.author_id < d.author_id))
+	`
+	MoveShopCredits = "UPDATE book_authors SET author_id = $1 WHERE author_id = ANY($2)"
+
+	MergeBiography = `
+		UPDATE author
+		SET biography = (
+			SELECT d.biography
+			FROM author AS d
+			WHERE d.id = ANY($2) AND coalesce(d.biography, '') <> ''
+			ORDER BY d.id
+			LIMIT 1
+		)
+		WHERE id = $1 AND coalesce(biography, '') = ''
+	`
+	DeleteAuthors = "DELETE FROM author WHERE id = ANY($1)"
+)
+
+const (
+	selectShelf = `
+		SELECT
+			s.id,
+			s.user_id,
+			s.name,
+			coalesce(s.description, ''),
+			s.public,
+			(SELECT COUNT(*) FROM shelf_books AS sb JOIN user_book AS b ON b.id = sb.user_book_id WHERE sb.shelf_id = s.id AND b.deleted_at IS NULL),
+			s.created_at
+		FROM
+			shelf AS s`
+
+	ListShelves    = selectShelf + " WHERE s.user_id = $1 ORDER BY s.name, s.id"
+	GetShelf       = selectShelf + " WHERE s.id = $1"
+	ShelfNameTaken = "SELECT EXISTS (SELECT 1 FROM shelf WHERE user_id = $1 AND lower(name) = lower($2) AND id <> $3)"
+	InsertShelf    = "INSERT INTO shelf (user_id, name, description, public) VALUES ($1, $2, $3, $4) RETURNING id"
+	UpdateShelf    = "UPDATE shelf SET name = $1, description = $2, public = $3 WHERE id = $4 AND user_id = $5"
+	DeleteShelf    = "DELETE FROM shelf WHERE id = $1 AND user_id = $2"
+	ShelfBooks     = ListBooks + " JOIN shelf_books AS sb ON sb.user_book_id = b.id WHERE sb.shelf_id = $1 AND b.deleted_at IS NULL ORDER BY sb.position, sb.added_at"
+
+	LockShelf          = "SELECT id FROM shelf WHERE id = $1 AND user_id = $2 FOR UPDATE"
+	IsShelvable        = "SELECT EXISTS (SELECT 1 FROM user_book WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)"
+	IsOnShelf          = "SELECT EXISTS (SELECT 1 FROM shelf_books WHERE shelf_id = $1 AND user_book_id = $2)"
+	NextShelfPosition  = "SELECT coalesce(max(position), 0) + 1 FROM shelf_books WHERE shelf_id = $1"
+	OpenShelfPosition  = "UPDATE shelf_books SET position = position + 1 WHERE shelf_id = $1 AND position >= $2"
+	InsertShelfBook    = "INSERT INTO shelf_books (shelf_id, user_book_id, position) VALUES ($1, $2, $3)"
+	DeleteShelfBook    = "DELETE FROM shelf_books WHERE shelf_id = $1 AND user_book_id = $2 RETURNING position"
+	CloseShelfPosition = "UPDATE shelf_books SET position = position - 1 WHERE shelf_id = $1 AND position > $2"
+	ListShelfBookIDs   = "SELECT sb.user_book_id FROM shelf_books AS sb JOIN user_book AS b ON b.id = sb.user_book_id WHERE sb.shelf_id = $1 AND b.deleted_at IS NULL"
+	MoveShelfBooks     = "UPDATE shelf_books SET position = position + $2 WHERE shelf_id = $1"
+	ReorderShelfBooks  = `
+		UPDATE shelf_books AS sb
+		SET position = o.position
+		FROM unnest($2::int[]) WITH ORDINALITY AS o (user_book_id, position)
+		WHERE sb.shelf_id = $1 AND sb.user_book_id = o.user_book_id
+	`
 )
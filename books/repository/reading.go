@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"library/books/models"
+	"library/pkg/utils"
+	"time"
+)
+
+var ErrInvalidProgress = errors.New("invalid reading progress")
+
+func (b *BookRepository) GetReading(bookID, userID int) (*models.ReadingResponse, error) {
+	reading := &models.ReadingResponse{Sessions: []models.ReadingSession{}}
+
+	log := utils.GetLogger(b.ctx)
+
+	isBookAssigned, err := isAssigned(log, bookID, userID, b.DB.GetDB())
+	if err != nil {
+		log.Errorf("Error checking book assignment: %v", err)
+		return reading, err
+	}
+
+	if !isBookAssigned {
+		log.Warningf("Book %v does not belong to user %v", bookID, userID)
+		return reading, ErrBookNotFound
+	}
+
+	rows, err := b.DB.DB.Query(ListReadingSessions, bookID)
+	if err != nil {
+		log.Errorf("Failed to perform a query on reading sessions table: %v", err)
+		return reading, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var session models.ReadingSession
+
+		err = rows.Scan(&session.ID, &session.Status, &session.CurrentPage, &session.StartedAt, &session.FinishedAt, &session.UpdatedAt)
+		if err != nil {
+			log.Errorf("Failed to scan rows: %v", err)
+			return reading, err
+		}
+
+		if session.Status == models.ReadingStatusFinished {
+			reading.ReadCount++
+		}
+
+		reading.Sessions = append(reading.Sessions, session)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Errorf("Query failed: %v", err)
+		return reading, err
+	}
+
+	if len(reading.Sessions) > 0 {
+		reading.Current = &reading.Sessions[0]
+	}
+
+	return reading, nil
+}
+
+// UpdateReading applies the progress to the latest reading session of the book. Moving a
+// finished or abandoned book back to reading or want to read starts a new session, so
+// re-reads keep the dates of the previous ones.
+func (b *BookRepository) UpdateReading(bookID, userID int, progress *models.ReadingProgressRequest) (*models.ReadingSession, error) {
+	log := utils.GetLogger(b.ctx)
+
+	tx, err := b.DB.DB.Begin()
+	if err != nil {
+		log.Errorf("Failed to begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var pageCount int
+	err = tx.QueryRow(LockReadingBook, bookID, userID).Scan(&pageCount)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("Book %v does not belong to user %v", bookID, userID)
+		return nil, ErrBookNotFound
+	}
+
+	if err != nil {
+		log.Errorf("Query row on user book table failed: %v", err)
+		return nil, err
+	}
+
+	session := &models.ReadingSession{}
+	err = tx.QueryRow(LatestReadingSession, bookID).Scan(
+		&session.ID,
+		&session.Status,
+		&session.CurrentPage,
+		&session.StartedAt,
+		&session.FinishedAt,
+		&session.UpdatedAt,
+	)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Errorf("Query row on reading sessions table failed: %v", err)
+		return nil, err
+	}
+
+	rereading := progress.Status == models.ReadingStatusReading || progress.Status == models.ReadingStatusWantToRead
+	if errors.Is(err, sql.ErrNoRows) || (session.Done() && rereading) {
+		session = &models.ReadingSession{Status: models.ReadingStatusReading}
+	}
+
+	if err = applyProgress(session, progress, pageCount); err != nil {
+		log.Warningf("Invalid reading progress for book %v: %v", bookID, err)
+		return nil, err
+	}
+
+	if session.ID == 0 {
+		err = tx.QueryRow(
+			InsertReadingSession,
+			bookID,
+			session.Status,
+			session.CurrentPage,
+			nullDate(session.StartedAt),
+			nullDate(session.FinishedAt),
+		).Scan(&session.ID, &session.UpdatedAt)
+	} else {
+		err = tx.QueryRow(
+			UpdateReadingSession,
+			session.Status,
+			session.CurrentPage,
+			nullDate(session.StartedAt),
+			nullDate(session.FinishedAt),
+			session.ID,
+		).Scan(&session.UpdatedAt)
+	}
+
+	if err != nil {
+		log.Errorf("Failed to save reading session: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Errorf("Failed to commit transaction: %v", err)
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// applyProgress copies the requested progress onto the session, fills in the dates
+// implied by the status and checks the result against the book page count.
+func applyProgress(session *models.ReadingSession, progress *models.ReadingProgressRequest, pageCount int) error {
+	today := time.Now().Format(time.DateOnly)
+
+	if progress.Status != "" {
+		session.Status = progress.Status
+	}
+
+	if progress.StartedAt != "" {
+		session.StartedAt = progress.StartedAt
+	}
+
+	if progress.FinishedAt != "" {
+		session.FinishedAt = progress.FinishedAt
+	}
+
+	if progress.CurrentPage != nil {
+		session.CurrentPage = *progress.CurrentPage
+	}
+
+	switch session.Status {
+	case models.ReadingStatusReading:
+		if session.StartedAt == "" {
+			session.StartedAt = today
+		}
+	case models.ReadingStatusFinished:
+		if session.FinishedAt == "" {
+			session.FinishedAt = today
+		}
+
+		if progress.CurrentPage == nil && pageCount > 0 {
+			session.CurrentPage = pageCount
+		}
+	}
+
+	if pageCount > 0 && session.CurrentPage > pageCount {
+		return fmt.Errorf("%w: current page must be between 0 and %d", ErrInvalidProgress, pageCount)
+	}
+
+	if session.StartedAt != "" && session.FinishedAt != "" && session.FinishedAt < session.StartedAt {
+		return fmt.Errorf("%w: finished_at cannot be before started_at", ErrInvalidProgress)
+	}
+
+	return nil
+}
+
+func nullDate(date string) interface{} {
+	if date == "" {
+		return nil
+	}
+
+	return date
+}
@@ -4,15 +4,16 @@ package repositoryfakes
 import (
 	"library/books/models"
 	"library/books/repository"
-	"library/pkg/postgres"
 	"sync"
+	"time"
 )
 
 type FakeBookerRepository struct {
-	AddBookStub        func(*models.BookRequest) (int, error)
+	AddBookStub        func(*models.BookRequest, int) (int, error)
 	addBookMutex       sync.RWMutex
 	addBookArgsForCall []struct {
 		arg1 *models.BookRequest
+		arg2 int
 	}
 	addBookReturns struct {
 		result1 int
@@ -22,11 +23,24 @@ type FakeBookerRepository struct {
 		result1 int
 		result2 error
 	}
-	DeleteBookStub        func(int, int) (int, error)
+	CheckCoverStub        func(int, int) error
+	checkCoverMutex       sync.RWMutex
+	checkCoverArgsForCall []struct {
+		arg1 int
+		arg2 int
+	}
+	checkCoverReturns struct {
+		result1 error
+	}
+	checkCoverReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteBookStub        func(int, int, int) (int, error)
 	deleteBookMutex       sync.RWMutex
 	deleteBookArgsForCall []struct {
 		arg1 int
 		arg2 int
+		arg3 int
 	}
 	deleteBookReturns struct {
 		result1 int
@@ -36,22 +50,24 @@ type FakeBookerRepository struct {
 		result1 int
 		result2 error
 	}
-	GetAllBooksStub        func() ([]models.BookResponse, error)
-	getAllBooksMutex       sync.RWMutex
-	getAllBooksArgsForCall []struct {
+	ExportBooksStub        func(int, int, func([]models.BookResponse) error) error
+	exportBooksMutex       sync.RWMutex
+	exportBooksArgsForCall []struct {
+		arg1 int
+		arg2 int
+		arg3 func([]models.BookResponse) error
 	}
-	getAllBooksReturns struct {
-		result1 []models.BookResponse
-		result2 error
+	exportBooksReturns struct {
+		result1 error
 	}
-	getAllBooksReturnsOnCall map[int]struct {
-		result1 []models.BookResponse
-		result2 error
+	exportBooksReturnsOnCall map[int]struct {
+		result1 error
 	}
-	GetBookStub        func(int) (*models.BookResponse, error)
+	GetBookStub        func(int, int) (*models.BookResponse, error)
 	getBookMutex       sync.RWMutex
 	getBookArgsForCall []struct {
 		arg1 int
+		arg2 int
 	}
 	getBookReturns struct {
 		result1 *models.BookResponse
@@ -61,20 +77,171 @@ type FakeBookerRepository struct {
 		result1 *models.BookResponse
 		result2 error
 	}
-	GetDBPoolStub        func() postgres.DB
-	getDBPoolMutex       sync.RWMutex
-	getDBPoolArgsForCall []struct {
+	GetReadingStub        func(int, int) (*models.ReadingResponse, error)
+	getReadingMutex       sync.RWMutex
+	getReadingArgsForCall []struct {
+		arg1 int
+		arg2 int
+	}
+	getReadingReturns struct {
+		result1 *models.ReadingResponse
+		result2 error
+	}
+	getReadingReturnsOnCall map[int]struct {
+		result1 *models.ReadingResponse
+		result2 error
+	}
+	ImportBooksStub        func(int, int, []models.ImportRow, string) (*models.ImportReport, error)
+	importBooksMutex       sync.RWMutex
+	importBooksArgsForCall []struct {
+		arg1 int
+		arg2 int
+		arg3 []models.ImportRow
+		arg4 string
+	}
+	importBooksReturns struct {
+		result1 *models.ImportReport
+		result2 error
+	}
+	importBooksReturnsOnCall map[int]struct {
+		result1 *models.ImportReport
+		result2 error
+	}
+	ListBooksStub        func(int, *models.BookFilter) (*models.BookListResponse, error)
+	listBooksMutex       sync.RWMutex
+	listBooksArgsForCall []struct {
+		arg1 int
+		arg2 *models.BookFilter
+	}
+	listBooksReturns struct {
+		result1 *models.BookListResponse
+		result2 error
+	}
+	listBooksReturnsOnCall map[int]struct {
+		result1 *models.BookListResponse
+		result2 error
+	}
+	ListHistoryStub        func(int, int) ([]models.BookRevision, error)
+	listHistoryMutex       sync.RWMutex
+	listHistoryArgsForCall []struct {
+		arg1 int
+		arg2 int
+	}
+	listHistoryReturns struct {
+		result1 []models.BookRevision
+		result2 error
+	}
+	listHistoryReturnsOnCall map[int]struct {
+		result1 []models.BookRevision
+		result2 error
+	}
+	ListTrashStub        func(int) ([]models.BookResponse, error)
+	listTrashMutex       sync.RWMutex
+	listTrashArgsForCall []struct {
+		arg1 int
+	}
+	listTrashReturns struct {
+		result1 []models.BookResponse
+		result2 error
+	}
+	listTrashReturnsOnCall map[int]struct {
+		result1 []models.BookResponse
+		result2 error
 	}
-	getDBPoolReturns struct {
-		result1 postgres.DB
+	LookupISBNStub        func(string) (*models.BookRequest, error)
+	lookupISBNMutex       sync.RWMutex
+	lookupISBNArgsForCall []struct {
+		arg1 string
 	}
-	getDBPoolReturnsOnCall map[int]struct {
-		result1 postgres.DB
+	lookupISBNReturns struct {
+		result1 *models.BookRequest
+		result2 error
+	}
+	lookupISBNReturnsOnCall map[int]struct {
+		result1 *models.BookRequest
+		result2 error
+	}
+	PurgeTrashStub        func(time.Time) (int64, []string, error)
+	purgeTrashMutex       sync.RWMutex
+	purgeTrashArgsForCall []struct {
+		arg1 time.Time
+	}
+	purgeTrashReturns struct {
+		result1 int64
+		result2 []string
+		result3 error
+	}
+	purgeTrashReturnsOnCall map[int]struct {
+		result1 int64
+		result2 []string
+		result3 error
+	}
+	RestoreBookStub        func(int, int, int) (int, error)
+	restoreBookMutex       sync.RWMutex
+	restoreBookArgsForCall []struct {
+		arg1 int
+		arg2 int
+		arg3 int
+	}
+	restoreBookReturns struct {
+		result1 int
+		result2 error
+	}
+	restoreBookReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
 	}
-	UpdateBookStub        func(*models.BookRequest) (*models.BookResponse, error)
+	RevertBookStub        func(int, int, int, int) (*models.BookResponse, error)
+	revertBookMutex       sync.RWMutex
+	revertBookArgsForCall []struct {
+		arg1 int
+		arg2 int
+		arg3 int
+		arg4 int
+	}
+	revertBookReturns struct {
+		result1 *models.BookResponse
+		result2 error
+	}
+	revertBookReturnsOnCall map[int]struct {
+		result1 *models.BookResponse
+		result2 error
+	}
+	SearchBooksStub        func(int, *models.SearchRequest) ([]models.SearchResult, error)
+	searchBooksMutex       sync.RWMutex
+	searchBooksArgsForCall []struct {
+		arg1 int
+		arg2 *models.SearchRequest
+	}
+	searchBooksReturns struct {
+		result1 []models.SearchResult
+		result2 error
+	}
+	searchBooksReturnsOnCall map[int]struct {
+		result1 []models.SearchResult
+		result2 error
+	}
+	SetCoverStub        func(int, int, models.Cover, []string) ([]string, error)
+	setCoverMutex       sync.RWMutex
+	setCoverArgsForCall []struct {
+		arg1 int
+		arg2 int
+		arg3 models.Cover
+		arg4 []string
+	}
+	setCoverReturns struct {
+		result1 []string
+		result2 error
+	}
+	setCoverReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	UpdateBookStub        func(*models.BookRequest, int) (*models.BookResponse, error)
 	updateBookMutex       sync.RWMutex
 	updateBookArgsForCall []struct {
 		arg1 *models.BookRequest
+		arg2 int
 	}
 	updateBookReturns struct {
 		result1 *models.BookResponse
@@ -84,22 +251,38 @@ type FakeBookerRepository struct {
 		result1 *models.BookResponse
 		result2 error
 	}
+	UpdateReadingStub        func(int, int, *models.ReadingProgressRequest) (*models.ReadingSession, error)
+	updateReadingMutex       sync.RWMutex
+	updateReadingArgsForCall []struct {
+		arg1 int
+		arg2 int
+		arg3 *models.ReadingProgressRequest
+	}
+	updateReadingReturns struct {
+		result1 *models.ReadingSession
+		result2 error
+	}
+	updateReadingReturnsOnCall map[int]struct {
+		result1 *models.ReadingSession
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeBookerRepository) AddBook(arg1 *models.BookRequest) (int, error) {
+func (fake *FakeBookerRepository) AddBook(arg1 *models.BookRequest, arg2 int) (int, error) {
 	fake.addBookMutex.Lock()
 	ret, specificReturn := fake.addBookReturnsOnCall[len(fake.addBookArgsForCall)]
 	fake.addBookArgsForCall = append(fake.addBookArgsForCall, struct {
 		arg1 *models.BookRequest
-	}{arg1})
+		arg2 int
+	}{arg1, arg2})
 	stub := fake.AddBookStub
 	fakeReturns := fake.addBookReturns
-	fake.recordInvocation("AddBook", []interface{}{arg1})
+	fake.recordInvocation("AddBook", []interface{}{arg1, arg2})
 	fake.addBookMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -113,17 +296,17 @@ func (fake *FakeBookerRepository) AddBookCallCount() int {
 	return len(fake.addBookArgsForCall)
 }
 
-func (fake *FakeBookerRepository) AddBookCalls(stub func(*models.BookRequest) (int, error)) {
+func (fake *FakeBookerRepository) AddBookCalls(stub func(*models.BookRequest, int) (int, error)) {
 	fake.addBookMutex.Lock()
 	defer fake.addBookMutex.Unlock()
 	fake.AddBookStub = stub
 }
 
-func (fake *FakeBookerRepository) AddBookArgsForCall(i int) *models.BookRequest {
+func (fake *FakeBookerRepository) AddBookArgsForCall(i int) (*models.BookRequest, int) {
 	fake.addBookMutex.RLock()
 	defer fake.addBookMutex.RUnlock()
 	argsForCall := fake.addBookArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeBookerRepository) AddBookReturns(result1 int, result2 error) {
@@ -152,19 +335,82 @@ func (fake *FakeBookerRepository) AddBookReturnsOnCall(i int, result1 int, resul
 	}{result1, result2}
 }
 
-func (fake *FakeBookerRepository) DeleteBook(arg1 int, arg2 int) (int, error) {
+func (fake *FakeBookerRepository) CheckCover(arg1 int, arg2 int) error {
+	fake.checkCoverMutex.Lock()
+	ret, specificReturn := fake.checkCoverReturnsOnCall[len(fake.checkCoverArgsForCall)]
+	fake.checkCoverArgsForCall = append(fake.checkCoverArgsForCall, struct {
+		arg1 int
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.CheckCoverStub
+	fakeReturns := fake.checkCoverReturns
+	fake.recordInvocation("CheckCover", []interface{}{arg1, arg2})
+	fake.checkCoverMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeBookerRepository) CheckCoverCallCount() int {
+	fake.checkCoverMutex.RLock()
+	defer fake.checkCoverMutex.RUnlock()
+	return len(fake.checkCoverArgsForCall)
+}
+
+func (fake *FakeBookerRepository) CheckCoverCalls(stub func(int, int) error) {
+	fake.checkCoverMutex.Lock()
+	defer fake.checkCoverMutex.Unlock()
+	fake.CheckCoverStub = stub
+}
+
+func (fake *FakeBookerRepository) CheckCoverArgsForCall(i int) (int, int) {
+	fake.checkCoverMutex.RLock()
+	defer fake.checkCoverMutex.RUnlock()
+	argsForCall := fake.checkCoverArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBookerRepository) CheckCoverReturns(result1 error) {
+	fake.checkCoverMutex.Lock()
+	defer fake.checkCoverMutex.Unlock()
+	fake.CheckCoverStub = nil
+	fake.checkCoverReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBookerRepository) CheckCoverReturnsOnCall(i int, result1 error) {
+	fake.checkCoverMutex.Lock()
+	defer fake.checkCoverMutex.Unlock()
+	fake.CheckCoverStub = nil
+	if fake.checkCoverReturnsOnCall == nil {
+		fake.checkCoverReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.checkCoverReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBookerRepository) DeleteBook(arg1 int, arg2 int, arg3 int) (int, error) {
 	fake.deleteBookMutex.Lock()
 	ret, specificReturn := fake.deleteBookReturnsOnCall[len(fake.deleteBookArgsForCall)]
 	fake.deleteBookArgsForCall = append(fake.deleteBookArgsForCall, struct {
 		arg1 int
 		arg2 int
-	}{arg1, arg2})
+		arg3 int
+	}{arg1, arg2, arg3})
 	stub := fake.DeleteBookStub
 	fakeReturns := fake.deleteBookReturns
-	fake.recordInvocation("DeleteBook", []interface{}{arg1, arg2})
+	fake.recordInvocation("DeleteBook", []interface{}{arg1, arg2, arg3})
 	fake.deleteBookMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -178,17 +424,17 @@ func (fake *FakeBookerRepository) DeleteBookCallCount() int {
 	return len(fake.deleteBookArgsForCall)
 }
 
-func (fake *FakeBookerRepository) DeleteBookCalls(stub func(int, int) (int, error)) {
+func (fake *FakeBookerRepository) DeleteBookCalls(stub func(int, int, int) (int, error)) {
 	fake.deleteBookMutex.Lock()
 	defer fake.deleteBookMutex.Unlock()
 	fake.DeleteBookStub = stub
 }
 
-func (fake *FakeBookerRepository) DeleteBookArgsForCall(i int) (int, int) {
+func (fake *FakeBookerRepository) DeleteBookArgsForCall(i int) (int, int, int) {
 	fake.deleteBookMutex.RLock()
 	defer fake.deleteBookMutex.RUnlock()
 	argsForCall := fake.deleteBookArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeBookerRepository) DeleteBookReturns(result1 int, result2 error) {
@@ -217,74 +463,82 @@ func (fake *FakeBookerRepository) DeleteBookReturnsOnCall(i int, result1 int, re
 	}{result1, result2}
 }
 
-func (fake *FakeBookerRepository) GetAllBooks() ([]models.BookResponse, error) {
-	fake.getAllBooksMutex.Lock()
-	ret, specificReturn := fake.getAllBooksReturnsOnCall[len(fake.getAllBooksArgsForCall)]
-	fake.getAllBooksArgsForCall = append(fake.getAllBooksArgsForCall, struct {
-	}{})
-	stub := fake.GetAllBooksStub
-	fakeReturns := fake.getAllBooksReturns
-	fake.recordInvocation("GetAllBooks", []interface{}{})
-	fake.getAllBooksMutex.Unlock()
+func (fake *FakeBookerRepository) ExportBooks(arg1 int, arg2 int, arg3 func([]models.BookResponse) error) error {
+	fake.exportBooksMutex.Lock()
+	ret, specificReturn := fake.exportBooksReturnsOnCall[len(fake.exportBooksArgsForCall)]
+	fake.exportBooksArgsForCall = append(fake.exportBooksArgsForCall, struct {
+		arg1 int
+		arg2 int
+		arg3 func([]models.BookResponse) error
+	}{arg1, arg2, arg3})
+	stub := fake.ExportBooksStub
+	fakeReturns := fake.exportBooksReturns
+	fake.recordInvocation("ExportBooks", []interface{}{arg1, arg2, arg3})
+	fake.exportBooksMutex.Unlock()
 	if stub != nil {
-		return stub()
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1
 }
 
-func (fake *FakeBookerRepository) GetAllBooksCallCount() int {
-	fake.getAllBooksMutex.RLock()
-	defer fake.getAllBooksMutex.RUnlock()
-	return len(fake.getAllBooksArgsForCall)
+func (fake *FakeBookerRepository) ExportBooksCallCount() int {
+	fake.exportBooksMutex.RLock()
+	defer fake.exportBooksMutex.RUnlock()
+	return len(fake.exportBooksArgsForCall)
 }
 
-func (fake *FakeBookerRepository) GetAllBooksCalls(stub func() ([]models.BookResponse, error)) {
-	fake.getAllBooksMutex.Lock()
-	defer fake.getAllBooksMutex.Unlock()
-	fake.GetAllBooksStub = stub
+func (fake *FakeBookerRepository) ExportBooksCalls(stub func(int, int, func([]models.BookResponse) error) error) {
+	fake.exportBooksMutex.Lock()
+	defer fake.exportBooksMutex.Unlock()
+	fake.ExportBooksStub = stub
 }
 
-func (fake *FakeBookerRepository) GetAllBooksReturns(result1 []models.BookResponse, result2 error) {
-	fake.getAllBooksMutex.Lock()
-	defer fake.getAllBooksMutex.Unlock()
-	fake.GetAllBooksStub = nil
-	fake.getAllBooksReturns = struct {
-		result1 []models.BookResponse
-		result2 error
-	}{result1, result2}
+func (fake *FakeBookerRepository) ExportBooksArgsForCall(i int) (int, int, func([]models.BookResponse) error) {
+	fake.exportBooksMutex.RLock()
+	defer fake.exportBooksMutex.RUnlock()
+	argsForCall := fake.exportBooksArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeBookerRepository) GetAllBooksReturnsOnCall(i int, result1 []models.BookResponse, result2 error) {
-	fake.getAllBooksMutex.Lock()
-	defer fake.getAllBooksMutex.Unlock()
-	fake.GetAllBooksStub = nil
-	if fake.getAllBooksReturnsOnCall == nil {
-		fake.getAllBooksReturnsOnCall = make(map[int]struct {
-			result1 []models.BookResponse
-			result2 error
+func (fake *FakeBookerRepository) ExportBooksReturns(result1 error) {
+	fake.exportBooksMutex.Lock()
+	defer fake.exportBooksMutex.Unlock()
+	fake.ExportBooksStub = nil
+	fake.exportBooksReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBookerRepository) ExportBooksReturnsOnCall(i int, result1 error) {
+	fake.exportBooksMutex.Lock()
+	defer fake.exportBooksMutex.Unlock()
+	fake.ExportBooksStub = nil
+	if fake.exportBooksReturnsOnCall == nil {
+		fake.exportBooksReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.getAllBooksReturnsOnCall[i] = struct {
-		result1 []models.BookResponse
-		result2 error
-	}{result1, result2}
+	fake.exportBooksReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeBookerRepository) GetBook(arg1 int) (*models.BookResponse, error) {
+func (fake *FakeBookerRepository) GetBook(arg1 int, arg2 int) (*models.BookResponse, error) {
 	fake.getBookMutex.Lock()
 	ret, specificReturn := fake.getBookReturnsOnCall[len(fake.getBookArgsForCall)]
 	fake.getBookArgsForCall = append(fake.getBookArgsForCall, struct {
 		arg1 int
-	}{arg1})
+		arg2 int
+	}{arg1, arg2})
 	stub := fake.GetBookStub
 	fakeReturns := fake.getBookReturns
-	fake.recordInvocation("GetBook", []interface{}{arg1})
+	fake.recordInvocation("GetBook", []interface{}{arg1, arg2})
 	fake.getBookMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -298,17 +552,17 @@ func (fake *FakeBookerRepository) GetBookCallCount() int {
 	return len(fake.getBookArgsForCall)
 }
 
-func (fake *FakeBookerRepository) GetBookCalls(stub func(int) (*models.BookResponse, error)) {
+func (fake *FakeBookerRepository) GetBookCalls(stub func(int, int) (*models.BookResponse, error)) {
 	fake.getBookMutex.Lock()
 	defer fake.getBookMutex.Unlock()
 	fake.GetBookStub = stub
 }
 
-func (fake *FakeBookerRepository) GetBookArgsForCall(i int) int {
+func (fake *FakeBookerRepository) GetBookArgsForCall(i int) (int, int) {
 	fake.getBookMutex.RLock()
 	defer fake.getBookMutex.RUnlock()
 	argsForCall := fake.getBookArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeBookerRepository) GetBookReturns(result1 *models.BookResponse, result2 error) {
@@ -337,71 +591,751 @@ func (fake *FakeBookerRepository) GetBookReturnsOnCall(i int, result1 *models.Bo
 	}{result1, result2}
 }
 
-func (fake *FakeBookerRepository) GetDBPool() postgres.DB {
-	fake.getDBPoolMutex.Lock()
-	ret, specificReturn := fake.getDBPoolReturnsOnCall[len(fake.getDBPoolArgsForCall)]
-	fake.getDBPoolArgsForCall = append(fake.getDBPoolArgsForCall, struct {
-	}{})
-	stub := fake.GetDBPoolStub
-	fakeReturns := fake.getDBPoolReturns
-	fake.recordInvocation("GetDBPool", []interface{}{})
-	fake.getDBPoolMutex.Unlock()
+func (fake *FakeBookerRepository) GetReading(arg1 int, arg2 int) (*models.ReadingResponse, error) {
+	fake.getReadingMutex.Lock()
+	ret, specificReturn := fake.getReadingReturnsOnCall[len(fake.getReadingArgsForCall)]
+	fake.getReadingArgsForCall = append(fake.getReadingArgsForCall, struct {
+		arg1 int
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.GetReadingStub
+	fakeReturns := fake.getReadingReturns
+	fake.recordInvocation("GetReading", []interface{}{arg1, arg2})
+	fake.getReadingMutex.Unlock()
 	if stub != nil {
-		return stub()
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeBookerRepository) GetDBPoolCallCount() int {
-	fake.getDBPoolMutex.RLock()
-	defer fake.getDBPoolMutex.RUnlock()
-	return len(fake.getDBPoolArgsForCall)
+func (fake *FakeBookerRepository) GetReadingCallCount() int {
+	fake.getReadingMutex.RLock()
+	defer fake.getReadingMutex.RUnlock()
+	return len(fake.getReadingArgsForCall)
 }
 
-func (fake *FakeBookerRepository) GetDBPoolCalls(stub func() postgres.DB) {
-	fake.getDBPoolMutex.Lock()
-	defer fake.getDBPoolMutex.Unlock()
-	fake.GetDBPoolStub = stub
+func (fake *FakeBookerRepository) GetReadingCalls(stub func(int, int) (*models.ReadingResponse, error)) {
+	fake.getReadingMutex.Lock()
+	defer fake.getReadingMutex.Unlock()
+	fake.GetReadingStub = stub
 }
 
-func (fake *FakeBookerRepository) GetDBPoolReturns(result1 postgres.DB) {
-	fake.getDBPoolMutex.Lock()
-	defer fake.getDBPoolMutex.Unlock()
-	fake.GetDBPoolStub = nil
-	fake.getDBPoolReturns = struct {
-		result1 postgres.DB
-	}{result1}
+func (fake *FakeBookerRepository) GetReadingArgsForCall(i int) (int, int) {
+	fake.getReadingMutex.RLock()
+	defer fake.getReadingMutex.RUnlock()
+	argsForCall := fake.getReadingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBookerRepository) GetReadingReturns(result1 *models.ReadingResponse, result2 error) {
+	fake.getReadingMutex.Lock()
+	defer fake.getReadingMutex.Unlock()
+	fake.GetReadingStub = nil
+	fake.getReadingReturns = struct {
+		result1 *models.ReadingResponse
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeBookerRepository) GetDBPoolReturnsOnCall(i int, result1 postgres.DB) {
-	fake.getDBPoolMutex.Lock()
-	defer fake.getDBPoolMutex.Unlock()
-	fake.GetDBPoolStub = nil
-	if fake.getDBPoolReturnsOnCall == nil {
-		fake.getDBPoolReturnsOnCall = make(map[int]struct {
-			result1 postgres.DB
+func (fake *FakeBookerRepository) GetReadingReturnsOnCall(i int, result1 *models.ReadingResponse, result2 error) {
+	fake.getReadingMutex.Lock()
+	defer fake.getReadingMutex.Unlock()
+	fake.GetReadingStub = nil
+	if fake.getReadingReturnsOnCall == nil {
+		fake.getReadingReturnsOnCall = make(map[int]struct {
+			result1 *models.ReadingResponse
+			result2 error
 		})
 	}
-	fake.getDBPoolReturnsOnCall[i] = struct {
-		result1 postgres.DB
-	}{result1}
+	fake.getReadingReturnsOnCall[i] = struct {
+		result1 *models.ReadingResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) ImportBooks(arg1 int, arg2 int, arg3 []models.ImportRow, arg4 string) (*models.ImportReport, error) {
+	var arg3Copy []models.ImportRow
+	if arg3 != nil {
+		arg3Copy = make([]models.ImportRow, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.importBooksMutex.Lock()
+	ret, specificReturn := fake.importBooksReturnsOnCall[len(fake.importBooksArgsForCall)]
+	fake.importBooksArgsForCall = append(fake.importBooksArgsForCall, struct {
+		arg1 int
+		arg2 int
+		arg3 []models.ImportRow
+		arg4 string
+	}{arg1, arg2, arg3Copy, arg4})
+	stub := fake.ImportBooksStub
+	fakeReturns := fake.importBooksReturns
+	fake.recordInvocation("ImportBooks", []interface{}{arg1, arg2, arg3Copy, arg4})
+	fake.importBooksMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBookerRepository) ImportBooksCallCount() int {
+	fake.importBooksMutex.RLock()
+	defer fake.importBooksMutex.RUnlock()
+	return len(fake.importBooksArgsForCall)
 }
 
-func (fake *FakeBookerRepository) UpdateBook(arg1 *models.BookRequest) (*models.BookResponse, error) {
+func (fake *FakeBookerRepository) ImportBooksCalls(stub func(int, int, []models.ImportRow, string) (*models.ImportReport, error)) {
+	fake.importBooksMutex.Lock()
+	defer fake.importBooksMutex.Unlock()
+	fake.ImportBooksStub = stub
+}
+
+func (fake *FakeBookerRepository) ImportBooksArgsForCall(i int) (int, int, []models.ImportRow, string) {
+	fake.importBooksMutex.RLock()
+	defer fake.importBooksMutex.RUnlock()
+	argsForCall := fake.importBooksArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeBookerRepository) ImportBooksReturns(result1 *models.ImportReport, result2 error) {
+	fake.importBooksMutex.Lock()
+	defer fake.importBooksMutex.Unlock()
+	fake.ImportBooksStub = nil
+	fake.importBooksReturns = struct {
+		result1 *models.ImportReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) ImportBooksReturnsOnCall(i int, result1 *models.ImportReport, result2 error) {
+	fake.importBooksMutex.Lock()
+	defer fake.importBooksMutex.Unlock()
+	fake.ImportBooksStub = nil
+	if fake.importBooksReturnsOnCall == nil {
+		fake.importBooksReturnsOnCall = make(map[int]struct {
+			result1 *models.ImportReport
+			result2 error
+		})
+	}
+	fake.importBooksReturnsOnCall[i] = struct {
+		result1 *models.ImportReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) ListBooks(arg1 int, arg2 *models.BookFilter) (*models.BookListResponse, error) {
+	fake.listBooksMutex.Lock()
+	ret, specificReturn := fake.listBooksReturnsOnCall[len(fake.listBooksArgsForCall)]
+	fake.listBooksArgsForCall = append(fake.listBooksArgsForCall, struct {
+		arg1 int
+		arg2 *models.BookFilter
+	}{arg1, arg2})
+	stub := fake.ListBooksStub
+	fakeReturns := fake.listBooksReturns
+	fake.recordInvocation("ListBooks", []interface{}{arg1, arg2})
+	fake.listBooksMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBookerRepository) ListBooksCallCount() int {
+	fake.listBooksMutex.RLock()
+	defer fake.listBooksMutex.RUnlock()
+	return len(fake.listBooksArgsForCall)
+}
+
+func (fake *FakeBookerRepository) ListBooksCalls(stub func(int, *models.BookFilter) (*models.BookListResponse, error)) {
+	fake.listBooksMutex.Lock()
+	defer fake.listBooksMutex.Unlock()
+	fake.ListBooksStub = stub
+}
+
+func (fake *FakeBookerRepository) ListBooksArgsForCall(i int) (int, *models.BookFilter) {
+	fake.listBooksMutex.RLock()
+	defer fake.listBooksMutex.RUnlock()
+	argsForCall := fake.listBooksArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBookerRepository) ListBooksReturns(result1 *models.BookListResponse, result2 error) {
+	fake.listBooksMutex.Lock()
+	defer fake.listBooksMutex.Unlock()
+	fake.ListBooksStub = nil
+	fake.listBooksReturns = struct {
+		result1 *models.BookListResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) ListBooksReturnsOnCall(i int, result1 *models.BookListResponse, result2 error) {
+	fake.listBooksMutex.Lock()
+	defer fake.listBooksMutex.Unlock()
+	fake.ListBooksStub = nil
+	if fake.listBooksReturnsOnCall == nil {
+		fake.listBooksReturnsOnCall = make(map[int]struct {
+			result1 *models.BookListResponse
+			result2 error
+		})
+	}
+	fake.listBooksReturnsOnCall[i] = struct {
+		result1 *models.BookListResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) ListHistory(arg1 int, arg2 int) ([]models.BookRevision, error) {
+	fake.listHistoryMutex.Lock()
+	ret, specificReturn := fake.listHistoryReturnsOnCall[len(fake.listHistoryArgsForCall)]
+	fake.listHistoryArgsForCall = append(fake.listHistoryArgsForCall, struct {
+		arg1 int
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.ListHistoryStub
+	fakeReturns := fake.listHistoryReturns
+	fake.recordInvocation("ListHistory", []interface{}{arg1, arg2})
+	fake.listHistoryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBookerRepository) ListHistoryCallCount() int {
+	fake.listHistoryMutex.RLock()
+	defer fake.listHistoryMutex.RUnlock()
+	return len(fake.listHistoryArgsForCall)
+}
+
+func (fake *FakeBookerRepository) ListHistoryCalls(stub func(int, int) ([]models.BookRevision, error)) {
+	fake.listHistoryMutex.Lock()
+	defer fake.listHistoryMutex.Unlock()
+	fake.ListHistoryStub = stub
+}
+
+func (fake *FakeBookerRepository) ListHistoryArgsForCall(i int) (int, int) {
+	fake.listHistoryMutex.RLock()
+	defer fake.listHistoryMutex.RUnlock()
+	argsForCall := fake.listHistoryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBookerRepository) ListHistoryReturns(result1 []models.BookRevision, result2 error) {
+	fake.listHistoryMutex.Lock()
+	defer fake.listHistoryMutex.Unlock()
+	fake.ListHistoryStub = nil
+	fake.listHistoryReturns = struct {
+		result1 []models.BookRevision
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) ListHistoryReturnsOnCall(i int, result1 []models.BookRevision, result2 error) {
+	fake.listHistoryMutex.Lock()
+	defer fake.listHistoryMutex.Unlock()
+	fake.ListHistoryStub = nil
+	if fake.listHistoryReturnsOnCall == nil {
+		fake.listHistoryReturnsOnCall = make(map[int]struct {
+			result1 []models.BookRevision
+			result2 error
+		})
+	}
+	fake.listHistoryReturnsOnCall[i] = struct {
+		result1 []models.BookRevision
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) ListTrash(arg1 int) ([]models.BookResponse, error) {
+	fake.listTrashMutex.Lock()
+	ret, specificReturn := fake.listTrashReturnsOnCall[len(fake.listTrashArgsForCall)]
+	fake.listTrashArgsForCall = append(fake.listTrashArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.ListTrashStub
+	fakeReturns := fake.listTrashReturns
+	fake.recordInvocation("ListTrash", []interface{}{arg1})
+	fake.listTrashMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBookerRepository) ListTrashCallCount() int {
+	fake.listTrashMutex.RLock()
+	defer fake.listTrashMutex.RUnlock()
+	return len(fake.listTrashArgsForCall)
+}
+
+func (fake *FakeBookerRepository) ListTrashCalls(stub func(int) ([]models.BookResponse, error)) {
+	fake.listTrashMutex.Lock()
+	defer fake.listTrashMutex.Unlock()
+	fake.ListTrashStub = stub
+}
+
+func (fake *FakeBookerRepository) ListTrashArgsForCall(i int) int {
+	fake.listTrashMutex.RLock()
+	defer fake.listTrashMutex.RUnlock()
+	argsForCall := fake.listTrashArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeBookerRepository) ListTrashReturns(result1 []models.BookResponse, result2 error) {
+	fake.listTrashMutex.Lock()
+	defer fake.listTrashMutex.Unlock()
+	fake.ListTrashStub = nil
+	fake.listTrashReturns = struct {
+		result1 []models.BookResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) ListTrashReturnsOnCall(i int, result1 []models.BookResponse, result2 error) {
+	fake.listTrashMutex.Lock()
+	defer fake.listTrashMutex.Unlock()
+	fake.ListTrashStub = nil
+	if fake.listTrashReturnsOnCall == nil {
+		fake.listTrashReturnsOnCall = make(map[int]struct {
+			result1 []models.BookResponse
+			result2 error
+		})
+	}
+	fake.listTrashReturnsOnCall[i] = struct {
+		result1 []models.BookResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) LookupISBN(arg1 string) (*models.BookRequest, error) {
+	fake.lookupISBNMutex.Lock()
+	ret, specificReturn := fake.lookupISBNReturnsOnCall[len(fake.lookupISBNArgsForCall)]
+	fake.lookupISBNArgsForCall = append(fake.lookupISBNArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.LookupISBNStub
+	fakeReturns := fake.lookupISBNReturns
+	fake.recordInvocation("LookupISBN", []interface{}{arg1})
+	fake.lookupISBNMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBookerRepository) LookupISBNCallCount() int {
+	fake.lookupISBNMutex.RLock()
+	defer fake.lookupISBNMutex.RUnlock()
+	return len(fake.lookupISBNArgsForCall)
+}
+
+func (fake *FakeBookerRepository) LookupISBNCalls(stub func(string) (*models.BookRequest, error)) {
+	fake.lookupISBNMutex.Lock()
+	defer fake.lookupISBNMutex.Unlock()
+	fake.LookupISBNStub = stub
+}
+
+func (fake *FakeBookerRepository) LookupISBNArgsForCall(i int) string {
+	fake.lookupISBNMutex.RLock()
+	defer fake.lookupISBNMutex.RUnlock()
+	argsForCall := fake.lookupISBNArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeBookerRepository) LookupISBNReturns(result1 *models.BookRequest, result2 error) {
+	fake.lookupISBNMutex.Lock()
+	defer fake.lookupISBNMutex.Unlock()
+	fake.LookupISBNStub = nil
+	fake.lookupISBNReturns = struct {
+		result1 *models.BookRequest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) LookupISBNReturnsOnCall(i int, result1 *models.BookRequest, result2 error) {
+	fake.lookupISBNMutex.Lock()
+	defer fake.lookupISBNMutex.Unlock()
+	fake.LookupISBNStub = nil
+	if fake.lookupISBNReturnsOnCall == nil {
+		fake.lookupISBNReturnsOnCall = make(map[int]struct {
+			result1 *models.BookRequest
+			result2 error
+		})
+	}
+	fake.lookupISBNReturnsOnCall[i] = struct {
+		result1 *models.BookRequest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) PurgeTrash(arg1 time.Time) (int64, []string, error) {
+	fake.purgeTrashMutex.Lock()
+	ret, specificReturn := fake.purgeTrashReturnsOnCall[len(fake.purgeTrashArgsForCall)]
+	fake.purgeTrashArgsForCall = append(fake.purgeTrashArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	stub := fake.PurgeTrashStub
+	fakeReturns := fake.purgeTrashReturns
+	fake.recordInvocation("PurgeTrash", []interface{}{arg1})
+	fake.purgeTrashMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeBookerRepository) PurgeTrashCallCount() int {
+	fake.purgeTrashMutex.RLock()
+	defer fake.purgeTrashMutex.RUnlock()
+	return len(fake.purgeTrashArgsForCall)
+}
+
+func (fake *FakeBookerRepository) PurgeTrashCalls(stub func(time.Time) (int64, []string, error)) {
+	fake.purgeTrashMutex.Lock()
+	defer fake.purgeTrashMutex.Unlock()
+	fake.PurgeTrashStub = stub
+}
+
+func (fake *FakeBookerRepository) PurgeTrashArgsForCall(i int) time.Time {
+	fake.purgeTrashMutex.RLock()
+	defer fake.purgeTrashMutex.RUnlock()
+	argsForCall := fake.purgeTrashArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeBookerRepository) PurgeTrashReturns(result1 int64, result2 []string, result3 error) {
+	fake.purgeTrashMutex.Lock()
+	defer fake.purgeTrashMutex.Unlock()
+	fake.PurgeTrashStub = nil
+	fake.purgeTrashReturns = struct {
+		result1 int64
+		result2 []string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBookerRepository) PurgeTrashReturnsOnCall(i int, result1 int64, result2 []string, result3 error) {
+	fake.purgeTrashMutex.Lock()
+	defer fake.purgeTrashMutex.Unlock()
+	fake.PurgeTrashStub = nil
+	if fake.purgeTrashReturnsOnCall == nil {
+		fake.purgeTrashReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 []string
+			result3 error
+		})
+	}
+	fake.purgeTrashReturnsOnCall[i] = struct {
+		result1 int64
+		result2 []string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBookerRepository) RestoreBook(arg1 int, arg2 int, arg3 int) (int, error) {
+	fake.restoreBookMutex.Lock()
+	ret, specificReturn := fake.restoreBookReturnsOnCall[len(fake.restoreBookArgsForCall)]
+	fake.restoreBookArgsForCall = append(fake.restoreBookArgsForCall, struct {
+		arg1 int
+		arg2 int
+		arg3 int
+	}{arg1, arg2, arg3})
+	stub := fake.RestoreBookStub
+	fakeReturns := fake.restoreBookReturns
+	fake.recordInvocation("RestoreBook", []interface{}{arg1, arg2, arg3})
+	fake.restoreBookMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBookerRepository) RestoreBookCallCount() int {
+	fake.restoreBookMutex.RLock()
+	defer fake.restoreBookMutex.RUnlock()
+	return len(fake.restoreBookArgsForCall)
+}
+
+func (fake *FakeBookerRepository) RestoreBookCalls(stub func(int, int, int) (int, error)) {
+	fake.restoreBookMutex.Lock()
+	defer fake.restoreBookMutex.Unlock()
+	fake.RestoreBookStub = stub
+}
+
+func (fake *FakeBookerRepository) RestoreBookArgsForCall(i int) (int, int, int) {
+	fake.restoreBookMutex.RLock()
+	defer fake.restoreBookMutex.RUnlock()
+	argsForCall := fake.restoreBookArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeBookerRepository) RestoreBookReturns(result1 int, result2 error) {
+	fake.restoreBookMutex.Lock()
+	defer fake.restoreBookMutex.Unlock()
+	fake.RestoreBookStub = nil
+	fake.restoreBookReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) RestoreBookReturnsOnCall(i int, result1 int, result2 error) {
+	fake.restoreBookMutex.Lock()
+	defer fake.restoreBookMutex.Unlock()
+	fake.RestoreBookStub = nil
+	if fake.restoreBookReturnsOnCall == nil {
+		fake.restoreBookReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.restoreBookReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) RevertBook(arg1 int, arg2 int, arg3 int, arg4 int) (*models.BookResponse, error) {
+	fake.revertBookMutex.Lock()
+	ret, specificReturn := fake.revertBookReturnsOnCall[len(fake.revertBookArgsForCall)]
+	fake.revertBookArgsForCall = append(fake.revertBookArgsForCall, struct {
+		arg1 int
+		arg2 int
+		arg3 int
+		arg4 int
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.RevertBookStub
+	fakeReturns := fake.revertBookReturns
+	fake.recordInvocation("RevertBook", []interface{}{arg1, arg2, arg3, arg4})
+	fake.revertBookMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBookerRepository) RevertBookCallCount() int {
+	fake.revertBookMutex.RLock()
+	defer fake.revertBookMutex.RUnlock()
+	return len(fake.revertBookArgsForCall)
+}
+
+func (fake *FakeBookerRepository) RevertBookCalls(stub func(int, int, int, int) (*models.BookResponse, error)) {
+	fake.revertBookMutex.Lock()
+	defer fake.revertBookMutex.Unlock()
+	fake.RevertBookStub = stub
+}
+
+func (fake *FakeBookerRepository) RevertBookArgsForCall(i int) (int, int, int, int) {
+	fake.revertBookMutex.RLock()
+	defer fake.revertBookMutex.RUnlock()
+	argsForCall := fake.revertBookArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeBookerRepository) RevertBookReturns(result1 *models.BookResponse, result2 error) {
+	fake.revertBookMutex.Lock()
+	defer fake.revertBookMutex.Unlock()
+	fake.RevertBookStub = nil
+	fake.revertBookReturns = struct {
+		result1 *models.BookResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) RevertBookReturnsOnCall(i int, result1 *models.BookResponse, result2 error) {
+	fake.revertBookMutex.Lock()
+	defer fake.revertBookMutex.Unlock()
+	fake.RevertBookStub = nil
+	if fake.revertBookReturnsOnCall == nil {
+		fake.revertBookReturnsOnCall = make(map[int]struct {
+			result1 *models.BookResponse
+			result2 error
+		})
+	}
+	fake.revertBookReturnsOnCall[i] = struct {
+		result1 *models.BookResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) SearchBooks(arg1 int, arg2 *models.SearchRequest) ([]models.SearchResult, error) {
+	fake.searchBooksMutex.Lock()
+	ret, specificReturn := fake.searchBooksReturnsOnCall[len(fake.searchBooksArgsForCall)]
+	fake.searchBooksArgsForCall = append(fake.searchBooksArgsForCall, struct {
+		arg1 int
+		arg2 *models.SearchRequest
+	}{arg1, arg2})
+	stub := fake.SearchBooksStub
+	fakeReturns := fake.searchBooksReturns
+	fake.recordInvocation("SearchBooks", []interface{}{arg1, arg2})
+	fake.searchBooksMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBookerRepository) SearchBooksCallCount() int {
+	fake.searchBooksMutex.RLock()
+	defer fake.searchBooksMutex.RUnlock()
+	return len(fake.searchBooksArgsForCall)
+}
+
+func (fake *FakeBookerRepository) SearchBooksCalls(stub func(int, *models.SearchRequest) ([]models.SearchResult, error)) {
+	fake.searchBooksMutex.Lock()
+	defer fake.searchBooksMutex.Unlock()
+	fake.SearchBooksStub = stub
+}
+
+func (fake *FakeBookerRepository) SearchBooksArgsForCall(i int) (int, *models.SearchRequest) {
+	fake.searchBooksMutex.RLock()
+	defer fake.searchBooksMutex.RUnlock()
+	argsForCall := fake.searchBooksArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBookerRepository) SearchBooksReturns(result1 []models.SearchResult, result2 error) {
+	fake.searchBooksMutex.Lock()
+	defer fake.searchBooksMutex.Unlock()
+	fake.SearchBooksStub = nil
+	fake.searchBooksReturns = struct {
+		result1 []models.SearchResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) SearchBooksReturnsOnCall(i int, result1 []models.SearchResult, result2 error) {
+	fake.searchBooksMutex.Lock()
+	defer fake.searchBooksMutex.Unlock()
+	fake.SearchBooksStub = nil
+	if fake.searchBooksReturnsOnCall == nil {
+		fake.searchBooksReturnsOnCall = make(map[int]struct {
+			result1 []models.SearchResult
+			result2 error
+		})
+	}
+	fake.searchBooksReturnsOnCall[i] = struct {
+		result1 []models.SearchResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) SetCover(arg1 int, arg2 int, arg3 models.Cover, arg4 []string) ([]string, error) {
+	var arg4Copy []string
+	if arg4 != nil {
+		arg4Copy = make([]string, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.setCoverMutex.Lock()
+	ret, specificReturn := fake.setCoverReturnsOnCall[len(fake.setCoverArgsForCall)]
+	fake.setCoverArgsForCall = append(fake.setCoverArgsForCall, struct {
+		arg1 int
+		arg2 int
+		arg3 models.Cover
+		arg4 []string
+	}{arg1, arg2, arg3, arg4Copy})
+	stub := fake.SetCoverStub
+	fakeReturns := fake.setCoverReturns
+	fake.recordInvocation("SetCover", []interface{}{arg1, arg2, arg3, arg4Copy})
+	fake.setCoverMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBookerRepository) SetCoverCallCount() int {
+	fake.setCoverMutex.RLock()
+	defer fake.setCoverMutex.RUnlock()
+	return len(fake.setCoverArgsForCall)
+}
+
+func (fake *FakeBookerRepository) SetCoverCalls(stub func(int, int, models.Cover, []string) ([]string, error)) {
+	fake.setCoverMutex.Lock()
+	defer fake.setCoverMutex.Unlock()
+	fake.SetCoverStub = stub
+}
+
+func (fake *FakeBookerRepository) SetCoverArgsForCall(i int) (int, int, models.Cover, []string) {
+	fake.setCoverMutex.RLock()
+	defer fake.setCoverMutex.RUnlock()
+	argsForCall := fake.setCoverArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeBookerRepository) SetCoverReturns(result1 []string, result2 error) {
+	fake.setCoverMutex.Lock()
+	defer fake.setCoverMutex.Unlock()
+	fake.SetCoverStub = nil
+	fake.setCoverReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) SetCoverReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.setCoverMutex.Lock()
+	defer fake.setCoverMutex.Unlock()
+	fake.SetCoverStub = nil
+	if fake.setCoverReturnsOnCall == nil {
+		fake.setCoverReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.setCoverReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) UpdateBook(arg1 *models.BookRequest, arg2 int) (*models.BookResponse, error) {
 	fake.updateBookMutex.Lock()
 	ret, specificReturn := fake.updateBookReturnsOnCall[len(fake.updateBookArgsForCall)]
 	fake.updateBookArgsForCall = append(fake.updateBookArgsForCall, struct {
 		arg1 *models.BookRequest
-	}{arg1})
+		arg2 int
+	}{arg1, arg2})
 	stub := fake.UpdateBookStub
 	fakeReturns := fake.updateBookReturns
-	fake.recordInvocation("UpdateBook", []interface{}{arg1})
+	fake.recordInvocation("UpdateBook", []interface{}{arg1, arg2})
 	fake.updateBookMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -415,17 +1349,17 @@ func (fake *FakeBookerRepository) UpdateBookCallCount() int {
 	return len(fake.updateBookArgsForCall)
 }
 
-func (fake *FakeBookerRepository) UpdateBookCalls(stub func(*models.BookRequest) (*models.BookResponse, error)) {
+func (fake *FakeBookerRepository) UpdateBookCalls(stub func(*models.BookRequest, int) (*models.BookResponse, error)) {
 	fake.updateBookMutex.Lock()
 	defer fake.updateBookMutex.Unlock()
 	fake.UpdateBookStub = stub
 }
 
-func (fake *FakeBookerRepository) UpdateBookArgsForCall(i int) *models.BookRequest {
+func (fake *FakeBookerRepository) UpdateBookArgsForCall(i int) (*models.BookRequest, int) {
 	fake.updateBookMutex.RLock()
 	defer fake.updateBookMutex.RUnlock()
 	argsForCall := fake.updateBookArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeBookerRepository) UpdateBookReturns(result1 *models.BookResponse, result2 error) {
@@ -454,21 +1388,111 @@ func (fake *FakeBookerRepository) UpdateBookReturnsOnCall(i int, result1 *models
 	}{result1, result2}
 }
 
+func (fake *FakeBookerRepository) UpdateReading(arg1 int, arg2 int, arg3 *models.ReadingProgressRequest) (*models.ReadingSession, error) {
+	fake.updateReadingMutex.Lock()
+	ret, specificReturn := fake.updateReadingReturnsOnCall[len(fake.updateReadingArgsForCall)]
+	fake.updateReadingArgsForCall = append(fake.updateReadingArgsForCall, struct {
+		arg1 int
+		arg2 int
+		arg3 *models.ReadingProgressRequest
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateReadingStub
+	fakeReturns := fake.updateReadingReturns
+	fake.recordInvocation("UpdateReading", []interface{}{arg1, arg2, arg3})
+	fake.updateReadingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBookerRepository) UpdateReadingCallCount() int {
+	fake.updateReadingMutex.RLock()
+	defer fake.updateReadingMutex.RUnlock()
+	return len(fake.updateReadingArgsForCall)
+}
+
+func (fake *FakeBookerRepository) UpdateReadingCalls(stub func(int, int, *models.ReadingProgressRequest) (*models.ReadingSession, error)) {
+	fake.updateReadingMutex.Lock()
+	defer fake.updateReadingMutex.Unlock()
+	fake.UpdateReadingStub = stub
+}
+
+func (fake *FakeBookerRepository) UpdateReadingArgsForCall(i int) (int, int, *models.ReadingProgressRequest) {
+	fake.updateReadingMutex.RLock()
+	defer fake.updateReadingMutex.RUnlock()
+	argsForCall := fake.updateReadingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeBookerRepository) UpdateReadingReturns(result1 *models.ReadingSession, result2 error) {
+	fake.updateReadingMutex.Lock()
+	defer fake.updateReadingMutex.Unlock()
+	fake.UpdateReadingStub = nil
+	fake.updateReadingReturns = struct {
+		result1 *models.ReadingSession
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBookerRepository) UpdateReadingReturnsOnCall(i int, result1 *models.ReadingSession, result2 error) {
+	fake.updateReadingMutex.Lock()
+	defer fake.updateReadingMutex.Unlock()
+	fake.UpdateReadingStub = nil
+	if fake.updateReadingReturnsOnCall == nil {
+		fake.updateReadingReturnsOnCall = make(map[int]struct {
+			result1 *models.ReadingSession
+			result2 error
+		})
+	}
+	fake.updateReadingReturnsOnCall[i] = struct {
+		result1 *models.ReadingSession
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBookerRepository) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.addBookMutex.RLock()
 	defer fake.addBookMutex.RUnlock()
+	fake.checkCoverMutex.RLock()
+	defer fake.checkCoverMutex.RUnlock()
 	fake.deleteBookMutex.RLock()
 	defer fake.deleteBookMutex.RUnlock()
-	fake.getAllBooksMutex.RLock()
-	defer fake.getAllBooksMutex.RUnlock()
+	fake.exportBooksMutex.RLock()
+	defer fake.exportBooksMutex.RUnlock()
 	fake.getBookMutex.RLock()
 	defer fake.getBookMutex.RUnlock()
-	fake.getDBPoolMutex.RLock()
-	defer fake.getDBPoolMutex.RUnlock()
+	fake.getReadingMutex.RLock()
+	defer fake.getReadingMutex.RUnlock()
+	fake.importBooksMutex.RLock()
+	defer fake.importBooksMutex.RUnlock()
+	fake.listBooksMutex.RLock()
+	defer fake.listBooksMutex.RUnlock()
+	fake.listHistoryMutex.RLock()
+	defer fake.listHistoryMutex.RUnlock()
+	fake.listTrashMutex.RLock()
+	defer fake.listTrashMutex.RUnlock()
+	fake.lookupISBNMutex.RLock()
+	defer fake.lookupISBNMutex.RUnlock()
+	fake.purgeTrashMutex.RLock()
+	defer fake.purgeTrashMutex.RUnlock()
+	fake.restoreBookMutex.RLock()
+	defer fake.restoreBookMutex.RUnlock()
+	fake.revertBookMutex.RLock()
+	defer fake.revertBookMutex.RUnlock()
+	fake.searchBooksMutex.RLock()
+	defer fake.searchBooksMutex.RUnlock()
+	fake.setCoverMutex.RLock()
+	defer fake.setCoverMutex.RUnlock()
 	fake.updateBookMutex.RLock()
 	defer fake.updateBookMutex.RUnlock()
+	fake.updateReadingMutex.RLock()
+	defer fake.updateReadingMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
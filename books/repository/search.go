@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"errors"
+	"html"
+	"library/books/models"
+	"library/pkg/utils"
+	"strings"
+	"unicode"
+)
+
+const (
+	SearchSourcePersonal = "personal"
+	SearchSourceShop     = "shop"
+)
+
+// The search query delimits the matches with private use characters rather than
+// markup, names of books and shop titles are escaped before the matches are marked.
+const (
+	highlightStart = "\uE000"
+	highlightStop  = "\uE001"
+)
+
+var highlightMarks = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+var ErrEmptySearch = errors.New("search query must contain at least one letter or digit")
+
+func (b *BookRepository) SearchBooks(userID int, search *models.SearchRequest) ([]models.SearchResult, error) {
+	results := []models.SearchResult{}
+
+	log := utils.GetLogger(b.ctx)
+
+	tsQuery := toPrefixQuery(search.Query)
+	if tsQuery == "" {
+		log.Warningf("Empty search query: %q", search.Query)
+		return results, ErrEmptySearch
+	}
+
+	rows, err := b.DB.DB.Query(SearchBooks, tsQuery, userID, search.Limit)
+	if err != nil {
+		log.Errorf("Failed to perform a search query: %v", err)
+		return results, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var result models.SearchResult
+
+		err = rows.Scan(
+			&result.Source,
+			&result.ID,
+			&result.Name,
+			&result.ISBN,
+			&result.Authors,
+			&result.Rank,
+			&result.Highlight,
+		)
+		if err != nil {
+			log.Errorf("Failed to scan rows: %v", err)
+			return results, err
+		}
+
+		result.Highlight = markHighlight(result.Highlight)
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Errorf("Query failed: %v", err)
+		return results, err
+	}
+
+	return results, nil
+}
+
+// markHighlight escapes the headline of a match as HTML, and marks its matches.
+func markHighlight(headline string) string {
+	return highlightMarks.Replace(html.EscapeString(headline))
+}
+
+// toPrefixQuery turns free text into a to_tsquery expression where every term
+// has to match as a prefix, e.g. "tolk hob" becomes "tolk:* & hob:*".
+func toPrefixQuery(query string) string {
+	terms := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+
+	return strings.Join(terms, " & ")
+}
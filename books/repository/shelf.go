@@ -0,0 +1,437 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"library/books/models"
+	"library/pkg/logger"
+	"library/pkg/postgres"
+	"library/pkg/utils"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrShelfNotFound    = errors.New("shelf not found")
+	ErrShelfNameTaken   = errors.New("you already have a shelf with this name")
+	ErrBookOnShelf      = errors.New("book is already on the shelf")
+	ErrBookNotOnShelf   = errors.New("book is not on the shelf")
+	ErrInvalidOrder     = errors.New("order must list every book on the shelf exactly once")
+	ErrBookNotShelvable = errors.New("only your own books can be put on your shelves")
+)
+
+type ShelferRepository interface {
+	ListShelves(userID int) ([]models.Shelf, error)
+	CreateShelf(userID int, shelf *models.ShelfRequest) (*models.Shelf, error)
+	GetShelf(shelfID, ownerID, readerID int, readAny bool) (*models.ShelfResponse, error)
+	UpdateShelf(shelfID, userID int, shelf *models.ShelfRequest) (*models.Shelf, error)
+	DeleteShelf(shelfID, userID int) error
+	AddShelfBook(shelfID, userID int, book *models.ShelfBookRequest) error
+	RemoveShelfBook(shelfID, userID, bookID int) error
+	ReorderShelf(shelfID, userID int, bookIDs []int) error
+}
+
+type ShelfRepository struct {
+	ctx context.Context
+	DB  postgres.DB
+}
+
+func NewShelfRepository(ctx context.Context, db postgres.DB) ShelferRepository {
+	return &ShelfRepository{
+		ctx: ctx,
+		DB:  db,
+	}
+}
+
+func (s *ShelfRepository) ListShelves(userID int) ([]models.Shelf, error) {
+	shelves := []models.Shelf{}
+
+	log := utils.GetLogger(s.ctx)
+
+	rows, err := s.DB.DB.Query(ListShelves, userID)
+	if err != nil {
+		log.Errorf("Failed to perform a query on shelf table: %v", err)
+		return shelves, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var shelf models.Shelf
+
+		err = rows.Scan(&shelf.ID, &shelf.UserID, &shelf.Name, &shelf.Description, &shelf.Public, &shelf.BookCount, &shelf.CreatedAt)
+		if err != nil {
+			log.Errorf("Failed to scan rows: %v", err)
+			return shelves, err
+		}
+
+		shelves = append(shelves, shelf)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Errorf("Query failed: %v", err)
+		return shelves, err
+	}
+
+	return shelves, nil
+}
+
+func (s *ShelfRepository) CreateShelf(userID int, shelf *models.ShelfRequest) (*models.Shelf, error) {
+	log := utils.GetLogger(s.ctx)
+
+	if err := checkShelfName(log, userID, 0, shelf.Name, s.DB.GetDB()); err != nil {
+		return nil, err
+	}
+
+	var shelfID int
+	err := s.DB.DB.QueryRow(InsertShelf, userID, shelf.Name, shelf.Description, shelf.Public).Scan(&shelfID)
+	if err != nil {
+		log.Errorf("Failed to insert shelf: %v", err)
+		return nil, err
+	}
+
+	return getShelf(log, shelfID, s.DB.GetDB())
+}
+
+// GetShelf returns the shelf of the owner with its books in shelf order. Other readers
+// can only read the shelf when it is public or readAny is set, otherwise it is reported
+// as not found.
+func (s *ShelfRepository) GetShelf(shelfID, ownerID, readerID int, readAny bool) (*models.ShelfResponse, error) {
+	log := utils.GetLogger(s.ctx)
+
+	shelf, err := getShelf(log, shelfID, s.DB.GetDB())
+	if err != nil {
+		return nil, err
+	}
+
+	if shelf.UserID != ownerID {
+		log.Warningf("Shelf %v does not belong to user %v", shelfID, ownerID)
+		return nil, ErrShelfNotFound
+	}
+
+	if readerID != ownerID && !shelf.Public && !readAny {
+		log.Warningf("Shelf %v is private to user %v", shelfID, ownerID)
+		return nil, ErrShelfNotFound
+	}
+
+	response := &models.ShelfResponse{Shelf: *shelf, Books: []models.BookResponse{}}
+
+	rows, err := s.DB.DB.Query(ShelfBooks, shelfID)
+	if err != nil {
+		log.Errorf("Failed to perform a query on shelf books table: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var book models.BookResponse
+
+		if err = rows.Scan(&book.ID, &book.Name, &book.DatePublished, &book.ISBN, &book.PageCount, &book.Cover); err != nil {
+			log.Errorf("Failed to scan rows: %v", err)
+			return nil, err
+		}
+
+		response.Books = append(response.Books, book)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Errorf("Query failed: %v", err)
+		return nil, err
+	}
+
+	if err = loadBookAuthors(log, response.Books, s.DB.GetDB()); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *ShelfRepository) UpdateShelf(shelfID, userID int, shelf *models.ShelfRequest) (*models.Shelf, error) {
+	log := utils.GetLogger(s.ctx)
+
+	if err := checkShelfName(log, userID, shelfID, shelf.Name, s.DB.GetDB()); err != nil {
+		return nil, err
+	}
+
+	result, err := s.DB.DB.Exec(UpdateShelf, shelf.Name, shelf.Description, shelf.Public, shelfID, userID)
+	if err != nil {
+		log.Errorf("Failed to update shelf: %v", err)
+		return nil, err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		log.Errorf("Error number of rows affected: %v", err)
+		return nil, err
+	}
+
+	if updated == 0 {
+		log.Warningf("Shelf %v does not belong to user %v", shelfID, userID)
+		return nil, ErrShelfNotFound
+	}
+
+	return getShelf(log, shelfID, s.DB.GetDB())
+}
+
+func (s *ShelfRepository) DeleteShelf(shelfID, userID int) error {
+	log := utils.GetLogger(s.ctx)
+
+	result, err := s.DB.DB.Exec(DeleteShelf, shelfID, userID)
+	if err != nil {
+		log.Errorf("Failed to delete shelf: %v", err)
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		log.Errorf("Error number of rows affected: %v", err)
+		return err
+	}
+
+	if deleted == 0 {
+		log.Warningf("Shelf %v does not belong to user %v", shelfID, userID)
+		return ErrShelfNotFound
+	}
+
+	return nil
+}
+
+// AddShelfBook puts the book on the shelf at the requested position, moving the books
+// from that position onwards one place down. Without a position the book goes last.
+func (s *ShelfRepository) AddShelfBook(shelfID, userID int, book *models.ShelfBookRequest) error {
+	log := utils.GetLogger(s.ctx)
+
+	tx, err := s.DB.DB.Begin()
+	if err != nil {
+		log.Errorf("Failed to begin transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = lockShelf(log, shelfID, userID, tx); err != nil {
+		return err
+	}
+
+	var shelvable bool
+	if err = tx.QueryRow(IsShelvable, book.BookID, userID).Scan(&shelvable); err != nil {
+		log.Errorf("Error checking book assignment: %v", err)
+		return err
+	}
+
+	if !shelvable {
+		log.Warningf("Book %v does not belong to user %v", book.BookID, userID)
+		return ErrBookNotShelvable
+	}
+
+	var onShelf bool
+	if err = tx.QueryRow(IsOnShelf, shelfID, book.BookID).Scan(&onShelf); err != nil {
+		log.Errorf("Error checking shelf books: %v", err)
+		return err
+	}
+
+	if onShelf {
+		log.Warningf("Book %v is already on shelf %v", book.BookID, shelfID)
+		return ErrBookOnShelf
+	}
+
+	var next int
+	if err = tx.QueryRow(NextShelfPosition, shelfID).Scan(&next); err != nil {
+		log.Errorf("Failed to get the next shelf position: %v", err)
+		return err
+	}
+
+	position := book.Position
+	if position == 0 || position > next {
+		position = next
+	}
+
+	if position < next {
+		if _, err = tx.Exec(OpenShelfPosition, shelfID, position); err != nil {
+			log.Errorf("Failed to move shelf books: %v", err)
+			return err
+		}
+	}
+
+	if _, err = tx.Exec(InsertShelfBook, shelfID, book.BookID, position); err != nil {
+		log.Errorf("Failed to insert shelf book: %v", err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Errorf("Failed to commit transaction: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *ShelfRepository) RemoveShelfBook(shelfID, userID, bookID int) error {
+	log := utils.GetLogger(s.ctx)
+
+	tx, err := s.DB.DB.Begin()
+	if err != nil {
+		log.Errorf("Failed to begin transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = lockShelf(log, shelfID, userID, tx); err != nil {
+		return err
+	}
+
+	var position int
+	err = tx.QueryRow(DeleteShelfBook, shelfID, bookID).Scan(&position)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("Book %v is not on shelf %v", bookID, shelfID)
+		return ErrBookNotOnShelf
+	}
+
+	if err != nil {
+		log.Errorf("Failed to delete shelf book: %v", err)
+		return err
+	}
+
+	if _, err = tx.Exec(CloseShelfPosition, shelfID, position); err != nil {
+		log.Errorf("Failed to move shelf books: %v", err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Errorf("Failed to commit transaction: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// ReorderShelf gives the books of the shelf the order of bookIDs, which must list every
+// book on the shelf. Books in the trash are moved after the others.
+func (s *ShelfRepository) ReorderShelf(shelfID, userID int, bookIDs []int) error {
+	log := utils.GetLogger(s.ctx)
+
+	tx, err := s.DB.DB.Begin()
+	if err != nil {
+		log.Errorf("Failed to begin transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = lockShelf(log, shelfID, userID, tx); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(ListShelfBookIDs, shelfID)
+	if err != nil {
+		log.Errorf("Failed to perform a query on shelf books table: %v", err)
+		return err
+	}
+
+	shelved := make(map[int]bool)
+	for rows.Next() {
+		var bookID int
+
+		if err = rows.Scan(&bookID); err != nil {
+			rows.Close()
+			log.Errorf("Failed to scan rows: %v", err)
+			return err
+		}
+
+		shelved[bookID] = true
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		log.Errorf("Query failed: %v", err)
+		return err
+	}
+
+	if len(bookIDs) != len(shelved) {
+		log.Warningf("Order of shelf %v has %d books, the shelf has %d", shelfID, len(bookIDs), len(shelved))
+		return ErrInvalidOrder
+	}
+
+	ids := make([]int64, len(bookIDs))
+	for i, bookID := range bookIDs {
+		if !shelved[bookID] {
+			log.Warningf("Book %v is not on shelf %v", bookID, shelfID)
+			return ErrInvalidOrder
+		}
+
+		ids[i] = int64(bookID)
+	}
+
+	if _, err = tx.Exec(MoveShelfBooks, shelfID, len(ids)); err != nil {
+		log.Errorf("Failed to move shelf books: %v", err)
+		return err
+	}
+
+	if _, err = tx.Exec(ReorderShelfBooks, shelfID, pq.Array(ids)); err != nil {
+		log.Errorf("Failed to reorder shelf books: %v", err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Errorf("Failed to commit transaction: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func getShelf(log logger.Logger, shelfID int, db postgres.Queryer) (*models.Shelf, error) {
+	shelf := &models.Shelf{}
+
+	err := db.QueryRow(GetShelf, shelfID).Scan(
+		&shelf.ID,
+		&shelf.UserID,
+		&shelf.Name,
+		&shelf.Description,
+		&shelf.Public,
+		&shelf.BookCount,
+		&shelf.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("Shelf not found: %v", shelfID)
+		return nil, ErrShelfNotFound
+	}
+
+	if err != nil {
+		log.Errorf("Query row on shelf table failed: %v", err)
+		return nil, err
+	}
+
+	return shelf, nil
+}
+
+func checkShelfName(log logger.Logger, userID, shelfID int, name string, db postgres.Queryer) error {
+	var taken bool
+	if err := db.QueryRow(ShelfNameTaken, userID, name, shelfID).Scan(&taken); err != nil {
+		log.Errorf("Failed to check shelf name: %v", err)
+		return err
+	}
+
+	if taken {
+		log.Warningf("Shelf name already taken: %v", name)
+		return ErrShelfNameTaken
+	}
+
+	return nil
+}
+
+// lockShelf locks the shelf of the user for the rest of the transaction, so concurrent
+// changes to its books cannot interleave positions.
+func lockShelf(log logger.Logger, shelfID, userID int, tx *sql.Tx) error {
+	var id int
+	err := tx.QueryRow(LockShelf, shelfID, userID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("Shelf %v does not belong to user %v", shelfID, userID)
+		return ErrShelfNotFound
+	}
+
+	if err != nil {
+		log.Errorf("Failed to lock shelf: %v", err)
+		return err
+	}
+
+	return nil
+}
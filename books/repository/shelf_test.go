@@ -0,0 +1,207 @@
+package repository_test
+
+import (
+	"context"
+	"library/books/models"
+	"library/books/repository"
+	"library/pkg/logger"
+	"library/pkg/postgres"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shelf Repository Test", func() {
+	var (
+		shelfRepo repository.ShelferRepository
+		mock      sqlmock.Sqlmock
+		fakeDB    *postgres.DB
+		shelfRow  func(id, userID int, public bool) *sqlmock.Rows
+	)
+
+	JustBeforeEach(func() {
+		ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+		fakeDB, _ = postgres.NewFakeDB(ctx)
+		shelfRepo = repository.NewShelfRepository(ctx, *fakeDB)
+		mock = fakeDB.GetMock()
+
+		shelfRow = func(id, userID int, public bool) *sqlmock.Rows {
+			return sqlmock.NewRows([]string{"id", "user_id", "name", "description", "public", "book_count", "created_at"}).
+				AddRow(id, userID, "Sci-fi", "", public, 1, time.Now())
+		}
+	})
+
+	AfterEach(func() {
+		fakeDB.Close()
+	})
+
+	Describe("CreateShelf", func() {
+		It("should refuse a name the user already has", func() {
+			mock.ExpectQuery(repository.ShelfNameTaken).
+				WithArgs(1, "Sci-fi", 0).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+			_, err := shelfRepo.CreateShelf(1, &models.ShelfRequest{Name: "Sci-fi"})
+
+			Expect(err).To(MatchError(repository.ErrShelfNameTaken))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+
+	Describe("GetShelf", func() {
+		It("should return a public shelf of another user with its books", func() {
+			mock.ExpectQuery(repository.GetShelf).
+				WithArgs(3).
+				WillReturnRows(shelfRow(3, 2, true))
+
+			mock.ExpectQuery(repository.ShelfBooks).
+				WithArgs(3).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "date_published", "isbn", "page_count", "cover"}).
+					AddRow(7, "Dune", "1965-08-01", "9780441013593", 412, []byte(`{"small":"/v1/books/covers/covers/7/ab/small.jpg"}`)))
+
+			mock.ExpectQuery(repository.GetBookAuthors).
+				WithArgs(pq.Array([]int64{7})).
+				WillReturnRows(sqlmock.NewRows([]string{"user_book_id", "id", "name", "role"}).AddRow(7, 1, "Frank Herbert", "author"))
+
+			shelf, err := shelfRepo.GetShelf(3, 2, 1, false)
+
+			Expect(err).To(BeNil())
+			Expect(shelf.Books).To(HaveLen(1))
+			Expect(shelf.Books[0].Authors[0].Name).To(Equal("Frank Herbert"))
+			Expect(shelf.Books[0].Cover).To(HaveKeyWithValue("small", "/v1/books/covers/covers/7/ab/small.jpg"))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("should hide a private shelf of another user", func() {
+			mock.ExpectQuery(repository.GetShelf).
+				WithArgs(3).
+				WillReturnRows(shelfRow(3, 2, false))
+
+			_, err := shelfRepo.GetShelf(3, 2, 1, false)
+
+			Expect(err).To(MatchError(repository.ErrShelfNotFound))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("should let a reader with the permission read a private shelf", func() {
+			mock.ExpectQuery(repository.GetShelf).
+				WithArgs(3).
+				WillReturnRows(shelfRow(3, 2, false))
+
+			mock.ExpectQuery(repository.ShelfBooks).
+				WithArgs(3).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "date_published", "isbn", "page_count", "cover"}))
+
+			shelf, err := shelfRepo.GetShelf(3, 2, 1, true)
+
+			Expect(err).To(BeNil())
+			Expect(shelf.Books).To(BeEmpty())
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("should not find the shelf under another user", func() {
+			mock.ExpectQuery(repository.GetShelf).
+				WithArgs(3).
+				WillReturnRows(shelfRow(3, 2, true))
+
+			_, err := shelfRepo.GetShelf(3, 1, 1, true)
+
+			Expect(err).To(MatchError(repository.ErrShelfNotFound))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+
+	Describe("AddShelfBook", func() {
+		It("should make room for the book at the requested position", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(repository.LockShelf).
+				WithArgs(3, 1).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+			mock.ExpectQuery(repository.IsShelvable).
+				WithArgs(7, 1).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			mock.ExpectQuery(repository.IsOnShelf).
+				WithArgs(3, 7).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			mock.ExpectQuery(repository.NextShelfPosition).
+				WithArgs(3).
+				WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(4))
+			mock.ExpectExec(repository.OpenShelfPosition).
+				WithArgs(3, 2).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectExec(repository.InsertShelfBook).
+				WithArgs(3, 7, 2).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			err := shelfRepo.AddShelfBook(3, 1, &models.ShelfBookRequest{BookID: 7, Position: 2})
+
+			Expect(err).To(BeNil())
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("should refuse a book that is already on the shelf", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(repository.LockShelf).
+				WithArgs(3, 1).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+			mock.ExpectQuery(repository.IsShelvable).
+				WithArgs(7, 1).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			mock.ExpectQuery(repository.IsOnShelf).
+				WithArgs(3, 7).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			mock.ExpectRollback()
+
+			err := shelfRepo.AddShelfBook(3, 1, &models.ShelfBookRequest{BookID: 7})
+
+			Expect(err).To(MatchError(repository.ErrBookOnShelf))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+
+	Describe("ReorderShelf", func() {
+		It("should apply the new order", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(repository.LockShelf).
+				WithArgs(3, 1).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+			mock.ExpectQuery(repository.ListShelfBookIDs).
+				WithArgs(3).
+				WillReturnRows(sqlmock.NewRows([]string{"user_book_id"}).AddRow(7).AddRow(8))
+			mock.ExpectExec(repository.MoveShelfBooks).
+				WithArgs(3, 2).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectExec(repository.ReorderShelfBooks).
+				WithArgs(3, pq.Array([]int64{8, 7})).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectCommit()
+
+			err := shelfRepo.ReorderShelf(3, 1, []int{8, 7})
+
+			Expect(err).To(BeNil())
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("should refuse an order missing a book", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(repository.LockShelf).
+				WithArgs(3, 1).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+			mock.ExpectQuery(repository.ListShelfBookIDs).
+				WithArgs(3).
+				WillReturnRows(sqlmock.NewRows([]string{"user_book_id"}).AddRow(7).AddRow(8))
+			mock.ExpectRollback()
+
+			err := shelfRepo.ReorderShelf(3, 1, []int{7})
+
+			Expect(err).To(MatchError(repository.ErrInvalidOrder))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+})
@@ -6,34 +6,179 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"library/books/handler"
 
+	"library/pkg/authz"
 	"library/pkg/middleware"
 	"library/pkg/tracing"
 )
 
-func NewRouter(handlerBook handler.BookerHandler) *gin.Engine {
+func NewRouter(handlerBook handler.BookerHandler, handlerAuthor handler.AuthorerHandler, handlerShelf handler.ShelferHandler) *gin.Engine {
 	router := gin.Default()
 
 	v1 := router.Group("/v1/books")
 
 	v1.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	v1.GET("/search",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireScope(authz.BooksRead),
+		handlerBook.SearchBooks,
+	)
+
+	v1.GET("/authors",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireScope(authz.BooksRead),
+		handlerAuthor.ListAuthors,
+	)
+	v1.GET("/authors/:author_id",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireScope(authz.BooksRead),
+		middleware.GetAuthorParam,
+		handlerAuthor.GetAuthor,
+	)
+	v1.PUT("/authors/:author_id",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequirePermission(authz.AuthorsWrite),
+		middleware.GetAuthorParam,
+		handlerAuthor.UpdateAuthor,
+	)
+	v1.GET("/authors/:author_id/duplicates",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireScope(authz.BooksRead),
+		middleware.GetAuthorParam,
+		handlerAuthor.SuggestDuplicates,
+	)
+	v1.POST("/authors/:author_id/merge",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequirePermission(authz.AuthorsMerge),
+		middleware.GetAuthorParam,
+		handlerAuthor.MergeAuthors,
+	)
+
 	v1.POST("/:user_id/books",
 		tracing.TraceMiddleware,
 		middleware.IsAuthorized,
 		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
 		handlerBook.AddBook,
 	)
+	v1.POST("/:user_id/books/import",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
+		handlerBook.ImportBooks,
+	)
+	v1.POST("/:user_id/books/lookup",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
+		handlerBook.LookupBook,
+	)
 	v1.PUT("/:user_id/books/:book_id",
 		tracing.TraceMiddleware,
 		middleware.IsAuthorized,
 		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
 		middleware.GetBookParam,
 		handlerBook.UpdateBook,
 	)
+	v1.PATCH("/:user_id/books/:book_id",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
+		middleware.GetBookParam,
+		handlerBook.PatchBook,
+	)
+	v1.GET("/:user_id/books/trash",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksReadAny, authz.BooksRead),
+		handlerBook.ListTrash,
+	)
+	v1.POST("/:user_id/books/:book_id/restore",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
+		middleware.GetBookParam,
+		handlerBook.RestoreBook,
+	)
+	v1.GET("/:user_id/books/:book_id/history",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksReadAny, authz.BooksRead),
+		middleware.GetBookParam,
+		handlerBook.ListHistory,
+	)
+	v1.POST("/:user_id/books/:book_id/history/:revision_id/revert",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
+		middleware.GetBookParam,
+		middleware.GetRevisionParam,
+		handlerBook.RevertBook,
+	)
+	v1.GET("/:user_id/books/:book_id/reading",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksReadAny, authz.BooksRead),
+		middleware.GetBookParam,
+		handlerBook.GetReading,
+	)
+	v1.PUT("/:user_id/books/:book_id/reading",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
+		middleware.GetBookParam,
+		handlerBook.UpdateReading,
+	)
+	v1.PUT("/:user_id/books/:book_id/cover",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
+		middleware.GetBookParam,
+		handlerBook.UploadCover,
+	)
+	v1.DELETE("/:user_id/books/:book_id/cover",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
+		middleware.GetBookParam,
+		handlerBook.DeleteCover,
+	)
+	v1.GET("/:user_id/books/export",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksReadAny, authz.BooksRead),
+		handlerBook.ExportBooks,
+	)
 	v1.GET("/:user_id/books/:book_id",
 		tracing.TraceMiddleware,
 		middleware.IsAuthorized,
 		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksReadAny, authz.BooksRead),
 		middleware.GetBookParam,
 		handlerBook.GetBook,
 	)
@@ -41,15 +186,81 @@ func NewRouter(handlerBook handler.BookerHandler) *gin.Engine {
 		tracing.TraceMiddleware,
 		middleware.IsAuthorized,
 		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksReadAny, authz.BooksRead),
 		handlerBook.GetAllBooks,
 	)
 	v1.DELETE("/:user_id/books/:book_id",
 		tracing.TraceMiddleware,
 		middleware.IsAuthorized,
 		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
 		middleware.GetBookParam,
 		handlerBook.DeleteBook,
 	)
 
+	v1.GET("/:user_id/shelves",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksReadAny, authz.BooksRead),
+		handlerShelf.ListShelves,
+	)
+	v1.POST("/:user_id/shelves",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
+		handlerShelf.CreateShelf,
+	)
+	v1.GET("/:user_id/shelves/:shelf_id",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireScope(authz.BooksRead),
+		middleware.GetOwnerParam,
+		middleware.GetShelfParam,
+		handlerShelf.GetShelf,
+	)
+	v1.PUT("/:user_id/shelves/:shelf_id",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
+		middleware.GetShelfParam,
+		handlerShelf.UpdateShelf,
+	)
+	v1.DELETE("/:user_id/shelves/:shelf_id",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
+		middleware.GetShelfParam,
+		handlerShelf.DeleteShelf,
+	)
+	v1.POST("/:user_id/shelves/:shelf_id/books",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
+		middleware.GetShelfParam,
+		handlerShelf.AddShelfBook,
+	)
+	v1.PUT("/:user_id/shelves/:shelf_id/books/order",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
+		middleware.GetShelfParam,
+		handlerShelf.ReorderShelf,
+	)
+	v1.DELETE("/:user_id/shelves/:shelf_id/books/:book_id",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireOwnerOr(authz.BooksWriteAny, authz.BooksWrite),
+		middleware.GetShelfParam,
+		handlerShelf.RemoveShelfBook,
+	)
+
 	return router
 }
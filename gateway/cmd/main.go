@@ -5,11 +5,15 @@ import (
 	"github.com/kelseyhightower/envconfig"
 	"library/gateway/server"
 	"library/pkg/config"
+	"library/pkg/jwks"
 	"library/pkg/logger"
+	"library/pkg/middleware"
 	"library/pkg/utils"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
 func main() {
@@ -28,6 +32,8 @@ func main() {
 		log.Fatalf(err.Error())
 	}
 
+	middleware.UsePublicKeys(jwks.NewRemote(cfg.JWKSURL, &http.Client{Timeout: 5 * time.Second}, cfg.JWKSCacheTTL))
+
 	go gateway.Run(ctx, cfg, ":"+cfg.GatewayServerPort)
 
 	for {
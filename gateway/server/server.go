@@ -2,11 +2,14 @@ package gateway
 
 import (
 	"context"
+	"library/pkg/apikey"
 	"library/pkg/config"
+	"library/pkg/middleware"
 	"library/pkg/utils"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strings"
 )
 
 func Run(ctx context.Context, cfg config.GlobalEnv, port string) {
@@ -41,17 +44,11 @@ func Run(ctx context.Context, cfg config.GlobalEnv, port string) {
 		usersProxy.ServeHTTP(w, r)
 	})
 
-	http.HandleFunc("/books", func(w http.ResponseWriter, r *http.Request) {
-		booksProxy.ServeHTTP(w, r)
-	})
+	http.Handle("/books", authenticate(booksProxy))
 
-	http.HandleFunc("/shops", func(w http.ResponseWriter, r *http.Request) {
-		shopsProxy.ServeHTTP(w, r)
-	})
+	http.Handle("/shops", authenticate(shopsProxy))
 
-	http.HandleFunc("/transactions", func(w http.ResponseWriter, r *http.Request) {
-		transactionsProxy.ServeHTTP(w, r)
-	})
+	http.Handle("/transactions", authenticate(transactionsProxy))
 
 	log.Infof("API Gateway server is starting on :%s", cfg.GatewayServerPort)
 
@@ -59,3 +56,37 @@ func Run(ctx context.Context, cfg config.GlobalEnv, port string) {
 		log.Errorf("Failed to start API Gateway: %v", err)
 	}
 }
+
+// authenticate refuses the requests carrying an access token that does not verify
+// against the JWKS of the users service, before they reach a service. Requests without
+// a token or with an API key are left to the services. The users routes are not
+// checked, a session is refreshed there once its access token expired.
+func authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := accessToken(r)
+		if token != "" && !apikey.IsKey(token) {
+			if _, err := middleware.VerifyJWT(token); err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":"unauthorized"}`))
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func accessToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	}
+
+	cookie, err := r.Cookie("token")
+	if err != nil {
+		return ""
+	}
+
+	return cookie.Value
+}
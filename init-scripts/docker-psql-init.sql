@@ -1,16 +1,106 @@
 \c booksdb
 
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(20) PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR(50) PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(20) NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    permission VARCHAR(50) NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name) VALUES ('user'), ('superuser')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO permissions (name) VALUES
+    ('users:read'),
+    ('users:write:any'),
+    ('users:delete'),
+    ('users:roles'),
+    ('users:unlock'),
+    ('books:read'),
+    ('books:write'),
+    ('books:read:any'),
+    ('books:write:any'),
+    ('authors:write'),
+    ('authors:merge'),
+    ('shops:import'),
+    ('reviews:write'),
+    ('reviews:moderate'),
+    ('transactions:create'),
+    ('transactions:read'),
+    ('transactions:read:any')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('user', 'books:read'),
+    ('user', 'books:write'),
+    ('user', 'reviews:write'),
+    ('user', 'transactions:create'),
+    ('user', 'transactions:read'),
+    ('superuser', 'users:read'),
+    ('superuser', 'users:write:any'),
+    ('superuser', 'users:delete'),
+    ('superuser', 'users:roles'),
+    ('superuser', 'users:unlock'),
+    ('superuser', 'books:read'),
+    ('superuser', 'books:write'),
+    ('superuser', 'books:read:any'),
+    ('superuser', 'books:write:any'),
+    ('superuser', 'authors:write'),
+    ('superuser', 'authors:merge'),
+    ('superuser', 'shops:import'),
+    ('superuser', 'reviews:write'),
+    ('superuser', 'reviews:moderate'),
+    ('superuser', 'transactions:create'),
+    ('superuser', 'transactions:read'),
+    ('superuser', 'transactions:read:any')
+ON CONFLICT DO NOTHING;
+
 CREATE TABLE IF NOT EXISTS users (
     id SERIAL PRIMARY KEY,
     firstname VARCHAR(100) NOT NULL,
     lastname VARCHAR(100) NOT NULL,
     email VARCHAR(100) NOT NULL,
     password VARCHAR(200) NOT NULL,
-    role VARCHAR(20),
+    role VARCHAR(20) NOT NULL DEFAULT 'user' REFERENCES roles (name),
     activated boolean DEFAULT false,
+    mfa_secret TEXT,
+    mfa_enabled BOOLEAN NOT NULL DEFAULT false,
+    mfa_last_step BIGINT NOT NULL DEFAULT 0,
     UNIQUE (email)
 );
 
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    used_at TIMESTAMPTZ,
+    UNIQUE (user_id, code_hash)
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    hint VARCHAR(20) NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_user_idx ON api_keys (user_id);
+
 CREATE TABLE IF NOT EXISTS transactions (
     id SERIAL PRIMARY KEY,
     user_id INTEGER REFERENCES users (id),
@@ -21,7 +111,10 @@ CREATE TABLE IF NOT EXISTS transactions (
 
 CREATE TABLE IF NOT EXISTS author (
     id SERIAL PRIMARY KEY,
-    name VARCHAR(100) NOT NULL
+    name VARCHAR(100) NOT NULL,
+    biography TEXT,
+    name_key TEXT GENERATED ALWAYS AS (regexp_replace(lower(name), '[^[:alnum:]]+', '', 'g')) STORED,
+    search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', coalesce(name, ''))) STORED
 );
 
 CREATE TABLE IF NOT EXISTS user_book (
@@ -31,7 +124,11 @@ CREATE TABLE IF NOT EXISTS user_book (
     isbn VARCHAR(50),
     page_count INTEGER,
     user_id INTEGER REFERENCES users (id),
-    author_id INTEGER REFERENCES author (id) ON DELETE CASCADE
+    version INTEGER NOT NULL DEFAULT 1,
+    deleted_at TIMESTAMPTZ,
+    cover JSONB,
+    cover_keys TEXT[],
+    search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', coalesce(name, ''))) STORED
 );
 
 CREATE TABLE IF NOT EXISTS book (
@@ -40,9 +137,17 @@ CREATE TABLE IF NOT EXISTS book (
     date_published DATE,
     isbn VARCHAR(50),
     page_count INTEGER,
-    quantity INTEGER
+    quantity INTEGER,
+    search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', coalesce(name, ''))) STORED
 );
 
+CREATE INDEX IF NOT EXISTS author_search_idx ON author USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS author_name_key_idx ON author (name_key);
+CREATE INDEX IF NOT EXISTS author_name_trgm_idx ON author USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS user_book_search_idx ON user_book USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS user_book_deleted_at_idx ON user_book (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS book_search_idx ON book USING GIN (search_vector);
+
 CREATE TABLE book_authors  (
     book_id INTEGER,
     author_id INTEGER,
@@ -51,9 +156,94 @@ CREATE TABLE book_authors  (
     FOREIGN KEY (author_id) REFERENCES author(id)
 );
 
+CREATE TABLE IF NOT EXISTS user_book_authors (
+    user_book_id INTEGER NOT NULL REFERENCES user_book (id) ON DELETE CASCADE,
+    author_id INTEGER NOT NULL REFERENCES author (id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'author' CHECK (role IN ('author', 'editor', 'translator', 'illustrator')),
+    position INTEGER NOT NULL,
+    PRIMARY KEY (user_book_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS user_book_authors_author_idx ON user_book_authors (author_id);
+
+CREATE TABLE IF NOT EXISTS user_book_history (
+    id SERIAL PRIMARY KEY,
+    user_book_id INTEGER NOT NULL REFERENCES user_book (id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'revert')),
+    actor_id INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    before JSONB,
+    after JSONB,
+    changes JSONB NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS user_book_history_book_idx ON user_book_history (user_book_id, id);
+
+CREATE TABLE IF NOT EXISTS reading_sessions (
+    id SERIAL PRIMARY KEY,
+    user_book_id INTEGER NOT NULL REFERENCES user_book (id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('want_to_read', 'reading', 'finished', 'abandoned')),
+    current_page INTEGER NOT NULL DEFAULT 0 CHECK (current_page >= 0),
+    started_at DATE,
+    finished_at DATE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (finished_at IS NULL OR started_at IS NULL OR finished_at >= started_at)
+);
+
+CREATE INDEX IF NOT EXISTS reading_sessions_book_idx ON reading_sessions (user_book_id, id);
+
+CREATE TABLE IF NOT EXISTS shelf (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    public BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS shelf_user_name_idx ON shelf (user_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS shelf_books (
+    shelf_id INTEGER NOT NULL REFERENCES shelf (id) ON DELETE CASCADE,
+    user_book_id INTEGER NOT NULL REFERENCES user_book (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (shelf_id, user_book_id)
+);
+
+CREATE INDEX IF NOT EXISTS shelf_books_book_idx ON shelf_books (user_book_id);
+
+CREATE TABLE IF NOT EXISTS book_reviews (
+    id SERIAL PRIMARY KEY,
+    book_id INTEGER NOT NULL REFERENCES book (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    review TEXT,
+    hidden BOOLEAN NOT NULL DEFAULT false,
+    moderated_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    moderated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (book_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS book_reviews_listing_idx ON book_reviews (book_id, updated_at DESC, id DESC);
+
 ALTER TABLE users OWNER TO tmosto;
 ALTER TABLE user_book OWNER TO tmosto;
 ALTER TABLE transactions OWNER TO tmosto;
 ALTER TABLE author OWNER TO tmosto;
 ALTER TABLE book OWNER TO tmosto;
 ALTER TABLE book_authors  OWNER TO tmosto;
+ALTER TABLE user_book_authors OWNER TO tmosto;
+ALTER TABLE user_book_history OWNER TO tmosto;
+ALTER TABLE reading_sessions OWNER TO tmosto;
+ALTER TABLE shelf OWNER TO tmosto;
+ALTER TABLE shelf_books OWNER TO tmosto;
+ALTER TABLE book_reviews OWNER TO tmosto;
+ALTER TABLE roles OWNER TO tmosto;
+ALTER TABLE permissions OWNER TO tmosto;
+ALTER TABLE role_permissions OWNER TO tmosto;
+ALTER TABLE user_recovery_codes OWNER TO tmosto;
+ALTER TABLE api_keys OWNER TO tmosto;
@@ -15,4 +15,4 @@ POSTGRES_BOOKS_CONTAINER_IP=172.19.0.3
 NETWORK=bookapi_library
 GOPATH=$HOME/go
 PATH=$PATH:/usr/local/go/bin
-SECRET_KEY=mysecretkeyshh
+JWKS_URL=http://users:5000/v1/users/.well-known/jwks.json
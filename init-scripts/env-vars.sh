@@ -20,7 +20,7 @@ echo "export POSTGRES_BOOKS_CONTAINER_IP=172.19.0.3" >> "$SOURCE_FILE"
 echo "export GOPATH=/usr/local/go" >> "$SOURCE_FILE"
 echo "export GOROOT=/usr/local/go" >> "$SOURCE_FILE"
 echo "export PATH=$PATH:/usr/local/go/bin" >> "$SOURCE_FILE"
-echo "export SECRET_KEY=mysecretkeyshh" >> "$SOURCE_FILE"
+echo "export JWKS_URL=http://localhost:5000/v1/users/.well-known/jwks.json" >> "$SOURCE_FILE"
 echo "export NETWORK=bookapi_library" >> "$SOURCE_FILE"
 echo "export GOPATH=$HOME/go" >> "$SOURCE_FILE"
 
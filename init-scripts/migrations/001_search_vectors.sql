@@ -0,0 +1,17 @@
+\c booksdb
+
+ALTER TABLE author
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', coalesce(name, ''))) STORED;
+
+ALTER TABLE user_book
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', coalesce(name, ''))) STORED;
+
+ALTER TABLE book
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', coalesce(name, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS author_search_idx ON author USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS user_book_search_idx ON user_book USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS book_search_idx ON book USING GIN (search_vector);
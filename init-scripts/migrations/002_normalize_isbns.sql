@@ -0,0 +1,43 @@
+\c booksdb
+
+-- Returns the canonical ISBN-13 of an ISBN-10 or ISBN-13, or NULL when the value is not a valid ISBN.
+CREATE OR REPLACE FUNCTION normalize_isbn(value TEXT) RETURNS TEXT AS $$
+DECLARE
+    cleaned TEXT := upper(regexp_replace(coalesce(value, ''), '[\s-]', '', 'g'));
+    isbn TEXT;
+    total INT := 0;
+BEGIN
+    IF cleaned ~ '^[0-9]{9}[0-9X]$' THEN
+        FOR i IN 1..10 LOOP
+            total := total + (11 - i) * CASE WHEN substr(cleaned, i, 1) = 'X' THEN 10 ELSE substr(cleaned, i, 1)::INT END;
+        END LOOP;
+
+        IF total % 11 <> 0 THEN
+            RETURN NULL;
+        END IF;
+
+        isbn := '978' || substr(cleaned, 1, 9);
+    ELSIF cleaned ~ '^97[89][0-9]{10}$' THEN
+        isbn := substr(cleaned, 1, 12);
+    ELSE
+        RETURN NULL;
+    END IF;
+
+    total := 0;
+    FOR i IN 1..12 LOOP
+        total := total + substr(isbn, i, 1)::INT * CASE WHEN i % 2 = 0 THEN 3 ELSE 1 END;
+    END LOOP;
+
+    isbn := isbn || ((10 - total % 10) % 10)::TEXT;
+
+    IF length(cleaned) = 13 AND isbn <> cleaned THEN
+        RETURN NULL;
+    END IF;
+
+    RETURN isbn;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Invalid values are left as they are so they can be fixed by hand.
+UPDATE user_book SET isbn = normalize_isbn(isbn) WHERE normalize_isbn(isbn) IS NOT NULL AND isbn <> normalize_isbn(isbn);
+UPDATE book SET isbn = normalize_isbn(isbn) WHERE normalize_isbn(isbn) IS NOT NULL AND isbn <> normalize_isbn(isbn);
@@ -0,0 +1,26 @@
+\c booksdb
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS user_book_authors (
+    user_book_id INTEGER NOT NULL REFERENCES user_book (id) ON DELETE CASCADE,
+    author_id INTEGER NOT NULL REFERENCES author (id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'author' CHECK (role IN ('author', 'editor', 'translator', 'illustrator')),
+    position INTEGER NOT NULL,
+    PRIMARY KEY (user_book_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS user_book_authors_author_idx ON user_book_authors (author_id);
+
+-- every existing book keeps its single author as the first contributor
+INSERT INTO user_book_authors (user_book_id, author_id, role, position)
+SELECT id, author_id, 'author', 1
+FROM user_book
+WHERE author_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE user_book DROP COLUMN IF EXISTS author_id;
+
+ALTER TABLE user_book_authors OWNER TO tmosto;
+
+COMMIT;
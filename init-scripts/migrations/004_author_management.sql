@@ -0,0 +1,13 @@
+\c booksdb
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE author
+    ADD COLUMN IF NOT EXISTS biography TEXT;
+
+ALTER TABLE author
+    ADD COLUMN IF NOT EXISTS name_key TEXT
+    GENERATED ALWAYS AS (regexp_replace(lower(name), '[^[:alnum:]]+', '', 'g')) STORED;
+
+CREATE INDEX IF NOT EXISTS author_name_key_idx ON author (name_key);
+CREATE INDEX IF NOT EXISTS author_name_trgm_idx ON author USING GIN (name gin_trgm_ops);
@@ -0,0 +1,4 @@
+\c booksdb
+
+ALTER TABLE user_book
+    ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
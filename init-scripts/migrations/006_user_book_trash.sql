@@ -0,0 +1,6 @@
+\c booksdb
+
+ALTER TABLE user_book
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS user_book_deleted_at_idx ON user_book (deleted_at) WHERE deleted_at IS NOT NULL;
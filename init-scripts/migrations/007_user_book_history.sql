@@ -0,0 +1,17 @@
+\c booksdb
+
+CREATE TABLE IF NOT EXISTS user_book_history (
+    id SERIAL PRIMARY KEY,
+    user_book_id INTEGER NOT NULL REFERENCES user_book (id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'revert')),
+    actor_id INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    before JSONB,
+    after JSONB,
+    changes JSONB NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS user_book_history_book_idx ON user_book_history (user_book_id, id);
+
+ALTER TABLE user_book_history OWNER TO tmosto;
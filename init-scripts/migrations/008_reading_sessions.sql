@@ -0,0 +1,16 @@
+\c booksdb
+
+CREATE TABLE IF NOT EXISTS reading_sessions (
+    id SERIAL PRIMARY KEY,
+    user_book_id INTEGER NOT NULL REFERENCES user_book (id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('want_to_read', 'reading', 'finished', 'abandoned')),
+    current_page INTEGER NOT NULL DEFAULT 0 CHECK (current_page >= 0),
+    started_at DATE,
+    finished_at DATE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (finished_at IS NULL OR started_at IS NULL OR finished_at >= started_at)
+);
+
+CREATE INDEX IF NOT EXISTS reading_sessions_book_idx ON reading_sessions (user_book_id, id);
+
+ALTER TABLE reading_sessions OWNER TO tmosto;
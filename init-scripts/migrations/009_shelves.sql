@@ -0,0 +1,25 @@
+\c booksdb
+
+CREATE TABLE IF NOT EXISTS shelf (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    public BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS shelf_user_name_idx ON shelf (user_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS shelf_books (
+    shelf_id INTEGER NOT NULL REFERENCES shelf (id) ON DELETE CASCADE,
+    user_book_id INTEGER NOT NULL REFERENCES user_book (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (shelf_id, user_book_id)
+);
+
+CREATE INDEX IF NOT EXISTS shelf_books_book_idx ON shelf_books (user_book_id);
+
+ALTER TABLE shelf OWNER TO tmosto;
+ALTER TABLE shelf_books OWNER TO tmosto;
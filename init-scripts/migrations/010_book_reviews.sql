@@ -0,0 +1,19 @@
+\c booksdb
+
+CREATE TABLE IF NOT EXISTS book_reviews (
+    id SERIAL PRIMARY KEY,
+    book_id INTEGER NOT NULL REFERENCES book (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    review TEXT,
+    hidden BOOLEAN NOT NULL DEFAULT false,
+    moderated_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    moderated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (book_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS book_reviews_listing_idx ON book_reviews (book_id, updated_at DESC, id DESC);
+
+ALTER TABLE book_reviews OWNER TO tmosto;
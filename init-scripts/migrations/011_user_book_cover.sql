@@ -0,0 +1,5 @@
+\c booksdb
+
+ALTER TABLE user_book
+    ADD COLUMN IF NOT EXISTS cover JSONB,
+    ADD COLUMN IF NOT EXISTS cover_keys TEXT[];
@@ -0,0 +1,55 @@
+\c booksdb
+
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(20) PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR(50) PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(20) NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    permission VARCHAR(50) NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name) VALUES ('user'), ('superuser')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO permissions (name) VALUES
+    ('users:read'),
+    ('users:delete'),
+    ('users:roles'),
+    ('books:write:any'),
+    ('authors:merge'),
+    ('shops:import'),
+    ('reviews:moderate'),
+    ('transactions:create'),
+    ('transactions:read')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('user', 'transactions:create'),
+    ('user', 'transactions:read'),
+    ('superuser', 'users:read'),
+    ('superuser', 'users:delete'),
+    ('superuser', 'users:roles'),
+    ('superuser', 'books:write:any'),
+    ('superuser', 'authors:merge'),
+    ('superuser', 'shops:import'),
+    ('superuser', 'reviews:moderate'),
+    ('superuser', 'transactions:create'),
+    ('superuser', 'transactions:read')
+ON CONFLICT DO NOTHING;
+
+UPDATE users SET role = 'user' WHERE role IS NULL OR role NOT IN (SELECT name FROM roles);
+
+ALTER TABLE users
+    ALTER COLUMN role SET DEFAULT 'user',
+    ALTER COLUMN role SET NOT NULL,
+    ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES roles (name);
+
+ALTER TABLE roles OWNER TO tmosto;
+ALTER TABLE permissions OWNER TO tmosto;
+ALTER TABLE role_permissions OWNER TO tmosto;
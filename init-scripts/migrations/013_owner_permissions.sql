@@ -0,0 +1,13 @@
+\c booksdb
+
+INSERT INTO permissions (name) VALUES
+    ('users:write:any'),
+    ('books:read:any'),
+    ('transactions:read:any')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('superuser', 'users:write:any'),
+    ('superuser', 'books:read:any'),
+    ('superuser', 'transactions:read:any')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,7 @@
+\c booksdb
+
+INSERT INTO permissions (name) VALUES ('users:unlock')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES ('superuser', 'users:unlock')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,8 @@
+\c booksdb
+
+INSERT INTO permissions (name) VALUES ('authors:write')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('superuser', 'authors:write')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,13 @@
+\c booksdb
+
+INSERT INTO permissions (name) VALUES
+    ('books:read'),
+    ('books:write'),
+    ('reviews:write')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission)
+SELECT r.name, p.name
+FROM roles AS r CROSS JOIN permissions AS p
+WHERE p.name IN ('books:read', 'books:write', 'reviews:write')
+ON CONFLICT DO NOTHING;
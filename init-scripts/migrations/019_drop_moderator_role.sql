@@ -0,0 +1,7 @@
+\c booksdb
+
+-- merging authors and moderating reviews are for superusers only, which leaves
+-- the moderator role nothing its users do not already hold
+UPDATE users SET role = 'user' WHERE role = 'moderator';
+
+DELETE FROM roles WHERE name = 'moderator';
@@ -0,0 +1,94 @@
+// Package apikey issues the personal access tokens of scripts and CI, and resolves
+// them into the claims of their owner for the auth middleware.
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"library/pkg/utils"
+	"library/users/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Prefix marks the bearer credentials that are API keys rather than access tokens.
+const Prefix = "lib_"
+
+// hintLength is the number of characters of a key kept in clear to tell keys apart.
+const hintLength = len(Prefix) + 8
+
+var ErrInvalidKey = errors.New("invalid API key")
+
+const resolveKey = `
+	UPDATE api_keys k SET last_used_at = now()
+	FROM users u
+	WHERE k.key_hash = $1 AND k.revoked_at IS NULL AND k.expires_at > now() AND u.id = k.user_id
+	RETURNING k.id, u.id, u.email, u.role, k.expires_at,
+		ARRAY(SELECT rp.permission FROM role_permissions rp WHERE rp.role = u.role AND rp.permission = ANY(k.scopes) ORDER BY rp.permission)
+	`
+
+// Generate returns a new key and its hint, the start of the key shown in listings.
+func Generate() (key, hint string, err error) {
+	random := make([]byte, 32)
+	if _, err = rand.Read(random); err != nil {
+		return "", "", err
+	}
+
+	key = Prefix + hex.EncodeToString(random)
+
+	return key, key[:hintLength], nil
+}
+
+// IsKey reports whether a bearer credential is an API key.
+func IsKey(credential string) bool {
+	return strings.HasPrefix(credential, Prefix)
+}
+
+// Hash returns the form of a key that is stored, keys are only shown once.
+func Hash(key string) string {
+	return utils.HashToken(key)
+}
+
+// Store resolves the API keys stored in the users database.
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// ResolveAPIKey returns the claims of the owner of an active key. The permissions
+// are the scopes of the key that the role of the owner still grants.
+func (s *Store) ResolveAPIKey(ctx context.Context, key string) (*models.Claims, error) {
+	var (
+		claims    models.Claims
+		keyID     int
+		userID    int
+		expiresAt time.Time
+	)
+
+	err := s.db.QueryRowContext(ctx, resolveKey, Hash(key)).Scan(
+		&keyID, &userID, &claims.Email, &claims.Role, &expiresAt, pq.Array(&claims.Permissions),
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidKey
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims.UserID = strconv.Itoa(userID)
+	claims.APIKeyID = keyID
+	claims.Subject = claims.Email
+	claims.ExpiresAt = expiresAt.Unix()
+
+	return &claims, nil
+}
@@ -0,0 +1,104 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGenerate(t *testing.T) {
+	key, hint, err := Generate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !IsKey(key) || len(key) != len(Prefix)+64 {
+		t.Errorf("unexpected key %q", key)
+	}
+
+	if !strings.HasPrefix(key, hint) || len(hint) != hintLength {
+		t.Errorf("unexpected hint %q of key %q", hint, key)
+	}
+
+	other, _, err := Generate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if other == key {
+		t.Errorf("expected a new key, got %q twice", key)
+	}
+}
+
+func TestResolveAPIKey(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name string
+		rows *sqlmock.Rows
+		err  error
+		want error
+	}{
+		{
+			name: "active key",
+			rows: sqlmock.NewRows([]string{"id", "user_id", "email", "role", "expires_at", "permissions"}).
+				AddRow(3, 7, "ci@example.com", "superuser", expiresAt, "{shops:import}"),
+		},
+		{
+			name: "unknown, expired or revoked key",
+			rows: sqlmock.NewRows([]string{"id", "user_id", "email", "role", "expires_at", "permissions"}),
+			want: ErrInvalidKey,
+		},
+		{
+			name: "database unavailable",
+			err:  errors.New("connection refused"),
+			want: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer db.Close()
+
+			query := mock.ExpectQuery(resolveKey).WithArgs(Hash("lib_key"))
+			if tt.err != nil {
+				query.WillReturnError(tt.err)
+			} else {
+				query.WillReturnRows(tt.rows)
+			}
+
+			claims, err := NewStore(db).ResolveAPIKey(context.Background(), "lib_key")
+			if tt.want != nil {
+				if err == nil || err.Error() != tt.want.Error() {
+					t.Fatalf("expected error %v, got %v", tt.want, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if claims.UserID != "7" || claims.APIKeyID != 3 || claims.Role != "superuser" || claims.ExpiresAt != expiresAt.Unix() {
+				t.Errorf("unexpected claims %+v", claims)
+			}
+
+			if !reflect.DeepEqual(claims.Permissions, []string{"shops:import"}) {
+				t.Errorf("expected the scoped permissions, got %v", claims.Permissions)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Package authors holds the author lookups shared by the books and shops services.
+package authors
+
+import (
+	"database/sql"
+	"errors"
+	"library/pkg/postgres"
+	"strings"
+)
+
+// Authors are matched on name_key, the lower-cased name without spaces and punctuation,
+// so "J.R.R. Tolkien" and "J. R. R. Tolkien" are the same author.
+const (
+	SelectAuthor = "SELECT id, name FROM author WHERE name_key = regexp_replace(lower($1), '[^[:alnum:]]+', '', 'g') ORDER BY id LIMIT 1"
+	InsertAuthor = "INSERT INTO author (name) VALUES ($1) RETURNING id"
+)
+
+var ErrEmptyName = errors.New("author name cannot be empty")
+
+// GetOrCreate returns the ID and stored name of the author matching name, creating the author when there is none.
+func GetOrCreate(db postgres.Queryer, name string) (int, string, error) {
+	var id int
+	var storedName string
+
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return 0, "", ErrEmptyName
+	}
+
+	err := db.QueryRow(SelectAuthor, name).Scan(&id, &storedName)
+	if err == nil {
+		return id, storedName, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, "", err
+	}
+
+	if err = db.QueryRow(InsertAuthor, name).Scan(&id); err != nil {
+		return 0, "", err
+	}
+
+	return id, name, nil
+}
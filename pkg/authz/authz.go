@@ -0,0 +1,81 @@
+// Package authz checks what the caller of a route is allowed to do. Roles are
+// granted named permissions in the role_permissions table, and the permissions of
+// the user's role are embedded in the access token at login.
+package authz
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Roles seeded by the init scripts. New users always start as RoleUser.
+const (
+	RoleUser      = "user"
+	RoleSuperuser = "superuser"
+)
+
+// The :any permissions let a user act on the routes of other users, see RequireOwnerOr.
+// Every role holds the books:read, books:write and reviews:write scopes, so API keys
+// can be given them.
+const (
+	UsersRead     = "users:read"
+	UsersWriteAny = "users:write:any"
+	UsersDelete   = "users:delete"
+	UsersRoles    = "users:roles"
+	UsersUnlock   = "users:unlock"
+
+	BooksRead     = "books:read"
+	BooksWrite    = "books:write"
+	BooksReadAny  = "books:read:any"
+	BooksWriteAny = "books:write:any"
+	AuthorsWrite  = "authors:write"
+	AuthorsMerge  = "authors:merge"
+
+	ShopsImport     = "shops:import"
+	ReviewsWrite    = "reviews:write"
+	ReviewsModerate = "reviews:moderate"
+
+	TransactionsCreate  = "transactions:create"
+	TransactionsRead    = "transactions:read"
+	TransactionsReadAny = "transactions:read:any"
+)
+
+// HasPermission reports whether every one of the wanted permissions is granted.
+func HasPermission(granted []string, wanted ...string) bool {
+	for _, permission := range wanted {
+		found := false
+		for _, g := range granted {
+			if g == permission {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Can reports whether the caller of the request holds the permissions, for routes
+// where a permission only changes what is returned.
+func Can(c *gin.Context, permissions ...string) bool {
+	return HasPermission(c.GetStringSlice("permissions"), permissions...)
+}
+
+// RequirePermission only lets through callers holding all the permissions. It runs
+// after middleware.IsAuthorized, which puts the permissions of the token in the context.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !Can(c, permissions...) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not enough permissions"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
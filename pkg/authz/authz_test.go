@@ -0,0 +1,69 @@
+package authz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHasPermission(t *testing.T) {
+	tests := []struct {
+		name    string
+		granted []string
+		wanted  []string
+		want    bool
+	}{
+		{"nothing wanted", nil, nil, true},
+		{"granted", []string{UsersRead, UsersDelete}, []string{UsersDelete}, true},
+		{"all granted", []string{UsersRead, UsersDelete}, []string{UsersDelete, UsersRead}, true},
+		{"one missing", []string{UsersRead}, []string{UsersRead, UsersDelete}, false},
+		{"no permissions", nil, []string{UsersRead}, false},
+		{"no prefix matching", []string{"books:write"}, []string{BooksWriteAny}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasPermission(tt.granted, tt.wanted...); got != tt.want {
+				t.Errorf("HasPermission(%v, %v) = %v, want %v", tt.granted, tt.wanted, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		permissions []string
+		want        int
+	}{
+		{"granted", []string{ShopsImport}, http.StatusOK},
+		{"not granted", []string{ReviewsModerate}, http.StatusForbidden},
+		{"not authorized", nil, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/",
+				func(c *gin.Context) {
+					if tt.permissions != nil {
+						c.Set("permissions", tt.permissions)
+					}
+				},
+				RequirePermission(ShopsImport),
+				func(c *gin.Context) { c.Status(http.StatusOK) },
+			)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != tt.want {
+				t.Errorf("got status %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
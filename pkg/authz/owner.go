@@ -0,0 +1,48 @@
+package authz
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireOwner only lets callers act on their own user_id. It runs after
+// middleware.GetToken, which puts the token subject in the context as userID. API keys
+// also need the scope, an empty scope refuses them.
+func RequireOwner(scope string) gin.HandlerFunc {
+	return requireOwner("", scope)
+}
+
+// RequireOwnerOr lets callers act on their own user_id, and callers holding the
+// permission act on any user. The handlers find the user of the path as userID, the
+// caller stays in the context as actorID.
+func RequireOwnerOr(permission, scope string) gin.HandlerFunc {
+	return requireOwner(permission, scope)
+}
+
+func requireOwner(permission, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.Atoi(c.Param("user_id"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		if userID != c.GetInt("userID") && (permission == "" || !Can(c, permission)) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not enough permissions"})
+			c.Abort()
+			return
+		}
+
+		if !allowsScope(c, scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API key lacks the " + scope + " scope"})
+			c.Abort()
+			return
+		}
+
+		c.Set("userID", userID)
+		c.Next()
+	}
+}
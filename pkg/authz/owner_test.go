@@ -0,0 +1,63 @@
+package authz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireOwner(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		middleware  gin.HandlerFunc
+		path        string
+		permissions []string
+		apiKeyID    int
+		want        int
+		wantUserID  int
+	}{
+		{"own user", RequireOwner(""), "/1", nil, 0, http.StatusOK, 1},
+		{"other user", RequireOwner(""), "/2", nil, 0, http.StatusForbidden, 0},
+		{"other user with a permission", RequireOwner(""), "/2", []string{BooksWriteAny}, 0, http.StatusForbidden, 0},
+		{"own user or permission", RequireOwnerOr(BooksWriteAny, BooksWrite), "/1", nil, 0, http.StatusOK, 1},
+		{"other user without the permission", RequireOwnerOr(BooksWriteAny, BooksWrite), "/2", []string{BooksReadAny}, 0, http.StatusForbidden, 0},
+		{"other user with the permission", RequireOwnerOr(BooksWriteAny, BooksWrite), "/2", []string{BooksWriteAny}, 0, http.StatusOK, 2},
+		{"invalid user", RequireOwnerOr(BooksWriteAny, BooksWrite), "/me", []string{BooksWriteAny}, 0, http.StatusNotFound, 0},
+		{"API key with the scope", RequireOwnerOr(BooksWriteAny, BooksWrite), "/1", []string{BooksWrite}, 3, http.StatusOK, 1},
+		{"API key without scopes", RequireOwnerOr(BooksWriteAny, BooksWrite), "/1", nil, 3, http.StatusForbidden, 0},
+		{"API key with another scope", RequireOwnerOr(BooksWriteAny, BooksWrite), "/1", []string{BooksRead}, 3, http.StatusForbidden, 0},
+		{"API key on other users without the scope", RequireOwnerOr(BooksWriteAny, BooksWrite), "/2", []string{BooksWriteAny}, 3, http.StatusForbidden, 0},
+		{"API key on a route without a scope", RequireOwner(""), "/1", []string{BooksWrite}, 3, http.StatusForbidden, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/:user_id",
+				func(c *gin.Context) {
+					c.Set("userID", 1)
+					c.Set("permissions", tt.permissions)
+					c.Set("apiKeyID", tt.apiKeyID)
+				},
+				tt.middleware,
+				func(c *gin.Context) { c.String(http.StatusOK, strconv.Itoa(c.GetInt("userID"))) },
+			)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.want {
+				t.Fatalf("got status %d, want %d", w.Code, tt.want)
+			}
+
+			if tt.want == http.StatusOK && w.Body.String() != strconv.Itoa(tt.wantUserID) {
+				t.Errorf("got user %s, want %d", w.Body.String(), tt.wantUserID)
+			}
+		})
+	}
+}
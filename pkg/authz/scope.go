@@ -0,0 +1,47 @@
+package authz
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IsAPIKey reports whether the request was authenticated with an API key. It runs
+// after middleware.GetToken, which puts the ID of the key in the context as apiKeyID.
+func IsAPIKey(c *gin.Context) bool {
+	return c.GetInt("apiKeyID") != 0
+}
+
+// RequireSession refuses API keys, for the routes managing the account itself: a
+// leaked key must not be able to read or change the email, the second factor or the keys.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if IsAPIKey(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API keys cannot manage the account, log in instead"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireScope only lets API keys through when they were given the scope. Logged in
+// users are not limited by scopes.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !allowsScope(c, scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API key lacks the " + scope + " scope"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// allowsScope reports whether the caller may use a route of the scope. The permissions
+// of an API key are its scopes, and an empty scope is never given to a key.
+func allowsScope(c *gin.Context, scope string) bool {
+	return !IsAPIKey(c) || (scope != "" && Can(c, scope))
+}
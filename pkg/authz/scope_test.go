@@ -0,0 +1,49 @@
+package authz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		middleware  gin.HandlerFunc
+		permissions []string
+		apiKeyID    int
+		want        int
+	}{
+		{"session", RequireScope(ReviewsWrite), nil, 0, http.StatusOK},
+		{"API key with the scope", RequireScope(ReviewsWrite), []string{ReviewsWrite}, 3, http.StatusOK},
+		{"API key without scopes", RequireScope(ReviewsWrite), nil, 3, http.StatusForbidden},
+		{"API key with another scope", RequireScope(ReviewsWrite), []string{BooksWrite}, 3, http.StatusForbidden},
+		{"session on an account route", RequireSession(), nil, 0, http.StatusOK},
+		{"API key on an account route", RequireSession(), []string{UsersWriteAny}, 3, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/",
+				func(c *gin.Context) {
+					c.Set("permissions", tt.permissions)
+					c.Set("apiKeyID", tt.apiKeyID)
+				},
+				tt.middleware,
+				func(c *gin.Context) { c.Status(http.StatusOK) },
+			)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != tt.want {
+				t.Errorf("got status %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
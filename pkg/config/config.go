@@ -1,15 +1,34 @@
 package config
 
+import "time"
+
 type GlobalEnv struct {
-	GatewayServerPort      string `envconfig:"gateway_server_port"`
-	UsersServerPort        string `envconfig:"users_server_port"`
-	BooksServerPort        string `envconfig:"books_server_port"`
-	ShopsServerPort        string `envconfig:"shops_server_port"`
-	TransactionsServerPort string `envconfig:"transactions_server_port"`
-	RedisPort              string `envconfig:"redis_port"`
-	PostgresBooks          string `envconfig:"postgres_books"`
-	RabbitMQ               string `envconfig:"rabbitmq"`
-	SecretKey              string `envconfig:"secret_key"`
-	Network                string `envconfig:"network"`
-	AutoSplitVar           string `split_words:"true"`
+	GatewayServerPort      string        `envconfig:"gateway_server_port"`
+	UsersServerPort        string        `envconfig:"users_server_port"`
+	BooksServerPort        string        `envconfig:"books_server_port"`
+	ShopsServerPort        string        `envconfig:"shops_server_port"`
+	TransactionsServerPort string        `envconfig:"transactions_server_port"`
+	RedisPort              string        `envconfig:"redis_port"`
+	PostgresBooks          string        `envconfig:"postgres_books"`
+	RabbitMQ               string        `envconfig:"rabbitmq"`
+	JWTKeysDir             string        `envconfig:"jwt_keys_dir"`
+	JWTSigningKeyID        string        `envconfig:"jwt_signing_key_id"`
+	JWKSURL                string        `envconfig:"jwks_url" default:"http://localhost:5000/v1/users/.well-known/jwks.json"`
+	JWKSCacheTTL           time.Duration `envconfig:"jwks_cache_ttl" default:"10m"`
+	RefreshTokenTTL        time.Duration `envconfig:"refresh_token_ttl" default:"720h"`
+	ActivationURL          string        `envconfig:"activation_url" default:"https://localhost:5000/v1/users/activate"`
+	PasswordResetURL       string        `envconfig:"password_reset_url" default:"https://localhost:3000/password/reset"`
+	Network                string        `envconfig:"network"`
+	TrashRetention         time.Duration `envconfig:"trash_retention" default:"720h"`
+	TrashPurgeInterval     time.Duration `envconfig:"trash_purge_interval" default:"1h"`
+	CoverStorage           string        `envconfig:"cover_storage" default:"local"`
+	CoverDir               string        `envconfig:"cover_dir" default:"covers"`
+	CoverURL               string        `envconfig:"cover_url" default:"/v1/books/covers"`
+	S3Endpoint             string        `envconfig:"s3_endpoint"`
+	S3Region               string        `envconfig:"s3_region" default:"us-east-1"`
+	S3Bucket               string        `envconfig:"s3_bucket"`
+	S3AccessKey            string        `envconfig:"s3_access_key"`
+	S3SecretKey            string        `envconfig:"s3_secret_key"`
+	S3PublicURL            string        `envconfig:"s3_public_url"`
+	AutoSplitVar           string        `split_words:"true"`
 }
@@ -0,0 +1,24 @@
+package pkg
+
+import (
+	"library/books/models"
+	"library/pkg/isbn"
+	"library/pkg/logger"
+)
+
+// CheckISBN validates the book ISBN, when one is given, and replaces it with the canonical ISBN-13.
+func CheckISBN(log logger.Logger, book *models.BookRequest) error {
+	if book.ISBN == "" {
+		return nil
+	}
+
+	normalized, err := isbn.Normalize(book.ISBN)
+	if err != nil {
+		log.Errorf("Failed to validate ISBN: %s", err)
+		return err
+	}
+
+	book.ISBN = normalized
+
+	return nil
+}
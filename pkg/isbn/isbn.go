@@ -0,0 +1,160 @@
+// Package isbn validates ISBN-10 and ISBN-13 numbers and converts them to a
+// canonical ISBN-13 without separators.
+package isbn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Identifier types used by Google Books industryIdentifiers.
+const (
+	TypeISBN10 = "ISBN_10"
+	TypeISBN13 = "ISBN_13"
+)
+
+// Error describes why a value is not a valid ISBN.
+type Error struct {
+	ISBN   string
+	Reason string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid ISBN %q: %s", e.ISBN, e.Reason)
+}
+
+// Clean strips hyphens and spaces and upper-cases the ISBN-10 check character.
+func Clean(value string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(value)))
+}
+
+// Normalize validates an ISBN-10 or ISBN-13 and returns it as a canonical ISBN-13.
+func Normalize(value string) (string, error) {
+	isbn := Clean(value)
+
+	switch len(isbn) {
+	case 10:
+		if err := validate10(value, isbn); err != nil {
+			return "", err
+		}
+
+		return To13(isbn), nil
+	case 13:
+		if err := validate13(value, isbn); err != nil {
+			return "", err
+		}
+
+		return isbn, nil
+	default:
+		return "", &Error{ISBN: value, Reason: fmt.Sprintf("must have 10 or 13 digits, got %d", len(isbn))}
+	}
+}
+
+// Valid reports whether the value is a valid ISBN-10 or ISBN-13.
+func Valid(value string) bool {
+	_, err := Normalize(value)
+	return err == nil
+}
+
+// Equal reports whether two values are the same ISBN, whatever their format.
+// Values that are not valid ISBNs are compared after cleaning.
+func Equal(a, b string) bool {
+	canonicalA, errA := Normalize(a)
+	canonicalB, errB := Normalize(b)
+	if errA != nil || errB != nil {
+		return Clean(a) == Clean(b)
+	}
+
+	return canonicalA == canonicalB
+}
+
+// To13 converts a cleaned, valid ISBN-10 into an ISBN-13.
+func To13(isbn10 string) string {
+	isbn := "978" + isbn10[:9]
+	return isbn + string(checkDigit13(isbn))
+}
+
+// FromIndustryIdentifier returns the canonical ISBN-13 for a Google Books
+// industry identifier, or false when it is not a valid ISBN.
+func FromIndustryIdentifier(identifierType, identifier string) (string, bool) {
+	if identifierType != TypeISBN10 && identifierType != TypeISBN13 {
+		return "", false
+	}
+
+	isbn, err := Normalize(identifier)
+	if err != nil {
+		return "", false
+	}
+
+	return isbn, true
+}
+
+func validate10(value, isbn string) error {
+	sum := 0
+
+	for i, r := range isbn {
+		var digit int
+
+		switch {
+		case r >= '0' && r <= '9':
+			digit = int(r - '0')
+		case r == 'X' && i == 9:
+			digit = 10
+		default:
+			return &Error{ISBN: value, Reason: fmt.Sprintf("unexpected character %q", r)}
+		}
+
+		sum += digit * (10 - i)
+	}
+
+	if sum%11 != 0 {
+		return &Error{ISBN: value, Reason: fmt.Sprintf("wrong check digit, expected %c", checkDigit10(isbn[:9]))}
+	}
+
+	return nil
+}
+
+func validate13(value, isbn string) error {
+	for _, r := range isbn {
+		if r < '0' || r > '9' {
+			return &Error{ISBN: value, Reason: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+
+	if !strings.HasPrefix(isbn, "978") && !strings.HasPrefix(isbn, "979") {
+		return &Error{ISBN: value, Reason: "ISBN-13 must start with 978 or 979"}
+	}
+
+	if expected := checkDigit13(isbn[:12]); isbn[12] != expected {
+		return &Error{ISBN: value, Reason: fmt.Sprintf("wrong check digit, expected %c", expected)}
+	}
+
+	return nil
+}
+
+func checkDigit10(first9 string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(first9[i]-'0') * (10 - i)
+	}
+
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+
+	return byte('0' + check)
+}
+
+func checkDigit13(first12 string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(first12[i]-'0') * weight
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
@@ -0,0 +1,77 @@
+package isbn_test
+
+import (
+	"errors"
+	"library/pkg/isbn"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		reason   string
+	}{
+		{name: "isbn-13", value: "9780261102217", expected: "9780261102217"},
+		{name: "isbn-13 with hyphens", value: "978-0-261-10221-7", expected: "9780261102217"},
+		{name: "isbn-10", value: "0261102214", expected: "9780261102217"},
+		{name: "isbn-10 with spaces", value: " 0 261 10221 4 ", expected: "9780261102217"},
+		{name: "isbn-10 with X check digit", value: "080442957x", expected: "9780804429573"},
+		{name: "979 prefix", value: "979-10-90636-07-1", expected: "9791090636071"},
+		{name: "wrong length", value: "12345", reason: "must have 10 or 13 digits, got 5"},
+		{name: "letters", value: "97802611A2217", reason: "unexpected character 'A'"},
+		{name: "X inside isbn-10", value: "02611X2214", reason: "unexpected character 'X'"},
+		{name: "wrong isbn-10 check digit", value: "0261102215", reason: "wrong check digit, expected 4"},
+		{name: "wrong isbn-13 check digit", value: "9780261102218", reason: "wrong check digit, expected 7"},
+		{name: "unknown prefix", value: "9770261102217", reason: "ISBN-13 must start with 978 or 979"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := isbn.Normalize(tt.value)
+
+			if tt.reason == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got != tt.expected {
+					t.Errorf("expected %s, got %s", tt.expected, got)
+				}
+				return
+			}
+
+			var isbnErr *isbn.Error
+			if !errors.As(err, &isbnErr) {
+				t.Fatalf("expected an isbn error, got %v", err)
+			}
+			if isbnErr.Reason != tt.reason {
+				t.Errorf("expected reason %q, got %q", tt.reason, isbnErr.Reason)
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	if !isbn.Equal("0-261-10221-4", "9780261102217") {
+		t.Error("expected ISBN-10 and ISBN-13 of the same book to be equal")
+	}
+
+	if isbn.Equal("9780261102217", "9780441172719") {
+		t.Error("expected different books not to be equal")
+	}
+}
+
+func TestFromIndustryIdentifier(t *testing.T) {
+	if got, ok := isbn.FromIndustryIdentifier(isbn.TypeISBN10, "0261102214"); !ok || got != "9780261102217" {
+		t.Errorf("expected 9780261102217, got %q", got)
+	}
+
+	if _, ok := isbn.FromIndustryIdentifier("OTHER", "PKEY:0261102214"); ok {
+		t.Error("expected other identifier types to be ignored")
+	}
+
+	if _, ok := isbn.FromIndustryIdentifier(isbn.TypeISBN13, "9780261102218"); ok {
+		t.Error("expected an invalid ISBN to be ignored")
+	}
+}
@@ -0,0 +1,66 @@
+// Package jwks holds the RSA keys that sign the access tokens. The users service signs
+// with a KeySet and publishes its public keys as a JSON Web Key Set, the other services
+// verify the tokens with a Remote copy of that set.
+package jwks
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+)
+
+// Algorithm is the signing algorithm of the access tokens.
+const Algorithm = "RS256"
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// JWK is the public half of a signing key, as published in the JWKS (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+}
+
+// Set is a JSON Web Key Set.
+type Set struct {
+	Keys []JWK `json:"keys"`
+}
+
+func newJWK(kid string, key *rsa.PublicKey) JWK {
+	return JWK{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: Algorithm,
+		KeyID:     kid,
+		N:         base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// publicKey decodes an RSA signing key of the set, other keys are not for us.
+func (k JWK) publicKey() (*rsa.PublicKey, error) {
+	if k.KeyType != "RSA" || (k.Algorithm != "" && k.Algorithm != Algorithm) || (k.Use != "" && k.Use != "sig") {
+		return nil, errors.New("not an RS256 signing key")
+	}
+
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid RSA public key")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
@@ -0,0 +1,155 @@
+package jwks
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func writeKey(t *testing.T, dir, kid string) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, minKeyBits)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err = os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return key
+}
+
+func TestLoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+	old := writeKey(t, dir, "2024-01")
+	next := writeKey(t, dir, "2024-05")
+
+	if _, err := LoadKeySet(dir, ""); err == nil {
+		t.Errorf("expected an error without the signing key ID of several keys")
+	}
+
+	if _, err := LoadKeySet(dir, "2023-01"); err == nil {
+		t.Errorf("expected an error for an unknown signing key ID")
+	}
+
+	keys, err := LoadKeySet(dir, "2024-05")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	kid, key := keys.SigningKey()
+	if kid != "2024-05" || !key.Equal(next) {
+		t.Errorf("expected to sign with key 2024-05, got %q", kid)
+	}
+
+	public, err := keys.PublicKey("2024-01")
+	if err != nil || !public.Equal(&old.PublicKey) {
+		t.Errorf("expected the retired key to keep verifying, got %v", err)
+	}
+
+	set := keys.JWKS()
+	if len(set.Keys) != 2 || set.Keys[0].KeyID != "2024-01" || set.Keys[1].KeyID != "2024-05" {
+		t.Fatalf("expected both keys to be published, got %+v", set)
+	}
+
+	decoded, err := set.Keys[1].publicKey()
+	if err != nil || !decoded.Equal(&next.PublicKey) {
+		t.Errorf("expected the published key to decode to the signing key, got %v", err)
+	}
+}
+
+func TestLoadKeySetSingleKey(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "only")
+
+	keys, err := LoadKeySet(dir, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if kid, _ := keys.SigningKey(); kid != "only" {
+		t.Errorf("expected to sign with the only key, got %q", kid)
+	}
+
+	if _, err = LoadKeySet(t.TempDir(), ""); err == nil {
+		t.Errorf("expected an error for a directory without keys")
+	}
+}
+
+func TestRemote(t *testing.T) {
+	first, err := GenerateKeySet()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var (
+		published atomic.Value
+		fetches   atomic.Int32
+		down      atomic.Bool
+	)
+	published.Store(first.JWKS())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		json.NewEncoder(w).Encode(published.Load())
+	}))
+	defer server.Close()
+
+	remote := NewRemote(server.URL, server.Client(), time.Hour)
+
+	key, err := remote.PublicKey("ephemeral")
+	if err != nil || !key.Equal(&first.keys["ephemeral"].PublicKey) {
+		t.Fatalf("expected the published key, got %v", err)
+	}
+
+	if _, err = remote.PublicKey("ephemeral"); err != nil || fetches.Load() != 1 {
+		t.Errorf("expected the cached key, got %v after %d fetches", err, fetches.Load())
+	}
+
+	if _, err = remote.PublicKey("rotated"); !errors.Is(err, ErrUnknownKey) || fetches.Load() != 1 {
+		t.Errorf("expected unknown keys not to refetch within %v, got %v after %d fetches", minRefreshInterval, err, fetches.Load())
+	}
+
+	rotated, err := GenerateKeySet()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rotated.keys["rotated"] = rotated.keys["ephemeral"]
+	rotated.keys["ephemeral"] = first.keys["ephemeral"]
+	published.Store(rotated.JWKS())
+
+	remote.lastAttempt = time.Now().Add(-minRefreshInterval)
+	if _, err = remote.PublicKey("rotated"); err != nil || fetches.Load() != 2 {
+		t.Errorf("expected a new key to be fetched, got %v after %d fetches", err, fetches.Load())
+	}
+
+	down.Store(true)
+	remote.fetchedAt = time.Now().Add(-2 * time.Hour)
+	remote.lastAttempt = time.Now().Add(-minRefreshInterval)
+	if _, err = remote.PublicKey("ephemeral"); err != nil || fetches.Load() != 3 {
+		t.Errorf("expected the stale key while the users service is down, got %v after %d fetches", err, fetches.Load())
+	}
+}
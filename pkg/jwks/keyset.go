@@ -0,0 +1,148 @@
+package jwks
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// minKeyBits is the smallest RSA key accepted for signing.
+const minKeyBits = 2048
+
+// KeySet holds the private keys of the users service. Every key is published, so a new
+// key can be rolled out before it signs, and a retired one keeps verifying the tokens it
+// signed until they expire.
+type KeySet struct {
+	signingKID string
+	keys       map[string]*rsa.PrivateKey
+}
+
+// NewKeySet returns a set of keys by ID, signing with the key signingKID.
+func NewKeySet(signingKID string, keys map[string]*rsa.PrivateKey) (*KeySet, error) {
+	if _, ok := keys[signingKID]; !ok {
+		return nil, fmt.Errorf("signing key %q not found", signingKID)
+	}
+
+	for kid, key := range keys {
+		if key.N.BitLen() < minKeyBits {
+			return nil, fmt.Errorf("key %q is shorter than %d bits", kid, minKeyBits)
+		}
+	}
+
+	return &KeySet{signingKID: signingKID, keys: keys}, nil
+}
+
+// LoadKeySet reads the PEM encoded RSA private keys of dir, a key ID is the name of its
+// file without the .pem extension. signingKID may be empty when dir holds a single key.
+func LoadKeySet(dir, signingKID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no .pem keys in %s", dir)
+	}
+
+	keys := make(map[string]*rsa.PrivateKey, len(paths))
+	for _, path := range paths {
+		key, err := readPrivateKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		keys[strings.TrimSuffix(filepath.Base(path), ".pem")] = key
+	}
+
+	if signingKID == "" {
+		if len(keys) > 1 {
+			return nil, errors.New("several keys found, the signing key ID must be set")
+		}
+
+		for kid := range keys {
+			signingKID = kid
+		}
+	}
+
+	return NewKeySet(signingKID, keys)
+}
+
+// GenerateKeySet returns a set with a single new key, for development and tests. Its
+// tokens do not survive a restart.
+func GenerateKeySet() (*KeySet, error) {
+	key, err := rsa.GenerateKey(rand.Reader, minKeyBits)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeySet("ephemeral", map[string]*rsa.PrivateKey{"ephemeral": key})
+}
+
+// SigningKey returns the key that signs the new tokens.
+func (k *KeySet) SigningKey() (string, *rsa.PrivateKey) {
+	return k.signingKID, k.keys[k.signingKID]
+}
+
+// PublicKey returns the key that verifies the tokens signed by key kid.
+func (k *KeySet) PublicKey(kid string) (*rsa.PublicKey, error) {
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return &key.PublicKey, nil
+}
+
+// JWKS returns the public keys of the set, sorted by ID.
+func (k *KeySet) JWKS() Set {
+	kids := make([]string, 0, len(k.keys))
+	for kid := range k.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	set := Set{Keys: make([]JWK, len(kids))}
+	for i, kid := range kids {
+		set.Keys[i] = newJWK(kid, &k.keys[kid].PublicKey)
+	}
+
+	return set
+}
+
+func readPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("not an RSA key")
+		}
+
+		return rsaKey, nil
+	default:
+		return nil, fmt.Errorf("unexpected PEM block %q", block.Type)
+	}
+}
@@ -0,0 +1,103 @@
+package jwks
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minRefreshInterval bounds the fetches triggered by tokens signed with unknown keys.
+const minRefreshInterval = 30 * time.Second
+
+// Remote is a cached copy of the JWKS of the users service. The set is fetched again
+// once older than its TTL, or when a token names a key it does not hold yet.
+type Remote struct {
+	url    string
+	client *http.Client
+	ttl    time.Duration
+
+	mu          sync.Mutex
+	keys        map[string]*rsa.PublicKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+func NewRemote(url string, client *http.Client, ttl time.Duration) *Remote {
+	return &Remote{
+		url:    url,
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// PublicKey returns the key that verifies the tokens signed by key kid. When the users
+// service is unreachable, the keys fetched before keep being used.
+func (r *Remote) PublicKey(kid string) (*rsa.PublicKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[kid]
+	if ok && time.Since(r.fetchedAt) < r.ttl {
+		return key, nil
+	}
+
+	if time.Since(r.lastAttempt) < minRefreshInterval {
+		if ok {
+			return key, nil
+		}
+
+		return nil, ErrUnknownKey
+	}
+
+	if err := r.refresh(); err != nil {
+		if ok {
+			return key, nil
+		}
+
+		return nil, err
+	}
+
+	key, ok = r.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+func (r *Remote) refresh() error {
+	r.lastAttempt = time.Now()
+
+	resp, err := r.client.Get(r.url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	var set Set
+	if err = json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("decoding JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.KeyID] = key
+	}
+
+	r.keys = keys
+	r.fetchedAt = time.Now()
+
+	return nil
+}
@@ -0,0 +1,56 @@
+// Package mergepatch applies JSON Merge Patch documents as described in RFC 7386.
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ContentType is the media type of a JSON Merge Patch document.
+const ContentType = "application/merge-patch+json"
+
+var ErrNotObject = errors.New("merge patch must be a JSON object")
+
+// Apply merges the patch into the document and returns the patched document.
+// Members set to null in the patch are removed, objects are merged recursively
+// and any other value, arrays included, replaces the original one.
+func Apply(document, patch []byte) ([]byte, error) {
+	var target, changes interface{}
+
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, err
+	}
+
+	if _, ok := changes.(map[string]interface{}); !ok {
+		return nil, ErrNotObject
+	}
+
+	return json.Marshal(merge(target, changes))
+}
+
+func merge(target, patch interface{}) interface{} {
+	changes, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	result, ok := target.(map[string]interface{})
+	if !ok {
+		result = make(map[string]interface{}, len(changes))
+	}
+
+	for name, value := range changes {
+		if value == nil {
+			delete(result, name)
+			continue
+		}
+
+		result[name] = merge(result[name], value)
+	}
+
+	return result
+}
@@ -0,0 +1,53 @@
+package mergepatch
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		document string
+		patch    string
+		want     string
+	}{
+		// examples from RFC 7386 appendix A
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, test := range tests {
+		got, err := Apply([]byte(test.document), []byte(test.patch))
+		if err != nil {
+			t.Errorf("Apply(%s, %s) returned error: %v", test.document, test.patch, err)
+			continue
+		}
+
+		if string(got) != test.want {
+			t.Errorf("Apply(%s, %s) = %s, want %s", test.document, test.patch, got, test.want)
+		}
+	}
+}
+
+func TestApplyRejectsNonObjectPatch(t *testing.T) {
+	for _, patch := range []string{`["a"]`, `"a"`, `null`} {
+		if _, err := Apply([]byte(`{"a":"b"}`), []byte(patch)); !errors.Is(err, ErrNotObject) {
+			t.Errorf("Apply with patch %s returned %v, want %v", patch, err, ErrNotObject)
+		}
+	}
+}
+
+func TestApplyInvalidJSON(t *testing.T) {
+	if _, err := Apply([]byte(`{"a":"b"}`), []byte(`{"a":`)); err == nil {
+		t.Error("Apply with a truncated patch returned no error")
+	}
+}
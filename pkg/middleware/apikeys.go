@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"library/pkg/apikey"
+	"library/users/models"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeys resolves the API keys sent as bearer credentials into the claims of their owner.
+type APIKeys interface {
+	ResolveAPIKey(ctx context.Context, key string) (*models.Claims, error)
+}
+
+var apiKeys APIKeys
+
+// UseAPIKeys makes the auth middleware accept the API keys resolved by k.
+func UseAPIKeys(k APIKeys) {
+	apiKeys = k
+}
+
+// credential returns the bearer credential of the Authorization header, or else
+// the access token of the token cookie.
+func credential(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	}
+
+	token, _ := c.Cookie("token")
+
+	return token
+}
+
+// resolveAPIKey resolves the key once per request, IsAuthorized and GetToken both need it.
+func resolveAPIKey(c *gin.Context, key string) (*models.Claims, error) {
+	if claims, ok := c.Get("apiKeyClaims"); ok {
+		return claims.(*models.Claims), nil
+	}
+
+	if apiKeys == nil {
+		return nil, apikey.ErrInvalidKey
+	}
+
+	claims, err := apiKeys.ResolveAPIKey(c.Request.Context(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Set("apiKeyClaims", claims)
+
+	return claims, nil
+}
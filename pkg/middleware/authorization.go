@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"errors"
+	"library/pkg/apikey"
+	"library/users/models"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,33 +12,80 @@ import (
 )
 
 func IsAuthorized(c *gin.Context) {
-	cookie, err := c.Cookie("token")
-	if err != nil || cookie == "" {
+	token := credential(c)
+	if token == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		c.Abort()
 		return
 	}
 
-	claims, err := VerifyJWT(cookie)
-	if err != nil {
+	if apikey.IsKey(token) {
+		claims, err := resolveAPIKey(c, token)
+		if errors.Is(err, apikey.ErrInvalidKey) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
+		}
+
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "API key check failed"})
+			c.Abort()
+			return
+		}
+
+		c.Set("role", claims.Role)
+		c.Set("permissions", claims.Permissions)
+		c.Next()
+		return
+	}
+
+	claims, err := VerifyJWT(token)
+	if err != nil || claims.Id == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		c.Abort()
 		return
 	}
 
+	if denylist != nil {
+		revoked, err := denylist.IsRevoked(c.Request.Context(), claims.Id)
+		if err == nil && !revoked && claims.SessionID != "" {
+			revoked, err = denylist.IsSessionRevoked(c.Request.Context(), claims.SessionID)
+		}
+
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "token revocation check failed"})
+			c.Abort()
+			return
+		}
+
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
+		}
+	}
+
+	if sessionTracker != nil && claims.SessionID != "" {
+		// the request does not depend on it, a failed write only leaves the last use behind
+		_ = sessionTracker.TouchSession(c.Request.Context(), claims.SessionID, time.Unix(claims.ExpiresAt, 0))
+	}
+
 	c.Set("role", claims.Role)
+	c.Set("permissions", claims.Permissions)
 	c.Next()
 }
 
 func GetToken(c *gin.Context) {
-	token, err := c.Cookie("token")
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-		c.Abort()
-		return
+	var claims *models.Claims
+	var err error
+
+	token := credential(c)
+	if apikey.IsKey(token) {
+		claims, err = resolveAPIKey(c, token)
+	} else {
+		claims, err = VerifyJWT(token)
 	}
 
-	claims, err := VerifyJWT(token)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		c.Abort()
@@ -57,6 +107,8 @@ func GetToken(c *gin.Context) {
 
 	c.Set("claims", claims)
 	c.Set("userID", userID)
+	c.Set("actorID", userID)
+	c.Set("apiKeyID", claims.APIKeyID)
 	c.Next()
 }
 
@@ -83,3 +135,65 @@ func GetDeleteParam(c *gin.Context) {
 	c.Set("deleteID", deleteID)
 	c.Next()
 }
+
+func GetAuthorParam(c *gin.Context) {
+	authorID, err := strconv.Atoi(c.Param("author_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Abort()
+		return
+	}
+
+	c.Set("authorID", authorID)
+	c.Next()
+}
+
+func GetRevisionParam(c *gin.Context) {
+	revisionID, err := strconv.Atoi(c.Param("revision_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Abort()
+		return
+	}
+
+	c.Set("revisionID", revisionID)
+	c.Next()
+}
+
+// GetOwnerParam puts the user of the path in the context as ownerID, for routes that
+// other users can read as well, so userID stays the caller.
+func GetOwnerParam(c *gin.Context) {
+	ownerID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Abort()
+		return
+	}
+
+	c.Set("ownerID", ownerID)
+	c.Next()
+}
+
+func GetShelfParam(c *gin.Context) {
+	shelfID, err := strconv.Atoi(c.Param("shelf_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Abort()
+		return
+	}
+
+	c.Set("shelfID", shelfID)
+	c.Next()
+}
+
+func GetReviewParam(c *gin.Context) {
+	reviewID, err := strconv.Atoi(c.Param("review_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Abort()
+		return
+	}
+
+	c.Set("reviewID", reviewID)
+	c.Next()
+}
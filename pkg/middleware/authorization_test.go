@@ -0,0 +1,320 @@
+package middleware
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"library/pkg/apikey"
+	"library/pkg/jwks"
+	"library/users/models"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeDenylist struct {
+	revoked         map[string]bool
+	revokedSessions map[string]bool
+	err             error
+}
+
+func (f *fakeDenylist) IsRevoked(_ context.Context, tokenID string) (bool, error) {
+	return f.revoked[tokenID], f.err
+}
+
+func (f *fakeDenylist) IsSessionRevoked(_ context.Context, sessionID string) (bool, error) {
+	return f.revokedSessions[sessionID], f.err
+}
+
+type fakeSessionTracker struct {
+	touched []string
+	err     error
+}
+
+func (f *fakeSessionTracker) TouchSession(_ context.Context, sessionID string, _ time.Time) error {
+	f.touched = append(f.touched, sessionID)
+	return f.err
+}
+
+var testKeys *jwks.KeySet
+
+func TestMain(m *testing.M) {
+	var err error
+
+	testKeys, err = jwks.GenerateKeySet()
+	if err != nil {
+		panic(err)
+	}
+	UseSigningKeys(testKeys)
+
+	os.Exit(m.Run())
+}
+
+func TestIsAuthorizedDenylist(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	token, claims, err := GenerateAccessToken(models.User{ID: 1, Email: "user@example.com", Role: "user"}, "session-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	kid, key := testKeys.SigningKey()
+	withoutIDToken := jwt.NewWithClaims(jwt.SigningMethodRS256, &models.Claims{
+		UserID:         "1",
+		StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Hour).Unix()},
+	})
+	withoutIDToken.Header["kid"] = kid
+	withoutID, err := withoutIDToken.SignedString(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		denylist Denylist
+		want     int
+	}{
+		{"no denylist", token, nil, http.StatusOK},
+		{"not revoked", token, &fakeDenylist{}, http.StatusOK},
+		{"revoked", token, &fakeDenylist{revoked: map[string]bool{claims.Id: true}}, http.StatusUnauthorized},
+		{"session revoked", token, &fakeDenylist{revokedSessions: map[string]bool{"session-1": true}}, http.StatusUnauthorized},
+		{"other session revoked", token, &fakeDenylist{revokedSessions: map[string]bool{"session-2": true}}, http.StatusOK},
+		{"denylist unavailable", token, &fakeDenylist{err: errors.New("connection refused")}, http.StatusServiceUnavailable},
+		{"token without ID", withoutID, &fakeDenylist{}, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			UseDenylist(tt.denylist)
+			defer UseDenylist(nil)
+
+			router := gin.New()
+			router.GET("/", IsAuthorized, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(&http.Cookie{Name: "token", Value: tt.token})
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("got status %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAuthorizedSessionTracker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	token, claims, err := GenerateAccessToken(models.User{ID: 1, Email: "user@example.com", Role: "user"}, "session-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		denylist Denylist
+		tracker  *fakeSessionTracker
+		want     int
+		touched  []string
+	}{
+		{"used session", nil, &fakeSessionTracker{}, http.StatusOK, []string{"session-1"}},
+		{"revoked token", &fakeDenylist{revoked: map[string]bool{claims.Id: true}}, &fakeSessionTracker{}, http.StatusUnauthorized, nil},
+		{"tracker unavailable", nil, &fakeSessionTracker{err: errors.New("connection refused")}, http.StatusOK, []string{"session-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			UseDenylist(tt.denylist)
+			defer UseDenylist(nil)
+			UseSessionTracker(tt.tracker)
+			defer UseSessionTracker(nil)
+
+			router := gin.New()
+			router.GET("/", IsAuthorized, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(&http.Cookie{Name: "token", Value: token})
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("got status %d, want %d", w.Code, tt.want)
+			}
+
+			if len(tt.tracker.touched) != len(tt.touched) || (len(tt.touched) > 0 && tt.tracker.touched[0] != tt.touched[0]) {
+				t.Errorf("got touched sessions %v, want %v", tt.tracker.touched, tt.touched)
+			}
+		})
+	}
+}
+
+type fakeAPIKeys struct {
+	claims map[string]*models.Claims
+	err    error
+	calls  int
+}
+
+func (f *fakeAPIKeys) ResolveAPIKey(_ context.Context, key string) (*models.Claims, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	claims, ok := f.claims[key]
+	if !ok {
+		return nil, apikey.ErrInvalidKey
+	}
+
+	return claims, nil
+}
+
+func TestBearerCredentials(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	token, _, err := GenerateAccessToken(models.User{ID: 1, Email: "user@example.com", Role: "user"}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keyClaims := &models.Claims{
+		UserID:         "7",
+		Role:           "superuser",
+		Permissions:    []string{"shops:import"},
+		APIKeyID:       3,
+		StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Hour).Unix()},
+	}
+	expiredClaims := &models.Claims{
+		UserID:         "7",
+		APIKeyID:       4,
+		StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(-time.Hour).Unix()},
+	}
+
+	tests := []struct {
+		name       string
+		header     string
+		keys       *fakeAPIKeys
+		want       int
+		wantUserID int
+	}{
+		{"access token", "Bearer " + token, nil, http.StatusOK, 1},
+		{"API key", "Bearer lib_key", &fakeAPIKeys{claims: map[string]*models.Claims{"lib_key": keyClaims}}, http.StatusOK, 7},
+		{"unknown API key", "Bearer lib_other", &fakeAPIKeys{}, http.StatusUnauthorized, 0},
+		{"expired API key", "Bearer lib_old", &fakeAPIKeys{claims: map[string]*models.Claims{"lib_old": expiredClaims}}, http.StatusUnauthorized, 0},
+		{"API keys unavailable", "Bearer lib_key", &fakeAPIKeys{err: errors.New("connection refused")}, http.StatusServiceUnavailable, 0},
+		{"API keys not enabled", "Bearer lib_key", nil, http.StatusUnauthorized, 0},
+		{"not a bearer", "Basic dXNlcjpwYXNz", nil, http.StatusUnauthorized, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.keys != nil {
+				UseAPIKeys(tt.keys)
+			}
+			defer UseAPIKeys(nil)
+
+			var userID int
+			var permissions []string
+
+			router := gin.New()
+			router.GET("/", IsAuthorized, GetToken, func(c *gin.Context) {
+				userID = c.GetInt("userID")
+				permissions = c.GetStringSlice("permissions")
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", tt.header)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("got status %d, want %d", w.Code, tt.want)
+			}
+
+			if userID != tt.wantUserID {
+				t.Errorf("got user %d, want %d", userID, tt.wantUserID)
+			}
+
+			if tt.wantUserID == 7 {
+				if tt.keys.calls != 1 {
+					t.Errorf("expected the key to be resolved once, got %d", tt.keys.calls)
+				}
+
+				if len(permissions) != 1 || permissions[0] != "shops:import" {
+					t.Errorf("expected the scoped permissions, got %v", permissions)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyJWT(t *testing.T) {
+	claims := &models.Claims{
+		UserID:         "1",
+		StandardClaims: jwt.StandardClaims{Id: "id", ExpiresAt: time.Now().Add(time.Hour).Unix()},
+	}
+
+	kid, key := testKeys.SigningKey()
+
+	sign := func(method jwt.SigningMethod, kid string, key interface{}) string {
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = kid
+
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return signed
+	}
+
+	otherKeys, err := jwks.GenerateKeySet()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, otherKey := otherKeys.SigningKey()
+
+	publicKeyPEM := x509.MarshalPKCS1PublicKey(&key.PublicKey)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"signed with the signing key", sign(jwt.SigningMethodRS256, kid, key), false},
+		{"unknown key ID", sign(jwt.SigningMethodRS256, "retired", key), true},
+		{"signed with another key", sign(jwt.SigningMethodRS256, kid, otherKey), true},
+		{"HMAC keyed with the public key", sign(jwt.SigningMethodHS256, kid, publicKeyPEM), true},
+		{"HMAC keyed with an empty secret", sign(jwt.SigningMethodHS256, kid, []byte("")), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyJWT(tt.token)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected the token to be refused, got claims %+v", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.UserID != "1" {
+				t.Errorf("unexpected claims %+v", got)
+			}
+		})
+	}
+}
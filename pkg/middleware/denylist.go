@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"context"
+	"time"
+)
+
+// Denylist holds the IDs of the access tokens, and of the sessions, revoked before
+// they expired.
+type Denylist interface {
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+	IsSessionRevoked(ctx context.Context, sessionID string) (bool, error)
+}
+
+var denylist Denylist
+
+// UseDenylist makes IsAuthorized reject the access tokens revoked in the denylist.
+func UseDenylist(d Denylist) {
+	denylist = d
+}
+
+// SessionTracker records when the sessions were last used.
+type SessionTracker interface {
+	TouchSession(ctx context.Context, sessionID string, expiresAt time.Time) error
+}
+
+var sessionTracker SessionTracker
+
+// UseSessionTracker makes IsAuthorized record the use of the session of every access token.
+func UseSessionTracker(t SessionTracker) {
+	sessionTracker = t
+}
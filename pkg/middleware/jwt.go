@@ -1,52 +1,134 @@
 package middleware
 
 import (
-	"library/pkg/config"
+	"crypto/rsa"
+	"errors"
+	"library/pkg/jwks"
 	"library/users/models"
-	"os"
+	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+// AccessTokenTTL is the lifetime of an access token, sessions are kept alive with refresh tokens.
+const AccessTokenTTL = 15 * time.Minute
+
+var (
+	ErrNoSigningKey = errors.New("no signing key configured")
+	ErrNoPublicKeys = errors.New("no public keys configured")
+)
+
+// SigningKeys are the keys of the users service, the only service that signs tokens.
+type SigningKeys interface {
+	PublicKeys
+	SigningKey() (kid string, key *rsa.PrivateKey)
+	JWKS() jwks.Set
+}
+
+// PublicKeys verify the tokens, by the ID of the key that signed them.
+type PublicKeys interface {
+	PublicKey(kid string) (*rsa.PublicKey, error)
+}
+
+var (
+	signingKeys SigningKeys
+	publicKeys  PublicKeys
+)
+
+// UseSigningKeys signs the access tokens with k, and verifies them with its public keys.
+func UseSigningKeys(k SigningKeys) {
+	signingKeys = k
+	publicKeys = k
+}
+
+// UsePublicKeys verifies the access tokens with k, in the services that do not sign them.
+func UsePublicKeys(k PublicKeys) {
+	signingKeys = nil
+	publicKeys = k
+}
+
 func GenerateJWT(user models.User) (string, error) {
+	tokenString, _, err := GenerateAccessToken(user, "")
+
+	return tokenString, err
+}
+
+// GenerateAccessToken signs a short-lived token for the user in the session. Its
+// claims carry a unique ID, so the token can be revoked before it expires, alone or
+// with its session.
+func GenerateAccessToken(user models.User, sessionID string) (string, *models.Claims, error) {
+	if signingKeys == nil {
+		return "", nil, ErrNoSigningKey
+	}
+
+	now := time.Now()
+
 	claims := &models.Claims{
-		UserID: strconv.Itoa(user.ID),
-		Email:  user.Email,
-		Role:   user.Role,
+		UserID:      strconv.Itoa(user.ID),
+		Email:       user.Email,
+		Role:        user.Role,
+		Permissions: user.Permissions,
+		SessionID:   sessionID,
 		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewString(),
 			Subject:   user.Email,
-			ExpiresAt: time.Now().Add(time.Hour * 24).Unix(),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(AccessTokenTTL).Unix(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	kid, key := signingKeys.SigningKey()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
 
-	tokenString, err := token.SignedString([]byte(os.Getenv("SECRET_KEY")))
+	tokenString, err := token.SignedString(key)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	return tokenString, nil
+	return tokenString, claims, nil
 }
 
+// VerifyJWT checks the signature of the token against the key named by its kid
+// header. Only RS256 is accepted, a token cannot pick another algorithm.
 func VerifyJWT(tokenString string) (claims *models.Claims, err error) {
-	var cfg config.GlobalEnv
-
 	token, err := jwt.ParseWithClaims(tokenString, &models.Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(cfg.SecretKey), nil
-	})
+		if token.Method.Alg() != jwks.Algorithm {
+			return nil, errors.New("unexpected signing method " + token.Method.Alg())
+		}
+
+		if publicKeys == nil {
+			return nil, ErrNoPublicKeys
+		}
+
+		kid, _ := token.Header["kid"].(string)
 
+		return publicKeys.PublicKey(kid)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	claims, ok := token.Claims.(*models.Claims)
-
-	if !ok {
-		return nil, err
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
 	}
 
 	return claims, nil
 }
+
+// JWKS publishes the public keys of the signing keys, for the services that verify tokens.
+func JWKS(c *gin.Context) {
+	if signingKeys == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": ErrNoSigningKey.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, signingKeys.JWKS())
+}
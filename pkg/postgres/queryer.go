@@ -0,0 +1,10 @@
+package postgres
+
+import "database/sql"
+
+// Queryer is satisfied by both *sql.DB and *sql.Tx, so helpers can run inside or outside a transaction.
+type Queryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
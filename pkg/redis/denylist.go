@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	denylistPrefix       = "token:denylist:"
+	revokedSessionPrefix = "session:revoked:"
+)
+
+// RevokeToken denylists an access token ID for the rest of the token lifetime.
+func (c *Client) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return c.Client.Set(ctx, denylistPrefix+tokenID, 1, ttl).Err()
+}
+
+// IsRevoked reports whether the access token ID is denylisted.
+func (c *Client) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	count, err := c.Client.Exists(ctx, denylistPrefix+tokenID).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// RevokeSession marks a session revoked until the last access token issued in it expires.
+func (c *Client) RevokeSession(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return c.Client.Set(ctx, revokedSessionPrefix+sessionID, 1, ttl).Err()
+}
+
+// IsSessionRevoked reports whether the session of an access token was revoked.
+func (c *Client) IsSessionRevoked(ctx context.Context, sessionID string) (bool, error) {
+	count, err := c.Client.Exists(ctx, revokedSessionPrefix+sessionID).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,398 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NewFakeRedis returns a client of an in-memory server speaking the Redis protocol. It
+// knows the string, hash and set commands the services use, so repositories can be
+// tested without a Redis instance.
+func NewFakeRedis(ctx context.Context) (*Client, error) {
+	server := &fakeServer{
+		strings: map[string]string{},
+		hashes:  map[string]map[string]string{},
+		sets:    map[string]map[string]struct{}{},
+		expires: map[string]time.Time{},
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:             "fake",
+		Protocol:         2,
+		DisableIndentity: true,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			clientConn, serverConn := net.Pipe()
+			go server.serve(serverConn)
+
+			return clientConn, nil
+		},
+	})
+
+	return &Client{
+		Client: client,
+	}, nil
+}
+
+type fakeStatus string
+
+type fakeError string
+
+type fakeServer struct {
+	mu      sync.Mutex
+	strings map[string]string
+	hashes  map[string]map[string]string
+	sets    map[string]map[string]struct{}
+	expires map[string]time.Time
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+
+	var queued [][]string
+	inMulti := false
+
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		var reply interface{}
+		switch name := strings.ToUpper(args[0]); {
+		case name == "MULTI":
+			inMulti, queued = true, nil
+			reply = fakeStatus("OK")
+		case name == "EXEC":
+			results := make([]interface{}, 0, len(queued))
+			for _, command := range queued {
+				results = append(results, s.execute(command))
+			}
+			inMulti, queued = false, nil
+			reply = results
+		case name == "DISCARD":
+			inMulti, queued = false, nil
+			reply = fakeStatus("OK")
+		case inMulti:
+			queued = append(queued, args)
+			reply = fakeStatus("QUEUED")
+		default:
+			reply = s.execute(args)
+		}
+
+		writeReply(writer, reply)
+		if err = writer.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeServer) execute(args []string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name, args := strings.ToUpper(args[0]), args[1:]
+	for _, key := range args {
+		s.expire(key)
+	}
+
+	switch name {
+	case "PING":
+		return fakeStatus("PONG")
+	case "GET":
+		value, ok := s.strings[args[0]]
+		if !ok {
+			return nil
+		}
+		return value
+	case "SET":
+		var unit string
+		var ttl int64
+		for i := 2; i < len(args); i++ {
+			switch option := strings.ToUpper(args[i]); option {
+			case "NX":
+				if s.exists(args[0]) {
+					return nil
+				}
+			case "EX", "PX":
+				if i+1 < len(args) {
+					unit = option
+					ttl, _ = strconv.ParseInt(args[i+1], 10, 64)
+					i++
+				}
+			}
+		}
+		s.delete(args[0])
+		s.strings[args[0]] = args[1]
+		if unit != "" {
+			s.setTTL(args[0], unit, ttl)
+		}
+		return fakeStatus("OK")
+	case "INCR":
+		value, err := strconv.ParseInt(s.stringOr(args[0], "0"), 10, 64)
+		if err != nil {
+			return fakeError("ERR value is not an integer or out of range")
+		}
+		s.strings[args[0]] = strconv.FormatInt(value+1, 10)
+		return value + 1
+	case "DEL":
+		var deleted int64
+		for _, key := range args {
+			if s.exists(key) {
+				s.delete(key)
+				deleted++
+			}
+		}
+		return deleted
+	case "EXISTS":
+		var count int64
+		for _, key := range args {
+			if s.exists(key) {
+				count++
+			}
+		}
+		return count
+	case "EXPIRE", "PEXPIRE":
+		ttl, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || !s.exists(args[0]) {
+			return int64(0)
+		}
+		s.setTTL(args[0], strings.TrimSuffix(name, "IRE"), ttl)
+		return int64(1)
+	case "PTTL":
+		if !s.exists(args[0]) {
+			return int64(-2)
+		}
+		expiresAt, ok := s.expires[args[0]]
+		if !ok {
+			return int64(-1)
+		}
+		return time.Until(expiresAt).Milliseconds()
+	case "HSET":
+		hash := s.hash(args[0])
+		var added int64
+		for i := 1; i+1 < len(args); i += 2 {
+			if _, ok := hash[args[i]]; !ok {
+				added++
+			}
+			hash[args[i]] = args[i+1]
+		}
+		return added
+	case "HSETNX":
+		hash := s.hash(args[0])
+		if _, ok := hash[args[1]]; ok {
+			return int64(0)
+		}
+		hash[args[1]] = args[2]
+		return int64(1)
+	case "HGET":
+		value, ok := s.hashes[args[0]][args[1]]
+		if !ok {
+			return nil
+		}
+		return value
+	case "HMGET":
+		values := make([]interface{}, 0, len(args)-1)
+		for _, field := range args[1:] {
+			if value, ok := s.hashes[args[0]][field]; ok {
+				values = append(values, value)
+			} else {
+				values = append(values, nil)
+			}
+		}
+		return values
+	case "HGETALL":
+		fields := make([]string, 0, len(s.hashes[args[0]]))
+		for field := range s.hashes[args[0]] {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		values := make([]interface{}, 0, 2*len(fields))
+		for _, field := range fields {
+			values = append(values, field, s.hashes[args[0]][field])
+		}
+		return values
+	case "HINCRBY":
+		hash := s.hash(args[0])
+		increment, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return fakeError("ERR value is not an integer or out of range")
+		}
+		value, err := strconv.ParseInt(stringOr(hash, args[1], "0"), 10, 64)
+		if err != nil {
+			return fakeError("ERR hash value is not an integer")
+		}
+		hash[args[1]] = strconv.FormatInt(value+increment, 10)
+		return value + increment
+	case "SADD":
+		set, ok := s.sets[args[0]]
+		if !ok {
+			set = map[string]struct{}{}
+			s.sets[args[0]] = set
+		}
+		var added int64
+		for _, member := range args[1:] {
+			if _, ok := set[member]; !ok {
+				set[member] = struct{}{}
+				added++
+			}
+		}
+		return added
+	case "SREM":
+		var removed int64
+		for _, member := range args[1:] {
+			if _, ok := s.sets[args[0]][member]; ok {
+				delete(s.sets[args[0]], member)
+				removed++
+			}
+		}
+		if len(s.sets[args[0]]) == 0 {
+			s.delete(args[0])
+		}
+		return removed
+	case "SMEMBERS":
+		members := make([]string, 0, len(s.sets[args[0]]))
+		for member := range s.sets[args[0]] {
+			members = append(members, member)
+		}
+		sort.Strings(members)
+		values := make([]interface{}, 0, len(members))
+		for _, member := range members {
+			values = append(values, member)
+		}
+		return values
+	default:
+		return fakeError(fmt.Sprintf("ERR unknown command '%s'", name))
+	}
+}
+
+func (s *fakeServer) hash(key string) map[string]string {
+	hash, ok := s.hashes[key]
+	if !ok {
+		hash = map[string]string{}
+		s.hashes[key] = hash
+	}
+
+	return hash
+}
+
+func (s *fakeServer) stringOr(key, fallback string) string {
+	return stringOr(s.strings, key, fallback)
+}
+
+func (s *fakeServer) exists(key string) bool {
+	_, isString := s.strings[key]
+	_, isHash := s.hashes[key]
+	_, isSet := s.sets[key]
+
+	return isString || isHash || isSet
+}
+
+func (s *fakeServer) delete(key string) {
+	delete(s.strings, key)
+	delete(s.hashes, key)
+	delete(s.sets, key)
+	delete(s.expires, key)
+}
+
+func (s *fakeServer) setTTL(key, unit string, ttl int64) {
+	duration := time.Duration(ttl) * time.Second
+	if unit == "PX" || unit == "PEXP" {
+		duration = time.Duration(ttl) * time.Millisecond
+	}
+
+	s.expires[key] = time.Now().Add(duration)
+}
+
+// expire drops the key once its time to live is over.
+func (s *fakeServer) expire(key string) {
+	if expiresAt, ok := s.expires[key]; ok && !time.Now().Before(expiresAt) {
+		s.delete(key)
+	}
+}
+
+func stringOr(values map[string]string, key, fallback string) string {
+	if value, ok := values[key]; ok {
+		return value
+	}
+
+	return fallback
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := readLine(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(line, "*") {
+		return nil, errors.New("expected an array of bulk strings")
+	}
+
+	count, err := strconv.Atoi(line[1:])
+	if err != nil || count < 1 {
+		return nil, errors.New("invalid command length")
+	}
+
+	args := make([]string, count)
+	for i := range args {
+		if line, err = readLine(reader); err != nil {
+			return nil, err
+		}
+
+		length, err := strconv.Atoi(strings.TrimPrefix(line, "$"))
+		if err != nil {
+			return nil, err
+		}
+
+		data := make([]byte, length+2)
+		if _, err = io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:length])
+	}
+
+	return args, nil
+}
+
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
+
+func writeReply(writer *bufio.Writer, reply interface{}) {
+	switch reply := reply.(type) {
+	case nil:
+		writer.WriteString("$-1\r\n")
+	case fakeStatus:
+		fmt.Fprintf(writer, "+%s\r\n", reply)
+	case fakeError:
+		fmt.Fprintf(writer, "-%s\r\n", reply)
+	case int64:
+		fmt.Fprintf(writer, ":%d\r\n", reply)
+	case string:
+		fmt.Fprintf(writer, "$%d\r\n%s\r\n", len(reply), reply)
+	case []interface{}:
+		fmt.Fprintf(writer, "*%d\r\n", len(reply))
+		for _, item := range reply {
+			writeReply(writer, item)
+		}
+	}
+}
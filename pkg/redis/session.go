@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SessionTouchInterval is how often the last use of a session is written at most.
+const SessionTouchInterval = time.Minute
+
+const (
+	sessionSeenPrefix  = "session:seen:"
+	sessionTouchPrefix = "session:touch:"
+)
+
+// TouchSession records that the session was just used with an access token. Busy
+// sessions are only written once every SessionTouchInterval. The record is kept until
+// the access token expires, the refresh of the token records the use from then on.
+func (c *Client) TouchSession(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	due, err := c.Client.SetNX(ctx, sessionTouchPrefix+sessionID, 1, SessionTouchInterval).Result()
+	if err != nil || !due {
+		return err
+	}
+
+	return c.Client.Set(ctx, sessionSeenPrefix+sessionID, time.Now().Unix(), ttl).Err()
+}
+
+// SessionLastSeen returns when the session was last used with an access token, or
+// the zero time when it was not used during the lifetime of its last access token.
+func (c *Client) SessionLastSeen(ctx context.Context, sessionID string) (time.Time, error) {
+	value, err := c.Client.Get(ctx, sessionSeenPrefix+sessionID).Result()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, nil
+	}
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	seen, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(seen, 0), nil
+}
@@ -0,0 +1,35 @@
+// Package storage stores uploaded files, such as book covers, behind a common interface.
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage saves objects under slash separated keys and tells where they can be downloaded.
+type Storage interface {
+	// Put stores the object, replacing any object with the same key.
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Delete removes the object, deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns the address the object can be downloaded from.
+	URL(key string) string
+}
+
+// validKey rejects keys that could escape the storage root or the bucket.
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"errors"
 	"library/books/models"
 	"library/pkg/logger"
 	"time"
@@ -16,7 +17,7 @@ func CheckPublishedDate(log logger.Logger, book *models.BookRequest) error {
 	today := time.Now().Local().Truncate(24 * time.Hour)
 	if datePublished.After(today) {
 		log.Errorf("Date cannot be future date")
-		return err
+		return errors.New("date cannot be a future date")
 	}
 
 	book.DatePublished = datePublished.Format(time.DateOnly)
@@ -0,0 +1,83 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The SHA-1 test vectors of RFC 6238, appendix B, truncated to 6 digits.
+func TestCode(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(secret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got != tt.want {
+			t.Errorf("Code at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	code, _ := Code(secret, Step(now))
+	previous, _ := Code(secret, Step(now)-1)
+	stale, _ := Code(secret, Step(now)-2)
+
+	tests := []struct {
+		name     string
+		code     string
+		want     bool
+		wantStep int64
+	}{
+		{"current", code, true, Step(now)},
+		{"previous period", previous, true, Step(now) - 1},
+		{"too old", stale, false, 0},
+		{"wrong length", code[:5], false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(secret, tt.code, now)
+			if ok != tt.want || step != tt.wantStep {
+				t.Errorf("Validate = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.want)
+			}
+		})
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Library", "user@example.com", "JBSWY3DPEHPK3PXP")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/Library:user@example.com?") {
+		t.Errorf("unexpected label in %s", uri)
+	}
+
+	for _, param := range []string{"secret=JBSWY3DPEHPK3PXP", "issuer=Library", "digits=6", "period=30"} {
+		if !strings.Contains(uri, param) {
+			t.Errorf("%s is missing %s", uri, param)
+		}
+	}
+}
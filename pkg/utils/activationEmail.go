@@ -3,31 +3,54 @@ package utils
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"library/pkg/redis"
-	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 )
 
-func GenerateActivationLink(ctx context.Context, redisClient *redis.Client, userID int) (string, error) {
+const (
+	// ActivationTokenTTL is how long an activation link can be used.
+	ActivationTokenTTL = 24 * time.Hour
+	// activationGrace keeps expired activation tokens around, so they can be told
+	// apart from invalid ones.
+	activationGrace = 7 * 24 * time.Hour
+)
+
+// Links are the addresses the emailed links point at.
+type Links struct {
+	// ActivationURL is the activation endpoint, the link is followed with a GET.
+	ActivationURL string
+	// PasswordResetURL is the page of the frontend that asks for the new password and
+	// posts it with the token and user ID of its query to /v1/users/password/reset.
+	PasswordResetURL string
+}
+
+// GenerateActivationLink stores an activation token for the user, replacing the
+// previous one, and returns the link to email. Redis keeps the hash of the token
+// with its expiry time.
+func GenerateActivationLink(ctx context.Context, redisClient *redis.Client, activationURL string, userID int) (string, error) {
 	log := GetLogger(ctx)
 
 	token, err := generateRandomToken()
 	if err != nil {
-		log.Fatalf("Failed to generate random token: %v", err)
+		log.Errorf("Failed to generate random token: %v", err)
+		return "", err
 	}
 
-	req, _ := http.NewRequest("GET", "https://localhost:5000/v1/users/activate", nil)
-	q := req.URL.Query()
-
-	q.Add("token", token)
-	q.Add("userID", strconv.Itoa(userID))
+	activationLink, err := tokenLink(activationURL, token, userID)
+	if err != nil {
+		log.Errorf("Invalid activation URL: %v", err)
+		return "", err
+	}
 
-	req.URL.RawQuery = q.Encode()
-	activationLink := req.URL.String()
+	expiresAt := time.Now().Add(ActivationTokenTTL).Unix()
+	value := HashToken(token) + ":" + strconv.FormatInt(expiresAt, 10)
 
-	err = redisClient.Client.Set(ctx, strconv.Itoa(userID), token, 24*time.Hour).Err()
+	err = redisClient.Client.Set(ctx, ActivationKey(userID), value, ActivationTokenTTL+activationGrace).Err()
 	if err != nil {
 		log.Errorf("failed to set activation link in Redis: %v", err)
 		return "", err
@@ -36,6 +59,68 @@ func GenerateActivationLink(ctx context.Context, redisClient *redis.Client, user
 	return activationLink, nil
 }
 
+// ActivationKey is the Redis key of the pending activation of the user.
+func ActivationKey(userID int) string {
+	return "activation:" + strconv.Itoa(userID)
+}
+
+// PasswordResetTTL is how long a password reset link can be used.
+const PasswordResetTTL = time.Hour
+
+// GeneratePasswordResetLink stores a single-use password reset token for the user,
+// replacing the previous one, and returns the link to email. Only the hash of the
+// token is kept in Redis.
+func GeneratePasswordResetLink(ctx context.Context, redisClient *redis.Client, passwordResetURL string, userID int) (string, error) {
+	log := GetLogger(ctx)
+
+	token, err := generateRandomToken()
+	if err != nil {
+		log.Errorf("Failed to generate random token: %v", err)
+		return "", err
+	}
+
+	resetLink, err := tokenLink(passwordResetURL, token, userID)
+	if err != nil {
+		log.Errorf("Invalid password reset URL: %v", err)
+		return "", err
+	}
+
+	err = redisClient.Client.Set(ctx, PasswordResetKey(userID), HashToken(token), PasswordResetTTL).Err()
+	if err != nil {
+		log.Errorf("failed to set password reset token in Redis: %v", err)
+		return "", err
+	}
+
+	return resetLink, nil
+}
+
+// PasswordResetKey is the Redis key of the pending password reset of the user.
+func PasswordResetKey(userID int) string {
+	return "password_reset:" + strconv.Itoa(userID)
+}
+
+// HashToken returns the hex SHA-256 of a token, for storing tokens that are only
+// ever compared.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// tokenLink adds the token and the user ID to the query of the link.
+func tokenLink(link, token string, userID int) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	q.Set("userID", strconv.Itoa(userID))
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
 func generateRandomToken() (string, error) {
 	tokenBytes := make([]byte, 32)
 	_, err := rand.Read(tokenBytes)
@@ -3,13 +3,18 @@ package main
 import (
 	"context"
 	"github.com/kelseyhightower/envconfig"
+	"library/pkg/apikey"
 	"library/pkg/config"
+	"library/pkg/jwks"
 	"library/pkg/logger"
+	"library/pkg/middleware"
 	"library/pkg/postgres"
+	"library/pkg/redis"
 	"library/pkg/utils"
 	"library/shops/handler"
 	"library/shops/repository"
 	"library/shops/server"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -46,10 +51,22 @@ func main() {
 	}
 	defer db.Close()
 
+	redisClient, err := redis.NewRedis(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer redisClient.Close()
+	middleware.UseDenylist(redisClient)
+	middleware.UseSessionTracker(redisClient)
+	middleware.UseAPIKeys(apikey.NewStore(db.GetDB()))
+	middleware.UsePublicKeys(jwks.NewRemote(cfg.JWKSURL, &http.Client{Timeout: 5 * time.Second}, cfg.JWKSCacheTTL))
+
 	shopRepository := repository.NewShopRepository(ctx, *db)
 	shopHandler := handler.NewShopHandler(ctx, shopRepository)
+	reviewRepository := repository.NewReviewRepository(ctx, *db)
+	reviewHandler := handler.NewReviewHandler(ctx, reviewRepository)
 
-	router := server.NewRouter(shopHandler)
+	router := server.NewRouter(shopHandler, reviewHandler)
 	go router.Run(":" + cfg.ShopsServerPort)
 
 	select {
@@ -0,0 +1,23 @@
+package handler_test
+
+import (
+	"library/pkg/jwks"
+	"library/pkg/middleware"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Handler Suite")
+}
+
+var _ = BeforeSuite(func() {
+	keys, err := jwks.GenerateKeySet()
+	Expect(err).To(BeNil())
+
+	middleware.UseSigningKeys(keys)
+})
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"library/pkg/authz"
+	"library/pkg/utils"
+	"library/shops/models"
+	"library/shops/repository"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewerHandler interface {
+	GetCatalogBook(c *gin.Context)
+	ListReviews(c *gin.Context)
+	SaveReview(c *gin.Context)
+	DeleteReview(c *gin.Context)
+	ModerateReview(c *gin.Context)
+}
+
+type ReviewHandler struct {
+	ctx              context.Context
+	reviewRepository repository.ReviewerRepository
+}
+
+func NewReviewHandler(ctx context.Context, reviewer repository.ReviewerRepository) ReviewerHandler {
+	return &ReviewHandler{
+		ctx:              ctx,
+		reviewRepository: reviewer,
+	}
+}
+
+// GetCatalogBook retrieves a shop book with its rating.
+//
+//	@Summary		Retrieve a catalog book
+//	@Description	Retrieves a shop book with the average and number of ratings of its visible reviews.
+//	@Tags			reviews
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"JWT Token"
+//	@Param			book_id			path		int		true	"Catalog book ID"
+//	@Success		200
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/shops/books/{book_id} [get]
+func (r *ReviewHandler) GetCatalogBook(c *gin.Context) {
+	log := utils.GetLogger(r.ctx)
+
+	book, err := r.reviewRepository.GetCatalogBook(c.GetInt("bookID"))
+	if err != nil {
+		log.Errorf("Get catalog book repository error: %v", err)
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"book": book})
+}
+
+// ListReviews retrieves a page of the reviews of a catalog book.
+//
+//	@Summary		List reviews
+//	@Description	Retrieves the reviews of a catalog book, most recently updated first. Hidden reviews are only listed for callers with the reviews:moderate permission.
+//	@Tags			reviews
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"JWT Token"
+//	@Param			book_id			path		int		true	"Catalog book ID"
+//	@Param			limit			query		int		false	"Page size (default 20, max 100)"
+//	@Param			offset			query		int		false	"Number of reviews to skip"
+//	@Success		200
+//	@Failure		400
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/shops/books/{book_id}/reviews [get]
+func (r *ReviewHandler) ListReviews(c *gin.Context) {
+	var filter models.ReviewFilter
+
+	log := utils.GetLogger(r.ctx)
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		log.Errorf("Query binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := filter.Validate(); err != nil {
+		log.Warningf("Invalid review filter: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter.IncludeHidden = authz.Can(c, authz.ReviewsModerate)
+
+	reviews, err := r.reviewRepository.ListReviews(c.GetInt("bookID"), &filter)
+	if err != nil {
+		log.Errorf("List reviews repository error: %v", err)
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// SaveReview posts or edits the review of the user for a catalog book.
+//
+//	@Summary		Review a catalog book
+//	@Description	Posts a 1 to 5 star rating with an optional review, or replaces the previous review of the user.
+//	@Tags			reviews
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string					true	"JWT Token"
+//	@Param			book_id			path		int						true	"Catalog book ID"
+//	@Param			review			body		models.ReviewRequest	true	"Rating and review"
+//	@Success		200
+//	@Success		201
+//	@Failure		400
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/shops/books/{book_id}/reviews [put]
+func (r *ReviewHandler) SaveReview(c *gin.Context) {
+	var request models.ReviewRequest
+
+	log := utils.GetLogger(r.ctx)
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		log.Warningf("Invalid review: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, created, err := r.reviewRepository.SaveReview(c.GetInt("bookID"), c.GetInt("userID"), &request)
+	if err != nil {
+		log.Errorf("Save review repository error: %v", err)
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+
+	log.Infof("Review saved successfully, id: %v", review.ID)
+	c.JSON(status, gin.H{"review": review})
+}
+
+// DeleteReview removes the review of the user for a catalog book.
+//
+//	@Summary		Delete a review
+//	@Description	Removes the review the user posted for a catalog book.
+//	@Tags			reviews
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"JWT Token"
+//	@Param			book_id			path		int		true	"Catalog book ID"
+//	@Success		200
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/shops/books/{book_id}/reviews [delete]
+func (r *ReviewHandler) DeleteReview(c *gin.Context) {
+	log := utils.GetLogger(r.ctx)
+
+	if err := r.reviewRepository.DeleteReview(c.GetInt("bookID"), c.GetInt("userID")); err != nil {
+		log.Errorf("Delete review repository error: %v", err)
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review deleted successfully"})
+}
+
+// ModerateReview hides an abusive review or shows it again. The route requires the reviews:moderate permission.
+//
+//	@Summary		Moderate a review
+//	@Description	Hides a review from the listings and the rating, or shows it again.
+//	@Tags			reviews
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string						true	"JWT Token"
+//	@Param			review_id		path		int							true	"Review ID"
+//	@Param			moderation		body		models.ModerationRequest	true	"Whether the review is hidden"
+//	@Success		200
+//	@Failure		400
+//	@Failure		403
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/shops/reviews/{review_id}/moderation [put]
+func (r *ReviewHandler) ModerateReview(c *gin.Context) {
+	var request models.ModerationRequest
+
+	log := utils.GetLogger(r.ctx)
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := r.reviewRepository.ModerateReview(c.GetInt("reviewID"), c.GetInt("userID"), request.Hidden)
+	if err != nil {
+		log.Errorf("Moderate review repository error: %v", err)
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infof("Review %v moderated, hidden: %v", review.ID, review.Hidden)
+	c.JSON(http.StatusOK, gin.H{"review": review})
+}
+
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrCatalogBookNotFound), errors.Is(err, repository.ErrReviewNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
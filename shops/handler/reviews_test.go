@@ -0,0 +1,245 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"library/pkg/authz"
+	"library/pkg/logger"
+	"library/pkg/middleware"
+	"library/shops/handler"
+	"library/shops/models"
+	"library/shops/repository"
+	"library/shops/repository/repositoryfakes"
+	"library/shops/server"
+	userModel "library/users/models"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type staticAPIKeys struct {
+	claims *userModel.Claims
+}
+
+func (s staticAPIKeys) ResolveAPIKey(context.Context, string) (*userModel.Claims, error) {
+	return s.claims, nil
+}
+
+var _ = Describe("Review API Test", func() {
+	var (
+		fakeReviewer *repositoryfakes.FakeReviewerRepository
+		w            *httptest.ResponseRecorder
+		permissions  []string
+		request      func(method, path string, body interface{}) *http.Request
+		serve        func(req *http.Request)
+	)
+
+	BeforeEach(func() {
+		permissions = nil
+	})
+
+	JustBeforeEach(func() {
+		w = httptest.NewRecorder()
+
+		ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+		fakeReviewer = &repositoryfakes.FakeReviewerRepository{}
+		router := server.NewRouter(handler.NewShopHandler(ctx, nil), handler.NewReviewHandler(ctx, fakeReviewer))
+
+		token, _ := middleware.GenerateJWT(userModel.User{ID: 1, Email: "tmostowashere@tmostowashere.com", Role: "user", Permissions: permissions})
+
+		request = func(method, path string, body interface{}) *http.Request {
+			var payload bytes.Buffer
+			if body != nil {
+				Expect(json.NewEncoder(&payload).Encode(body)).To(Succeed())
+			}
+
+			req, err := http.NewRequest(method, path, &payload)
+			Expect(err).To(BeNil())
+			req.AddCookie(&http.Cookie{Name: "token", Value: token, Expires: time.Now().Add(time.Hour)})
+			req.Header.Set("Content-Type", "application/json")
+
+			return req
+		}
+
+		serve = func(req *http.Request) {
+			router.ServeHTTP(w, req)
+		}
+	})
+
+	Describe("GetCatalogBook", func() {
+		It("should return the book with its rating", func() {
+			fakeReviewer.GetCatalogBookReturns(&models.CatalogBook{
+				ID:     4,
+				Name:   "The Hobbit",
+				Rating: models.RatingSummary{Average: 4.5, Count: 2},
+			}, nil)
+
+			serve(request("GET", "/v1/shops/books/4", nil))
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+			Expect(w.Body.String()).To(ContainSubstring(`"rating":{"average":4.5,"count":2}`))
+			Expect(fakeReviewer.GetCatalogBookArgsForCall(0)).To(Equal(4))
+		})
+
+		It("should return not found for an unknown book", func() {
+			fakeReviewer.GetCatalogBookReturns(nil, repository.ErrCatalogBookNotFound)
+
+			serve(request("GET", "/v1/shops/books/42", nil))
+
+			Expect(w.Code).To(Equal(http.StatusNotFound), "Expected HTTP status Not Found")
+		})
+	})
+
+	Describe("ListReviews", func() {
+		It("should hide moderated reviews from users", func() {
+			fakeReviewer.ListReviewsReturns(&models.ReviewListResponse{Reviews: []models.Review{}}, nil)
+
+			serve(request("GET", "/v1/shops/books/4/reviews?limit=5&offset=10", nil))
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+
+			bookID, filter := fakeReviewer.ListReviewsArgsForCall(0)
+			Expect(bookID).To(Equal(4))
+			Expect(filter.Limit).To(Equal(5))
+			Expect(filter.Offset).To(Equal(10))
+			Expect(filter.IncludeHidden).To(BeFalse())
+		})
+
+		Context("with the reviews:moderate permission", func() {
+			BeforeEach(func() {
+				permissions = []string{authz.ReviewsModerate}
+			})
+
+			It("should include moderated reviews", func() {
+				fakeReviewer.ListReviewsReturns(&models.ReviewListResponse{Reviews: []models.Review{}}, nil)
+
+				serve(request("GET", "/v1/shops/books/4/reviews", nil))
+
+				Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+
+				_, filter := fakeReviewer.ListReviewsArgsForCall(0)
+				Expect(filter.Limit).To(Equal(models.DefaultReviewPageSize))
+				Expect(filter.IncludeHidden).To(BeTrue())
+			})
+		})
+
+		It("should reject a page that is too large", func() {
+			serve(request("GET", "/v1/shops/books/4/reviews?limit=500", nil))
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest), "Expected HTTP status Bad Request")
+			Expect(fakeReviewer.ListReviewsCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("SaveReview", func() {
+		It("should create the review of the user", func() {
+			fakeReviewer.SaveReviewReturns(&models.Review{ID: 9, BookID: 4, UserID: 1, Rating: 5}, true, nil)
+
+			serve(request("PUT", "/v1/shops/books/4/reviews", models.ReviewRequest{Rating: 5, Review: "  Lovely. "}))
+
+			Expect(w.Code).To(Equal(http.StatusCreated), "Expected HTTP status Created")
+
+			bookID, userID, review := fakeReviewer.SaveReviewArgsForCall(0)
+			Expect(bookID).To(Equal(4))
+			Expect(userID).To(Equal(1))
+			Expect(review.Review).To(Equal("Lovely."))
+		})
+
+		It("should answer OK when the review is edited", func() {
+			fakeReviewer.SaveReviewReturns(&models.Review{ID: 9, BookID: 4, UserID: 1, Rating: 3}, false, nil)
+
+			serve(request("PUT", "/v1/shops/books/4/reviews", models.ReviewRequest{Rating: 3}))
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+		})
+
+		It("should reject a rating out of range", func() {
+			serve(request("PUT", "/v1/shops/books/4/reviews", models.ReviewRequest{Rating: 6}))
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest), "Expected HTTP status Bad Request")
+			Expect(fakeReviewer.SaveReviewCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("ModerateReview", func() {
+		It("should forbid users without the permission", func() {
+			serve(request("PUT", "/v1/shops/reviews/9/moderation", models.ModerationRequest{Hidden: true}))
+
+			Expect(w.Code).To(Equal(http.StatusForbidden), "Expected HTTP status Forbidden")
+			Expect(fakeReviewer.ModerateReviewCallCount()).To(Equal(0))
+		})
+
+		Context("with the reviews:moderate permission", func() {
+			BeforeEach(func() {
+				permissions = []string{authz.ReviewsModerate}
+			})
+
+			It("should hide the review", func() {
+				fakeReviewer.ModerateReviewReturns(&models.Review{ID: 9, Hidden: true}, nil)
+
+				serve(request("PUT", "/v1/shops/reviews/9/moderation", models.ModerationRequest{Hidden: true}))
+
+				Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+
+				reviewID, moderatorID, hidden := fakeReviewer.ModerateReviewArgsForCall(0)
+				Expect(reviewID).To(Equal(9))
+				Expect(moderatorID).To(Equal(1))
+				Expect(hidden).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("Scopes", func() {
+		useKey := func(permissions ...string) {
+			middleware.UseAPIKeys(staticAPIKeys{claims: &userModel.Claims{
+				UserID:         "1",
+				Permissions:    permissions,
+				APIKeyID:       3,
+				StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Hour).Unix()},
+			}})
+		}
+
+		keyRequest := func(path string) *http.Request {
+			req, err := http.NewRequest("GET", path, nil)
+			Expect(err).To(BeNil())
+			req.Header.Set("Authorization", "Bearer lib_key")
+
+			return req
+		}
+
+		AfterEach(func() {
+			middleware.UseAPIKeys(nil)
+		})
+
+		It("should refuse the catalog to an API key without the books:read scope", func() {
+			useKey(authz.ReviewsWrite)
+
+			serve(keyRequest("/v1/shops/books/4"))
+			Expect(w.Code).To(Equal(http.StatusForbidden), "Expected HTTP status Forbidden")
+
+			w = httptest.NewRecorder()
+			serve(keyRequest("/v1/shops/books/4/reviews"))
+			Expect(w.Code).To(Equal(http.StatusForbidden), "Expected HTTP status Forbidden")
+
+			Expect(fakeReviewer.GetCatalogBookCallCount()).To(Equal(0))
+			Expect(fakeReviewer.ListReviewsCallCount()).To(Equal(0))
+		})
+
+		It("should read the catalog with an API key of the books:read scope", func() {
+			useKey(authz.BooksRead)
+
+			fakeReviewer.GetCatalogBookReturns(&models.CatalogBook{ID: 4, Name: "The Hobbit"}, nil)
+
+			serve(keyRequest("/v1/shops/books/4"))
+
+			Expect(w.Code).To(Equal(http.StatusOK), "Expected HTTP status OK")
+		})
+	})
+})
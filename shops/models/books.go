@@ -6,6 +6,7 @@ type Author struct {
 }
 
 type ISBN struct {
+	Type       string `json:"type"`
 	Identifier string `json:"identifier"`
 }
 
@@ -14,7 +15,7 @@ type BooksRequest struct {
 		VolumeInfo struct {
 			Name          string   `json:"title"`
 			DatePublished string   `json:"publishedDate"`
-			ISBN          []ISBN   `json:"isbn"`
+			ISBN          []ISBN   `json:"industryIdentifiers"`
 			PageCount     int      `json:"pageCount"`
 			Authors       []string `json:"authors"`
 		} `json:"volumeInfo"`
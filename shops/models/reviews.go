@@ -0,0 +1,105 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+
+	DefaultReviewPageSize = 20
+	MaxReviewPageSize     = 100
+
+	maxReviewLength = 5000
+)
+
+// CatalogBook represents a shop book with the rating of its visible reviews.
+type CatalogBook struct {
+	ID            int           `json:"id"`
+	Name          string        `json:"name"`
+	DatePublished string        `json:"date_published,omitempty"`
+	ISBN          string        `json:"isbn,omitempty"`
+	PageCount     int           `json:"page_count,omitempty"`
+	Quantity      int           `json:"quantity"`
+	Authors       string        `json:"authors,omitempty"`
+	Rating        RatingSummary `json:"rating"`
+}
+
+// RatingSummary aggregates the ratings of the reviews that are not hidden.
+type RatingSummary struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+// Review represents the rating and optional text a user gave to a catalog book.
+type Review struct {
+	ID        int       `json:"id"`
+	BookID    int       `json:"book_id"`
+	UserID    int       `json:"user_id"`
+	Rating    int       `json:"rating"`
+	Review    string    `json:"review,omitempty"`
+	Hidden    bool      `json:"hidden"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReviewRequest represents the request body for posting or editing a review.
+type ReviewRequest struct {
+	Rating int    `json:"rating"`
+	Review string `json:"review,omitempty"`
+}
+
+// ModerationRequest represents the request body for hiding or showing a review.
+type ModerationRequest struct {
+	Hidden bool `json:"hidden"`
+}
+
+// ReviewFilter represents the query parameters for listing the reviews of a book.
+type ReviewFilter struct {
+	Limit  int `form:"limit"`
+	Offset int `form:"offset"`
+	// IncludeHidden is set for moderators, it is not read from the query.
+	IncludeHidden bool `form:"-"`
+}
+
+// ReviewListResponse represents a page of reviews.
+type ReviewListResponse struct {
+	Reviews []Review `json:"reviews"`
+	Total   int      `json:"total"`
+}
+
+// Validate checks the rating and trims the review text.
+func (r *ReviewRequest) Validate() error {
+	r.Review = strings.TrimSpace(r.Review)
+
+	if r.Rating < MinRating || r.Rating > MaxRating {
+		return fmt.Errorf("rating must be between %d and %d", MinRating, MaxRating)
+	}
+
+	if len(r.Review) > maxReviewLength {
+		return fmt.Errorf("review cannot be longer than %d characters", maxReviewLength)
+	}
+
+	return nil
+}
+
+// Validate fills in the default page size and checks the range of the page.
+func (f *ReviewFilter) Validate() error {
+	if f.Limit == 0 {
+		f.Limit = DefaultReviewPageSize
+	}
+
+	if f.Limit < 1 || f.Limit > MaxReviewPageSize {
+		return fmt.Errorf("limit must be between 1 and %d", MaxReviewPageSize)
+	}
+
+	if f.Offset < 0 {
+		return errors.New("offset cannot be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+const (
+	GetCatalogBook = `
+		SELECT
+			bk.id,
+			bk.name,
+			coalesce(to_char(bk.date_published, 'YYYY-MM-DD'), ''),
+			coalesce(bk.isbn, ''),
+			coalesce(bk.page_count, 0),
+			coalesce(bk.quantity, 0),
+			coalesce((SELECT string_agg(a.name, ', ' ORDER BY a.name) FROM book_authors AS ba JOIN author AS a ON a.id = ba.author_id WHERE ba.book_id = bk.id), ''),
+			coalesce(round(avg(r.rating), 2), 0),
+			count(r.id)
+		FROM
+			book AS bk
+			LEFT JOIN book_reviews AS r ON r.book_id = bk.id AND NOT r.hidden
+		WHERE
+			bk.id = $1
+		GROUP BY
+			bk.id
+	`
+
+	CatalogBookExists = "SELECT EXISTS (SELECT 1 FROM book WHERE id = $1)"
+	CountReviews      = "SELECT COUNT(*) FROM book_reviews AS r WHERE r.book_id = $1 AND ($2 OR NOT r.hidden)"
+	ListReviews       = `
+		SELECT r.id, r.book_id, r.user_id, r.rating, coalesce(r.review, ''), r.hidden, r.created_at, r.updated_at
+		FROM book_reviews AS r
+		WHERE r.book_id = $1 AND ($2 OR NOT r.hidden)
+		ORDER BY r.updated_at DESC, r.id DESC
+		LIMIT $3 OFFSET $4
+	`
+	// UpsertReview keeps the moderation flag, so editing a hidden review does not show it again.
+	UpsertReview = `
+		INSERT INTO book_reviews (book_id, user_id, rating, review)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (book_id, user_id) DO UPDATE
+		SET rating = excluded.rating, review = excluded.review, updated_at = now()
+		RETURNING id, hidden, created_at, updated_at, (xmax = 0)
+	`
+	DeleteReview   = "DELETE FROM book_reviews WHERE book_id = $1 AND user_id = $2"
+	ModerateReview = `
+		UPDATE book_reviews
+		SET hidden = $1, moderated_by = $2, moderated_at = now()
+		WHERE id = $3
+		RETURNING id, book_id, user_id, rating, coalesce(review, ''), hidden, created_at, updated_at
+	`
+)
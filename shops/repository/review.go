@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"library/pkg/postgres"
+	"library/pkg/utils"
+	"library/shops/models"
+)
+
+var (
+	ErrCatalogBookNotFound = errors.New("book not found in the catalog")
+	ErrReviewNotFound      = errors.New("review not found")
+)
+
+type ReviewerRepository interface {
+	GetCatalogBook(bookID int) (*models.CatalogBook, error)
+	ListReviews(bookID int, filter *models.ReviewFilter) (*models.ReviewListResponse, error)
+	SaveReview(bookID, userID int, review *models.ReviewRequest) (*models.Review, bool, error)
+	DeleteReview(bookID, userID int) error
+	ModerateReview(reviewID, moderatorID int, hidden bool) (*models.Review, error)
+}
+
+type ReviewRepository struct {
+	ctx context.Context
+	DB  postgres.DB
+}
+
+func NewReviewRepository(ctx context.Context, db postgres.DB) ReviewerRepository {
+	return &ReviewRepository{
+		ctx: ctx,
+		DB:  db,
+	}
+}
+
+// GetCatalogBook returns a shop book with the average and number of its visible ratings.
+func (r *ReviewRepository) GetCatalogBook(bookID int) (*models.CatalogBook, error) {
+	book := &models.CatalogBook{}
+
+	log := utils.GetLogger(r.ctx)
+
+	err := r.DB.DB.QueryRow(GetCatalogBook, bookID).Scan(
+		&book.ID,
+		&book.Name,
+		&book.DatePublished,
+		&book.ISBN,
+		&book.PageCount,
+		&book.Quantity,
+		&book.Authors,
+		&book.Rating.Average,
+		&book.Rating.Count,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("Catalog book not found: %v", bookID)
+		return nil, ErrCatalogBookNotFound
+	}
+
+	if err != nil {
+		log.Errorf("Query row on book table failed: %v", err)
+		return nil, err
+	}
+
+	return book, nil
+}
+
+func (r *ReviewRepository) ListReviews(bookID int, filter *models.ReviewFilter) (*models.ReviewListResponse, error) {
+	reviewList := &models.ReviewListResponse{Reviews: []models.Review{}}
+
+	log := utils.GetLogger(r.ctx)
+
+	if err := r.catalogBookExists(bookID); err != nil {
+		return reviewList, err
+	}
+
+	err := r.DB.DB.QueryRow(CountReviews, bookID, filter.IncludeHidden).Scan(&reviewList.Total)
+	if err != nil {
+		log.Errorf("Failed to count reviews: %v", err)
+		return reviewList, err
+	}
+
+	rows, err := r.DB.DB.Query(ListReviews, bookID, filter.IncludeHidden, filter.Limit, filter.Offset)
+	if err != nil {
+		log.Errorf("Failed to perform a query on book reviews table: %v", err)
+		return reviewList, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var review models.Review
+
+		err = rows.Scan(
+			&review.ID,
+			&review.BookID,
+			&review.UserID,
+			&review.Rating,
+			&review.Review,
+			&review.Hidden,
+			&review.CreatedAt,
+			&review.UpdatedAt,
+		)
+		if err != nil {
+			log.Errorf("Failed to scan rows: %v", err)
+			return reviewList, err
+		}
+
+		reviewList.Reviews = append(reviewList.Reviews, review)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Errorf("Query failed: %v", err)
+		return reviewList, err
+	}
+
+	return reviewList, nil
+}
+
+// SaveReview posts the review of the user or replaces their previous one. It also
+// reports whether the review was created rather than edited.
+func (r *ReviewRepository) SaveReview(bookID, userID int, request *models.ReviewRequest) (*models.Review, bool, error) {
+	var created bool
+
+	log := utils.GetLogger(r.ctx)
+
+	if err := r.catalogBookExists(bookID); err != nil {
+		return nil, false, err
+	}
+
+	review := &models.Review{
+		BookID: bookID,
+		UserID: userID,
+		Rating: request.Rating,
+		Review: request.Review,
+	}
+
+	var text sql.NullString
+	if request.Review != "" {
+		text = sql.NullString{String: request.Review, Valid: true}
+	}
+
+	err := r.DB.DB.QueryRow(UpsertReview, bookID, userID, request.Rating, text).Scan(
+		&review.ID,
+		&review.Hidden,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+		&created,
+	)
+	if err != nil {
+		log.Errorf("Failed to save review: %v", err)
+		return nil, false, err
+	}
+
+	return review, created, nil
+}
+
+func (r *ReviewRepository) DeleteReview(bookID, userID int) error {
+	log := utils.GetLogger(r.ctx)
+
+	result, err := r.DB.DB.Exec(DeleteReview, bookID, userID)
+	if err != nil {
+		log.Errorf("Failed to delete review: %v", err)
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		log.Errorf("Error number of rows affected: %v", err)
+		return err
+	}
+
+	if deleted == 0 {
+		log.Warningf("User %v has no review of book %v", userID, bookID)
+		return ErrReviewNotFound
+	}
+
+	return nil
+}
+
+// ModerateReview hides or shows a review. Hidden reviews do not count towards the rating.
+func (r *ReviewRepository) ModerateReview(reviewID, moderatorID int, hidden bool) (*models.Review, error) {
+	review := &models.Review{}
+
+	log := utils.GetLogger(r.ctx)
+
+	err := r.DB.DB.QueryRow(ModerateReview, hidden, moderatorID, reviewID).Scan(
+		&review.ID,
+		&review.BookID,
+		&review.UserID,
+		&review.Rating,
+		&review.Review,
+		&review.Hidden,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("Review not found: %v", reviewID)
+		return nil, ErrReviewNotFound
+	}
+
+	if err != nil {
+		log.Errorf("Failed to moderate review: %v", err)
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (r *ReviewRepository) catalogBookExists(bookID int) error {
+	log := utils.GetLogger(r.ctx)
+
+	var exists bool
+	if err := r.DB.DB.QueryRow(CatalogBookExists, bookID).Scan(&exists); err != nil {
+		log.Errorf("Failed to check catalog book: %v", err)
+		return err
+	}
+
+	if !exists {
+		log.Warningf("Catalog book not found: %v", bookID)
+		return ErrCatalogBookNotFound
+	}
+
+	return nil
+}
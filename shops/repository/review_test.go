@@ -0,0 +1,131 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"library/pkg/logger"
+	"library/pkg/postgres"
+	"library/shops/models"
+	"library/shops/repository"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Review Repository Test", func() {
+	var (
+		reviewRepo repository.ReviewerRepository
+		mock       sqlmock.Sqlmock
+		fakeDB     *postgres.DB
+		bookExists func(bookID int, exists bool)
+	)
+
+	JustBeforeEach(func() {
+		ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+		fakeDB, _ = postgres.NewFakeDB(ctx)
+		reviewRepo = repository.NewReviewRepository(ctx, *fakeDB)
+		mock = fakeDB.GetMock()
+
+		bookExists = func(bookID int, exists bool) {
+			mock.ExpectQuery(repository.CatalogBookExists).
+				WithArgs(bookID).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(exists))
+		}
+	})
+
+	AfterEach(func() {
+		fakeDB.Close()
+	})
+
+	Describe("GetCatalogBook", func() {
+		It("should return the rating of the book", func() {
+			mock.ExpectQuery(repository.GetCatalogBook).
+				WithArgs(4).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "date_published", "isbn", "page_count", "quantity", "authors", "average", "count"}).
+					AddRow(4, "The Hobbit", "1937-09-21", "9780261103344", 310, 2, "J. R. R. Tolkien", 4.5, 2))
+
+			book, err := reviewRepo.GetCatalogBook(4)
+
+			Expect(err).To(BeNil())
+			Expect(book.Rating).To(Equal(models.RatingSummary{Average: 4.5, Count: 2}))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("should report a missing book", func() {
+			mock.ExpectQuery(repository.GetCatalogBook).
+				WithArgs(42).
+				WillReturnError(sql.ErrNoRows)
+
+			_, err := reviewRepo.GetCatalogBook(42)
+
+			Expect(err).To(MatchError(repository.ErrCatalogBookNotFound))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+
+	Describe("ListReviews", func() {
+		It("should page through the visible reviews", func() {
+			bookExists(4, true)
+
+			mock.ExpectQuery(repository.CountReviews).
+				WithArgs(4, false).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+			mock.ExpectQuery(repository.ListReviews).
+				WithArgs(4, false, 10, 10).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "user_id", "rating", "review", "hidden", "created_at", "updated_at"}).
+					AddRow(1, 4, 2, 5, "Lovely.", false, time.Now(), time.Now()))
+
+			reviews, err := reviewRepo.ListReviews(4, &models.ReviewFilter{Limit: 10, Offset: 10})
+
+			Expect(err).To(BeNil())
+			Expect(reviews.Total).To(Equal(11))
+			Expect(reviews.Reviews).To(HaveLen(1))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+
+	Describe("SaveReview", func() {
+		It("should store a rating without text as NULL", func() {
+			bookExists(4, true)
+
+			mock.ExpectQuery(repository.UpsertReview).
+				WithArgs(4, 1, 4, nil).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "hidden", "created_at", "updated_at", "created"}).
+					AddRow(9, false, time.Now(), time.Now(), true))
+
+			review, created, err := reviewRepo.SaveReview(4, 1, &models.ReviewRequest{Rating: 4})
+
+			Expect(err).To(BeNil())
+			Expect(created).To(BeTrue())
+			Expect(review.ID).To(Equal(9))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("should refuse a book that is not in the catalog", func() {
+			bookExists(42, false)
+
+			_, _, err := reviewRepo.SaveReview(42, 1, &models.ReviewRequest{Rating: 4})
+
+			Expect(err).To(MatchError(repository.ErrCatalogBookNotFound))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+
+	Describe("ModerateReview", func() {
+		It("should report a missing review", func() {
+			mock.ExpectQuery(repository.ModerateReview).
+				WithArgs(true, 1, 9).
+				WillReturnError(sql.ErrNoRows)
+
+			_, err := reviewRepo.ModerateReview(9, 1, true)
+
+			Expect(err).To(MatchError(repository.ErrReviewNotFound))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+})
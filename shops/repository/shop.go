@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"library/pkg/authors"
+	"library/pkg/isbn"
 	"library/pkg/logger"
 	"library/pkg/postgres"
 	"library/shops/models"
@@ -27,21 +29,15 @@ func NewShopRepository(ctx context.Context, db postgres.DB) ShopperRepository {
 
 func (s *ShopRepository) GetOrCreateAuthor(authorName string) (models.Author, error) {
 	var author models.Author
+	var err error
 	log := s.ctx.Value("logger").(logger.Logger)
 
-	selectAuthor := "SELECT id FROM author WHERE name = $1"
-	err := s.DB.DB.QueryRow(selectAuthor, authorName).Scan(&author.ID)
+	author.ID, author.Names, err = authors.GetOrCreate(s.DB.DB, authorName)
 	if err != nil {
-		insertAuthor := "INSERT INTO author (name) VALUES ($1) RETURNING id"
-		err = s.DB.DB.QueryRow(insertAuthor, authorName).Scan(&author.ID)
-		if err != nil {
-			log.Errorf("Failed to perform an insert query on author table: %v", err)
-			return author, err
-		}
+		log.Errorf("Failed to get or create author %q: %v", authorName, err)
+		return author, err
 	}
 
-	author.Names = authorName
-
 	return author, nil
 }
 
@@ -58,6 +54,11 @@ func (s *ShopRepository) LoadBooks() error {
 			var bookID int
 
 			isbn := ConvertIndustryIdentifiers(item.VolumeInfo.ISBN)
+			if isbn == "" {
+				log.Warningf("Skipping %q, it has no valid ISBN", item.VolumeInfo.Name)
+				continue
+			}
+
 			checkISBN, _ := IsISBN(log, isbn, s.DB)
 			datePublished, _ := ParseDateString(log, item.VolumeInfo.DatePublished)
 
@@ -119,14 +120,25 @@ func searchBooksByTypes(log logger.Logger, bookType string) (models.BooksRequest
 	return booksResponse, nil
 }
 
+// ConvertIndustryIdentifiers returns the canonical ISBN-13 of a volume, preferring
+// the ISBN_13 identifier over ISBN_10, or an empty string when it has no valid ISBN.
 func ConvertIndustryIdentifiers(identifiers []models.ISBN) string {
-	var result []string
+	var result string
 
 	for _, id := range identifiers {
-		result = append(result, id.Identifier)
+		canonical, ok := isbn.FromIndustryIdentifier(id.Type, id.Identifier)
+		if !ok {
+			continue
+		}
+
+		if id.Type == isbn.TypeISBN13 {
+			return canonical
+		}
+
+		result = canonical
 	}
 
-	return ""
+	return result
 }
 
 func ParseDateString(log logger.Logger, publishedDate string) (string, error) {
@@ -2,15 +2,65 @@ package server
 
 import (
 	"github.com/gin-gonic/gin"
+	"library/pkg/authz"
+	"library/pkg/middleware"
+	"library/pkg/tracing"
 	"library/shops/handler"
 )
 
-func NewRouter(shopHandler handler.ShopperHandler) *gin.Engine {
+func NewRouter(shopHandler handler.ShopperHandler, reviewHandler handler.ReviewerHandler) *gin.Engine {
 	router := gin.Default()
 
 	v1 := router.Group("/v1/shops")
 
-	v1.POST("/load-books", shopHandler.LoadBooks)
+	v1.POST("/load-books",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequirePermission(authz.ShopsImport),
+		shopHandler.LoadBooks,
+	)
+
+	v1.GET("/books/:book_id",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireScope(authz.BooksRead),
+		middleware.GetBookParam,
+		reviewHandler.GetCatalogBook,
+	)
+	v1.GET("/books/:book_id/reviews",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireScope(authz.BooksRead),
+		middleware.GetBookParam,
+		reviewHandler.ListReviews,
+	)
+	v1.PUT("/books/:book_id/reviews",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireScope(authz.ReviewsWrite),
+		middleware.GetBookParam,
+		reviewHandler.SaveReview,
+	)
+	v1.DELETE("/books/:book_id/reviews",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireScope(authz.ReviewsWrite),
+		middleware.GetBookParam,
+		reviewHandler.DeleteReview,
+	)
+	v1.PUT("/reviews/:review_id/moderation",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequirePermission(authz.ReviewsModerate),
+		middleware.GetReviewParam,
+		reviewHandler.ModerateReview,
+	)
 
 	return router
 }
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"library/shops/models"
 	"net/http"
+	"time"
 )
 
+var client = &http.Client{Timeout: 10 * time.Second}
+
 func GetBooks(bookTitle string) (models.BooksRequest, error) {
 	var googleBooksRequest models.BooksRequest
 
@@ -25,3 +28,27 @@ func GetBooks(bookTitle string) (models.BooksRequest, error) {
 
 	return googleBooksRequest, nil
 }
+
+// GetBookByISBN searches Google Books for the volumes with the given canonical ISBN.
+func GetBookByISBN(isbn string) (models.BooksRequest, error) {
+	var googleBooksRequest models.BooksRequest
+
+	url := fmt.Sprintf("https://www.googleapis.com/books/v1/volumes?q=isbn:%s", isbn)
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return googleBooksRequest, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return googleBooksRequest, fmt.Errorf("google books responded with status %d", resp.StatusCode)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&googleBooksRequest)
+	if err != nil {
+		return models.BooksRequest{}, err
+	}
+
+	return googleBooksRequest, nil
+}
@@ -3,8 +3,11 @@ package main
 import (
 	"context"
 	"github.com/kelseyhightower/envconfig"
+	"library/pkg/apikey"
 	"library/pkg/config"
+	"library/pkg/jwks"
 	"library/pkg/logger"
+	"library/pkg/middleware"
 	"library/pkg/postgres"
 	"library/pkg/rabbitMQ/rabbitMQ"
 	"library/pkg/redis"
@@ -12,6 +15,7 @@ import (
 	"library/transactions/handler"
 	"library/transactions/repository"
 	"library/transactions/server"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -51,6 +55,10 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to connect to Redis: %v", err)
 	}
+	middleware.UseDenylist(redisClient)
+	middleware.UseSessionTracker(redisClient)
+	middleware.UseAPIKeys(apikey.NewStore(db.GetDB()))
+	middleware.UsePublicKeys(jwks.NewRemote(cfg.JWKSURL, &http.Client{Timeout: 5 * time.Second}, cfg.JWKSCacheTTL))
 
 	rmq, err := rabbitMQ.NewConn(cfg)
 	if err != nil {
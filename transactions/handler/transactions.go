@@ -34,17 +34,19 @@ func NewTransactionHandler(ctx context.Context, transactionHandler repository.Tr
 //	@Description	Buy a book with the provided transaction data
 //	@Accept			json
 //	@Produce		json
+//	@Param			user_id		path		int											true	"User ID"
 //	@Param			bookID		path		int											true	"Book ID"
 //	@Param			transaction	body		models.TransactionResponse							true	"Transaction data"
 //	@Success		201
 //	@Failure		400
+//	@Failure		403
 //	@Failure		500
-//	@Router			/v1/transactions/buy-book/{bookID} [post]
+//	@Router			/v1/transactions/{user_id}/buy-book/{bookID} [post]
 func (t *TransactionHandler) BuyBook(c *gin.Context) {
 	transaction := models.TransactionResponse{}
 	log := utils.GetLogger(t.ctx)
 
-	userID := 1
+	userID := c.GetInt("userID")
 	bookID := c.GetInt("bookID")
 
 	if err := c.ShouldBindJSON(&transaction); err != nil {
@@ -67,14 +69,15 @@ func (t *TransactionHandler) BuyBook(c *gin.Context) {
 // TransactionHistory retrieves the transaction history for a user.
 //
 //	@Summary		Get transaction history
-//	@Description	Retrieves the transaction history for the specified user
+//	@Description	Retrieves the transaction history for the specified user. The history of other users requires the transactions:read:any permission.
 //	@Accept			json
 //	@Produce		json
-//	@Param			userID	path		int										true	"User ID"
+//	@Param			user_id	path		int										true	"User ID"
 //	@Success		200
 //	@Failure		400
+//	@Failure		403
 //	@Failure		500
-//	@Router			/v1/transactions/history/{userID} [get]
+//	@Router			/v1/transactions/{user_id}/history [post]
 func (t *TransactionHandler) TransactionHistory(c *gin.Context) {
 	log := utils.GetLogger(t.ctx)
 	userID := c.GetInt("userID")
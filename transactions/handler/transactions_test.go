@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"io"
 
+	"library/pkg/authz"
 	"library/pkg/logger"
 	"library/pkg/middleware"
 	"library/transactions/handler"
@@ -48,9 +49,10 @@ var _ = Describe("Transaction API Test", func() {
 		router = server.NewRouter(fakeTransactionHandler)
 
 		user = &userModel.User{
-			ID:    1,
-			Email: "tmostowashere@tmostowashere.com",
-			Role:  "superuser",
+			ID:          1,
+			Email:       "tmostowashere@tmostowashere.com",
+			Role:        "user",
+			Permissions: []string{authz.TransactionsCreate, authz.TransactionsRead},
 		}
 
 		token, _ := middleware.GenerateJWT(*user)
@@ -80,7 +82,7 @@ var _ = Describe("Transaction API Test", func() {
 			body, err := json.Marshal(request)
 			Expect(err).To(BeNil())
 
-			ginCtx.Request, err = http.NewRequest("POST", "/v1/transactions/buy-book/1", bytes.NewBuffer(body))
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/transactions/1/buy-book/1", bytes.NewBuffer(body))
 			Expect(err).To(BeNil())
 			ginCtx.Request.AddCookie(cookie)
 			ginCtx.Request = enricher(ginCtx.Request)
@@ -95,11 +97,28 @@ var _ = Describe("Transaction API Test", func() {
 		})
 	})
 
+	Describe("Buy Book for another user", func() {
+		It("should be forbidden", func() {
+			var err error
+
+			body, _ := json.Marshal(models.TransactionResponse{Quantity: 1})
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/transactions/2/buy-book/1", bytes.NewBuffer(body))
+			Expect(err).To(BeNil())
+			ginCtx.Request.AddCookie(cookie)
+			ginCtx.Request = enricher(ginCtx.Request)
+
+			router.ServeHTTP(w, ginCtx.Request)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden), "Expected HTTP status Forbidden")
+			Expect(fakeTransactioner.BuyBookCallCount()).To(Equal(0))
+		})
+	})
+
 	Describe("Transaction History", func() {
 		It("should return user transaction history", func() {
 			var err error
 
-			ginCtx.Request, err = http.NewRequest("POST", "/v1/transactions/transactions", nil)
+			ginCtx.Request, err = http.NewRequest("POST", "/v1/transactions/1/history", nil)
 			Expect(err).To(BeNil())
 			ginCtx.Request.AddCookie(cookie)
 			ginCtx.Request = enricher(ginCtx.Request)
@@ -7,6 +7,7 @@ import (
 	"library/pkg/rabbitMQ/rabbitMQ"
 	"library/pkg/redis"
 
+	"library/pkg/isbn"
 	"library/pkg/logger"
 	"library/pkg/postgres"
 	"library/pkg/utils"
@@ -189,7 +190,7 @@ func processTransaction(
 		return false, err
 	}
 
-	if book.ISBN == userTransaction.BookList.ISBN {
+	if isbn.Equal(book.ISBN, userTransaction.BookList.ISBN) {
 		_, err := db.DB.Exec(UpdateTransactionQuantity, userTransaction.Quantity, transaction.ID)
 		if err != nil {
 			log.Errorf("Failed to update available quantity: %v", err)
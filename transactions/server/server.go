@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"library/pkg/authz"
 	"library/pkg/middleware"
 	"library/pkg/tracing"
 	"library/transactions/handler"
@@ -16,17 +17,21 @@ func NewRouter(transactionsHandler handler.TransactionerHandler) *gin.Engine {
 
 	v1.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	v1.POST("/buy-book/:book_id",
+	v1.POST("/:user_id/buy-book/:book_id",
 		tracing.TraceMiddleware,
 		middleware.IsAuthorized,
 		middleware.GetToken,
+		authz.RequireOwner(authz.TransactionsCreate),
+		authz.RequirePermission(authz.TransactionsCreate),
 		middleware.GetBookParam,
 		transactionsHandler.BuyBook,
 	)
-	v1.POST("/history",
+	v1.POST("/:user_id/history",
 		tracing.TraceMiddleware,
 		middleware.IsAuthorized,
 		middleware.GetToken,
+		authz.RequireOwnerOr(authz.TransactionsReadAny, authz.TransactionsRead),
+		authz.RequirePermission(authz.TransactionsRead),
 		transactionsHandler.TransactionHistory,
 	)
 
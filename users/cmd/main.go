@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"library/pkg/apikey"
 	"library/pkg/config"
+	"library/pkg/jwks"
 	"library/pkg/logger"
+	"library/pkg/middleware"
 	"library/pkg/postgres"
 	"library/pkg/rabbitMQ/rabbitMQ"
 	"library/pkg/redis"
@@ -59,16 +62,36 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to connect to Redis: %v", err)
 	}
+	middleware.UseDenylist(redisClient)
+	middleware.UseSessionTracker(redisClient)
+	middleware.UseAPIKeys(apikey.NewStore(db.GetDB()))
+
+	var keySet *jwks.KeySet
+	if cfg.JWTKeysDir != "" {
+		keySet, err = jwks.LoadKeySet(cfg.JWTKeysDir, cfg.JWTSigningKeyID)
+	} else {
+		log.Warningf("No JWT keys directory configured, signing tokens with an ephemeral key")
+		keySet, err = jwks.GenerateKeySet()
+	}
+	if err != nil {
+		log.Fatalf("Failed to load the JWT signing keys: %v", err)
+	}
+	middleware.UseSigningKeys(keySet)
 
 	rmq, err := rabbitMQ.NewConn(cfg)
 	if err != nil {
 		log.Fatalf("Failed to create RabbitMQ instance: %v", err)
 	}
 
-	userRepository := repository.NewUserRepository(ctx, *db, redisClient, rmq)
+	userRepository := repository.NewUserRepository(ctx, *db, redisClient, rmq, utils.Links{
+		ActivationURL:    cfg.ActivationURL,
+		PasswordResetURL: cfg.PasswordResetURL,
+	})
 	authRepository := repository.NewAuthRepository(ctx, *db)
-	authUser := handler.NewUserAuth(ctx, authRepository)
-	handlerUser := handler.NewUserHandler(ctx, userRepository)
+	tokenRepository := repository.NewTokenRepository(ctx, redisClient, cfg.RefreshTokenTTL)
+	throttleRepository := repository.NewThrottleRepository(ctx, redisClient)
+	authUser := handler.NewUserAuth(ctx, authRepository, tokenRepository, throttleRepository)
+	handlerUser := handler.NewUserHandler(ctx, userRepository, tokenRepository)
 
 	router := server.NewRouter(authUser, handlerUser)
 
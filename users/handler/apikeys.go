@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"errors"
+	"library/pkg/apikey"
+	"library/pkg/authz"
+	"library/pkg/utils"
+	"library/users/models"
+	"library/users/repository"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// CreateAPIKey creates an API key for scripts and CI.
+//
+//	@Summary		Create an API key
+//	@Description	Creates a named API key, sent as an Authorization: Bearer header instead of the token cookie. The scopes must be permissions of the user, books:read, books:write and reviews:write cover the user's own books, shelves and reviews, books:read also the search, the authors and the shop catalog. The key is only returned once, and cannot manage the account: the profile, two-factor, API keys and sessions need a login.
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			user_id	path		int						true	"User ID"
+//	@Param			request	body		models.APIKeyRequest	true	"Name, scopes and lifetime of the key"
+//	@Success		201
+//	@Failure		400
+//	@Failure		401
+//	@Failure		403
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/users/{user_id}/api-keys [post]
+func (u *UserAuth) CreateAPIKey(c *gin.Context) {
+	var request models.APIKeyRequest
+
+	log := utils.GetLogger(u.ctx)
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.New().Struct(request); err != nil {
+		log.Warningf("Validation error: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, scope := range request.Scopes {
+		if !authz.HasPermission(c.GetStringSlice("permissions"), scope) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "scope not granted: " + scope})
+			return
+		}
+	}
+
+	key, hint, err := apikey.Generate()
+	if err != nil {
+		log.Errorf("Failed to generate API key: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	apiKey := models.APIKey{
+		Name:      request.Name,
+		Hint:      hint,
+		Scopes:    request.Scopes,
+		ExpiresAt: time.Now().AddDate(0, 0, request.ExpiresInDays).UTC(),
+	}
+	if apiKey.Scopes == nil {
+		apiKey.Scopes = []string{}
+	}
+
+	if err = u.authRepository.CreateAPIKey(userID, &apiKey, apikey.Hash(key)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infof("API key %v created for user %v", apiKey.ID, userID)
+	c.JSON(http.StatusCreated, gin.H{"api_key": apiKey, "key": key})
+}
+
+// GetAPIKeys lists the API keys of the user.
+//
+//	@Summary		List API keys
+//	@Description	Lists the API keys of the user that were not revoked. The keys themselves are not returned, only their hint.
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			user_id	path		int	true	"User ID"
+//	@Success		200
+//	@Failure		401
+//	@Failure		403
+//	@Failure		500
+//	@Router			/v1/users/{user_id}/api-keys [get]
+func (u *UserAuth) GetAPIKeys(c *gin.Context) {
+	keys, err := u.authRepository.GetAPIKeys(c.GetInt("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"api_keys": keys})
+}
+
+// RevokeAPIKey revokes an API key of the user.
+//
+//	@Summary		Revoke an API key
+//	@Description	Revokes an API key of the user, it is refused from the next request on.
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			user_id	path		int	true	"User ID"
+//	@Param			key_id	path		int	true	"API key ID"
+//	@Success		200
+//	@Failure		401
+//	@Failure		403
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/users/{user_id}/api-keys/{key_id} [delete]
+func (u *UserAuth) RevokeAPIKey(c *gin.Context) {
+	log := utils.GetLogger(u.ctx)
+
+	keyID, err := strconv.Atoi(c.Param("key_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	err = u.authRepository.RevokeAPIKey(userID, keyID)
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infof("API key %v of user %v revoked", keyID, userID)
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
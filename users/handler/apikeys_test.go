@@ -0,0 +1,230 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"library/pkg/apikey"
+	"library/pkg/authz"
+	"library/pkg/logger"
+	"library/pkg/middleware"
+	"library/users/handler"
+	"library/users/models"
+	"library/users/repository"
+	"library/users/repository/repositoryfakes"
+	"library/users/server"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type staticAPIKeys struct {
+	claims *models.Claims
+}
+
+func (s staticAPIKeys) ResolveAPIKey(context.Context, string) (*models.Claims, error) {
+	return s.claims, nil
+}
+
+var _ = Describe("API Keys API Test", func() {
+	var (
+		w           *httptest.ResponseRecorder
+		fakeAuther  *repositoryfakes.FakeAutherRepository
+		router      *gin.Engine
+		permissions []string
+		bearer      string
+	)
+
+	useKey := func(permissions ...string) {
+		middleware.UseAPIKeys(staticAPIKeys{claims: &models.Claims{
+			UserID:         "1",
+			Permissions:    permissions,
+			APIKeyID:       3,
+			StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Hour).Unix()},
+		}})
+		bearer = "lib_key"
+	}
+
+	send := func(method, path string, body interface{}) {
+		payload, err := json.Marshal(body)
+		Expect(err).To(BeNil())
+
+		req, err := http.NewRequest(method, path, bytes.NewBuffer(payload))
+		Expect(err).To(BeNil())
+		req.Header.Set("Content-Type", "application/json")
+
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		} else {
+			token, err := middleware.GenerateJWT(models.User{ID: 1, Email: "tmostowashere@tmostowashere.com", Role: "user", Permissions: permissions})
+			Expect(err).To(BeNil())
+			req.AddCookie(&http.Cookie{Name: "token", Value: token})
+		}
+
+		router.ServeHTTP(w, req)
+	}
+
+	BeforeEach(func() {
+		w = httptest.NewRecorder()
+		permissions = []string{authz.TransactionsRead}
+		bearer = ""
+
+		ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+		fakeAuther = &repositoryfakes.FakeAutherRepository{}
+		fakeTokener := &repositoryfakes.FakeTokenerRepository{}
+
+		router = server.NewRouter(
+			handler.NewUserAuth(ctx, fakeAuther, fakeTokener, &repositoryfakes.FakeThrottlerRepository{}),
+			handler.NewUserHandler(ctx, &repositoryfakes.FakeUsererRepository{}, fakeTokener),
+		)
+	})
+
+	AfterEach(func() {
+		middleware.UseAPIKeys(nil)
+	})
+
+	Describe("CreateAPIKey", func() {
+		It("should return the key once and store its hash", func() {
+			send("POST", "/v1/users/1/api-keys", models.APIKeyRequest{Name: "ci", Scopes: []string{authz.TransactionsRead}, ExpiresInDays: 30})
+
+			Expect(w.Code).To(Equal(http.StatusCreated))
+
+			var response struct {
+				APIKey models.APIKey `json:"api_key"`
+				Key    string        `json:"key"`
+			}
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(apikey.IsKey(response.Key)).To(BeTrue())
+			Expect(response.Key).To(HavePrefix(response.APIKey.Hint))
+
+			userID, stored, keyHash := fakeAuther.CreateAPIKeyArgsForCall(0)
+			Expect(userID).To(Equal(1))
+			Expect(keyHash).To(Equal(apikey.Hash(response.Key)))
+			Expect(stored.Scopes).To(Equal([]string{authz.TransactionsRead}))
+			Expect(stored.ExpiresAt).To(BeTemporally("~", time.Now().AddDate(0, 0, 30), time.Minute))
+		})
+
+		It("should refuse a scope the user is not granted", func() {
+			send("POST", "/v1/users/1/api-keys", models.APIKeyRequest{Name: "ci", Scopes: []string{authz.UsersDelete}, ExpiresInDays: 30})
+
+			Expect(w.Code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(fakeAuther.CreateAPIKeyCallCount()).To(Equal(0))
+		})
+
+		It("should require an expiry", func() {
+			send("POST", "/v1/users/1/api-keys", models.APIKeyRequest{Name: "ci"})
+
+			Expect(w.Code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(fakeAuther.CreateAPIKeyCallCount()).To(Equal(0))
+		})
+
+		It("should only create keys for the user of the session", func() {
+			send("POST", "/v1/users/2/api-keys", models.APIKeyRequest{Name: "ci", ExpiresInDays: 30})
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeAuther.CreateAPIKeyCallCount()).To(Equal(0))
+		})
+
+		It("should not let an API key create keys", func() {
+			useKey(authz.TransactionsRead)
+
+			send("POST", "/v1/users/1/api-keys", models.APIKeyRequest{Name: "ci", ExpiresInDays: 30})
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeAuther.CreateAPIKeyCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("GetAPIKeys", func() {
+		It("should list the keys of the user", func() {
+			fakeAuther.GetAPIKeysReturns([]models.APIKey{{ID: 2, Name: "ci", Hint: "lib_0123abcd"}}, nil)
+
+			send("GET", "/v1/users/1/api-keys", nil)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(fakeAuther.GetAPIKeysArgsForCall(0)).To(Equal(1))
+			Expect(w.Body.String()).To(ContainSubstring(`"hint":"lib_0123abcd"`))
+		})
+
+		It("should not let an API key list the keys", func() {
+			useKey(authz.TransactionsRead)
+
+			send("GET", "/v1/users/1/api-keys", nil)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeAuther.GetAPIKeysCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("RevokeAPIKey", func() {
+		It("should revoke a key of the user", func() {
+			send("DELETE", "/v1/users/1/api-keys/2", nil)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			userID, keyID := fakeAuther.RevokeAPIKeyArgsForCall(0)
+			Expect(userID).To(Equal(1))
+			Expect(keyID).To(Equal(2))
+		})
+
+		It("should return 404 for an unknown key", func() {
+			fakeAuther.RevokeAPIKeyReturns(repository.ErrAPIKeyNotFound)
+
+			send("DELETE", "/v1/users/1/api-keys/2", nil)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should not let an API key revoke keys", func() {
+			useKey(authz.TransactionsRead)
+
+			send("DELETE", "/v1/users/1/api-keys/2", nil)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeAuther.RevokeAPIKeyCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("Scopes", func() {
+		It("should accept an API key as bearer on a route of its scope", func() {
+			useKey(authz.UsersRead)
+
+			send("GET", "/v1/users", nil)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should refuse a scopeless API key on an owner route", func() {
+			useKey()
+
+			send("DELETE", "/v1/users/1/1", nil)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should refuse API keys on the account routes whatever their scopes", func() {
+			useKey(authz.UsersRead, authz.UsersWriteAny)
+
+			for _, route := range []struct{ method, path string }{
+				{"GET", "/v1/users/1"},
+				{"PUT", "/v1/users/1"},
+				{"POST", "/v1/users/1/mfa"},
+				{"POST", "/v1/users/1/mfa/confirm"},
+				{"GET", "/v1/users/1/sessions"},
+				{"DELETE", "/v1/users/1/sessions/family-1"},
+				{"DELETE", "/v1/users/1/sessions"},
+			} {
+				w = httptest.NewRecorder()
+				send(route.method, route.path, models.User{Email: "new@elo.com"})
+
+				Expect(w.Code).To(Equal(http.StatusForbidden), route.method+" "+route.path)
+			}
+		})
+	})
+})
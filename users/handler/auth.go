@@ -8,39 +8,68 @@ import (
 	"library/pkg/utils"
 	"library/users/models"
 	"library/users/repository"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+// refreshCookiePath limits the refresh token cookie to the users API, where it is
+// rotated and revoked.
+const refreshCookiePath = "/v1/users"
+
+// unknownUserHash is checked against the password of logins with an unknown email,
+// so they take as long as the logins with a wrong password.
+var unknownUserHash, _ = pkg.GenerateHashPassword("unknown user")
+
+var errInvalidCredentials = errors.New("invalid credentials")
+
 type UserAuther interface {
 	Login(c *gin.Context)
 	Logout(c *gin.Context)
+	RefreshToken(c *gin.Context)
+	UnlockAccount(c *gin.Context)
+	EnrollMFA(c *gin.Context)
+	ConfirmMFA(c *gin.Context)
+	LoginMFA(c *gin.Context)
+	CreateAPIKey(c *gin.Context)
+	GetAPIKeys(c *gin.Context)
+	RevokeAPIKey(c *gin.Context)
+	GetSessions(c *gin.Context)
+	RevokeSession(c *gin.Context)
+	RevokeAllSessions(c *gin.Context)
 }
 
 type UserAuth struct {
-	ctx            context.Context
-	authRepository repository.AutherRepository
+	ctx                context.Context
+	authRepository     repository.AutherRepository
+	tokenRepository    repository.TokenerRepository
+	throttleRepository repository.ThrottlerRepository
 }
 
-func NewUserAuth(ctx context.Context, authRepository repository.AutherRepository) UserAuther {
+func NewUserAuth(ctx context.Context, authRepository repository.AutherRepository, tokenRepository repository.TokenerRepository, throttleRepository repository.ThrottlerRepository) UserAuther {
 	return &UserAuth{
-		ctx:            ctx,
-		authRepository: authRepository,
+		ctx:                ctx,
+		authRepository:     authRepository,
+		tokenRepository:    tokenRepository,
+		throttleRepository: throttleRepository,
 	}
 }
 
-// Login authenticates a user and generates a JWT token.
+// Login authenticates a user and starts a session.
 //
 //	@Summary		Authenticate user
-//	@Description	Authenticate user and generate JWT token
+//	@Description	Authenticate user, set a short-lived JWT access token and a refresh token as cookies. With two-factor authentication enabled no cookie is set yet: the response carries an mfa_token, valid for 5 minutes, to send with a code to /v1/users/login/mfa. Failed logins are answered with an exponential backoff, 5 failures lock the account and 20 failures lock the client address for 15 minutes.
 //	@Accept			json
 //	@Produce		json
 //	@Param			user	body		models.Authentication							true	"User credentials"
 //	@Success		200
 //	@Failure		400
 //	@Failure		401
+//	@Failure		429
 //	@Failure		500
 //	@Router			/v1/users/login [post]
 func (u *UserAuth) Login(c *gin.Context) {
@@ -56,41 +85,235 @@ func (u *UserAuth) Login(c *gin.Context) {
 		return
 	}
 
-	err = u.authRepository.Login(&user, &auth)
+	wait, err := u.throttleRepository.LoginWait(auth.Email, c.ClientIP())
 	if err != nil {
+		log.Errorf("Login throttle error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "login is unavailable"})
+		return
+	}
+
+	if wait > 0 {
+		refuseLogin(c, wait)
+		return
+	}
+
+	err = u.authRepository.Login(&user, &auth)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		user.Password = unknownUserHash
+	} else if err != nil {
 		log.Errorf("Error repository login: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "login is unavailable"})
 		return
 	}
 
-	check := pkg.CheckPasswordHash(user.Password, auth.Password)
-	if !check {
-		log.Errorf("invalid credentials")
-		err = errors.New("invalid credentials")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	if !pkg.CheckPasswordHash(user.Password, auth.Password) || err != nil {
+		log.Warningf("invalid credentials")
+		if _, err = u.throttleRepository.RecordLoginFailure(auth.Email, c.ClientIP()); err != nil {
+			log.Errorf("Login throttle error: %v", err)
+		}
+
+		c.JSON(http.StatusUnauthorized, gin.H{"error": errInvalidCredentials.Error()})
 		return
 	}
 
-	token, err := middleware.GenerateJWT(user)
+	mfa, err := u.authRepository.GetMFA(user.ID)
 	if err != nil {
+		log.Errorf("Get MFA repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "login is unavailable"})
+		return
+	}
+
+	// The failed logins are only forgotten once the second factor is checked too.
+	if mfa.Enabled {
+		mfaToken, err := u.tokenRepository.CreateMFAToken(user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "login is unavailable"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"mfa_required": true, "mfa_token": mfaToken})
+		return
+	}
+
+	if err = u.throttleRepository.ResetLoginFailures(auth.Email); err != nil {
+		log.Errorf("Login throttle error: %v", err)
+	}
+
+	if err = u.startSession(c, &user, ""); err != nil {
 		log.Errorf("token generate error: %v", err)
 		err = errors.New("token generate error")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.SetCookie("token", token, int(time.Now().Add(time.Hour*24).Unix()), "/", "localhost", false, true)
 	c.JSON(http.StatusOK, gin.H{"message": "User logged successfully"})
 }
 
-// Logout revokes the user's JWT token.
+// RefreshToken rotates the refresh token and issues a new access token.
+//
+//	@Summary		Refresh the access token
+//	@Description	Spends the refresh token cookie and sets a new access token and refresh token. Reusing a spent refresh token revokes the whole session.
+//	@Produce		json
+//	@Success		200
+//	@Failure		401
+//	@Failure		500
+//	@Router			/v1/users/token/refresh [post]
+func (u *UserAuth) RefreshToken(c *gin.Context) {
+	log := utils.GetLogger(u.ctx)
+
+	refreshToken, err := c.Cookie("refresh_token")
+	if err != nil || refreshToken == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	session, err := u.tokenRepository.UseRefreshToken(refreshToken)
+	if errors.Is(err, repository.ErrInvalidRefreshToken) || errors.Is(err, repository.ErrRefreshTokenReused) {
+		log.Warningf("Refresh token rejected: %v", err)
+		clearSessionCookies(c)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("Refresh token repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := u.authRepository.GetUser(session.UserID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		clearSessionCookies(c)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("Get user repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err = u.startSession(c, user, session.Family); err != nil {
+		log.Errorf("token generate error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "token generate error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Token refreshed successfully"})
+}
+
+// Logout revokes the user's session.
 //
 //	@Summary		Logout user
-//	@Description	Revoke user's JWT token
+//	@Description	Revoke the user's access token and refresh token family
 //	@Produce		json
 //	@Success		200
+//	@Failure		500
 //	@Router			/v1/users/logout [post]
 func (u *UserAuth) Logout(c *gin.Context) {
+	log := utils.GetLogger(u.ctx)
+
+	clearSessionCookies(c)
+
+	if token, err := c.Cookie("token"); err == nil && token != "" {
+		// An expired access token does not verify, and needs no revoking.
+		if claims, err := middleware.VerifyJWT(token); err == nil {
+			if err = u.tokenRepository.RevokeAccessToken(claims); err != nil {
+				log.Errorf("Revoke access token error: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+	}
+
+	if refreshToken, err := c.Cookie("refresh_token"); err == nil && refreshToken != "" {
+		err = u.tokenRepository.RevokeRefreshToken(refreshToken)
+		if err != nil && !errors.Is(err, repository.ErrInvalidRefreshToken) {
+			log.Errorf("Revoke refresh token error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": "user logged out"})
+}
+
+// UnlockAccount lifts the login lockout of an account.
+//
+//	@Summary		Unlock an account
+//	@Description	Forgets the failed logins of the user and lifts the lockout of the account. Lockouts of client addresses expire on their own. Requires the users:unlock permission.
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			user_id	path		int	true	"User ID"
+//	@Success		200
+//	@Failure		401
+//	@Failure		403
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/users/{user_id}/lockout [delete]
+func (u *UserAuth) UnlockAccount(c *gin.Context) {
+	log := utils.GetLogger(u.ctx)
+
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := u.authRepository.GetUser(userID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("Get user repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err = u.throttleRepository.ResetLoginFailures(user.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infof("Account of user %v unlocked", userID)
+	c.JSON(http.StatusOK, gin.H{"message": "Account unlocked"})
+}
+
+// startSession sets a new access token and a refresh token of the family, an empty
+// family starts a new session.
+func (u *UserAuth) startSession(c *gin.Context, user *models.User, family string) error {
+	if family == "" {
+		family = uuid.NewString()
+	}
+
+	token, claims, err := middleware.GenerateAccessToken(*user, family)
+	if err != nil {
+		return err
+	}
+
+	client := models.SessionClient{UserAgent: c.Request.UserAgent(), IP: c.ClientIP()}
+
+	refreshToken, err := u.tokenRepository.CreateRefreshToken(user.ID, family, claims, client)
+	if err != nil {
+		return err
+	}
+
+	c.SetCookie("token", token, int(middleware.AccessTokenTTL.Seconds()), "/", "localhost", false, true)
+	c.SetCookie("refresh_token", refreshToken, 0, refreshCookiePath, "localhost", false, true)
+
+	return nil
+}
+
+// refuseLogin answers a login attempted before the backoff or lockout is over.
+func refuseLogin(c *gin.Context, wait time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": repository.ErrLoginLocked.Error()})
+}
+
+func clearSessionCookies(c *gin.Context) {
 	c.SetCookie("token", "", -1, "/", "localhost", false, true)
-	c.JSON(200, gin.H{"success": "user logged out"})
+	c.SetCookie("refresh_token", "", -1, refreshCookiePath, "localhost", false, true)
 }
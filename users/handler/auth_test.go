@@ -0,0 +1,372 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"library/pkg"
+	"library/pkg/authz"
+	"library/pkg/jwks"
+	"library/pkg/logger"
+	"library/pkg/middleware"
+	"library/users/handler"
+	"library/users/models"
+	"library/users/repository"
+	"library/users/repository/repositoryfakes"
+	"library/users/server"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Auth API Test", func() {
+	var (
+		w             *httptest.ResponseRecorder
+		fakeAuther    *repositoryfakes.FakeAutherRepository
+		fakeTokener   *repositoryfakes.FakeTokenerRepository
+		fakeThrottler *repositoryfakes.FakeThrottlerRepository
+		fakeUserer    *repositoryfakes.FakeUsererRepository
+		router        *gin.Engine
+		user          models.User
+	)
+
+	cookieNamed := func(name string) *http.Cookie {
+		for _, cookie := range w.Result().Cookies() {
+			if cookie.Name == name {
+				return cookie
+			}
+		}
+
+		return nil
+	}
+
+	send := func(method, path string, body []byte, cookies ...*http.Cookie) {
+		req, err := http.NewRequest(method, path, bytes.NewBuffer(body))
+		Expect(err).To(BeNil())
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "library-test")
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+
+		router.ServeHTTP(w, req)
+	}
+
+	BeforeEach(func() {
+		w = httptest.NewRecorder()
+
+		ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+		fakeAuther = &repositoryfakes.FakeAutherRepository{}
+		fakeTokener = &repositoryfakes.FakeTokenerRepository{}
+		fakeUserer = &repositoryfakes.FakeUsererRepository{}
+		fakeThrottler = &repositoryfakes.FakeThrottlerRepository{}
+		fakeAuther.GetMFAReturns(&models.MFA{}, nil)
+
+		router = server.NewRouter(
+			handler.NewUserAuth(ctx, fakeAuther, fakeTokener, fakeThrottler),
+			handler.NewUserHandler(ctx, fakeUserer, fakeTokener),
+		)
+
+		user = models.User{ID: 1, Email: "tmostowashere@tmostowashere.com", Role: "user"}
+	})
+
+	Describe("Login", func() {
+		It("should set an access token and a refresh token", func() {
+			hash, err := pkg.GenerateHashPassword("secret")
+			Expect(err).To(BeNil())
+
+			fakeAuther.LoginCalls(func(found *models.User, _ *models.Authentication) error {
+				*found = user
+				found.Password = hash
+				return nil
+			})
+			fakeTokener.CreateRefreshTokenReturns("refresh-1", nil)
+
+			body, _ := json.Marshal(models.Authentication{Email: user.Email, Password: "secret"})
+			send("POST", "/v1/users/login", body)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			userID, family, access, client := fakeTokener.CreateRefreshTokenArgsForCall(0)
+			Expect(userID).To(Equal(1))
+			Expect(family).NotTo(BeEmpty())
+			Expect(access.Id).NotTo(BeEmpty())
+			Expect(access.SessionID).To(Equal(family))
+			Expect(client.UserAgent).To(Equal("library-test"))
+
+			Expect(cookieNamed("token").MaxAge).To(Equal(int(middleware.AccessTokenTTL.Seconds())))
+			Expect(cookieNamed("refresh_token").Value).To(Equal("refresh-1"))
+			Expect(cookieNamed("refresh_token").Path).To(Equal("/v1/users"))
+			Expect(fakeThrottler.ResetLoginFailuresArgsForCall(0)).To(Equal(user.Email))
+		})
+
+		It("should count a wrong password", func() {
+			hash, err := pkg.GenerateHashPassword("secret")
+			Expect(err).To(BeNil())
+
+			fakeAuther.LoginCalls(func(found *models.User, _ *models.Authentication) error {
+				*found = user
+				found.Password = hash
+				return nil
+			})
+
+			body, _ := json.Marshal(models.Authentication{Email: user.Email, Password: "guess"})
+			send("POST", "/v1/users/login", body)
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(w.Body.String()).To(ContainSubstring("invalid credentials"))
+			email, _ := fakeThrottler.RecordLoginFailureArgsForCall(0)
+			Expect(email).To(Equal(user.Email))
+			Expect(fakeTokener.CreateRefreshTokenCallCount()).To(Equal(0))
+		})
+
+		It("should answer an unknown email like a wrong password", func() {
+			fakeAuther.LoginReturns(repository.ErrUserNotFound)
+
+			body, _ := json.Marshal(models.Authentication{Email: "unknown@example.com", Password: "guess"})
+			send("POST", "/v1/users/login", body)
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(w.Body.String()).To(ContainSubstring("invalid credentials"))
+			Expect(fakeThrottler.RecordLoginFailureCallCount()).To(Equal(1))
+		})
+
+		It("should refuse logins during the backoff", func() {
+			fakeThrottler.LoginWaitReturns(1500*time.Millisecond, nil)
+
+			body, _ := json.Marshal(models.Authentication{Email: user.Email, Password: "secret"})
+			send("POST", "/v1/users/login", body)
+
+			Expect(w.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(w.Header().Get("Retry-After")).To(Equal("2"))
+			Expect(fakeAuther.LoginCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("UnlockAccount", func() {
+		It("should require the users:unlock permission", func() {
+			token, err := middleware.GenerateJWT(user)
+			Expect(err).To(BeNil())
+
+			send("DELETE", "/v1/users/2/lockout", nil, &http.Cookie{Name: "token", Value: token})
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeThrottler.ResetLoginFailuresCallCount()).To(Equal(0))
+		})
+
+		It("should reset the failed logins of the user", func() {
+			admin := user
+			admin.Permissions = []string{authz.UsersUnlock}
+			token, err := middleware.GenerateJWT(admin)
+			Expect(err).To(BeNil())
+
+			fakeAuther.GetUserReturns(&models.User{ID: 2, Email: "locked@example.com"}, nil)
+
+			send("DELETE", "/v1/users/2/lockout", nil, &http.Cookie{Name: "token", Value: token})
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(fakeAuther.GetUserArgsForCall(0)).To(Equal(2))
+			Expect(fakeThrottler.ResetLoginFailuresArgsForCall(0)).To(Equal("locked@example.com"))
+		})
+	})
+
+	Describe("RefreshToken", func() {
+		It("should rotate the refresh token within its family", func() {
+			fakeTokener.UseRefreshTokenReturns(&models.RefreshSession{UserID: 1, Family: "family-1"}, nil)
+			fakeAuther.GetUserReturns(&user, nil)
+			fakeTokener.CreateRefreshTokenReturns("refresh-2", nil)
+
+			send("POST", "/v1/users/token/refresh", nil, &http.Cookie{Name: "refresh_token", Value: "refresh-1"})
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(fakeTokener.UseRefreshTokenArgsForCall(0)).To(Equal("refresh-1"))
+			Expect(fakeAuther.GetUserArgsForCall(0)).To(Equal(1))
+
+			_, family, access, _ := fakeTokener.CreateRefreshTokenArgsForCall(0)
+			Expect(family).To(Equal("family-1"))
+			Expect(access.SessionID).To(Equal("family-1"))
+			Expect(cookieNamed("refresh_token").Value).To(Equal("refresh-2"))
+
+			claims, err := middleware.VerifyJWT(cookieNamed("token").Value)
+			Expect(err).To(BeNil())
+			Expect(claims.Email).To(Equal(user.Email))
+		})
+
+		It("should clear the session when a spent token is reused", func() {
+			fakeTokener.UseRefreshTokenReturns(nil, repository.ErrRefreshTokenReused)
+
+			send("POST", "/v1/users/token/refresh", nil, &http.Cookie{Name: "refresh_token", Value: "refresh-1"})
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(cookieNamed("refresh_token").MaxAge).To(BeNumerically("<", 0))
+			Expect(fakeTokener.CreateRefreshTokenCallCount()).To(Equal(0))
+		})
+
+		It("should refuse a request without a refresh token", func() {
+			send("POST", "/v1/users/token/refresh", nil)
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(fakeTokener.UseRefreshTokenCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("JWKS", func() {
+		It("should publish the key that signs the access tokens", func() {
+			token, err := middleware.GenerateJWT(user)
+			Expect(err).To(BeNil())
+
+			send("GET", "/v1/users/.well-known/jwks.json", nil)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var set jwks.Set
+			Expect(json.Unmarshal(w.Body.Bytes(), &set)).To(Succeed())
+			Expect(set.Keys).To(HaveLen(1))
+			Expect(set.Keys[0].Algorithm).To(Equal("RS256"))
+
+			parsed, _ := jwt.Parse(token, nil)
+			Expect(parsed.Header["kid"]).To(Equal(set.Keys[0].KeyID))
+		})
+	})
+
+	Describe("Logout", func() {
+		It("should revoke the access token and the refresh token family", func() {
+			token, claims, err := middleware.GenerateAccessToken(user, "family-1")
+			Expect(err).To(BeNil())
+
+			send("POST", "/v1/users/logout", nil,
+				&http.Cookie{Name: "token", Value: token},
+				&http.Cookie{Name: "refresh_token", Value: "refresh-1"},
+			)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(fakeTokener.RevokeAccessTokenArgsForCall(0).Id).To(Equal(claims.Id))
+			Expect(fakeTokener.RevokeRefreshTokenArgsForCall(0)).To(Equal("refresh-1"))
+			Expect(cookieNamed("token").MaxAge).To(BeNumerically("<", 0))
+		})
+	})
+
+	Describe("ForgotPassword", func() {
+		It("should accept the request", func() {
+			body, _ := json.Marshal(models.ForgotPasswordRequest{Email: user.Email})
+			send("POST", "/v1/users/password/forgot", body)
+
+			Expect(w.Code).To(Equal(http.StatusAccepted))
+			Expect(fakeUserer.RequestPasswordResetArgsForCall(0)).To(Equal(user.Email))
+		})
+
+		It("should refuse too many requests for the email", func() {
+			fakeUserer.RequestPasswordResetReturns(repository.ErrTooManyResetRequests)
+
+			body, _ := json.Marshal(models.ForgotPasswordRequest{Email: user.Email})
+			send("POST", "/v1/users/password/forgot", body)
+
+			Expect(w.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(w.Header().Get("Retry-After")).To(Equal("3600"))
+		})
+
+		It("should reject an invalid email", func() {
+			body, _ := json.Marshal(models.ForgotPasswordRequest{Email: "not an email"})
+			send("POST", "/v1/users/password/forgot", body)
+
+			Expect(w.Code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(fakeUserer.RequestPasswordResetCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("ResetPassword", func() {
+		It("should store the new password hash and revoke the sessions", func() {
+			body, _ := json.Marshal(models.ResetPasswordRequest{UserID: 1, Token: "reset-1", Password: "new password 1"})
+			send("POST", "/v1/users/password/reset", body)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			userID, token, hash := fakeUserer.ResetPasswordArgsForCall(0)
+			Expect(userID).To(Equal(1))
+			Expect(token).To(Equal("reset-1"))
+			Expect(pkg.CheckPasswordHash(hash, "new password 1")).To(BeTrue())
+			Expect(fakeTokener.RevokeUserSessionsArgsForCall(0)).To(Equal(1))
+		})
+
+		It("should enforce the password policy", func() {
+			body, _ := json.Marshal(models.ResetPasswordRequest{UserID: 1, Token: "reset-1", Password: "short"})
+			send("POST", "/v1/users/password/reset", body)
+
+			Expect(w.Code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(fakeUserer.ResetPasswordCallCount()).To(Equal(0))
+		})
+
+		It("should reject an invalid token and keep the sessions", func() {
+			fakeUserer.ResetPasswordReturns(repository.ErrInvalidResetToken)
+
+			body, _ := json.Marshal(models.ResetPasswordRequest{UserID: 1, Token: "reset-1", Password: "new password 1"})
+			send("POST", "/v1/users/password/reset", body)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			Expect(fakeTokener.RevokeUserSessionsCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("ActivateAccount", func() {
+		It("should activate the account with its token", func() {
+			fakeUserer.ActivateUserReturns(1, nil)
+
+			send("GET", "/v1/users/activate?userID=1&token=activate-1", nil)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			userID, token := fakeUserer.ActivateUserArgsForCall(0)
+			Expect(userID).To(Equal(1))
+			Expect(token).To(Equal("activate-1"))
+		})
+
+		It("should require the token", func() {
+			send("GET", "/v1/users/activate?userID=1", nil)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			Expect(fakeUserer.ActivateUserCallCount()).To(Equal(0))
+		})
+
+		It("should reject an invalid token", func() {
+			fakeUserer.ActivateUserReturns(0, repository.ErrInvalidActivationToken)
+
+			send("GET", "/v1/users/activate?userID=1&token=guess", nil)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should tell an expired token apart", func() {
+			fakeUserer.ActivateUserReturns(0, repository.ErrActivationTokenExpired)
+
+			send("GET", "/v1/users/activate?userID=1&token=activate-1", nil)
+
+			Expect(w.Code).To(Equal(http.StatusGone))
+		})
+	})
+
+	Describe("ResendActivation", func() {
+		It("should accept the request", func() {
+			body, _ := json.Marshal(models.ResendActivationRequest{Email: user.Email})
+			send("POST", "/v1/users/activate/resend", body)
+
+			Expect(w.Code).To(Equal(http.StatusAccepted))
+			Expect(fakeUserer.ResendActivationArgsForCall(0)).To(Equal(user.Email))
+		})
+
+		It("should limit the requests", func() {
+			fakeUserer.ResendActivationReturns(repository.ErrTooManyResends)
+
+			body, _ := json.Marshal(models.ResendActivationRequest{Email: user.Email})
+			send("POST", "/v1/users/activate/resend", body)
+
+			Expect(w.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(w.Header().Get("Retry-After")).To(Equal("3600"))
+		})
+	})
+})
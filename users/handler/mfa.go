@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"library/pkg/totp"
+	"library/pkg/utils"
+	"library/users/models"
+	"library/users/repository"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	mfaIssuer         = "Library"
+	recoveryCodeCount = 10
+)
+
+var (
+	errInvalidMFACode = errors.New("invalid two-factor code")
+	errMFANotEnrolled = errors.New("two-factor authentication enrollment was not started")
+)
+
+// EnrollMFA starts the two-factor authentication enrollment of the user.
+//
+//	@Summary		Enroll in two-factor authentication
+//	@Description	Generates a TOTP secret and its otpauth:// URI for an authenticator app. The enrollment is only enabled once confirmed with a first code.
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			user_id	path		int	true	"User ID"
+//	@Success		200
+//	@Failure		401
+//	@Failure		403
+//	@Failure		409
+//	@Failure		500
+//	@Router			/v1/users/{user_id}/mfa [post]
+func (u *UserAuth) EnrollMFA(c *gin.Context) {
+	log := utils.GetLogger(u.ctx)
+
+	userID := c.GetInt("userID")
+
+	user, err := u.authRepository.GetUser(userID)
+	if err != nil {
+		log.Errorf("Get user repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		log.Errorf("Failed to generate MFA secret: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = u.authRepository.SetMFASecret(userID, secret)
+	if errors.Is(err, repository.ErrMFAEnabled) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"enrollment": models.MFAEnrollment{
+		Secret: secret,
+		URI:    totp.URI(mfaIssuer, user.Email, secret),
+	}})
+}
+
+// ConfirmMFA enables two-factor authentication with the first code of the app.
+//
+//	@Summary		Confirm two-factor authentication
+//	@Description	Enables two-factor authentication with a first code of the authenticator app, and returns 10 one-time recovery codes. The recovery codes are only shown once.
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			user_id	path		int						true	"User ID"
+//	@Param			request	body		models.MFACodeRequest	true	"Code of the authenticator app"
+//	@Success		200
+//	@Failure		400
+//	@Failure		401
+//	@Failure		403
+//	@Failure		409
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/users/{user_id}/mfa/confirm [post]
+func (u *UserAuth) ConfirmMFA(c *gin.Context) {
+	var request models.MFACodeRequest
+
+	log := utils.GetLogger(u.ctx)
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.New().Struct(request); err != nil {
+		log.Warningf("Validation error: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+
+	mfa, err := u.authRepository.GetMFA(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if mfa.Enabled {
+		c.JSON(http.StatusConflict, gin.H{"error": repository.ErrMFAEnabled.Error()})
+		return
+	}
+
+	if mfa.Secret == "" {
+		c.JSON(http.StatusConflict, gin.H{"error": errMFANotEnrolled.Error()})
+		return
+	}
+
+	step, ok := totp.Validate(mfa.Secret, request.Code, time.Now())
+	if !ok {
+		log.Warningf("Invalid MFA confirmation code of user %v", userID)
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidMFACode.Error()})
+		return
+	}
+
+	recoveryCodes, err := newRecoveryCodes()
+	if err != nil {
+		log.Errorf("Failed to generate recovery codes: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = u.authRepository.EnableMFA(userID, step, recoveryCodes)
+	if errors.Is(err, repository.ErrMFAEnabled) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infof("MFA enabled for user %v", userID)
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": recoveryCodes})
+}
+
+// LoginMFA completes a login with its second factor.
+//
+//	@Summary		Complete a two-factor login
+//	@Description	Exchanges the mfa_token of a password login and a code of the authenticator app, or a recovery code, for the session cookies. 5 codes can be tried per password login.
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.MFALoginRequest	true	"MFA token and code"
+//	@Success		200
+//	@Failure		400
+//	@Failure		401
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/users/login/mfa [post]
+func (u *UserAuth) LoginMFA(c *gin.Context) {
+	var request models.MFALoginRequest
+
+	log := utils.GetLogger(u.ctx)
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.New().Struct(request); err != nil {
+		log.Warningf("Validation error: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, err := u.tokenRepository.UseMFAToken(request.MFAToken)
+	if errors.Is(err, repository.ErrInvalidMFAToken) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("MFA token repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "login is unavailable"})
+		return
+	}
+
+	user, err := u.authRepository.GetUser(userID)
+	if err != nil {
+		log.Errorf("Get user repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "login is unavailable"})
+		return
+	}
+
+	accepted, err := u.checkSecondFactor(userID, &request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "login is unavailable"})
+		return
+	}
+
+	if !accepted {
+		log.Warningf("Invalid MFA code of user %v", userID)
+		if _, err = u.throttleRepository.RecordLoginFailure(user.Email, c.ClientIP()); err != nil {
+			log.Errorf("Login throttle error: %v", err)
+		}
+
+		c.JSON(http.StatusUnauthorized, gin.H{"error": errInvalidMFACode.Error()})
+		return
+	}
+
+	if err = u.tokenRepository.RevokeMFAToken(request.MFAToken); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "login is unavailable"})
+		return
+	}
+
+	if err = u.throttleRepository.ResetLoginFailures(user.Email); err != nil {
+		log.Errorf("Login throttle error: %v", err)
+	}
+
+	if err = u.startSession(c, user, ""); err != nil {
+		log.Errorf("token generate error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "token generate error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User logged successfully"})
+}
+
+// checkSecondFactor spends the code of the authenticator app or the recovery code.
+func (u *UserAuth) checkSecondFactor(userID int, request *models.MFALoginRequest) (bool, error) {
+	if request.RecoveryCode != "" {
+		return u.authRepository.UseRecoveryCode(userID, normalizeRecoveryCode(request.RecoveryCode))
+	}
+
+	mfa, err := u.authRepository.GetMFA(userID)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := totp.Validate(mfa.Secret, request.Code, time.Now())
+	if !mfa.Enabled || !ok {
+		return false, nil
+	}
+
+	return u.authRepository.UseTOTPStep(userID, step)
+}
+
+// newRecoveryCodes returns random codes formatted as xxxxx-xxxxx, 50 bits each.
+func newRecoveryCodes() ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		random := make([]byte, 10)
+		if _, err := rand.Read(random); err != nil {
+			return nil, err
+		}
+
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(random))[:10]
+		codes[i] = code[:5] + "-" + code[5:]
+	}
+
+	return codes, nil
+}
+
+// normalizeRecoveryCode accepts the codes typed in upper case or without the dash.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(code) != 10 {
+		return code
+	}
+
+	return code[:5] + "-" + code[5:]
+}
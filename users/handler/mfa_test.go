@@ -0,0 +1,245 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"library/pkg"
+	"library/pkg/logger"
+	"library/pkg/middleware"
+	"library/pkg/totp"
+	"library/users/handler"
+	"library/users/models"
+	"library/users/repository"
+	"library/users/repository/repositoryfakes"
+	"library/users/server"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MFA API Test", func() {
+	var (
+		w             *httptest.ResponseRecorder
+		fakeAuther    *repositoryfakes.FakeAutherRepository
+		fakeTokener   *repositoryfakes.FakeTokenerRepository
+		fakeThrottler *repositoryfakes.FakeThrottlerRepository
+		router        *gin.Engine
+		user          models.User
+		secret        string
+	)
+
+	send := func(method, path string, body interface{}, cookies ...*http.Cookie) {
+		payload, err := json.Marshal(body)
+		Expect(err).To(BeNil())
+
+		req, err := http.NewRequest(method, path, bytes.NewBuffer(payload))
+		Expect(err).To(BeNil())
+		req.Header.Set("Content-Type", "application/json")
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+
+		router.ServeHTTP(w, req)
+	}
+
+	session := func() *http.Cookie {
+		token, err := middleware.GenerateJWT(user)
+		Expect(err).To(BeNil())
+
+		return &http.Cookie{Name: "token", Value: token}
+	}
+
+	currentCode := func() string {
+		code, err := totp.Code(secret, totp.Step(time.Now()))
+		Expect(err).To(BeNil())
+
+		return code
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		w = httptest.NewRecorder()
+
+		ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+		fakeAuther = &repositoryfakes.FakeAutherRepository{}
+		fakeTokener = &repositoryfakes.FakeTokenerRepository{}
+		fakeThrottler = &repositoryfakes.FakeThrottlerRepository{}
+
+		router = server.NewRouter(
+			handler.NewUserAuth(ctx, fakeAuther, fakeTokener, fakeThrottler),
+			handler.NewUserHandler(ctx, &repositoryfakes.FakeUsererRepository{}, fakeTokener),
+		)
+
+		user = models.User{ID: 1, Email: "tmostowashere@tmostowashere.com", Role: "user"}
+		fakeAuther.GetUserReturns(&user, nil)
+
+		secret, err = totp.GenerateSecret()
+		Expect(err).To(BeNil())
+	})
+
+	Describe("EnrollMFA", func() {
+		It("should return a new secret and its otpauth URI", func() {
+			send("POST", "/v1/users/1/mfa", nil, session())
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var response struct {
+				Enrollment models.MFAEnrollment `json:"enrollment"`
+			}
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+
+			userID, stored := fakeAuther.SetMFASecretArgsForCall(0)
+			Expect(userID).To(Equal(1))
+			Expect(stored).To(Equal(response.Enrollment.Secret))
+			Expect(response.Enrollment.URI).To(HavePrefix("otpauth://totp/Library:"))
+		})
+
+		It("should only enroll the user of the session", func() {
+			send("POST", "/v1/users/2/mfa", nil, session())
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeAuther.SetMFASecretCallCount()).To(Equal(0))
+		})
+
+		It("should refuse a user with MFA enabled", func() {
+			fakeAuther.SetMFASecretReturns(repository.ErrMFAEnabled)
+
+			send("POST", "/v1/users/1/mfa", nil, session())
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+	})
+
+	Describe("ConfirmMFA", func() {
+		It("should enable MFA and return the recovery codes", func() {
+			fakeAuther.GetMFAReturns(&models.MFA{Secret: secret}, nil)
+
+			send("POST", "/v1/users/1/mfa/confirm", models.MFACodeRequest{Code: currentCode()}, session())
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var response struct {
+				RecoveryCodes []string `json:"recovery_codes"`
+			}
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.RecoveryCodes).To(HaveLen(10))
+
+			userID, step, codes := fakeAuther.EnableMFAArgsForCall(0)
+			Expect(userID).To(Equal(1))
+			Expect(step).To(BeNumerically("~", totp.Step(time.Now()), 1))
+			Expect(codes).To(Equal(response.RecoveryCodes))
+		})
+
+		It("should reject a wrong code", func() {
+			fakeAuther.GetMFAReturns(&models.MFA{Secret: secret}, nil)
+
+			send("POST", "/v1/users/1/mfa/confirm", models.MFACodeRequest{Code: "000000"}, session())
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			Expect(fakeAuther.EnableMFACallCount()).To(Equal(0))
+		})
+
+		It("should require an enrollment", func() {
+			fakeAuther.GetMFAReturns(&models.MFA{}, nil)
+
+			send("POST", "/v1/users/1/mfa/confirm", models.MFACodeRequest{Code: "123456"}, session())
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+	})
+
+	Describe("Login", func() {
+		It("should ask for the second factor instead of starting a session", func() {
+			hash, err := pkg.GenerateHashPassword("secret")
+			Expect(err).To(BeNil())
+
+			fakeAuther.LoginCalls(func(found *models.User, _ *models.Authentication) error {
+				*found = user
+				found.Password = hash
+				return nil
+			})
+			fakeAuther.GetMFAReturns(&models.MFA{Secret: secret, Enabled: true}, nil)
+			fakeTokener.CreateMFATokenReturns("mfa-1", nil)
+
+			send("POST", "/v1/users/login", models.Authentication{Email: user.Email, Password: "secret"})
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(ContainSubstring(`"mfa_token":"mfa-1"`))
+			Expect(fakeTokener.CreateMFATokenArgsForCall(0)).To(Equal(1))
+			Expect(fakeTokener.CreateRefreshTokenCallCount()).To(Equal(0))
+			Expect(fakeThrottler.ResetLoginFailuresCallCount()).To(Equal(0))
+			Expect(w.Result().Cookies()).To(BeEmpty())
+		})
+	})
+
+	Describe("LoginMFA", func() {
+		BeforeEach(func() {
+			fakeTokener.UseMFATokenReturns(1, nil)
+			fakeAuther.GetMFAReturns(&models.MFA{Secret: secret, Enabled: true}, nil)
+		})
+
+		It("should start the session with a valid code", func() {
+			fakeAuther.UseTOTPStepReturns(true, nil)
+
+			send("POST", "/v1/users/login/mfa", models.MFALoginRequest{MFAToken: "mfa-1", Code: currentCode()})
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(fakeTokener.UseMFATokenArgsForCall(0)).To(Equal("mfa-1"))
+			Expect(fakeTokener.RevokeMFATokenArgsForCall(0)).To(Equal("mfa-1"))
+			Expect(fakeTokener.CreateRefreshTokenCallCount()).To(Equal(1))
+			Expect(fakeThrottler.ResetLoginFailuresArgsForCall(0)).To(Equal(user.Email))
+		})
+
+		It("should refuse a code that was already used", func() {
+			fakeAuther.UseTOTPStepReturns(false, nil)
+
+			send("POST", "/v1/users/login/mfa", models.MFALoginRequest{MFAToken: "mfa-1", Code: currentCode()})
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(fakeTokener.CreateRefreshTokenCallCount()).To(Equal(0))
+			Expect(fakeThrottler.RecordLoginFailureCallCount()).To(Equal(1))
+		})
+
+		It("should refuse a wrong code", func() {
+			send("POST", "/v1/users/login/mfa", models.MFALoginRequest{MFAToken: "mfa-1", Code: "000000"})
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(fakeAuther.UseTOTPStepCallCount()).To(Equal(0))
+		})
+
+		It("should accept a recovery code", func() {
+			fakeAuther.UseRecoveryCodeReturns(true, nil)
+
+			send("POST", "/v1/users/login/mfa", models.MFALoginRequest{MFAToken: "mfa-1", RecoveryCode: "ABCDE FGHIJ"})
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			userID, code := fakeAuther.UseRecoveryCodeArgsForCall(0)
+			Expect(userID).To(Equal(1))
+			Expect(code).To(Equal("abcde-fghij"))
+		})
+
+		It("should refuse an expired MFA token", func() {
+			fakeTokener.UseMFATokenReturns(0, repository.ErrInvalidMFAToken)
+
+			send("POST", "/v1/users/login/mfa", models.MFALoginRequest{MFAToken: "mfa-1", Code: currentCode()})
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(fakeAuther.GetMFACallCount()).To(Equal(0))
+		})
+
+		It("should require a code", func() {
+			send("POST", "/v1/users/login/mfa", models.MFALoginRequest{MFAToken: "mfa-1"})
+
+			Expect(w.Code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(fakeTokener.UseMFATokenCallCount()).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"library/pkg"
+	"library/pkg/utils"
+	"library/users/models"
+	"library/users/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ForgotPassword emails a password reset link.
+//
+//	@Summary		Request a password reset
+//	@Description	Emails a single-use password reset link, valid for an hour, when the email belongs to a user. The response is the same for unknown emails.
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.ForgotPasswordRequest	true	"User email"
+//	@Success		202
+//	@Failure		400
+//	@Failure		422
+//	@Failure		429
+//	@Failure		500
+//	@Router			/v1/users/password/forgot [post]
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var request models.ForgotPasswordRequest
+
+	log := utils.GetLogger(h.ctx)
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.New().Struct(request); err != nil {
+		log.Warningf("Validation error: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.userRepository.RequestPasswordReset(request.Email)
+	if errors.Is(err, repository.ErrTooManyResetRequests) {
+		c.Header("Retry-After", strconv.Itoa(int(repository.PasswordResetWindow.Seconds())))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("Password reset request error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the email belongs to an account, a reset link has been sent"})
+}
+
+// ResetPassword sets a new password with the token of a reset link.
+//
+//	@Summary		Reset the password
+//	@Description	Spends the reset token, sets the new password and logs the user out of every session.
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.ResetPasswordRequest	true	"User ID, reset token and new password"
+//	@Success		200
+//	@Failure		400
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/users/password/reset [post]
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	var request models.ResetPasswordRequest
+
+	log := utils.GetLogger(h.ctx)
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.New().Struct(request); err != nil {
+		log.Warningf("Validation error: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := models.ValidatePassword(request.Password); err != nil {
+		log.Warningf("Password policy error: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	passwordHash, err := pkg.GenerateHashPassword(request.Password)
+	if err != nil {
+		log.Errorf("Error generating hashed password: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.userRepository.ResetPassword(request.UserID, request.Token, passwordHash)
+	if errors.Is(err, repository.ErrInvalidResetToken) || errors.Is(err, repository.ErrUserNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": repository.ErrInvalidResetToken.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("Password reset error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err = h.tokenRepository.RevokeUserSessions(request.UserID); err != nil {
+		log.Errorf("Failed to revoke the sessions of user %v: %v", request.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "password changed but the sessions could not be revoked"})
+		return
+	}
+
+	log.Infof("Password reset for user %v", request.UserID)
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+}
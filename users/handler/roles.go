@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"library/pkg/utils"
+	"library/users/models"
+	"library/users/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// GetRoles lists the roles and their permissions.
+//
+//	@Summary		List roles
+//	@Description	Lists the roles with the permissions they grant. Requires the users:roles permission.
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Success		200
+//	@Failure		401
+//	@Failure		403
+//	@Failure		500
+//	@Router			/v1/users/roles [get]
+func (h *UserHandler) GetRoles(c *gin.Context) {
+	log := utils.GetLogger(h.ctx)
+
+	roles, err := h.userRepository.GetRoles()
+	if err != nil {
+		log.Errorf("Error getting roles from the repository: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"roles": roles})
+}
+
+// SetUserRole assigns a role to a user.
+//
+//	@Summary		Assign a role
+//	@Description	Assigns a role to the user and logs them out of every session, so their next token carries the permissions of the new role. Requires the users:roles permission.
+//	@Accept			json
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			user_id	path		int					true	"User ID"
+//	@Param			request	body		models.RoleRequest	true	"Role name"
+//	@Success		200
+//	@Failure		400
+//	@Failure		401
+//	@Failure		403
+//	@Failure		404
+//	@Failure		422
+//	@Failure		500
+//	@Router			/v1/users/{user_id}/role [put]
+func (h *UserHandler) SetUserRole(c *gin.Context) {
+	var request models.RoleRequest
+
+	log := utils.GetLogger(h.ctx)
+
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err = validator.New().Struct(request); err != nil {
+		log.Warningf("Validation error: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.userRepository.SetUserRole(userID, request.Role)
+	switch {
+	case errors.Is(err, repository.ErrUnknownRole):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, repository.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		log.Errorf("Error setting the role of user %v: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err = h.tokenRepository.RevokeUserSessions(userID); err != nil {
+		log.Errorf("Failed to revoke the sessions of user %v: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "role assigned but the sessions could not be revoked"})
+		return
+	}
+
+	log.Infof("User %v now has role %v", userID, request.Role)
+	c.JSON(http.StatusOK, gin.H{"message": "Role assigned successfully"})
+}
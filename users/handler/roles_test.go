@@ -0,0 +1,188 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"library/pkg/authz"
+	"library/pkg/logger"
+	"library/pkg/middleware"
+	"library/users/handler"
+	"library/users/models"
+	"library/users/repository"
+	"library/users/repository/repositoryfakes"
+	"library/users/server"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Roles API Test", func() {
+	var (
+		w           *httptest.ResponseRecorder
+		fakeTokener *repositoryfakes.FakeTokenerRepository
+		fakeUserer  *repositoryfakes.FakeUsererRepository
+		router      *gin.Engine
+		permissions []string
+	)
+
+	send := func(method, path string, body interface{}) {
+		payload, err := json.Marshal(body)
+		Expect(err).To(BeNil())
+
+		req, err := http.NewRequest(method, path, bytes.NewBuffer(payload))
+		Expect(err).To(BeNil())
+		req.Header.Set("Content-Type", "application/json")
+
+		token, err := middleware.GenerateJWT(models.User{ID: 1, Email: "tmostowashere@tmostowashere.com", Role: "user", Permissions: permissions})
+		Expect(err).To(BeNil())
+		req.AddCookie(&http.Cookie{Name: "token", Value: token})
+
+		router.ServeHTTP(w, req)
+	}
+
+	BeforeEach(func() {
+		w = httptest.NewRecorder()
+		permissions = nil
+
+		ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+		fakeTokener = &repositoryfakes.FakeTokenerRepository{}
+		fakeUserer = &repositoryfakes.FakeUsererRepository{}
+
+		router = server.NewRouter(
+			handler.NewUserAuth(ctx, &repositoryfakes.FakeAutherRepository{}, fakeTokener, &repositoryfakes.FakeThrottlerRepository{}),
+			handler.NewUserHandler(ctx, fakeUserer, fakeTokener),
+		)
+	})
+
+	Describe("GetRoles", func() {
+		It("should be forbidden without the users:roles permission", func() {
+			send("GET", "/v1/users/roles", nil)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeUserer.GetRolesCallCount()).To(Equal(0))
+		})
+
+		It("should list the roles", func() {
+			permissions = []string{authz.UsersRoles}
+			fakeUserer.GetRolesReturns([]models.Role{{Name: authz.RoleUser, Permissions: []string{authz.TransactionsRead}}}, nil)
+
+			send("GET", "/v1/users/roles", nil)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(ContainSubstring(authz.TransactionsRead))
+		})
+	})
+
+	Describe("SetUserRole", func() {
+		It("should be forbidden without the users:roles permission", func() {
+			send("PUT", "/v1/users/2/role", models.RoleRequest{Role: authz.RoleSuperuser})
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeUserer.SetUserRoleCallCount()).To(Equal(0))
+		})
+
+		Context("with the users:roles permission", func() {
+			BeforeEach(func() {
+				permissions = []string{authz.UsersRoles}
+			})
+
+			It("should assign the role and revoke the sessions", func() {
+				send("PUT", "/v1/users/2/role", models.RoleRequest{Role: authz.RoleSuperuser})
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				userID, role := fakeUserer.SetUserRoleArgsForCall(0)
+				Expect(userID).To(Equal(2))
+				Expect(role).To(Equal(authz.RoleSuperuser))
+				Expect(fakeTokener.RevokeUserSessionsArgsForCall(0)).To(Equal(2))
+			})
+
+			It("should reject an unknown role", func() {
+				fakeUserer.SetUserRoleReturns(repository.ErrUnknownRole)
+
+				send("PUT", "/v1/users/2/role", models.RoleRequest{Role: "admin"})
+
+				Expect(w.Code).To(Equal(http.StatusUnprocessableEntity))
+				Expect(fakeTokener.RevokeUserSessionsCallCount()).To(Equal(0))
+			})
+
+			It("should tell an unknown user apart", func() {
+				fakeUserer.SetUserRoleReturns(repository.ErrUserNotFound)
+
+				send("PUT", "/v1/users/2/role", models.RoleRequest{Role: authz.RoleSuperuser})
+
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	Describe("DeleteUser", func() {
+		It("should be forbidden without the users:delete permission", func() {
+			send("DELETE", "/v1/users/1/2", nil)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeUserer.DeleteUserCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("GetUser", func() {
+		It("should forbid other users", func() {
+			send("GET", "/v1/users/2", nil)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeUserer.GetUserCallCount()).To(Equal(0))
+		})
+
+		It("should retrieve other users with the users:read permission", func() {
+			permissions = []string{authz.UsersRead}
+			fakeUserer.GetUserReturns(&models.UserResponse{ID: 2}, nil)
+
+			send("GET", "/v1/users/2", nil)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(fakeUserer.GetUserArgsForCall(0)).To(Equal(2))
+		})
+	})
+
+	Describe("UpdateUser", func() {
+		It("should forbid other users without the users:write:any permission", func() {
+			permissions = []string{authz.UsersRead}
+
+			send("PUT", "/v1/users/2", models.User{Firstname: "tmostowashere"})
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeUserer.UpdateUserCallCount()).To(Equal(0))
+		})
+
+		It("should update other users with the users:write:any permission", func() {
+			permissions = []string{authz.UsersWriteAny}
+			fakeUserer.UpdateUserReturns(&models.UserResponse{ID: 2}, nil)
+
+			send("PUT", "/v1/users/2", models.User{Firstname: "tmostowashere", Role: authz.RoleSuperuser})
+
+			Expect(w.Code).To(Equal(http.StatusCreated))
+			Expect(fakeUserer.UpdateUserArgsForCall(0).ID).To(Equal(2))
+		})
+	})
+
+	Describe("AddUser", func() {
+		It("should not let the new user choose their role", func() {
+			send("POST", "/v1/users", models.User{
+				Firstname: "tmostowashere",
+				Lastname:  "tmostowashere",
+				Email:     "tmostowashere@tmostowashere.com",
+				Password:  "tmostowashere",
+				Role:      authz.RoleSuperuser,
+			})
+
+			Expect(w.Code).To(Equal(http.StatusCreated))
+			Expect(fakeUserer.AddUserArgsForCall(0).Role).To(Equal(authz.RoleUser))
+		})
+	})
+})
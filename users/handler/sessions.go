@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"errors"
+	"library/pkg/utils"
+	"library/users/models"
+	"library/users/repository"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSessions lists the active logins of the user.
+//
+//	@Summary		List sessions
+//	@Description	Lists the active logins of the user with their device, address, and last use, the most recently used first. The session of the request is marked current.
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			user_id	path		int	true	"User ID"
+//	@Success		200
+//	@Failure		401
+//	@Failure		403
+//	@Failure		500
+//	@Router			/v1/users/{user_id}/sessions [get]
+func (u *UserAuth) GetSessions(c *gin.Context) {
+	log := utils.GetLogger(u.ctx)
+
+	sessions, err := u.tokenRepository.GetSessions(c.GetInt("userID"))
+	if err != nil {
+		log.Errorf("Get sessions repository error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	current := c.MustGet("claims").(*models.Claims).SessionID
+	for i := range sessions {
+		sessions[i].Current = current != "" && sessions[i].ID == current
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// RevokeSession logs the user out of one session.
+//
+//	@Summary		Revoke a session
+//	@Description	Logs the user out of the session, its tokens are refused from the next request on.
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			user_id		path		int		true	"User ID"
+//	@Param			session_id	path		string	true	"Session ID"
+//	@Success		200
+//	@Failure		401
+//	@Failure		403
+//	@Failure		404
+//	@Failure		500
+//	@Router			/v1/users/{user_id}/sessions/{session_id} [delete]
+func (u *UserAuth) RevokeSession(c *gin.Context) {
+	log := utils.GetLogger(u.ctx)
+
+	userID := c.GetInt("userID")
+	sessionID := c.Param("session_id")
+
+	err := u.tokenRepository.RevokeSession(userID, sessionID)
+	if errors.Is(err, repository.ErrSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.MustGet("claims").(*models.Claims).SessionID == sessionID {
+		clearSessionCookies(c)
+	}
+
+	log.Infof("Session %v of user %v revoked", sessionID, userID)
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
+
+// RevokeAllSessions logs the user out everywhere.
+//
+//	@Summary		Log out everywhere
+//	@Description	Logs the user out of every session, the current one included. API keys are not revoked.
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			user_id	path		int	true	"User ID"
+//	@Success		200
+//	@Failure		401
+//	@Failure		403
+//	@Failure		500
+//	@Router			/v1/users/{user_id}/sessions [delete]
+func (u *UserAuth) RevokeAllSessions(c *gin.Context) {
+	log := utils.GetLogger(u.ctx)
+
+	userID := c.GetInt("userID")
+
+	if err := u.tokenRepository.RevokeUserSessions(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	clearSessionCookies(c)
+
+	log.Infof("Every session of user %v revoked", userID)
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out of every session"})
+}
@@ -0,0 +1,168 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"library/pkg/logger"
+	"library/pkg/middleware"
+	"library/users/handler"
+	"library/users/models"
+	"library/users/repository"
+	"library/users/repository/repositoryfakes"
+	"library/users/server"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type sessionDenylist struct {
+	revoked map[string]bool
+}
+
+func (s sessionDenylist) IsRevoked(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+func (s sessionDenylist) IsSessionRevoked(_ context.Context, sessionID string) (bool, error) {
+	return s.revoked[sessionID], nil
+}
+
+var _ = Describe("Sessions API Test", func() {
+	var (
+		w           *httptest.ResponseRecorder
+		fakeTokener *repositoryfakes.FakeTokenerRepository
+		router      *gin.Engine
+		token       string
+	)
+
+	send := func(method, path string) {
+		req, err := http.NewRequest(method, path, nil)
+		Expect(err).To(BeNil())
+		req.AddCookie(&http.Cookie{Name: "token", Value: token})
+
+		router.ServeHTTP(w, req)
+	}
+
+	clearedCookies := func() bool {
+		for _, cookie := range w.Result().Cookies() {
+			if cookie.Name == "token" && cookie.MaxAge < 0 {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		w = httptest.NewRecorder()
+
+		ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+		fakeTokener = &repositoryfakes.FakeTokenerRepository{}
+
+		router = server.NewRouter(
+			handler.NewUserAuth(ctx, &repositoryfakes.FakeAutherRepository{}, fakeTokener, &repositoryfakes.FakeThrottlerRepository{}),
+			handler.NewUserHandler(ctx, &repositoryfakes.FakeUsererRepository{}, fakeTokener),
+		)
+
+		token, _, err = middleware.GenerateAccessToken(models.User{ID: 1, Email: "tmostowashere@tmostowashere.com", Role: "user"}, "family-1")
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		middleware.UseDenylist(nil)
+	})
+
+	Describe("GetSessions", func() {
+		It("should list the sessions and mark the current one", func() {
+			seen := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			fakeTokener.GetSessionsReturns([]models.Session{
+				{ID: "family-2", UserAgent: "curl/8.0", IP: "10.0.0.2", CreatedAt: seen, LastSeenAt: seen},
+				{ID: "family-1", UserAgent: "Firefox", IP: "10.0.0.1", CreatedAt: seen, LastSeenAt: seen},
+			}, nil)
+
+			send("GET", "/v1/users/1/sessions")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(fakeTokener.GetSessionsArgsForCall(0)).To(Equal(1))
+
+			var response struct {
+				Sessions []models.Session `json:"sessions"`
+			}
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Sessions).To(HaveLen(2))
+			Expect(response.Sessions[0].Current).To(BeFalse())
+			Expect(response.Sessions[1].Current).To(BeTrue())
+			Expect(response.Sessions[1].UserAgent).To(Equal("Firefox"))
+		})
+
+		It("should only list the sessions of the user of the token", func() {
+			send("GET", "/v1/users/2/sessions")
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(fakeTokener.GetSessionsCallCount()).To(Equal(0))
+		})
+
+		It("should refuse a token of a revoked session", func() {
+			middleware.UseDenylist(sessionDenylist{revoked: map[string]bool{"family-1": true}})
+
+			send("GET", "/v1/users/1/sessions")
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(fakeTokener.GetSessionsCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("RevokeSession", func() {
+		It("should revoke another session and keep the current one", func() {
+			send("DELETE", "/v1/users/1/sessions/family-2")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			userID, sessionID := fakeTokener.RevokeSessionArgsForCall(0)
+			Expect(userID).To(Equal(1))
+			Expect(sessionID).To(Equal("family-2"))
+			Expect(clearedCookies()).To(BeFalse())
+		})
+
+		It("should clear the cookies when revoking the current session", func() {
+			send("DELETE", "/v1/users/1/sessions/family-1")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(clearedCookies()).To(BeTrue())
+		})
+
+		It("should return 404 for a session of another user", func() {
+			fakeTokener.RevokeSessionReturns(repository.ErrSessionNotFound)
+
+			send("DELETE", "/v1/users/1/sessions/family-3")
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("RevokeAllSessions", func() {
+		It("should log the user out everywhere", func() {
+			send("DELETE", "/v1/users/1/sessions")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(fakeTokener.RevokeUserSessionsArgsForCall(0)).To(Equal(1))
+			Expect(clearedCookies()).To(BeTrue())
+		})
+
+		It("should fail when the sessions cannot be revoked", func() {
+			fakeTokener.RevokeUserSessionsReturns(errors.New("connection refused"))
+
+			send("DELETE", "/v1/users/1/sessions")
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
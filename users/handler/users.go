@@ -2,7 +2,9 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"library/pkg"
+	"library/pkg/authz"
 	"library/pkg/utils"
 	"library/users/models"
 	"library/users/repository"
@@ -20,17 +22,24 @@ type Userer interface {
 	GetAllUsers(c *gin.Context)
 	DeleteUser(c *gin.Context)
 	ActivateAccount(c *gin.Context)
+	ResendActivation(c *gin.Context)
+	ForgotPassword(c *gin.Context)
+	ResetPassword(c *gin.Context)
+	GetRoles(c *gin.Context)
+	SetUserRole(c *gin.Context)
 }
 
 type UserHandler struct {
-	ctx            context.Context
-	userRepository repository.UsererRepository
+	ctx             context.Context
+	userRepository  repository.UsererRepository
+	tokenRepository repository.TokenerRepository
 }
 
-func NewUserHandler(ctx context.Context, userRepository repository.UsererRepository) Userer {
+func NewUserHandler(ctx context.Context, userRepository repository.UsererRepository, tokenRepository repository.TokenerRepository) Userer {
 	return &UserHandler{
-		ctx:            ctx,
-		userRepository: userRepository,
+		ctx:             ctx,
+		userRepository:  userRepository,
+		tokenRepository: tokenRepository,
 	}
 }
 
@@ -65,6 +74,9 @@ func (h *UserHandler) AddUser(c *gin.Context) {
 		return
 	}
 
+	// Roles are only assigned by users holding the users:roles permission.
+	user.Role = authz.RoleUser
+
 	user.Password, err = pkg.GenerateHashPassword(user.Password)
 	if err != nil {
 		log.Errorf("Error generating hashed password: %v", err)
@@ -93,15 +105,16 @@ func (h *UserHandler) AddUser(c *gin.Context) {
 // UpdateUser updates a user data.
 //
 //	@Summary		Updates a user data
-//	@Description	Updates a user with the provided data
+//	@Description	Updates a user with the provided data. The role is not changed, see SetUserRole. Other users can only be updated with the users:write:any permission.
 //	@Accept			json
 //	@Produce		json
-//	@Param			userID	path		int												true	"User ID"
+//	@Param			user_id	path		int												true	"User ID"
 //	@Param			user	body		models.User											true	"Updated user object"
 //	@Success		201
 //	@Failure		400
+//	@Failure		403
 //	@Failure		500
-//	@Router			/users/{userID} [put]
+//	@Router			/v1/users/{user_id} [put]
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	var user models.User
 	var userResponse *models.UserResponse
@@ -130,12 +143,13 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 // GetUser retrieves user details by ID.
 //
 //	@Summary		Get user details
-//	@Description	Retrieves user details by the provided ID
+//	@Description	Retrieves user details by the provided ID. Other users can only be retrieved with the users:read permission.
 //	@Accept			json
 //	@Produce		json
 //	@Security		ApiKeyAuth
 //	@Param			user_id	path		int										true	"User ID"
 //	@Success		200
+//	@Failure		403
 //	@Failure		404
 //	@Failure		500
 //	@Router			/v1/users/{user_id} [get]
@@ -145,13 +159,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 
 	log := utils.GetLogger(h.ctx)
 
-	getUser, err := strconv.Atoi(c.Param("user_id"))
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		return
-	}
-
-	user, err = h.userRepository.GetUser(getUser)
+	user, err = h.userRepository.GetUser(c.GetInt("userID"))
 	if err != nil {
 		log.Errorf("Error getting user from the repository: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -165,7 +173,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 // GetAllUsers retrieves all users.
 //
 //	@Summary		Get all users
-//	@Description	Retrieves all users
+//	@Description	Retrieves all users. Requires the users:read permission.
 //	@Accept			json
 //	@Produce		json
 //	@Security		ApiKeyAuth
@@ -189,7 +197,7 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 // DeleteUser deletes a user.
 //
 //	@Summary		Delete a user
-//	@Description	Deletes a user
+//	@Description	Deletes a user. Requires the users:delete permission.
 //	@Accept			json
 //	@Produce		json
 //	@Security		ApiKeyAuth
@@ -198,30 +206,12 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 //	@Success		200
 //	@Failure		400
 //	@Failure		401
+//	@Failure		403
 //	@Router			/v1/users/{user_id}/{delete_id} [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	log := utils.GetLogger(h.ctx)
 
 	id := c.GetInt("deleteID")
-	claims, exists := c.Get("claims")
-	if !exists {
-		log.Errorf("Could not get claims from context")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "could not get claims from context"})
-		return
-	}
-
-	claimsData, ok := claims.(*models.Claims)
-	if !ok {
-		log.Errorf("Failed to convert claims")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "failed to convert claims"})
-		return
-	}
-
-	if claimsData.Role != "superuser" {
-		log.Warningf("not enough permissions")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "not enough permissions"})
-		return
-	}
 
 	deletedID, err := h.userRepository.DeleteUser(id)
 	if err != nil {
@@ -237,12 +227,15 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 // ActivateAccount activates a user account.
 //
 //	@Summary		Activate a user account
-//	@Description	Activates a user account
+//	@Description	Activates a user account with the token of the emailed activation link. The token can only be used once.
 //	@Accept			json
 //	@Produce		json
-//	@Param			userID	query		int											true	"User ID to activate"
+//	@Param			userID	query		int		true	"User ID to activate"
+//	@Param			token	query		string	true	"Activation token"
 //	@Success		200
 //	@Failure		400
+//	@Failure		404
+//	@Failure		410
 //	@Failure		500
 //	@Router			/v1/users/activate [get]
 func (h *UserHandler) ActivateAccount(c *gin.Context) {
@@ -256,9 +249,26 @@ func (h *UserHandler) ActivateAccount(c *gin.Context) {
 		return
 	}
 
-	id, err := h.userRepository.ActivateUser(userID)
-	if err != nil {
-		log.Errorf("Failed to activate user, id: %v", id)
+	token := c.Query("token")
+	if token == "" {
+		log.Warningf("Missing activation token for user %v", userID)
+		c.JSON(http.StatusBadRequest, gin.H{"error": repository.ErrInvalidActivationToken.Error()})
+		return
+	}
+
+	id, err := h.userRepository.ActivateUser(userID, token)
+	switch {
+	case errors.Is(err, repository.ErrInvalidActivationToken):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, repository.ErrActivationTokenExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, repository.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		log.Errorf("Failed to activate user, id: %v", userID)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to activate user"})
 		return
 	}
@@ -266,3 +276,49 @@ func (h *UserHandler) ActivateAccount(c *gin.Context) {
 	log.Infof("User account has been activated: %v", id)
 	c.JSON(http.StatusOK, gin.H{"message": "User account activated"})
 }
+
+// ResendActivation emails a new activation link.
+//
+//	@Summary		Resend the activation email
+//	@Description	Emails a new activation link, replacing the previous one, when the email belongs to an account that is not active yet. The response is the same for unknown emails. Limited to 3 requests per email an hour.
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.ResendActivationRequest	true	"User email"
+//	@Success		202
+//	@Failure		400
+//	@Failure		422
+//	@Failure		429
+//	@Failure		500
+//	@Router			/v1/users/activate/resend [post]
+func (h *UserHandler) ResendActivation(c *gin.Context) {
+	var request models.ResendActivationRequest
+
+	log := utils.GetLogger(h.ctx)
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("JSON binding error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validator.New().Struct(request); err != nil {
+		log.Warningf("Validation error: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.userRepository.ResendActivation(request.Email)
+	if errors.Is(err, repository.ErrTooManyResends) {
+		c.Header("Retry-After", strconv.Itoa(int(repository.ActivationResendWindow.Seconds())))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("Activation resend error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the email belongs to an inactive account, an activation link has been sent"})
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"github.com/kelseyhightower/envconfig"
 	"io"
+	"library/pkg/authz"
 	"library/pkg/config"
 	"library/pkg/middleware"
 	"library/pkg/rabbitMQ/rabbitMQ"
@@ -60,18 +61,19 @@ var _ = Describe("API Test", func() {
 		fakeAuther = &repositoryfakes.FakeAutherRepository{}
 		fakeUserer = &repositoryfakes.FakeUsererRepository{}
 
-		fakeAuthUser = handler.NewUserAuth(ctx, fakeAuther)
-		fakeUserHandler = handler.NewUserHandler(ctx, fakeUserer)
+		fakeAuthUser = handler.NewUserAuth(ctx, fakeAuther, &repositoryfakes.FakeTokenerRepository{}, &repositoryfakes.FakeThrottlerRepository{})
+		fakeUserHandler = handler.NewUserHandler(ctx, fakeUserer, &repositoryfakes.FakeTokenerRepository{})
 
 		router = server.NewRouter(fakeAuthUser, fakeUserHandler)
 
 		request = &models.User{
-			ID:        1,
-			Firstname: "tmostowashere",
-			Lastname:  "tmostowashere",
-			Email:     "tmostowashere@tmostowashere.com",
-			Password:  "tmostowashere",
-			Role:      "superuser",
+			ID:          1,
+			Firstname:   "tmostowashere",
+			Lastname:    "tmostowashere",
+			Email:       "tmostowashere@tmostowashere.com",
+			Password:    "tmostowashere",
+			Role:        "superuser",
+			Permissions: []string{authz.UsersRead, authz.UsersDelete},
 		}
 
 		token, _ := middleware.GenerateJWT(*request)
@@ -117,7 +119,7 @@ var _ = Describe("API Test", func() {
 			body, err := json.Marshal(request)
 			Expect(err).To(BeNil())
 
-			ginCtx.Request, err = http.NewRequest("PUT", "/v1/users/:user_id", bytes.NewBuffer(body))
+			ginCtx.Request, err = http.NewRequest("PUT", "/v1/users/1", bytes.NewBuffer(body))
 			Expect(err).To(BeNil())
 			ginCtx.Request.AddCookie(cookie)
 			ginCtx.Request = enricher(ginCtx.Request)
@@ -144,7 +146,7 @@ var _ = Describe("API Test", func() {
 		It("should delete a user successfully", func() {
 			var err error
 
-			ginCtx.Request, err = http.NewRequest("DELETE", "/v1/users/:user_id/2", nil)
+			ginCtx.Request, err = http.NewRequest("DELETE", "/v1/users/1/2", nil)
 			Expect(err).To(BeNil())
 			ginCtx.Request.AddCookie(cookie)
 			ginCtx.Request = enricher(ginCtx.Request)
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// APIKey is a personal access token of scripts and CI. The key itself is only shown
+// when it is created, the hint is kept to tell the keys apart.
+// swagger:model
+type APIKey struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Hint       string     `json:"hint"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// APIKeyRequest represents the request body for creating an API key. The scopes are
+// permissions of the user, the key grants no others.
+// swagger:model
+type APIKeyRequest struct {
+	Name          string   `json:"name" form:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" form:"scopes" validate:"dive,required"`
+	ExpiresInDays int      `json:"expires_in_days" form:"expires_in_days" validate:"required,min=1,max=365"`
+}
@@ -3,9 +3,14 @@ package models
 import "github.com/dgrijalva/jwt-go"
 
 type Claims struct {
-	UserID      string `json:"id"`
-	Email       string `json:"email"`
-	TokenString string `json:"token"`
-	Role        string `json:"role"`
+	UserID      string   `json:"id"`
+	Email       string   `json:"email"`
+	TokenString string   `json:"token"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+	// SessionID is the session the token was issued in, revoking it revokes the token.
+	SessionID string `json:"sid,omitempty"`
+	// APIKeyID is set when the request was authenticated with an API key.
+	APIKeyID int `json:"-"`
 	jwt.StandardClaims
 }
@@ -0,0 +1,31 @@
+package models
+
+// MFA is the two-factor authentication state of a user.
+type MFA struct {
+	Secret  string
+	Enabled bool
+	// LastStep is the TOTP step of the last code used, a code is only accepted once.
+	LastStep int64
+}
+
+// MFAEnrollment is the secret of a new enrollment, to be added to an authenticator app.
+// swagger:model
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// MFACodeRequest represents the request body for confirming an enrollment.
+// swagger:model
+type MFACodeRequest struct {
+	Code string `json:"code" form:"code" validate:"required,numeric,len=6"`
+}
+
+// MFALoginRequest represents the second step of a login, with either a code of the
+// authenticator app or a recovery code.
+// swagger:model
+type MFALoginRequest struct {
+	MFAToken     string `json:"mfa_token" form:"mfa_token" validate:"required"`
+	Code         string `json:"code,omitempty" form:"code" validate:"required_without=RecoveryCode,omitempty,numeric,len=6"`
+	RecoveryCode string `json:"recovery_code,omitempty" form:"recovery_code" validate:"required_without=Code"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+)
+
+const (
+	MinPasswordLength = 8
+	// MaxPasswordLength is the bcrypt limit, longer passwords are silently truncated.
+	MaxPasswordLength = 72
+)
+
+// ForgotPasswordRequest represents the request body for asking a password reset link.
+// swagger:model
+type ForgotPasswordRequest struct {
+	Email string `json:"email" form:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest represents the request body for setting a new password with
+// the token of a reset link.
+// swagger:model
+type ResetPasswordRequest struct {
+	UserID   int    `json:"user_id" form:"user_id" validate:"required"`
+	Token    string `json:"token" form:"token" validate:"required"`
+	Password string `json:"password" form:"password"`
+}
+
+// ValidatePassword checks the password policy: 8 to 72 bytes with at least one
+// letter and one digit.
+func ValidatePassword(password string) error {
+	if len(password) < MinPasswordLength {
+		return fmt.Errorf("password must be at least %d characters long", MinPasswordLength)
+	}
+
+	if len(password) > MaxPasswordLength {
+		return fmt.Errorf("password cannot be longer than %d bytes", MaxPasswordLength)
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+
+	if !hasLetter || !hasDigit {
+		return errors.New("password must contain at least one letter and one digit")
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{name: "Valid password", password: "correct horse 42", wantErr: false},
+		{name: "Too short", password: "abc123", wantErr: true},
+		{name: "Too long", password: strings.Repeat("a1", 37), wantErr: true},
+		{name: "No digit", password: "securepassword", wantErr: true},
+		{name: "No letter", password: "1234567890", wantErr: true},
+		{name: "Unicode letters", password: "zażółć123", wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidatePassword(tt.password); (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package models
+
+// Role is a named set of permissions assigned to users.
+// swagger:model
+type Role struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// RoleRequest represents the request body for assigning a role to a user.
+// swagger:model
+type RoleRequest struct {
+	Role string `json:"role" form:"role" validate:"required"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// RefreshSession is the login session a refresh token belongs to. Every rotation
+// hands out a new token of the same family.
+type RefreshSession struct {
+	UserID int
+	Family string
+}
+
+// SessionClient is the device a session was started or last refreshed from.
+type SessionClient struct {
+	UserAgent string
+	IP        string
+}
+
+// Session is an active login of the user, its ID is the family of its refresh tokens.
+// swagger:model
+type Session struct {
+	ID        string    `json:"id"`
+	UserAgent string    `json:"user_agent"`
+	IP        string    `json:"ip"`
+	CreatedAt time.Time `json:"created_at"`
+	// LastSeenAt is the last use of the session, written at most once a minute.
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
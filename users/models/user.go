@@ -16,6 +16,8 @@ type User struct {
 	Password  string `json:"password,omitempty" form:"password"`
 	Role      string `json:"role,omitempty" form:"role"`
 	IsActive  bool   `json:"is_active,omitempty" form:"is_active"`
+	// Permissions granted by the role, loaded at login to be embedded in the access token.
+	Permissions []string `json:"-" form:"-"`
 }
 
 // UserResponse represents a response for a user entity.
@@ -36,6 +38,12 @@ type Authentication struct {
 	Password string `json:"password" form:"password"`
 }
 
+// ResendActivationRequest represents the request body for asking a new activation link.
+// swagger:model
+type ResendActivationRequest struct {
+	Email string `json:"email" form:"email" validate:"required,email"`
+}
+
 // ValidateUser checks for valid user input in firstname, lastname and password
 func (u *User) ValidateUser() error {
 	validNameRegex := regexp.MustCompile(`^[a-zA-Z]+$`)
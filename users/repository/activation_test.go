@@ -0,0 +1,142 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"library/pkg/logger"
+	"library/pkg/postgres"
+	"library/pkg/redis"
+	"library/pkg/utils"
+	"library/users/repository"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+const activationURL = "https://localhost:5000/v1/users/activate"
+
+// pendingActivation stores an activation of user 1 and returns its token.
+func pendingActivation(t *testing.T, ctx context.Context, redisClient *redis.Client) string {
+	link, err := utils.GenerateActivationLink(ctx, redisClient, activationURL, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if u.Query().Get("userID") != "1" {
+		t.Fatalf("expected the link of user 1, got %v", link)
+	}
+
+	return u.Query().Get("token")
+}
+
+func TestUserRepository_ActivateUser(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+	fakeDB, _ := postgres.NewFakeDB(ctx)
+	redisClient, _ := redis.NewFakeRedis(ctx)
+	defer redisClient.Close()
+
+	newUserRepo := repository.NewUserRepository(ctx, *fakeDB, redisClient, nil, utils.Links{ActivationURL: activationURL})
+
+	token := pendingActivation(t, ctx, redisClient)
+
+	mock := fakeDB.GetMock()
+	mock.ExpectQuery(repository.GetUserByID).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "firstname", "lastname", "email", "role"}).
+			AddRow(1, "tmosto", "tmosto", "tmosto@elo.com", "user"))
+	mock.ExpectExec(repository.ActivateUser).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	id, err := newUserRepo.ActivateUser(1, token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if id != 1 {
+		t.Errorf("expected user 1 to be activated, got %v", id)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+
+	// the token is spent, the same link cannot activate again
+	if _, err = newUserRepo.ActivateUser(1, token); !errors.Is(err, repository.ErrInvalidActivationToken) {
+		t.Errorf("expected ErrInvalidActivationToken for a spent token, got %v", err)
+	}
+}
+
+func TestUserRepository_ActivateUserRejected(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+	expired := func(t *testing.T, redisClient *redis.Client, token string) {
+		value := utils.HashToken(token) + ":" + strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+		if err := redisClient.Client.Set(ctx, utils.ActivationKey(1), value, time.Hour).Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		token   func(token string) string
+		expired bool
+		want    error
+	}{
+		{"wrong token", func(string) string { return "not-the-token" }, false, repository.ErrInvalidActivationToken},
+		{"prefix of the token", func(token string) string { return token[:len(token)-1] }, false, repository.ErrInvalidActivationToken},
+		{"empty token", func(string) string { return "" }, false, repository.ErrInvalidActivationToken},
+		{"expired token", func(token string) string { return token }, true, repository.ErrActivationTokenExpired},
+		{"wrong expired token", func(string) string { return "not-the-token" }, true, repository.ErrInvalidActivationToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDB, _ := postgres.NewFakeDB(ctx)
+			redisClient, _ := redis.NewFakeRedis(ctx)
+			defer redisClient.Close()
+
+			newUserRepo := repository.NewUserRepository(ctx, *fakeDB, redisClient, nil, utils.Links{ActivationURL: activationURL})
+
+			token := pendingActivation(t, ctx, redisClient)
+			if tt.expired {
+				expired(t, redisClient, token)
+			}
+
+			if _, err := newUserRepo.ActivateUser(1, tt.token(token)); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+
+			// a rejected token is not spent
+			pending, err := redisClient.Client.Exists(ctx, utils.ActivationKey(1)).Result()
+			if err != nil || pending != 1 {
+				t.Errorf("expected the activation to stay pending, got %v, %v", pending, err)
+			}
+
+			if err = fakeDB.GetMock().ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+
+	t.Run("no pending activation", func(t *testing.T) {
+		fakeDB, _ := postgres.NewFakeDB(ctx)
+		redisClient, _ := redis.NewFakeRedis(ctx)
+		defer redisClient.Close()
+
+		newUserRepo := repository.NewUserRepository(ctx, *fakeDB, redisClient, nil, utils.Links{})
+
+		if _, err := newUserRepo.ActivateUser(1, "token"); !errors.Is(err, repository.ErrInvalidActivationToken) {
+			t.Errorf("expected ErrInvalidActivationToken, got %v", err)
+		}
+	})
+}
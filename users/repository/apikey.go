@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"errors"
+	"library/pkg/utils"
+	"library/users/models"
+
+	"github.com/lib/pq"
+)
+
+var ErrAPIKeyNotFound = errors.New("API key not found")
+
+// CreateAPIKey stores the hash of a new key, and fills in its ID and creation time.
+func (u *AuthRepository) CreateAPIKey(userID int, key *models.APIKey, keyHash string) error {
+	log := utils.GetLogger(u.ctx)
+
+	err := u.db.DB.QueryRow(
+		InsertAPIKey,
+		userID,
+		key.Name,
+		key.Hint,
+		keyHash,
+		pq.Array(key.Scopes),
+		key.ExpiresAt,
+	).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		log.Errorf("Failed to store the API key of user %v: %v", userID, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetAPIKeys lists the keys of the user that were not revoked, expired ones included.
+func (u *AuthRepository) GetAPIKeys(userID int) ([]models.APIKey, error) {
+	log := utils.GetLogger(u.ctx)
+
+	rows, err := u.db.DB.Query(GetAPIKeys, userID)
+	if err != nil {
+		log.Errorf("Failed to perform a select query: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		var key models.APIKey
+
+		err = rows.Scan(&key.ID, &key.Name, &key.Hint, pq.Array(&key.Scopes), &key.ExpiresAt, &key.LastUsedAt, &key.CreatedAt)
+		if err != nil {
+			log.Errorf("Failed to scan API key: %v", err)
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Errorf("Failed to iterate API keys: %v", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey revokes a key of the user, the key is refused from the next request on.
+func (u *AuthRepository) RevokeAPIKey(userID, keyID int) error {
+	log := utils.GetLogger(u.ctx)
+
+	result, err := u.db.DB.Exec(RevokeAPIKey, keyID, userID)
+	if err != nil {
+		log.Errorf("Failed to revoke API key %v: %v", keyID, err)
+		return err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		log.Errorf("Error number of rows affected: %v", err)
+		return err
+	}
+
+	if affectedRows == 0 {
+		log.Warningf("API key %v of user %v not found", keyID, userID)
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
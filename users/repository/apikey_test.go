@@ -0,0 +1,81 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"library/pkg/logger"
+	"library/pkg/postgres"
+	"library/users/models"
+	"library/users/repository"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestAuthRepository_GetAPIKeys(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+	fakeDB, _ := postgres.NewFakeDB(ctx)
+	newAuthRepo := repository.NewAuthRepository(ctx, *fakeDB)
+
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.AddDate(0, 0, 30)
+
+	mock := fakeDB.GetMock()
+	mock.ExpectQuery(repository.GetAPIKeys).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "hint", "scopes", "expires_at", "last_used_at", "created_at"}).
+			AddRow(2, "ci", "lib_0123abcd", "{shops:import}", expiresAt, nil, createdAt))
+
+	keys, err := newAuthRepo.GetAPIKeys(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []models.APIKey{{
+		ID:        2,
+		Name:      "ci",
+		Hint:      "lib_0123abcd",
+		Scopes:    []string{"shops:import"},
+		ExpiresAt: expiresAt,
+		CreatedAt: createdAt,
+	}}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected keys %+v, got %+v", expected, keys)
+	}
+}
+
+func TestAuthRepository_RevokeAPIKey(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+	tests := []struct {
+		name string
+		rows int64
+		want error
+	}{
+		{"revoked", 1, nil},
+		{"key of another user or already revoked", 0, repository.ErrAPIKeyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDB, _ := postgres.NewFakeDB(ctx)
+			newAuthRepo := repository.NewAuthRepository(ctx, *fakeDB)
+
+			mock := fakeDB.GetMock()
+			mock.ExpectExec(repository.RevokeAPIKey).
+				WithArgs(2, 1).
+				WillReturnResult(sqlmock.NewResult(0, tt.rows))
+
+			if err := newAuthRepo.RevokeAPIKey(1, 2); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
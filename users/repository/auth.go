@@ -2,13 +2,26 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"library/pkg/postgres"
 	"library/pkg/utils"
 	"library/users/models"
 )
 
+var ErrUserNotFound = errors.New("user not found")
+
 type AutherRepository interface {
 	Login(user *models.User, auth *models.Authentication) error
+	GetUser(id int) (*models.User, error)
+	GetMFA(userID int) (*models.MFA, error)
+	SetMFASecret(userID int, secret string) error
+	EnableMFA(userID int, step int64, recoveryCodes []string) error
+	UseTOTPStep(userID int, step int64) (bool, error)
+	UseRecoveryCode(userID int, code string) (bool, error)
+	CreateAPIKey(userID int, key *models.APIKey, keyHash string) error
+	GetAPIKeys(userID int) ([]models.APIKey, error)
+	RevokeAPIKey(userID, keyID int) error
 }
 
 type AuthRepository struct {
@@ -30,10 +43,78 @@ func (u *AuthRepository) Login(user *models.User, auth *models.Authentication) e
 		GetUserByEmail,
 		auth.Email,
 	).Scan(&user.ID, &user.Firstname, &user.Lastname, &user.Password, &user.Email, &user.Role)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("Login with unknown email")
+		return ErrUserNotFound
+	}
+
 	if err != nil {
 		log.Errorf("Failed to perform a select query: %v", err)
 		return err
 	}
 
+	user.Permissions, err = u.rolePermissions(user.Role)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// GetUser loads the user a refresh token was issued to, so the new access token
+// carries their current email, role and permissions.
+func (u *AuthRepository) GetUser(id int) (*models.User, error) {
+	user := &models.User{}
+
+	log := utils.GetLogger(u.ctx)
+
+	err := u.db.DB.QueryRow(
+		GetUserByID,
+		id,
+	).Scan(&user.ID, &user.Firstname, &user.Lastname, &user.Email, &user.Role)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("User not found: %v", id)
+		return nil, ErrUserNotFound
+	}
+
+	if err != nil {
+		log.Errorf("Failed to perform a select query: %v", err)
+		return nil, err
+	}
+
+	user.Permissions, err = u.rolePermissions(user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (u *AuthRepository) rolePermissions(role string) ([]string, error) {
+	log := utils.GetLogger(u.ctx)
+
+	rows, err := u.db.DB.Query(GetRolePermissions, role)
+	if err != nil {
+		log.Errorf("Failed to get the permissions of role %v: %v", role, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []string{}
+	for rows.Next() {
+		var permission string
+		if err = rows.Scan(&permission); err != nil {
+			log.Errorf("Failed to scan permission: %v", err)
+			return nil, err
+		}
+
+		permissions = append(permissions, permission)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Errorf("Failed to read the permissions of role %v: %v", role, err)
+		return nil, err
+	}
+
+	return permissions, nil
+}
@@ -2,10 +2,13 @@ package repository_test
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"library/pkg/logger"
 	"library/pkg/postgres"
 	"library/users/models"
 	"library/users/repository"
+	"reflect"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -29,6 +32,11 @@ func TestAuthRepository_Login(t *testing.T) {
 		WithArgs(testAuth.Email).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "firstname", "lastname", "password", "email", "role"}).
 			AddRow(1, "John", "Doe", "hashed_password", testAuth.Email, "user"))
+	mock.ExpectQuery(repository.GetRolePermissions).
+		WithArgs("user").
+		WillReturnRows(sqlmock.NewRows([]string{"permission"}).
+			AddRow("transactions:create").
+			AddRow("transactions:read"))
 
 	err := newAuthRepo.Login(testUser, testAuth)
 
@@ -37,14 +45,61 @@ func TestAuthRepository_Login(t *testing.T) {
 	}
 
 	expectedUser := &models.User{
-		ID:        1,
-		Firstname: "John",
-		Lastname:  "Doe",
-		Password:  "hashed_password",
-		Email:     testAuth.Email,
-		Role:      "user",
+		ID:          1,
+		Firstname:   "John",
+		Lastname:    "Doe",
+		Password:    "hashed_password",
+		Email:       testAuth.Email,
+		Role:        "user",
+		Permissions: []string{"transactions:create", "transactions:read"},
 	}
-	if *testUser != *expectedUser {
+	if !reflect.DeepEqual(testUser, expectedUser) {
 		t.Errorf("expected user %+v, got %+v", *expectedUser, *testUser)
 	}
+	mock.ExpectQuery(repository.GetUserByEmail).
+		WithArgs("unknown@example.com").
+		WillReturnError(sql.ErrNoRows)
+
+	err = newAuthRepo.Login(&models.User{}, &models.Authentication{Email: "unknown@example.com"})
+	if !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestAuthRepository_GetUser(t *testing.T) {
+	var ctx context.Context
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = context.WithValue(ctx, "logger", logger.NewLogger(2))
+
+	fakeDB, _ := postgres.NewFakeDB(ctx)
+
+	newAuthRepo := repository.NewAuthRepository(ctx, *fakeDB)
+
+	mock := fakeDB.GetMock()
+
+	mock.ExpectQuery(repository.GetUserByID).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "firstname", "lastname", "email", "role"}).
+			AddRow(1, "John", "Doe", "test@example.com", "user"))
+	mock.ExpectQuery(repository.GetRolePermissions).
+		WithArgs("user").
+		WillReturnRows(sqlmock.NewRows([]string{"permission"}))
+	mock.ExpectQuery(repository.GetUserByID).
+		WithArgs(2).
+		WillReturnError(sql.ErrNoRows)
+
+	user, err := newAuthRepo.GetUser(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedUser := &models.User{ID: 1, Firstname: "John", Lastname: "Doe", Email: "test@example.com", Role: "user", Permissions: []string{}}
+	if !reflect.DeepEqual(user, expectedUser) {
+		t.Errorf("expected user %+v, got %+v", *expectedUser, *user)
+	}
+
+	if _, err = newAuthRepo.GetUser(2); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
 }
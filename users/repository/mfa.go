@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"library/pkg/utils"
+	"library/users/models"
+
+	"github.com/lib/pq"
+)
+
+var ErrMFAEnabled = errors.New("two-factor authentication is already enabled")
+
+// GetMFA returns the two-factor authentication state of the user.
+func (u *AuthRepository) GetMFA(userID int) (*models.MFA, error) {
+	mfa := &models.MFA{}
+
+	log := utils.GetLogger(u.ctx)
+
+	err := u.db.DB.QueryRow(GetMFA, userID).Scan(&mfa.Secret, &mfa.Enabled, &mfa.LastStep)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("User not found: %v", userID)
+		return nil, ErrUserNotFound
+	}
+
+	if err != nil {
+		log.Errorf("Failed to perform a select query: %v", err)
+		return nil, err
+	}
+
+	return mfa, nil
+}
+
+// SetMFASecret starts an enrollment with a new secret, replacing the secret of an
+// enrollment that was never confirmed.
+func (u *AuthRepository) SetMFASecret(userID int, secret string) error {
+	log := utils.GetLogger(u.ctx)
+
+	result, err := u.db.DB.Exec(SetMFASecret, secret, userID)
+	if err != nil {
+		log.Errorf("Failed to store the MFA secret of user %v: %v", userID, err)
+		return err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		log.Errorf("Error number of rows affected: %v", err)
+		return err
+	}
+
+	if affectedRows == 0 {
+		log.Warningf("MFA of user %v already enabled", userID)
+		return ErrMFAEnabled
+	}
+
+	return nil
+}
+
+// EnableMFA confirms the enrollment with the step of its first code and stores the
+// recovery codes, hashed like the other tokens.
+func (u *AuthRepository) EnableMFA(userID int, step int64, recoveryCodes []string) error {
+	log := utils.GetLogger(u.ctx)
+
+	tx, err := u.db.DB.Begin()
+	if err != nil {
+		log.Errorf("Failed to begin transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(EnableMFA, step, userID)
+	if err != nil {
+		log.Errorf("Failed to enable MFA of user %v: %v", userID, err)
+		return err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		log.Errorf("Error number of rows affected: %v", err)
+		return err
+	}
+
+	if affectedRows == 0 {
+		log.Warningf("MFA of user %v already enabled", userID)
+		return ErrMFAEnabled
+	}
+
+	if _, err = tx.Exec(DeleteRecoveryCode, userID); err != nil {
+		log.Errorf("Failed to delete the recovery codes of user %v: %v", userID, err)
+		return err
+	}
+
+	hashes := make([]string, len(recoveryCodes))
+	for i, code := range recoveryCodes {
+		hashes[i] = utils.HashToken(code)
+	}
+
+	if _, err = tx.Exec(InsertRecoveryCode, userID, pq.Array(hashes)); err != nil {
+		log.Errorf("Failed to store the recovery codes of user %v: %v", userID, err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Errorf("Failed to commit transaction: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// UseTOTPStep spends the step of a valid code, and reports false when the code, or
+// a later one, was already used.
+func (u *AuthRepository) UseTOTPStep(userID int, step int64) (bool, error) {
+	return u.spend(UseTOTPStep, step, userID)
+}
+
+// UseRecoveryCode spends a recovery code, and reports false when it is unknown or
+// already used.
+func (u *AuthRepository) UseRecoveryCode(userID int, code string) (bool, error) {
+	return u.spend(UseRecoveryCode, userID, utils.HashToken(code))
+}
+
+func (u *AuthRepository) spend(query string, args ...interface{}) (bool, error) {
+	log := utils.GetLogger(u.ctx)
+
+	result, err := u.db.DB.Exec(query, args...)
+	if err != nil {
+		log.Errorf("Failed to spend MFA code: %v", err)
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		log.Errorf("Error number of rows affected: %v", err)
+		return false, err
+	}
+
+	return affectedRows == 1, nil
+}
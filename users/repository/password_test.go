@@ -0,0 +1,45 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"library/pkg/logger"
+	"library/pkg/postgres"
+	"library/pkg/redis"
+	"library/pkg/utils"
+	"library/users/repository"
+	"testing"
+)
+
+func TestUserRepository_RequestPasswordResetLimit(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+	fakeDB, _ := postgres.NewFakeDB(ctx)
+	redisClient, _ := redis.NewFakeRedis(ctx)
+	defer redisClient.Close()
+
+	newUserRepo := repository.NewUserRepository(ctx, *fakeDB, redisClient, nil, utils.Links{})
+
+	mock := fakeDB.GetMock()
+	for i := 0; i < repository.PasswordResetLimit; i++ {
+		mock.ExpectQuery(repository.GetUserIDByEmail).
+			WithArgs("nobody@elo.com").
+			WillReturnError(sql.ErrNoRows)
+	}
+
+	for i := 0; i < repository.PasswordResetLimit; i++ {
+		if err := newUserRepo.RequestPasswordReset("nobody@elo.com"); err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i+1, err)
+		}
+	}
+
+	// the limit is per email, whatever its case
+	if err := newUserRepo.RequestPasswordReset("Nobody@elo.com"); !errors.Is(err, repository.ErrTooManyResetRequests) {
+		t.Errorf("expected ErrTooManyResetRequests, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
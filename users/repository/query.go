@@ -8,10 +8,41 @@ const (
 					VALUES ($1, $2, $3, $4, $5)
 					RETURNING (id)
 					`
-	UpdateUser   = "UPDATE users SET firstname=$1, lastname=$2, email=$3, role=$4 WHERE id=$5"
+	UpdateUser   = "UPDATE users SET firstname=$1, lastname=$2, email=$3 WHERE id=$4"
 	GetUserByID  = "SELECT id, firstname, lastname, email, role FROM users WHERE id=$1"
 	GetUsers     = "SELECT firstname, lastname, email, role FROM users ORDER BY id"
 	DeleteUser   = "DELETE FROM users WHERE id=$1"
 	ActivateUser = "UPDATE users SET activated=true WHERE id=$1"
 	IsUserActive = "SELECT activated FROM users WHERE email=$1"
+
+	GetUserIDByEmail    = "SELECT id FROM users WHERE email=$1"
+	GetActivationStatus = "SELECT id, activated FROM users WHERE email=$1"
+	UpdatePassword      = "UPDATE users SET password=$1 WHERE id=$2"
+
+	GetRolePermissions = "SELECT permission FROM role_permissions WHERE role=$1 ORDER BY permission"
+	GetRoles           = `
+					SELECT r.name, COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')
+					FROM roles r
+					LEFT JOIN role_permissions rp ON rp.role = r.name
+					GROUP BY r.name
+					ORDER BY r.name
+					`
+	CheckRoleExists = "SELECT EXISTS(SELECT 1 FROM roles WHERE name=$1)"
+	UpdateUserRole  = "UPDATE users SET role=$1 WHERE id=$2"
+
+	GetMFA             = "SELECT coalesce(mfa_secret, ''), mfa_enabled, mfa_last_step FROM users WHERE id=$1"
+	SetMFASecret       = "UPDATE users SET mfa_secret=$1, mfa_last_step=0 WHERE id=$2 AND NOT mfa_enabled"
+	EnableMFA          = "UPDATE users SET mfa_enabled=true, mfa_last_step=$1 WHERE id=$2 AND NOT mfa_enabled AND mfa_secret IS NOT NULL"
+	DeleteRecoveryCode = "DELETE FROM user_recovery_codes WHERE user_id=$1"
+	InsertRecoveryCode = "INSERT INTO user_recovery_codes (user_id, code_hash) SELECT $1, unnest($2::text[])"
+	UseTOTPStep        = "UPDATE users SET mfa_last_step=$1 WHERE id=$2 AND mfa_enabled AND mfa_last_step < $1"
+	UseRecoveryCode    = "UPDATE user_recovery_codes SET used_at=now() WHERE user_id=$1 AND code_hash=$2 AND used_at IS NULL"
+
+	InsertAPIKey = `
+					INSERT INTO api_keys (user_id, name, hint, key_hash, scopes, expires_at)
+					VALUES ($1, $2, $3, $4, $5, $6)
+					RETURNING id, created_at
+					`
+	GetAPIKeys   = "SELECT id, name, hint, scopes, expires_at, last_used_at, created_at FROM api_keys WHERE user_id=$1 AND revoked_at IS NULL ORDER BY id"
+	RevokeAPIKey = "UPDATE api_keys SET revoked_at=now() WHERE id=$1 AND user_id=$2 AND revoked_at IS NULL"
 )
@@ -8,6 +8,71 @@ import (
 )
 
 type FakeAutherRepository struct {
+	CreateAPIKeyStub        func(int, *models.APIKey, string) error
+	createAPIKeyMutex       sync.RWMutex
+	createAPIKeyArgsForCall []struct {
+		arg1 int
+		arg2 *models.APIKey
+		arg3 string
+	}
+	createAPIKeyReturns struct {
+		result1 error
+	}
+	createAPIKeyReturnsOnCall map[int]struct {
+		result1 error
+	}
+	EnableMFAStub        func(int, int64, []string) error
+	enableMFAMutex       sync.RWMutex
+	enableMFAArgsForCall []struct {
+		arg1 int
+		arg2 int64
+		arg3 []string
+	}
+	enableMFAReturns struct {
+		result1 error
+	}
+	enableMFAReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetAPIKeysStub        func(int) ([]models.APIKey, error)
+	getAPIKeysMutex       sync.RWMutex
+	getAPIKeysArgsForCall []struct {
+		arg1 int
+	}
+	getAPIKeysReturns struct {
+		result1 []models.APIKey
+		result2 error
+	}
+	getAPIKeysReturnsOnCall map[int]struct {
+		result1 []models.APIKey
+		result2 error
+	}
+	GetMFAStub        func(int) (*models.MFA, error)
+	getMFAMutex       sync.RWMutex
+	getMFAArgsForCall []struct {
+		arg1 int
+	}
+	getMFAReturns struct {
+		result1 *models.MFA
+		result2 error
+	}
+	getMFAReturnsOnCall map[int]struct {
+		result1 *models.MFA
+		result2 error
+	}
+	GetUserStub        func(int) (*models.User, error)
+	getUserMutex       sync.RWMutex
+	getUserArgsForCall []struct {
+		arg1 int
+	}
+	getUserReturns struct {
+		result1 *models.User
+		result2 error
+	}
+	getUserReturnsOnCall map[int]struct {
+		result1 *models.User
+		result2 error
+	}
 	LoginStub        func(*models.User, *models.Authentication) error
 	loginMutex       sync.RWMutex
 	loginArgsForCall []struct {
@@ -20,10 +85,385 @@ type FakeAutherRepository struct {
 	loginReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RevokeAPIKeyStub        func(int, int) error
+	revokeAPIKeyMutex       sync.RWMutex
+	revokeAPIKeyArgsForCall []struct {
+		arg1 int
+		arg2 int
+	}
+	revokeAPIKeyReturns struct {
+		result1 error
+	}
+	revokeAPIKeyReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetMFASecretStub        func(int, string) error
+	setMFASecretMutex       sync.RWMutex
+	setMFASecretArgsForCall []struct {
+		arg1 int
+		arg2 string
+	}
+	setMFASecretReturns struct {
+		result1 error
+	}
+	setMFASecretReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UseRecoveryCodeStub        func(int, string) (bool, error)
+	useRecoveryCodeMutex       sync.RWMutex
+	useRecoveryCodeArgsForCall []struct {
+		arg1 int
+		arg2 string
+	}
+	useRecoveryCodeReturns struct {
+		result1 bool
+		result2 error
+	}
+	useRecoveryCodeReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	UseTOTPStepStub        func(int, int64) (bool, error)
+	useTOTPStepMutex       sync.RWMutex
+	useTOTPStepArgsForCall []struct {
+		arg1 int
+		arg2 int64
+	}
+	useTOTPStepReturns struct {
+		result1 bool
+		result2 error
+	}
+	useTOTPStepReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeAutherRepository) CreateAPIKey(arg1 int, arg2 *models.APIKey, arg3 string) error {
+	fake.createAPIKeyMutex.Lock()
+	ret, specificReturn := fake.createAPIKeyReturnsOnCall[len(fake.createAPIKeyArgsForCall)]
+	fake.createAPIKeyArgsForCall = append(fake.createAPIKeyArgsForCall, struct {
+		arg1 int
+		arg2 *models.APIKey
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.CreateAPIKeyStub
+	fakeReturns := fake.createAPIKeyReturns
+	fake.recordInvocation("CreateAPIKey", []interface{}{arg1, arg2, arg3})
+	fake.createAPIKeyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAutherRepository) CreateAPIKeyCallCount() int {
+	fake.createAPIKeyMutex.RLock()
+	defer fake.createAPIKeyMutex.RUnlock()
+	return len(fake.createAPIKeyArgsForCall)
+}
+
+func (fake *FakeAutherRepository) CreateAPIKeyCalls(stub func(int, *models.APIKey, string) error) {
+	fake.createAPIKeyMutex.Lock()
+	defer fake.createAPIKeyMutex.Unlock()
+	fake.CreateAPIKeyStub = stub
+}
+
+func (fake *FakeAutherRepository) CreateAPIKeyArgsForCall(i int) (int, *models.APIKey, string) {
+	fake.createAPIKeyMutex.RLock()
+	defer fake.createAPIKeyMutex.RUnlock()
+	argsForCall := fake.createAPIKeyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAutherRepository) CreateAPIKeyReturns(result1 error) {
+	fake.createAPIKeyMutex.Lock()
+	defer fake.createAPIKeyMutex.Unlock()
+	fake.CreateAPIKeyStub = nil
+	fake.createAPIKeyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAutherRepository) CreateAPIKeyReturnsOnCall(i int, result1 error) {
+	fake.createAPIKeyMutex.Lock()
+	defer fake.createAPIKeyMutex.Unlock()
+	fake.CreateAPIKeyStub = nil
+	if fake.createAPIKeyReturnsOnCall == nil {
+		fake.createAPIKeyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createAPIKeyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAutherRepository) EnableMFA(arg1 int, arg2 int64, arg3 []string) error {
+	var arg3Copy []string
+	if arg3 != nil {
+		arg3Copy = make([]string, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.enableMFAMutex.Lock()
+	ret, specificReturn := fake.enableMFAReturnsOnCall[len(fake.enableMFAArgsForCall)]
+	fake.enableMFAArgsForCall = append(fake.enableMFAArgsForCall, struct {
+		arg1 int
+		arg2 int64
+		arg3 []string
+	}{arg1, arg2, arg3Copy})
+	stub := fake.EnableMFAStub
+	fakeReturns := fake.enableMFAReturns
+	fake.recordInvocation("EnableMFA", []interface{}{arg1, arg2, arg3Copy})
+	fake.enableMFAMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAutherRepository) EnableMFACallCount() int {
+	fake.enableMFAMutex.RLock()
+	defer fake.enableMFAMutex.RUnlock()
+	return len(fake.enableMFAArgsForCall)
+}
+
+func (fake *FakeAutherRepository) EnableMFACalls(stub func(int, int64, []string) error) {
+	fake.enableMFAMutex.Lock()
+	defer fake.enableMFAMutex.Unlock()
+	fake.EnableMFAStub = stub
+}
+
+func (fake *FakeAutherRepository) EnableMFAArgsForCall(i int) (int, int64, []string) {
+	fake.enableMFAMutex.RLock()
+	defer fake.enableMFAMutex.RUnlock()
+	argsForCall := fake.enableMFAArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAutherRepository) EnableMFAReturns(result1 error) {
+	fake.enableMFAMutex.Lock()
+	defer fake.enableMFAMutex.Unlock()
+	fake.EnableMFAStub = nil
+	fake.enableMFAReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAutherRepository) EnableMFAReturnsOnCall(i int, result1 error) {
+	fake.enableMFAMutex.Lock()
+	defer fake.enableMFAMutex.Unlock()
+	fake.EnableMFAStub = nil
+	if fake.enableMFAReturnsOnCall == nil {
+		fake.enableMFAReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.enableMFAReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAutherRepository) GetAPIKeys(arg1 int) ([]models.APIKey, error) {
+	fake.getAPIKeysMutex.Lock()
+	ret, specificReturn := fake.getAPIKeysReturnsOnCall[len(fake.getAPIKeysArgsForCall)]
+	fake.getAPIKeysArgsForCall = append(fake.getAPIKeysArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.GetAPIKeysStub
+	fakeReturns := fake.getAPIKeysReturns
+	fake.recordInvocation("GetAPIKeys", []interface{}{arg1})
+	fake.getAPIKeysMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAutherRepository) GetAPIKeysCallCount() int {
+	fake.getAPIKeysMutex.RLock()
+	defer fake.getAPIKeysMutex.RUnlock()
+	return len(fake.getAPIKeysArgsForCall)
+}
+
+func (fake *FakeAutherRepository) GetAPIKeysCalls(stub func(int) ([]models.APIKey, error)) {
+	fake.getAPIKeysMutex.Lock()
+	defer fake.getAPIKeysMutex.Unlock()
+	fake.GetAPIKeysStub = stub
+}
+
+func (fake *FakeAutherRepository) GetAPIKeysArgsForCall(i int) int {
+	fake.getAPIKeysMutex.RLock()
+	defer fake.getAPIKeysMutex.RUnlock()
+	argsForCall := fake.getAPIKeysArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAutherRepository) GetAPIKeysReturns(result1 []models.APIKey, result2 error) {
+	fake.getAPIKeysMutex.Lock()
+	defer fake.getAPIKeysMutex.Unlock()
+	fake.GetAPIKeysStub = nil
+	fake.getAPIKeysReturns = struct {
+		result1 []models.APIKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAutherRepository) GetAPIKeysReturnsOnCall(i int, result1 []models.APIKey, result2 error) {
+	fake.getAPIKeysMutex.Lock()
+	defer fake.getAPIKeysMutex.Unlock()
+	fake.GetAPIKeysStub = nil
+	if fake.getAPIKeysReturnsOnCall == nil {
+		fake.getAPIKeysReturnsOnCall = make(map[int]struct {
+			result1 []models.APIKey
+			result2 error
+		})
+	}
+	fake.getAPIKeysReturnsOnCall[i] = struct {
+		result1 []models.APIKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAutherRepository) GetMFA(arg1 int) (*models.MFA, error) {
+	fake.getMFAMutex.Lock()
+	ret, specificReturn := fake.getMFAReturnsOnCall[len(fake.getMFAArgsForCall)]
+	fake.getMFAArgsForCall = append(fake.getMFAArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.GetMFAStub
+	fakeReturns := fake.getMFAReturns
+	fake.recordInvocation("GetMFA", []interface{}{arg1})
+	fake.getMFAMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAutherRepository) GetMFACallCount() int {
+	fake.getMFAMutex.RLock()
+	defer fake.getMFAMutex.RUnlock()
+	return len(fake.getMFAArgsForCall)
+}
+
+func (fake *FakeAutherRepository) GetMFACalls(stub func(int) (*models.MFA, error)) {
+	fake.getMFAMutex.Lock()
+	defer fake.getMFAMutex.Unlock()
+	fake.GetMFAStub = stub
+}
+
+func (fake *FakeAutherRepository) GetMFAArgsForCall(i int) int {
+	fake.getMFAMutex.RLock()
+	defer fake.getMFAMutex.RUnlock()
+	argsForCall := fake.getMFAArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAutherRepository) GetMFAReturns(result1 *models.MFA, result2 error) {
+	fake.getMFAMutex.Lock()
+	defer fake.getMFAMutex.Unlock()
+	fake.GetMFAStub = nil
+	fake.getMFAReturns = struct {
+		result1 *models.MFA
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAutherRepository) GetMFAReturnsOnCall(i int, result1 *models.MFA, result2 error) {
+	fake.getMFAMutex.Lock()
+	defer fake.getMFAMutex.Unlock()
+	fake.GetMFAStub = nil
+	if fake.getMFAReturnsOnCall == nil {
+		fake.getMFAReturnsOnCall = make(map[int]struct {
+			result1 *models.MFA
+			result2 error
+		})
+	}
+	fake.getMFAReturnsOnCall[i] = struct {
+		result1 *models.MFA
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAutherRepository) GetUser(arg1 int) (*models.User, error) {
+	fake.getUserMutex.Lock()
+	ret, specificReturn := fake.getUserReturnsOnCall[len(fake.getUserArgsForCall)]
+	fake.getUserArgsForCall = append(fake.getUserArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.GetUserStub
+	fakeReturns := fake.getUserReturns
+	fake.recordInvocation("GetUser", []interface{}{arg1})
+	fake.getUserMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAutherRepository) GetUserCallCount() int {
+	fake.getUserMutex.RLock()
+	defer fake.getUserMutex.RUnlock()
+	return len(fake.getUserArgsForCall)
+}
+
+func (fake *FakeAutherRepository) GetUserCalls(stub func(int) (*models.User, error)) {
+	fake.getUserMutex.Lock()
+	defer fake.getUserMutex.Unlock()
+	fake.GetUserStub = stub
+}
+
+func (fake *FakeAutherRepository) GetUserArgsForCall(i int) int {
+	fake.getUserMutex.RLock()
+	defer fake.getUserMutex.RUnlock()
+	argsForCall := fake.getUserArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAutherRepository) GetUserReturns(result1 *models.User, result2 error) {
+	fake.getUserMutex.Lock()
+	defer fake.getUserMutex.Unlock()
+	fake.GetUserStub = nil
+	fake.getUserReturns = struct {
+		result1 *models.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAutherRepository) GetUserReturnsOnCall(i int, result1 *models.User, result2 error) {
+	fake.getUserMutex.Lock()
+	defer fake.getUserMutex.Unlock()
+	fake.GetUserStub = nil
+	if fake.getUserReturnsOnCall == nil {
+		fake.getUserReturnsOnCall = make(map[int]struct {
+			result1 *models.User
+			result2 error
+		})
+	}
+	fake.getUserReturnsOnCall[i] = struct {
+		result1 *models.User
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAutherRepository) Login(arg1 *models.User, arg2 *models.Authentication) error {
 	fake.loginMutex.Lock()
 	ret, specificReturn := fake.loginReturnsOnCall[len(fake.loginArgsForCall)]
@@ -86,11 +526,283 @@ func (fake *FakeAutherRepository) LoginReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeAutherRepository) RevokeAPIKey(arg1 int, arg2 int) error {
+	fake.revokeAPIKeyMutex.Lock()
+	ret, specificReturn := fake.revokeAPIKeyReturnsOnCall[len(fake.revokeAPIKeyArgsForCall)]
+	fake.revokeAPIKeyArgsForCall = append(fake.revokeAPIKeyArgsForCall, struct {
+		arg1 int
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.RevokeAPIKeyStub
+	fakeReturns := fake.revokeAPIKeyReturns
+	fake.recordInvocation("RevokeAPIKey", []interface{}{arg1, arg2})
+	fake.revokeAPIKeyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAutherRepository) RevokeAPIKeyCallCount() int {
+	fake.revokeAPIKeyMutex.RLock()
+	defer fake.revokeAPIKeyMutex.RUnlock()
+	return len(fake.revokeAPIKeyArgsForCall)
+}
+
+func (fake *FakeAutherRepository) RevokeAPIKeyCalls(stub func(int, int) error) {
+	fake.revokeAPIKeyMutex.Lock()
+	defer fake.revokeAPIKeyMutex.Unlock()
+	fake.RevokeAPIKeyStub = stub
+}
+
+func (fake *FakeAutherRepository) RevokeAPIKeyArgsForCall(i int) (int, int) {
+	fake.revokeAPIKeyMutex.RLock()
+	defer fake.revokeAPIKeyMutex.RUnlock()
+	argsForCall := fake.revokeAPIKeyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAutherRepository) RevokeAPIKeyReturns(result1 error) {
+	fake.revokeAPIKeyMutex.Lock()
+	defer fake.revokeAPIKeyMutex.Unlock()
+	fake.RevokeAPIKeyStub = nil
+	fake.revokeAPIKeyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAutherRepository) RevokeAPIKeyReturnsOnCall(i int, result1 error) {
+	fake.revokeAPIKeyMutex.Lock()
+	defer fake.revokeAPIKeyMutex.Unlock()
+	fake.RevokeAPIKeyStub = nil
+	if fake.revokeAPIKeyReturnsOnCall == nil {
+		fake.revokeAPIKeyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revokeAPIKeyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAutherRepository) SetMFASecret(arg1 int, arg2 string) error {
+	fake.setMFASecretMutex.Lock()
+	ret, specificReturn := fake.setMFASecretReturnsOnCall[len(fake.setMFASecretArgsForCall)]
+	fake.setMFASecretArgsForCall = append(fake.setMFASecretArgsForCall, struct {
+		arg1 int
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.SetMFASecretStub
+	fakeReturns := fake.setMFASecretReturns
+	fake.recordInvocation("SetMFASecret", []interface{}{arg1, arg2})
+	fake.setMFASecretMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAutherRepository) SetMFASecretCallCount() int {
+	fake.setMFASecretMutex.RLock()
+	defer fake.setMFASecretMutex.RUnlock()
+	return len(fake.setMFASecretArgsForCall)
+}
+
+func (fake *FakeAutherRepository) SetMFASecretCalls(stub func(int, string) error) {
+	fake.setMFASecretMutex.Lock()
+	defer fake.setMFASecretMutex.Unlock()
+	fake.SetMFASecretStub = stub
+}
+
+func (fake *FakeAutherRepository) SetMFASecretArgsForCall(i int) (int, string) {
+	fake.setMFASecretMutex.RLock()
+	defer fake.setMFASecretMutex.RUnlock()
+	argsForCall := fake.setMFASecretArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAutherRepository) SetMFASecretReturns(result1 error) {
+	fake.setMFASecretMutex.Lock()
+	defer fake.setMFASecretMutex.Unlock()
+	fake.SetMFASecretStub = nil
+	fake.setMFASecretReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAutherRepository) SetMFASecretReturnsOnCall(i int, result1 error) {
+	fake.setMFASecretMutex.Lock()
+	defer fake.setMFASecretMutex.Unlock()
+	fake.SetMFASecretStub = nil
+	if fake.setMFASecretReturnsOnCall == nil {
+		fake.setMFASecretReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setMFASecretReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAutherRepository) UseRecoveryCode(arg1 int, arg2 string) (bool, error) {
+	fake.useRecoveryCodeMutex.Lock()
+	ret, specificReturn := fake.useRecoveryCodeReturnsOnCall[len(fake.useRecoveryCodeArgsForCall)]
+	fake.useRecoveryCodeArgsForCall = append(fake.useRecoveryCodeArgsForCall, struct {
+		arg1 int
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.UseRecoveryCodeStub
+	fakeReturns := fake.useRecoveryCodeReturns
+	fake.recordInvocation("UseRecoveryCode", []interface{}{arg1, arg2})
+	fake.useRecoveryCodeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAutherRepository) UseRecoveryCodeCallCount() int {
+	fake.useRecoveryCodeMutex.RLock()
+	defer fake.useRecoveryCodeMutex.RUnlock()
+	return len(fake.useRecoveryCodeArgsForCall)
+}
+
+func (fake *FakeAutherRepository) UseRecoveryCodeCalls(stub func(int, string) (bool, error)) {
+	fake.useRecoveryCodeMutex.Lock()
+	defer fake.useRecoveryCodeMutex.Unlock()
+	fake.UseRecoveryCodeStub = stub
+}
+
+func (fake *FakeAutherRepository) UseRecoveryCodeArgsForCall(i int) (int, string) {
+	fake.useRecoveryCodeMutex.RLock()
+	defer fake.useRecoveryCodeMutex.RUnlock()
+	argsForCall := fake.useRecoveryCodeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAutherRepository) UseRecoveryCodeReturns(result1 bool, result2 error) {
+	fake.useRecoveryCodeMutex.Lock()
+	defer fake.useRecoveryCodeMutex.Unlock()
+	fake.UseRecoveryCodeStub = nil
+	fake.useRecoveryCodeReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAutherRepository) UseRecoveryCodeReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.useRecoveryCodeMutex.Lock()
+	defer fake.useRecoveryCodeMutex.Unlock()
+	fake.UseRecoveryCodeStub = nil
+	if fake.useRecoveryCodeReturnsOnCall == nil {
+		fake.useRecoveryCodeReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.useRecoveryCodeReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAutherRepository) UseTOTPStep(arg1 int, arg2 int64) (bool, error) {
+	fake.useTOTPStepMutex.Lock()
+	ret, specificReturn := fake.useTOTPStepReturnsOnCall[len(fake.useTOTPStepArgsForCall)]
+	fake.useTOTPStepArgsForCall = append(fake.useTOTPStepArgsForCall, struct {
+		arg1 int
+		arg2 int64
+	}{arg1, arg2})
+	stub := fake.UseTOTPStepStub
+	fakeReturns := fake.useTOTPStepReturns
+	fake.recordInvocation("UseTOTPStep", []interface{}{arg1, arg2})
+	fake.useTOTPStepMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAutherRepository) UseTOTPStepCallCount() int {
+	fake.useTOTPStepMutex.RLock()
+	defer fake.useTOTPStepMutex.RUnlock()
+	return len(fake.useTOTPStepArgsForCall)
+}
+
+func (fake *FakeAutherRepository) UseTOTPStepCalls(stub func(int, int64) (bool, error)) {
+	fake.useTOTPStepMutex.Lock()
+	defer fake.useTOTPStepMutex.Unlock()
+	fake.UseTOTPStepStub = stub
+}
+
+func (fake *FakeAutherRepository) UseTOTPStepArgsForCall(i int) (int, int64) {
+	fake.useTOTPStepMutex.RLock()
+	defer fake.useTOTPStepMutex.RUnlock()
+	argsForCall := fake.useTOTPStepArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAutherRepository) UseTOTPStepReturns(result1 bool, result2 error) {
+	fake.useTOTPStepMutex.Lock()
+	defer fake.useTOTPStepMutex.Unlock()
+	fake.UseTOTPStepStub = nil
+	fake.useTOTPStepReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAutherRepository) UseTOTPStepReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.useTOTPStepMutex.Lock()
+	defer fake.useTOTPStepMutex.Unlock()
+	fake.UseTOTPStepStub = nil
+	if fake.useTOTPStepReturnsOnCall == nil {
+		fake.useTOTPStepReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.useTOTPStepReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAutherRepository) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.createAPIKeyMutex.RLock()
+	defer fake.createAPIKeyMutex.RUnlock()
+	fake.enableMFAMutex.RLock()
+	defer fake.enableMFAMutex.RUnlock()
+	fake.getAPIKeysMutex.RLock()
+	defer fake.getAPIKeysMutex.RUnlock()
+	fake.getMFAMutex.RLock()
+	defer fake.getMFAMutex.RUnlock()
+	fake.getUserMutex.RLock()
+	defer fake.getUserMutex.RUnlock()
 	fake.loginMutex.RLock()
 	defer fake.loginMutex.RUnlock()
+	fake.revokeAPIKeyMutex.RLock()
+	defer fake.revokeAPIKeyMutex.RUnlock()
+	fake.setMFASecretMutex.RLock()
+	defer fake.setMFASecretMutex.RUnlock()
+	fake.useRecoveryCodeMutex.RLock()
+	defer fake.useRecoveryCodeMutex.RUnlock()
+	fake.useTOTPStepMutex.RLock()
+	defer fake.useTOTPStepMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
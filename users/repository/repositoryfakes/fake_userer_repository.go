@@ -8,10 +8,11 @@ import (
 )
 
 type FakeUsererRepository struct {
-	ActivateUserStub        func(int) (int, error)
+	ActivateUserStub        func(int, string) (int, error)
 	activateUserMutex       sync.RWMutex
 	activateUserArgsForCall []struct {
 		arg1 int
+		arg2 string
 	}
 	activateUserReturns struct {
 		result1 int
@@ -59,6 +60,18 @@ type FakeUsererRepository struct {
 		result1 []models.UserResponse
 		result2 error
 	}
+	GetRolesStub        func() ([]models.Role, error)
+	getRolesMutex       sync.RWMutex
+	getRolesArgsForCall []struct {
+	}
+	getRolesReturns struct {
+		result1 []models.Role
+		result2 error
+	}
+	getRolesReturnsOnCall map[int]struct {
+		result1 []models.Role
+		result2 error
+	}
 	GetUserStub        func(int) (*models.UserResponse, error)
 	getUserMutex       sync.RWMutex
 	getUserArgsForCall []struct {
@@ -72,6 +85,53 @@ type FakeUsererRepository struct {
 		result1 *models.UserResponse
 		result2 error
 	}
+	RequestPasswordResetStub        func(string) error
+	requestPasswordResetMutex       sync.RWMutex
+	requestPasswordResetArgsForCall []struct {
+		arg1 string
+	}
+	requestPasswordResetReturns struct {
+		result1 error
+	}
+	requestPasswordResetReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ResendActivationStub        func(string) error
+	resendActivationMutex       sync.RWMutex
+	resendActivationArgsForCall []struct {
+		arg1 string
+	}
+	resendActivationReturns struct {
+		result1 error
+	}
+	resendActivationReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ResetPasswordStub        func(int, string, string) error
+	resetPasswordMutex       sync.RWMutex
+	resetPasswordArgsForCall []struct {
+		arg1 int
+		arg2 string
+		arg3 string
+	}
+	resetPasswordReturns struct {
+		result1 error
+	}
+	resetPasswordReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetUserRoleStub        func(int, string) error
+	setUserRoleMutex       sync.RWMutex
+	setUserRoleArgsForCall []struct {
+		arg1 int
+		arg2 string
+	}
+	setUserRoleReturns struct {
+		result1 error
+	}
+	setUserRoleReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UpdateUserStub        func(*models.User) (*models.UserResponse, error)
 	updateUserMutex       sync.RWMutex
 	updateUserArgsForCall []struct {
@@ -89,18 +149,19 @@ type FakeUsererRepository struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeUsererRepository) ActivateUser(arg1 int) (int, error) {
+func (fake *FakeUsererRepository) ActivateUser(arg1 int, arg2 string) (int, error) {
 	fake.activateUserMutex.Lock()
 	ret, specificReturn := fake.activateUserReturnsOnCall[len(fake.activateUserArgsForCall)]
 	fake.activateUserArgsForCall = append(fake.activateUserArgsForCall, struct {
 		arg1 int
-	}{arg1})
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.ActivateUserStub
 	fakeReturns := fake.activateUserReturns
-	fake.recordInvocation("ActivateUser", []interface{}{arg1})
+	fake.recordInvocation("ActivateUser", []interface{}{arg1, arg2})
 	fake.activateUserMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -114,17 +175,17 @@ func (fake *FakeUsererRepository) ActivateUserCallCount() int {
 	return len(fake.activateUserArgsForCall)
 }
 
-func (fake *FakeUsererRepository) ActivateUserCalls(stub func(int) (int, error)) {
+func (fake *FakeUsererRepository) ActivateUserCalls(stub func(int, string) (int, error)) {
 	fake.activateUserMutex.Lock()
 	defer fake.activateUserMutex.Unlock()
 	fake.ActivateUserStub = stub
 }
 
-func (fake *FakeUsererRepository) ActivateUserArgsForCall(i int) int {
+func (fake *FakeUsererRepository) ActivateUserArgsForCall(i int) (int, string) {
 	fake.activateUserMutex.RLock()
 	defer fake.activateUserMutex.RUnlock()
 	argsForCall := fake.activateUserArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeUsererRepository) ActivateUserReturns(result1 int, result2 error) {
@@ -337,6 +398,62 @@ func (fake *FakeUsererRepository) GetAllUsersReturnsOnCall(i int, result1 []mode
 	}{result1, result2}
 }
 
+func (fake *FakeUsererRepository) GetRoles() ([]models.Role, error) {
+	fake.getRolesMutex.Lock()
+	ret, specificReturn := fake.getRolesReturnsOnCall[len(fake.getRolesArgsForCall)]
+	fake.getRolesArgsForCall = append(fake.getRolesArgsForCall, struct {
+	}{})
+	stub := fake.GetRolesStub
+	fakeReturns := fake.getRolesReturns
+	fake.recordInvocation("GetRoles", []interface{}{})
+	fake.getRolesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeUsererRepository) GetRolesCallCount() int {
+	fake.getRolesMutex.RLock()
+	defer fake.getRolesMutex.RUnlock()
+	return len(fake.getRolesArgsForCall)
+}
+
+func (fake *FakeUsererRepository) GetRolesCalls(stub func() ([]models.Role, error)) {
+	fake.getRolesMutex.Lock()
+	defer fake.getRolesMutex.Unlock()
+	fake.GetRolesStub = stub
+}
+
+func (fake *FakeUsererRepository) GetRolesReturns(result1 []models.Role, result2 error) {
+	fake.getRolesMutex.Lock()
+	defer fake.getRolesMutex.Unlock()
+	fake.GetRolesStub = nil
+	fake.getRolesReturns = struct {
+		result1 []models.Role
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUsererRepository) GetRolesReturnsOnCall(i int, result1 []models.Role, result2 error) {
+	fake.getRolesMutex.Lock()
+	defer fake.getRolesMutex.Unlock()
+	fake.GetRolesStub = nil
+	if fake.getRolesReturnsOnCall == nil {
+		fake.getRolesReturnsOnCall = make(map[int]struct {
+			result1 []models.Role
+			result2 error
+		})
+	}
+	fake.getRolesReturnsOnCall[i] = struct {
+		result1 []models.Role
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeUsererRepository) GetUser(arg1 int) (*models.UserResponse, error) {
 	fake.getUserMutex.Lock()
 	ret, specificReturn := fake.getUserReturnsOnCall[len(fake.getUserArgsForCall)]
@@ -401,6 +518,253 @@ func (fake *FakeUsererRepository) GetUserReturnsOnCall(i int, result1 *models.Us
 	}{result1, result2}
 }
 
+func (fake *FakeUsererRepository) RequestPasswordReset(arg1 string) error {
+	fake.requestPasswordResetMutex.Lock()
+	ret, specificReturn := fake.requestPasswordResetReturnsOnCall[len(fake.requestPasswordResetArgsForCall)]
+	fake.requestPasswordResetArgsForCall = append(fake.requestPasswordResetArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.RequestPasswordResetStub
+	fakeReturns := fake.requestPasswordResetReturns
+	fake.recordInvocation("RequestPasswordReset", []interface{}{arg1})
+	fake.requestPasswordResetMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeUsererRepository) RequestPasswordResetCallCount() int {
+	fake.requestPasswordResetMutex.RLock()
+	defer fake.requestPasswordResetMutex.RUnlock()
+	return len(fake.requestPasswordResetArgsForCall)
+}
+
+func (fake *FakeUsererRepository) RequestPasswordResetCalls(stub func(string) error) {
+	fake.requestPasswordResetMutex.Lock()
+	defer fake.requestPasswordResetMutex.Unlock()
+	fake.RequestPasswordResetStub = stub
+}
+
+func (fake *FakeUsererRepository) RequestPasswordResetArgsForCall(i int) string {
+	fake.requestPasswordResetMutex.RLock()
+	defer fake.requestPasswordResetMutex.RUnlock()
+	argsForCall := fake.requestPasswordResetArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeUsererRepository) RequestPasswordResetReturns(result1 error) {
+	fake.requestPasswordResetMutex.Lock()
+	defer fake.requestPasswordResetMutex.Unlock()
+	fake.RequestPasswordResetStub = nil
+	fake.requestPasswordResetReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeUsererRepository) RequestPasswordResetReturnsOnCall(i int, result1 error) {
+	fake.requestPasswordResetMutex.Lock()
+	defer fake.requestPasswordResetMutex.Unlock()
+	fake.RequestPasswordResetStub = nil
+	if fake.requestPasswordResetReturnsOnCall == nil {
+		fake.requestPasswordResetReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.requestPasswordResetReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeUsererRepository) ResendActivation(arg1 string) error {
+	fake.resendActivationMutex.Lock()
+	ret, specificReturn := fake.resendActivationReturnsOnCall[len(fake.resendActivationArgsForCall)]
+	fake.resendActivationArgsForCall = append(fake.resendActivationArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ResendActivationStub
+	fakeReturns := fake.resendActivationReturns
+	fake.recordInvocation("ResendActivation", []interface{}{arg1})
+	fake.resendActivationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeUsererRepository) ResendActivationCallCount() int {
+	fake.resendActivationMutex.RLock()
+	defer fake.resendActivationMutex.RUnlock()
+	return len(fake.resendActivationArgsForCall)
+}
+
+func (fake *FakeUsererRepository) ResendActivationCalls(stub func(string) error) {
+	fake.resendActivationMutex.Lock()
+	defer fake.resendActivationMutex.Unlock()
+	fake.ResendActivationStub = stub
+}
+
+func (fake *FakeUsererRepository) ResendActivationArgsForCall(i int) string {
+	fake.resendActivationMutex.RLock()
+	defer fake.resendActivationMutex.RUnlock()
+	argsForCall := fake.resendActivationArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeUsererRepository) ResendActivationReturns(result1 error) {
+	fake.resendActivationMutex.Lock()
+	defer fake.resendActivationMutex.Unlock()
+	fake.ResendActivationStub = nil
+	fake.resendActivationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeUsererRepository) ResendActivationReturnsOnCall(i int, result1 error) {
+	fake.resendActivationMutex.Lock()
+	defer fake.resendActivationMutex.Unlock()
+	fake.ResendActivationStub = nil
+	if fake.resendActivationReturnsOnCall == nil {
+		fake.resendActivationReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resendActivationReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeUsererRepository) ResetPassword(arg1 int, arg2 string, arg3 string) error {
+	fake.resetPasswordMutex.Lock()
+	ret, specificReturn := fake.resetPasswordReturnsOnCall[len(fake.resetPasswordArgsForCall)]
+	fake.resetPasswordArgsForCall = append(fake.resetPasswordArgsForCall, struct {
+		arg1 int
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.ResetPasswordStub
+	fakeReturns := fake.resetPasswordReturns
+	fake.recordInvocation("ResetPassword", []interface{}{arg1, arg2, arg3})
+	fake.resetPasswordMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeUsererRepository) ResetPasswordCallCount() int {
+	fake.resetPasswordMutex.RLock()
+	defer fake.resetPasswordMutex.RUnlock()
+	return len(fake.resetPasswordArgsForCall)
+}
+
+func (fake *FakeUsererRepository) ResetPasswordCalls(stub func(int, string, string) error) {
+	fake.resetPasswordMutex.Lock()
+	defer fake.resetPasswordMutex.Unlock()
+	fake.ResetPasswordStub = stub
+}
+
+func (fake *FakeUsererRepository) ResetPasswordArgsForCall(i int) (int, string, string) {
+	fake.resetPasswordMutex.RLock()
+	defer fake.resetPasswordMutex.RUnlock()
+	argsForCall := fake.resetPasswordArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeUsererRepository) ResetPasswordReturns(result1 error) {
+	fake.resetPasswordMutex.Lock()
+	defer fake.resetPasswordMutex.Unlock()
+	fake.ResetPasswordStub = nil
+	fake.resetPasswordReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeUsererRepository) ResetPasswordReturnsOnCall(i int, result1 error) {
+	fake.resetPasswordMutex.Lock()
+	defer fake.resetPasswordMutex.Unlock()
+	fake.ResetPasswordStub = nil
+	if fake.resetPasswordReturnsOnCall == nil {
+		fake.resetPasswordReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resetPasswordReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeUsererRepository) SetUserRole(arg1 int, arg2 string) error {
+	fake.setUserRoleMutex.Lock()
+	ret, specificReturn := fake.setUserRoleReturnsOnCall[len(fake.setUserRoleArgsForCall)]
+	fake.setUserRoleArgsForCall = append(fake.setUserRoleArgsForCall, struct {
+		arg1 int
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.SetUserRoleStub
+	fakeReturns := fake.setUserRoleReturns
+	fake.recordInvocation("SetUserRole", []interface{}{arg1, arg2})
+	fake.setUserRoleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeUsererRepository) SetUserRoleCallCount() int {
+	fake.setUserRoleMutex.RLock()
+	defer fake.setUserRoleMutex.RUnlock()
+	return len(fake.setUserRoleArgsForCall)
+}
+
+func (fake *FakeUsererRepository) SetUserRoleCalls(stub func(int, string) error) {
+	fake.setUserRoleMutex.Lock()
+	defer fake.setUserRoleMutex.Unlock()
+	fake.SetUserRoleStub = stub
+}
+
+func (fake *FakeUsererRepository) SetUserRoleArgsForCall(i int) (int, string) {
+	fake.setUserRoleMutex.RLock()
+	defer fake.setUserRoleMutex.RUnlock()
+	argsForCall := fake.setUserRoleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeUsererRepository) SetUserRoleReturns(result1 error) {
+	fake.setUserRoleMutex.Lock()
+	defer fake.setUserRoleMutex.Unlock()
+	fake.SetUserRoleStub = nil
+	fake.setUserRoleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeUsererRepository) SetUserRoleReturnsOnCall(i int, result1 error) {
+	fake.setUserRoleMutex.Lock()
+	defer fake.setUserRoleMutex.Unlock()
+	fake.SetUserRoleStub = nil
+	if fake.setUserRoleReturnsOnCall == nil {
+		fake.setUserRoleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setUserRoleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeUsererRepository) UpdateUser(arg1 *models.User) (*models.UserResponse, error) {
 	fake.updateUserMutex.Lock()
 	ret, specificReturn := fake.updateUserReturnsOnCall[len(fake.updateUserArgsForCall)]
@@ -476,8 +840,18 @@ func (fake *FakeUsererRepository) Invocations() map[string][][]interface{} {
 	defer fake.deleteUserMutex.RUnlock()
 	fake.getAllUsersMutex.RLock()
 	defer fake.getAllUsersMutex.RUnlock()
+	fake.getRolesMutex.RLock()
+	defer fake.getRolesMutex.RUnlock()
 	fake.getUserMutex.RLock()
 	defer fake.getUserMutex.RUnlock()
+	fake.requestPasswordResetMutex.RLock()
+	defer fake.requestPasswordResetMutex.RUnlock()
+	fake.resendActivationMutex.RLock()
+	defer fake.resendActivationMutex.RUnlock()
+	fake.resetPasswordMutex.RLock()
+	defer fake.resetPasswordMutex.RUnlock()
+	fake.setUserRoleMutex.RLock()
+	defer fake.setUserRoleMutex.RUnlock()
 	fake.updateUserMutex.RLock()
 	defer fake.updateUserMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"errors"
+	"library/pkg/utils"
+	"library/users/models"
+
+	"github.com/lib/pq"
+)
+
+var ErrUnknownRole = errors.New("role does not exist")
+
+// GetRoles lists the roles with the permissions they grant.
+func (r *UserRepository) GetRoles() ([]models.Role, error) {
+	log := utils.GetLogger(r.ctx)
+
+	rows, err := r.DB.DB.Query(GetRoles)
+	if err != nil {
+		log.Errorf("Failed to get roles: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []models.Role{}
+	for rows.Next() {
+		var role models.Role
+		if err = rows.Scan(&role.Name, pq.Array(&role.Permissions)); err != nil {
+			log.Errorf("Failed to scan role: %v", err)
+			return nil, err
+		}
+
+		roles = append(roles, role)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Errorf("Failed to read roles: %v", err)
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// SetUserRole assigns the role to the user. The permissions of the new role are
+// only granted to access tokens issued afterwards.
+func (r *UserRepository) SetUserRole(userID int, role string) error {
+	log := utils.GetLogger(r.ctx)
+
+	var exists bool
+	if err := r.DB.DB.QueryRow(CheckRoleExists, role).Scan(&exists); err != nil {
+		log.Errorf("Failed to check role %v: %v", role, err)
+		return err
+	}
+
+	if !exists {
+		log.Warningf("Unknown role: %v", role)
+		return ErrUnknownRole
+	}
+
+	result, err := r.DB.DB.Exec(UpdateUserRole, role, userID)
+	if err != nil {
+		log.Errorf("Failed to update the role of user %v: %v", userID, err)
+		return err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		log.Errorf("Error number of rows affected: %v", err)
+		return err
+	}
+
+	if affectedRows == 0 {
+		log.Warningf("User not found: %v", userID)
+		return ErrUserNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"library/pkg/logger"
+	"library/pkg/postgres"
+	"library/pkg/utils"
+	"library/users/models"
+	"library/users/repository"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestUserRepository_GetRoles(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+	fakeDB, _ := postgres.NewFakeDB(ctx)
+	newUserRepo := repository.NewUserRepository(ctx, *fakeDB, nil, nil, utils.Links{})
+
+	mock := fakeDB.GetMock()
+	mock.ExpectQuery(repository.GetRoles).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "permissions"}).
+			AddRow("superuser", "{authors:merge,reviews:moderate}").
+			AddRow("user", "{}"))
+
+	roles, err := newUserRepo.GetRoles()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []models.Role{
+		{Name: "superuser", Permissions: []string{"authors:merge", "reviews:moderate"}},
+		{Name: "user", Permissions: []string{}},
+	}
+	if !reflect.DeepEqual(roles, expected) {
+		t.Errorf("expected roles %+v, got %+v", expected, roles)
+	}
+}
+
+func TestUserRepository_SetUserRole(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+	tests := []struct {
+		name   string
+		exists bool
+		rows   int64
+		want   error
+	}{
+		{"assigned", true, 1, nil},
+		{"unknown role", false, 0, repository.ErrUnknownRole},
+		{"unknown user", true, 0, repository.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDB, _ := postgres.NewFakeDB(ctx)
+			newUserRepo := repository.NewUserRepository(ctx, *fakeDB, nil, nil, utils.Links{})
+
+			mock := fakeDB.GetMock()
+			mock.ExpectQuery(repository.CheckRoleExists).
+				WithArgs("superuser").
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(tt.exists))
+			if tt.exists {
+				mock.ExpectExec(repository.UpdateUserRole).
+					WithArgs("superuser", 2).
+					WillReturnResult(sqlmock.NewResult(0, tt.rows))
+			}
+
+			if err := newUserRepo.SetUserRole(2, "superuser"); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
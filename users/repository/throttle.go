@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"library/pkg/redis"
+	"library/pkg/utils"
+	"strings"
+	"time"
+)
+
+var ErrLoginLocked = errors.New("too many failed login attempts, try again later")
+
+const (
+	// LoginAccountLimit is how many failed logins lock an account for LoginLockout.
+	// Failures below the limit are answered with an exponential backoff.
+	LoginAccountLimit = 5
+	// LoginIPLimit is how many failed logins lock out a client address, whichever
+	// accounts were tried.
+	LoginIPLimit       = 20
+	LoginBackoffBase   = time.Second
+	LoginLockout       = 15 * time.Minute
+	LoginFailureWindow = time.Hour
+)
+
+const (
+	loginFailuresPrefix = "login:failures:"
+	loginBlockPrefix    = "login:block:"
+)
+
+type ThrottlerRepository interface {
+	LoginWait(email, ip string) (time.Duration, error)
+	RecordLoginFailure(email, ip string) (time.Duration, error)
+	ResetLoginFailures(email string) error
+}
+
+type ThrottleRepository struct {
+	ctx         context.Context
+	redisClient *redis.Client
+}
+
+func NewThrottleRepository(ctx context.Context, redisClient *redis.Client) ThrottlerRepository {
+	return &ThrottleRepository{
+		ctx:         ctx,
+		redisClient: redisClient,
+	}
+}
+
+// LoginBackoff is how long an account must wait after its nth failed login in a row.
+func LoginBackoff(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+
+	if failures >= LoginAccountLimit {
+		return LoginLockout
+	}
+
+	backoff := LoginBackoffBase << (failures - 1)
+	if backoff > LoginLockout {
+		return LoginLockout
+	}
+
+	return backoff
+}
+
+// LoginWait returns how long logins to the account or from the address are still
+// refused, zero when they are allowed.
+func (t *ThrottleRepository) LoginWait(email, ip string) (time.Duration, error) {
+	log := utils.GetLogger(t.ctx)
+
+	var wait time.Duration
+	for _, subject := range []string{accountSubject(email), ipSubject(ip)} {
+		ttl, err := t.redisClient.Client.PTTL(t.ctx, loginBlockPrefix+subject).Result()
+		if err != nil {
+			log.Errorf("Failed to check the login block of %v: %v", subject, err)
+			return 0, err
+		}
+
+		if ttl > wait {
+			wait = ttl
+		}
+	}
+
+	return wait, nil
+}
+
+// RecordLoginFailure counts a failed login against the account and the address, and
+// returns how long the next login has to wait. Unknown emails are counted too, so
+// the responses do not tell them apart.
+func (t *ThrottleRepository) RecordLoginFailure(email, ip string) (time.Duration, error) {
+	log := utils.GetLogger(t.ctx)
+
+	accountFailures, err := t.countFailure(accountSubject(email))
+	if err != nil {
+		return 0, err
+	}
+
+	ipFailures, err := t.countFailure(ipSubject(ip))
+	if err != nil {
+		return 0, err
+	}
+
+	wait := LoginBackoff(int(accountFailures))
+	if err = t.block(accountSubject(email), wait); err != nil {
+		return 0, err
+	}
+
+	if ipFailures >= LoginIPLimit {
+		log.Warningf("Locking out logins from %v after %v failures", ip, ipFailures)
+		if err = t.block(ipSubject(ip), LoginLockout); err != nil {
+			return 0, err
+		}
+
+		wait = LoginLockout
+	}
+
+	if accountFailures >= LoginAccountLimit {
+		log.Warningf("Locking out account %v after %v failures", email, accountFailures)
+	}
+
+	return wait, nil
+}
+
+// ResetLoginFailures forgets the failed logins of the account and lifts its lockout.
+// Lockouts of client addresses are left to expire.
+func (t *ThrottleRepository) ResetLoginFailures(email string) error {
+	log := utils.GetLogger(t.ctx)
+
+	subject := accountSubject(email)
+
+	err := t.redisClient.Client.Del(t.ctx, loginFailuresPrefix+subject, loginBlockPrefix+subject).Err()
+	if err != nil {
+		log.Errorf("Failed to reset the failed logins of %v: %v", email, err)
+		return err
+	}
+
+	return nil
+}
+
+func (t *ThrottleRepository) countFailure(subject string) (int64, error) {
+	log := utils.GetLogger(t.ctx)
+
+	key := loginFailuresPrefix + subject
+
+	failures, err := t.redisClient.Client.Incr(t.ctx, key).Result()
+	if err != nil {
+		log.Errorf("Failed to count failed logins of %v: %v", subject, err)
+		return 0, err
+	}
+
+	if failures == 1 {
+		if err = t.redisClient.Client.Expire(t.ctx, key, LoginFailureWindow).Err(); err != nil {
+			log.Errorf("Failed to expire failed logins of %v: %v", subject, err)
+			return 0, err
+		}
+	}
+
+	return failures, nil
+}
+
+func (t *ThrottleRepository) block(subject string, wait time.Duration) error {
+	log := utils.GetLogger(t.ctx)
+
+	if err := t.redisClient.Client.Set(t.ctx, loginBlockPrefix+subject, 1, wait).Err(); err != nil {
+		log.Errorf("Failed to block logins of %v: %v", subject, err)
+		return err
+	}
+
+	return nil
+}
+
+func accountSubject(email string) string {
+	return "account:" + strings.ToLower(email)
+}
+
+func ipSubject(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,27 @@
+package repository_test
+
+import (
+	"library/users/repository"
+	"testing"
+	"time"
+)
+
+func TestLoginBackoff(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{repository.LoginAccountLimit, repository.LoginLockout},
+		{repository.LoginAccountLimit + 10, repository.LoginLockout},
+	}
+
+	for _, tt := range tests {
+		if got := repository.LoginBackoff(tt.failures); got != tt.want {
+			t.Errorf("LoginBackoff(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,436 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"library/pkg/redis"
+	"library/pkg/utils"
+	"library/users/models"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used, the session has been revoked")
+	ErrInvalidMFAToken     = errors.New("two-factor login is invalid or expired, log in again")
+	ErrSessionNotFound     = errors.New("session not found")
+)
+
+const (
+	// MFATokenTTL is how long a user has to enter the code after the password.
+	MFATokenTTL = 5 * time.Minute
+	// MFAMaxAttempts is how many codes can be tried with one password login.
+	MFAMaxAttempts = 5
+	// maxUserAgentLength bounds the user agent stored with a session.
+	maxUserAgentLength = 256
+)
+
+// Refresh tokens are stored by their SHA-256 hash, a copy of Redis is not enough to use them.
+const (
+	refreshTokenPrefix  = "token:refresh:"
+	refreshFamilyPrefix = "token:family:"
+	userFamiliesPrefix  = "token:user:"
+	mfaTokenPrefix      = "token:mfa:"
+)
+
+type TokenerRepository interface {
+	CreateRefreshToken(userID int, family string, access *models.Claims, client models.SessionClient) (string, error)
+	UseRefreshToken(token string) (*models.RefreshSession, error)
+	RevokeRefreshToken(token string) error
+	RevokeAccessToken(access *models.Claims) error
+	RevokeUserSessions(userID int) error
+	GetSessions(userID int) ([]models.Session, error)
+	RevokeSession(userID int, sessionID string) error
+	CreateMFAToken(userID int) (string, error)
+	UseMFAToken(token string) (int, error)
+	RevokeMFAToken(token string) error
+}
+
+type TokenRepository struct {
+	ctx         context.Context
+	redisClient *redis.Client
+	ttl         time.Duration
+}
+
+func NewTokenRepository(ctx context.Context, redisClient *redis.Client, ttl time.Duration) TokenerRepository {
+	return &TokenRepository{
+		ctx:         ctx,
+		redisClient: redisClient,
+		ttl:         ttl,
+	}
+}
+
+// CreateRefreshToken hands out a refresh token of the family, the session of the user,
+// and records the client as its last activity. The family remembers the access token
+// issued along, so revoking the session also revokes that access token.
+func (t *TokenRepository) CreateRefreshToken(userID int, family string, access *models.Claims, client models.SessionClient) (string, error) {
+	log := utils.GetLogger(t.ctx)
+
+	if len(client.UserAgent) > maxUserAgentLength {
+		client.UserAgent = client.UserAgent[:maxUserAgentLength]
+	}
+
+	now := time.Now().Unix()
+
+	token, err := randomToken()
+	if err != nil {
+		log.Errorf("Failed to generate refresh token: %v", err)
+		return "", err
+	}
+
+	tokenKey := refreshTokenPrefix + utils.HashToken(token)
+	familyKey := refreshFamilyPrefix + family
+	userKey := userFamiliesPrefix + strconv.Itoa(userID)
+
+	pipe := t.redisClient.Client.TxPipeline()
+	pipe.HSet(t.ctx, tokenKey, "user_id", userID, "family", family, "used", 0)
+	pipe.Expire(t.ctx, tokenKey, t.ttl)
+	pipe.HSet(t.ctx, familyKey,
+		"user_id", userID,
+		"access_id", access.Id,
+		"access_expires_at", access.ExpiresAt,
+		"user_agent", client.UserAgent,
+		"ip", client.IP,
+		"last_seen_at", now,
+	)
+	pipe.HSetNX(t.ctx, familyKey, "created_at", now)
+	pipe.Expire(t.ctx, familyKey, t.ttl)
+	pipe.SAdd(t.ctx, userKey, family)
+	pipe.Expire(t.ctx, userKey, t.ttl)
+
+	if _, err = pipe.Exec(t.ctx); err != nil {
+		log.Errorf("Failed to store refresh token: %v", err)
+		return "", err
+	}
+
+	return token, nil
+}
+
+// UseRefreshToken spends a refresh token and returns its session. A token can only
+// be used once: using it again means it leaked, so the whole family is revoked.
+func (t *TokenRepository) UseRefreshToken(token string) (*models.RefreshSession, error) {
+	log := utils.GetLogger(t.ctx)
+
+	tokenKey := refreshTokenPrefix + utils.HashToken(token)
+
+	session, err := t.getSession(tokenKey)
+	if err != nil {
+		return nil, err
+	}
+
+	family, err := t.redisClient.Client.HGetAll(t.ctx, refreshFamilyPrefix+session.Family).Result()
+	if err != nil {
+		log.Errorf("Failed to read refresh token family: %v", err)
+		return nil, err
+	}
+
+	if len(family) == 0 || family["revoked"] != "" {
+		log.Warningf("Refresh token of revoked session %v", session.Family)
+		return nil, ErrInvalidRefreshToken
+	}
+
+	uses, err := t.redisClient.Client.HIncrBy(t.ctx, tokenKey, "used", 1).Result()
+	if err != nil {
+		log.Errorf("Failed to spend refresh token: %v", err)
+		return nil, err
+	}
+
+	if uses > 1 {
+		log.Warningf("Refresh token reused, revoking session %v of user %v", session.Family, session.UserID)
+		if err = t.revokeFamily(session.Family); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrRefreshTokenReused
+	}
+
+	return session, nil
+}
+
+// RevokeRefreshToken ends the session of the refresh token.
+func (t *TokenRepository) RevokeRefreshToken(token string) error {
+	session, err := t.getSession(refreshTokenPrefix + utils.HashToken(token))
+	if err != nil {
+		return err
+	}
+
+	return t.revokeFamily(session.Family)
+}
+
+// RevokeAccessToken denylists the access token until it expires.
+func (t *TokenRepository) RevokeAccessToken(access *models.Claims) error {
+	log := utils.GetLogger(t.ctx)
+
+	err := t.redisClient.RevokeToken(t.ctx, access.Id, time.Unix(access.ExpiresAt, 0))
+	if err != nil {
+		log.Errorf("Failed to revoke access token: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// RevokeUserSessions ends every session of the user, e.g. after a password change.
+func (t *TokenRepository) RevokeUserSessions(userID int) error {
+	log := utils.GetLogger(t.ctx)
+
+	userKey := userFamiliesPrefix + strconv.Itoa(userID)
+
+	families, err := t.redisClient.Client.SMembers(t.ctx, userKey).Result()
+	if err != nil {
+		log.Errorf("Failed to list the sessions of user %v: %v", userID, err)
+		return err
+	}
+
+	for _, family := range families {
+		if err = t.revokeFamily(family); err != nil {
+			return err
+		}
+	}
+
+	if err = t.redisClient.Client.Del(t.ctx, userKey).Err(); err != nil {
+		log.Errorf("Failed to clear the sessions of user %v: %v", userID, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetSessions lists the active sessions of the user, the most recently seen first.
+func (t *TokenRepository) GetSessions(userID int) ([]models.Session, error) {
+	log := utils.GetLogger(t.ctx)
+
+	userKey := userFamiliesPrefix + strconv.Itoa(userID)
+
+	families, err := t.redisClient.Client.SMembers(t.ctx, userKey).Result()
+	if err != nil {
+		log.Errorf("Failed to list the sessions of user %v: %v", userID, err)
+		return nil, err
+	}
+
+	sessions := []models.Session{}
+	for _, family := range families {
+		values, err := t.redisClient.Client.HGetAll(t.ctx, refreshFamilyPrefix+family).Result()
+		if err != nil {
+			log.Errorf("Failed to read session %v: %v", family, err)
+			return nil, err
+		}
+
+		if len(values) == 0 {
+			// The session expired, forget it.
+			if err = t.redisClient.Client.SRem(t.ctx, userKey, family).Err(); err != nil {
+				log.Errorf("Failed to forget expired session %v: %v", family, err)
+			}
+			continue
+		}
+
+		if values["revoked"] != "" {
+			continue
+		}
+
+		createdAt, _ := strconv.ParseInt(values["created_at"], 10, 64)
+		lastSeenAt, _ := strconv.ParseInt(values["last_seen_at"], 10, 64)
+
+		// last_seen_at is the last login or refresh, the access tokens record their use apart
+		seenAt, err := t.redisClient.SessionLastSeen(t.ctx, family)
+		if err != nil {
+			log.Errorf("Failed to read the last use of session %v: %v", family, err)
+			return nil, err
+		}
+
+		if seenAt.Unix() > lastSeenAt {
+			lastSeenAt = seenAt.Unix()
+		}
+
+		sessions = append(sessions, models.Session{
+			ID:         family,
+			UserAgent:  values["user_agent"],
+			IP:         values["ip"],
+			CreatedAt:  time.Unix(createdAt, 0).UTC(),
+			LastSeenAt: time.Unix(lastSeenAt, 0).UTC(),
+		})
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+// RevokeSession ends a session of the user.
+func (t *TokenRepository) RevokeSession(userID int, sessionID string) error {
+	log := utils.GetLogger(t.ctx)
+
+	values, err := t.redisClient.Client.HMGet(t.ctx, refreshFamilyPrefix+sessionID, "user_id", "revoked").Result()
+	if err != nil {
+		log.Errorf("Failed to read session %v: %v", sessionID, err)
+		return err
+	}
+
+	if values[0] != strconv.Itoa(userID) || values[1] != nil {
+		log.Warningf("Session %v of user %v not found", sessionID, userID)
+		return ErrSessionNotFound
+	}
+
+	return t.revokeFamily(sessionID)
+}
+
+// CreateMFAToken hands out the token of a login waiting for its second factor.
+func (t *TokenRepository) CreateMFAToken(userID int) (string, error) {
+	log := utils.GetLogger(t.ctx)
+
+	token, err := randomToken()
+	if err != nil {
+		log.Errorf("Failed to generate MFA token: %v", err)
+		return "", err
+	}
+
+	key := mfaTokenPrefix + utils.HashToken(token)
+
+	pipe := t.redisClient.Client.TxPipeline()
+	pipe.HSet(t.ctx, key, "user_id", userID, "attempts", 0)
+	pipe.Expire(t.ctx, key, MFATokenTTL)
+
+	if _, err = pipe.Exec(t.ctx); err != nil {
+		log.Errorf("Failed to store MFA token: %v", err)
+		return "", err
+	}
+
+	return token, nil
+}
+
+// UseMFAToken counts a code attempt with the token and returns its user. The token
+// is revoked once MFAMaxAttempts codes were tried.
+func (t *TokenRepository) UseMFAToken(token string) (int, error) {
+	log := utils.GetLogger(t.ctx)
+
+	key := mfaTokenPrefix + utils.HashToken(token)
+
+	values, err := t.redisClient.Client.HGetAll(t.ctx, key).Result()
+	if err != nil {
+		log.Errorf("Failed to read MFA token: %v", err)
+		return 0, err
+	}
+
+	if len(values) == 0 {
+		log.Warningf("Unknown or expired MFA token")
+		return 0, ErrInvalidMFAToken
+	}
+
+	attempts, err := t.redisClient.Client.HIncrBy(t.ctx, key, "attempts", 1).Result()
+	if err != nil {
+		log.Errorf("Failed to count MFA attempt: %v", err)
+		return 0, err
+	}
+
+	if attempts > MFAMaxAttempts {
+		log.Warningf("Too many MFA attempts for user %v", values["user_id"])
+		if err = t.RevokeMFAToken(token); err != nil {
+			return 0, err
+		}
+
+		return 0, ErrInvalidMFAToken
+	}
+
+	userID, err := strconv.Atoi(values["user_id"])
+	if err != nil {
+		log.Errorf("Corrupt MFA token: %v", err)
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+// RevokeMFAToken ends the login waiting for its second factor.
+func (t *TokenRepository) RevokeMFAToken(token string) error {
+	log := utils.GetLogger(t.ctx)
+
+	if err := t.redisClient.Client.Del(t.ctx, mfaTokenPrefix+utils.HashToken(token)).Err(); err != nil {
+		log.Errorf("Failed to revoke MFA token: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (t *TokenRepository) getSession(tokenKey string) (*models.RefreshSession, error) {
+	log := utils.GetLogger(t.ctx)
+
+	values, err := t.redisClient.Client.HGetAll(t.ctx, tokenKey).Result()
+	if err != nil {
+		log.Errorf("Failed to read refresh token: %v", err)
+		return nil, err
+	}
+
+	if len(values) == 0 {
+		log.Warningf("Unknown or expired refresh token")
+		return nil, ErrInvalidRefreshToken
+	}
+
+	userID, err := strconv.Atoi(values["user_id"])
+	if err != nil {
+		log.Errorf("Corrupt refresh token: %v", err)
+		return nil, err
+	}
+
+	return &models.RefreshSession{UserID: userID, Family: values["family"]}, nil
+}
+
+// revokeFamily marks the session revoked, so none of its refresh tokens can be used,
+// and denylists it until the last access token issued in it expires.
+func (t *TokenRepository) revokeFamily(family string) error {
+	log := utils.GetLogger(t.ctx)
+
+	familyKey := refreshFamilyPrefix + family
+
+	values, err := t.redisClient.Client.HGetAll(t.ctx, familyKey).Result()
+	if err != nil {
+		log.Errorf("Failed to read refresh token family: %v", err)
+		return err
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	if err = t.redisClient.Client.HSet(t.ctx, familyKey, "revoked", 1).Err(); err != nil {
+		log.Errorf("Failed to revoke refresh token family: %v", err)
+		return err
+	}
+
+	if err = t.redisClient.Client.SRem(t.ctx, userFamiliesPrefix+values["user_id"], family).Err(); err != nil {
+		log.Errorf("Failed to forget revoked session %v: %v", family, err)
+		return err
+	}
+
+	expiresAt, err := strconv.ParseInt(values["access_expires_at"], 10, 64)
+	if err != nil || values["access_id"] == "" {
+		return nil
+	}
+
+	if err = t.redisClient.RevokeSession(t.ctx, family, time.Unix(expiresAt, 0)); err != nil {
+		log.Errorf("Failed to revoke session: %v", err)
+		return err
+	}
+
+	if err = t.redisClient.RevokeToken(t.ctx, values["access_id"], time.Unix(expiresAt, 0)); err != nil {
+		log.Errorf("Failed to revoke access token: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func randomToken() (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(tokenBytes), nil
+}
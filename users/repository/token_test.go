@@ -0,0 +1,183 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"library/pkg/logger"
+	"library/pkg/redis"
+	"library/users/models"
+	"library/users/repository"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func newTokenRepo(t *testing.T) (context.Context, *redis.Client, repository.TokenerRepository) {
+	ctx := context.WithValue(context.Background(), "logger", logger.NewLogger(2))
+
+	redisClient, _ := redis.NewFakeRedis(ctx)
+	t.Cleanup(func() { redisClient.Close() })
+
+	return ctx, redisClient, repository.NewTokenRepository(ctx, redisClient, time.Hour)
+}
+
+func accessClaims(id string) *models.Claims {
+	return &models.Claims{
+		SessionID: "family-1",
+		StandardClaims: jwt.StandardClaims{
+			Id:        id,
+			ExpiresAt: time.Now().Add(15 * time.Minute).Unix(),
+		},
+	}
+}
+
+func TestTokenRepository_UseRefreshToken(t *testing.T) {
+	_, _, tokenRepo := newTokenRepo(t)
+
+	client := models.SessionClient{UserAgent: "curl/8.0", IP: "10.0.0.1"}
+
+	first, err := tokenRepo.CreateRefreshToken(1, "family-1", accessClaims("access-1"), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	session, err := tokenRepo.UseRefreshToken(first)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if session.UserID != 1 || session.Family != "family-1" {
+		t.Errorf("expected session of user 1 in family-1, got %+v", session)
+	}
+
+	// the rotated token continues the same session
+	second, err := tokenRepo.CreateRefreshToken(session.UserID, session.Family, accessClaims("access-2"), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if second == first {
+		t.Fatalf("expected a new refresh token after the rotation")
+	}
+
+	if session, err = tokenRepo.UseRefreshToken(second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if session.Family != "family-1" {
+		t.Errorf("expected the rotated token in family-1, got %v", session.Family)
+	}
+
+	if _, err = tokenRepo.UseRefreshToken("unknown"); !errors.Is(err, repository.ErrInvalidRefreshToken) {
+		t.Errorf("expected ErrInvalidRefreshToken for an unknown token, got %v", err)
+	}
+}
+
+func TestTokenRepository_UseRefreshTokenReused(t *testing.T) {
+	ctx, redisClient, tokenRepo := newTokenRepo(t)
+
+	client := models.SessionClient{UserAgent: "curl/8.0", IP: "10.0.0.1"}
+
+	first, err := tokenRepo.CreateRefreshToken(1, "family-1", accessClaims("access-1"), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = tokenRepo.UseRefreshToken(first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	second, err := tokenRepo.CreateRefreshToken(1, "family-1", accessClaims("access-2"), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = tokenRepo.UseRefreshToken(first); !errors.Is(err, repository.ErrRefreshTokenReused) {
+		t.Fatalf("expected ErrRefreshTokenReused, got %v", err)
+	}
+
+	// the reuse revoked the whole family, so the token handed out after it is dead as well
+	if _, err = tokenRepo.UseRefreshToken(second); !errors.Is(err, repository.ErrInvalidRefreshToken) {
+		t.Errorf("expected ErrInvalidRefreshToken for the rest of the family, got %v", err)
+	}
+
+	revoked, err := redisClient.IsSessionRevoked(ctx, "family-1")
+	if err != nil || !revoked {
+		t.Errorf("expected session family-1 to be denylisted, got %v, %v", revoked, err)
+	}
+
+	revoked, err = redisClient.IsRevoked(ctx, "access-2")
+	if err != nil || !revoked {
+		t.Errorf("expected the last access token of the family to be denylisted, got %v, %v", revoked, err)
+	}
+
+	sessions, err := tokenRepo.GetSessions(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(sessions) != 0 {
+		t.Errorf("expected no sessions left, got %+v", sessions)
+	}
+}
+
+func TestTokenRepository_UseRefreshTokenRevokedFamily(t *testing.T) {
+	_, _, tokenRepo := newTokenRepo(t)
+
+	client := models.SessionClient{UserAgent: "curl/8.0", IP: "10.0.0.1"}
+
+	token, err := tokenRepo.CreateRefreshToken(1, "family-1", accessClaims("access-1"), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = tokenRepo.RevokeSession(2, "family-1"); !errors.Is(err, repository.ErrSessionNotFound) {
+		t.Fatalf("expected ErrSessionNotFound for the session of another user, got %v", err)
+	}
+
+	if err = tokenRepo.RevokeSession(1, "family-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = tokenRepo.UseRefreshToken(token); !errors.Is(err, repository.ErrInvalidRefreshToken) {
+		t.Errorf("expected ErrInvalidRefreshToken for a revoked family, got %v", err)
+	}
+}
+
+func TestTokenRepository_GetSessionsLastSeen(t *testing.T) {
+	ctx, redisClient, tokenRepo := newTokenRepo(t)
+
+	client := models.SessionClient{UserAgent: "curl/8.0", IP: "10.0.0.1"}
+
+	if _, err := tokenRepo.CreateRefreshToken(1, "family-1", accessClaims("access-1"), client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the session was logged in to an hour ago and used with its access token since
+	loggedIn := time.Now().Add(-time.Hour).Unix()
+	if err := redisClient.Client.HSet(ctx, "token:family:family-1", "last_seen_at", loggedIn).Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sessions, err := tokenRepo.GetSessions(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(sessions) != 1 || sessions[0].LastSeenAt.Unix() != loggedIn {
+		t.Fatalf("expected the login time before any use, got %+v", sessions)
+	}
+
+	if err = redisClient.TouchSession(ctx, "family-1", time.Now().Add(15*time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sessions, err = tokenRepo.GetSessions(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(sessions) != 1 || time.Since(sessions[0].LastSeenAt) > time.Minute {
+		t.Errorf("expected the session to be seen just now, got %+v", sessions)
+	}
+}
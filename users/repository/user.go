@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"crypto/subtle"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -11,7 +12,29 @@ import (
 	"library/pkg/utils"
 	"library/users/models"
 	"strconv"
+	"strings"
 	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const (
+	// ActivationResendLimit is how many activation emails can be asked for an email
+	// within ActivationResendWindow.
+	ActivationResendLimit  = 3
+	ActivationResendWindow = time.Hour
+	// PasswordResetLimit is how many password reset emails can be asked for an email
+	// within PasswordResetWindow.
+	PasswordResetLimit  = 3
+	PasswordResetWindow = time.Hour
+)
+
+var (
+	ErrInvalidResetToken      = errors.New("password reset token is invalid or expired")
+	ErrInvalidActivationToken = errors.New("activation token is invalid")
+	ErrActivationTokenExpired = errors.New("activation token has expired, ask for a new activation email")
+	ErrTooManyResends         = errors.New("too many activation emails requested, try again later")
+	ErrTooManyResetRequests   = errors.New("too many password reset emails requested, try again later")
 )
 
 type UsererRepository interface {
@@ -20,7 +43,12 @@ type UsererRepository interface {
 	GetUser(id int) (*models.UserResponse, error)
 	GetAllUsers() ([]models.UserResponse, error)
 	DeleteUser(id int) (int, error)
-	ActivateUser(id int) (int, error)
+	ActivateUser(id int, token string) (int, error)
+	ResendActivation(email string) error
+	RequestPasswordReset(email string) error
+	ResetPassword(userID int, token, passwordHash string) error
+	GetRoles() ([]models.Role, error)
+	SetUserRole(userID int, role string) error
 }
 
 type UserRepository struct {
@@ -28,14 +56,16 @@ type UserRepository struct {
 	DB          postgres.DB
 	redisClient *redis.Client
 	rmq         *rabbitMQ.RabbitMQ
+	links       utils.Links
 }
 
-func NewUserRepository(ctx context.Context, db postgres.DB, redisClient *redis.Client, rmq *rabbitMQ.RabbitMQ) UsererRepository {
+func NewUserRepository(ctx context.Context, db postgres.DB, redisClient *redis.Client, rmq *rabbitMQ.RabbitMQ, links utils.Links) UsererRepository {
 	return &UserRepository{
 		ctx:         ctx,
 		DB:          db,
 		redisClient: redisClient,
 		rmq:         rmq,
+		links:       links,
 	}
 }
 
@@ -68,7 +98,7 @@ func (r *UserRepository) AddUser(user *models.User) (int, error) {
 		return 0, err
 	}
 
-	activationLink, err := utils.GenerateActivationLink(r.ctx, r.redisClient, lastInsertedID)
+	activationLink, err := utils.GenerateActivationLink(r.ctx, r.redisClient, r.links.ActivationURL, lastInsertedID)
 	if err != nil {
 		log.Errorf("")
 		return 0, err
@@ -99,7 +129,6 @@ func (r *UserRepository) UpdateUser(user *models.User) (*models.UserResponse, er
 		Firstname: user.Firstname,
 		Lastname:  user.Lastname,
 		Email:     user.Email,
-		Role:      user.Role,
 	}
 
 	result, err := r.DB.DB.Exec(
@@ -107,7 +136,6 @@ func (r *UserRepository) UpdateUser(user *models.User) (*models.UserResponse, er
 		userResponse.Firstname,
 		userResponse.Lastname,
 		userResponse.Email,
-		userResponse.Role,
 		userResponse.ID,
 	)
 	if err != nil {
@@ -211,24 +239,60 @@ func (r *UserRepository) DeleteUser(id int) (int, error) {
 	return id, nil
 }
 
-func (r *UserRepository) ActivateUser(id int) (int, error) {
+// ActivateUser spends the activation token of the user and activates the account.
+func (r *UserRepository) ActivateUser(id int, token string) (int, error) {
 	userResponse := &models.UserResponse{}
-	var err error
 
 	log := utils.GetLogger(r.ctx)
 
-	_, err = r.redisClient.Client.Get(r.ctx, strconv.Itoa(id)).Result()
+	key := utils.ActivationKey(id)
+
+	value, err := r.redisClient.Client.Get(r.ctx, key).Result()
+	if errors.Is(err, goredis.Nil) {
+		log.Warningf("No pending activation for user %v", id)
+		return 0, ErrInvalidActivationToken
+	}
+
 	if err != nil {
-		fmt.Printf("Error retrieving value from Redis: %v\n", err)
+		log.Errorf("Failed to read activation token: %v", err)
 		return 0, err
 	}
 
+	storedHash, expires, _ := strings.Cut(value, ":")
+	if subtle.ConstantTimeCompare([]byte(storedHash), []byte(utils.HashToken(token))) != 1 {
+		log.Warningf("Wrong activation token for user %v", id)
+		return 0, ErrInvalidActivationToken
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		log.Warningf("Expired activation token for user %v", id)
+		return 0, ErrActivationTokenExpired
+	}
+
+	// Only the request that deletes the token may use it.
+	deleted, err := r.redisClient.Client.Del(r.ctx, key).Result()
+	if err != nil {
+		log.Errorf("Failed to spend activation token: %v", err)
+		return 0, err
+	}
+
+	if deleted == 0 {
+		log.Warningf("Activation token of user %v already used", id)
+		return 0, ErrInvalidActivationToken
+	}
+
 	userResponse, err = getUserByID(id, userResponse, r.DB.DB)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("User not found: %v", id)
+		return 0, ErrUserNotFound
+	}
+
 	if err != nil {
 		log.Errorf("QueryRows failed: %v", err)
 		return 0, err
 	}
-	log.Infof("userResponse %v", userResponse)
+
 	if err = activateUser(r.ctx, userResponse, r.redisClient, r.DB.DB); err != nil {
 		log.Errorf("Activate user error: %v", err)
 		return 0, err
@@ -237,6 +301,165 @@ func (r *UserRepository) ActivateUser(id int) (int, error) {
 	return id, nil
 }
 
+// ResendActivation emails a new activation link, which replaces the previous one.
+// Requests are limited per email; unknown and already active accounts are ignored
+// without an error, so the endpoint does not reveal who has an account.
+func (r *UserRepository) ResendActivation(email string) error {
+	var userID int
+	var activated bool
+
+	log := utils.GetLogger(r.ctx)
+
+	requests, err := r.countEmailRequest("activation_resend:", email, ActivationResendWindow)
+	if err != nil {
+		log.Errorf("Failed to count activation resends: %v", err)
+		return err
+	}
+
+	if requests > ActivationResendLimit {
+		log.Warningf("Too many activation resends for %v", email)
+		return ErrTooManyResends
+	}
+
+	err = r.DB.DB.QueryRow(GetActivationStatus, email).Scan(&userID, &activated)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("Activation resend requested for unknown email")
+		return nil
+	}
+
+	if err != nil {
+		log.Errorf("Failed to perform a select query: %v", err)
+		return err
+	}
+
+	if activated {
+		log.Infof("User %v is already activated", userID)
+		return nil
+	}
+
+	activationLink, err := utils.GenerateActivationLink(r.ctx, r.redisClient, r.links.ActivationURL, userID)
+	if err != nil {
+		log.Errorf("Failed to generate activation link: %v", err)
+		return err
+	}
+	r.rmq.Producer(r.ctx, activationLink)
+
+	return nil
+}
+
+// RequestPasswordReset emails a password reset link to the user. Requests are limited
+// per email; unknown emails are ignored without an error, so the endpoint does not
+// reveal who has an account.
+func (r *UserRepository) RequestPasswordReset(email string) error {
+	var userID int
+
+	log := utils.GetLogger(r.ctx)
+
+	requests, err := r.countEmailRequest("password_reset_request:", email, PasswordResetWindow)
+	if err != nil {
+		log.Errorf("Failed to count password reset requests: %v", err)
+		return err
+	}
+
+	if requests > PasswordResetLimit {
+		log.Warningf("Too many password reset requests for %v", email)
+		return ErrTooManyResetRequests
+	}
+
+	err = r.DB.DB.QueryRow(GetUserIDByEmail, email).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Warningf("Password reset requested for unknown email")
+		return nil
+	}
+
+	if err != nil {
+		log.Errorf("Failed to perform a select query: %v", err)
+		return err
+	}
+
+	resetLink, err := utils.GeneratePasswordResetLink(r.ctx, r.redisClient, r.links.PasswordResetURL, userID)
+	if err != nil {
+		log.Errorf("Failed to generate password reset link: %v", err)
+		return err
+	}
+	r.rmq.Producer(r.ctx, fmt.Sprintf("Password reset for %s: %s", email, resetLink))
+
+	return nil
+}
+
+// countEmailRequest counts a request for the email under the prefix and returns the
+// number of requests made within the window.
+func (r *UserRepository) countEmailRequest(prefix, email string, window time.Duration) (int64, error) {
+	limitKey := prefix + strings.ToLower(email)
+
+	requests, err := r.redisClient.Client.Incr(r.ctx, limitKey).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if requests == 1 {
+		if err = r.redisClient.Client.Expire(r.ctx, limitKey, window).Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	return requests, nil
+}
+
+// ResetPassword spends the reset token of the user and stores the new password hash.
+func (r *UserRepository) ResetPassword(userID int, token, passwordHash string) error {
+	log := utils.GetLogger(r.ctx)
+
+	key := utils.PasswordResetKey(userID)
+
+	storedHash, err := r.redisClient.Client.Get(r.ctx, key).Result()
+	if errors.Is(err, goredis.Nil) {
+		log.Warningf("No pending password reset for user %v", userID)
+		return ErrInvalidResetToken
+	}
+
+	if err != nil {
+		log.Errorf("Failed to read password reset token: %v", err)
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(storedHash), []byte(utils.HashToken(token))) != 1 {
+		log.Warningf("Wrong password reset token for user %v", userID)
+		return ErrInvalidResetToken
+	}
+
+	// Only the request that deletes the token may use it.
+	deleted, err := r.redisClient.Client.Del(r.ctx, key).Result()
+	if err != nil {
+		log.Errorf("Failed to spend password reset token: %v", err)
+		return err
+	}
+
+	if deleted == 0 {
+		log.Warningf("Password reset token of user %v already used", userID)
+		return ErrInvalidResetToken
+	}
+
+	result, err := r.DB.DB.Exec(UpdatePassword, passwordHash, userID)
+	if err != nil {
+		log.Errorf("Failed to update password: %v", err)
+		return err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		log.Errorf("Error number of rows affected: %v", err)
+		return err
+	}
+
+	if affectedRows == 0 {
+		log.Warningf("User not found: %v", userID)
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
 func checkUserEmailExist(email string, db *sql.DB) (bool, error) {
 	var exists bool
 	err := db.QueryRow(CheckUserByEmail, email).Scan(&exists)
@@ -10,6 +10,7 @@ import (
 	"library/pkg/postgres"
 	"library/pkg/rabbitMQ/rabbitMQ"
 	"library/pkg/redis"
+	"library/pkg/utils"
 	"library/users/models"
 	"library/users/repository"
 	"log"
@@ -44,7 +45,7 @@ var _ = Describe("API Test", func() {
 		redis, _ := redis.NewRedis(cfg)
 		rmq, _ := rabbitMQ.NewConn(cfg)
 
-		newUserRepo = repository.NewUserRepository(ctx, *fakeDB, redis, rmq)
+		newUserRepo = repository.NewUserRepository(ctx, *fakeDB, redis, rmq, utils.Links{})
 
 		user = &models.User{
 			Firstname: "tmosto",
@@ -119,7 +120,7 @@ var _ = Describe("API Test", func() {
 		It("should update a user successfully", func() {
 			rowsAffected := int64(1)
 			mock.ExpectExec(repository.UpdateUser).
-				WithArgs(user.Firstname, user.Lastname, user.Email, user.Role, user.ID).
+				WithArgs(user.Firstname, user.Lastname, user.Email, user.ID).
 				WillReturnResult(sqlmock.NewResult(0, rowsAffected))
 
 			userResponse, err = newUserRepo.UpdateUser(user)
@@ -130,7 +131,7 @@ var _ = Describe("API Test", func() {
 		It("should return an error if no rows were affected", func() {
 			rowsAffected := int64(0)
 			mock.ExpectExec(repository.UpdateUser).
-				WithArgs(user.Firstname, user.Lastname, user.Email, user.Role, user.ID).
+				WithArgs(user.Firstname, user.Lastname, user.Email, user.ID).
 				WillReturnResult(sqlmock.NewResult(0, rowsAffected))
 
 			_, err := newUserRepo.UpdateUser(user)
@@ -140,7 +141,7 @@ var _ = Describe("API Test", func() {
 
 		It("should return an error if database query fails", func() {
 			mock.ExpectExec(repository.UpdateUser).
-				WithArgs(user.Firstname, user.Lastname, user.Email, user.Role, user.ID).
+				WithArgs(user.Firstname, user.Lastname, user.Email, user.ID).
 				WillReturnError(errors.New("database error"))
 
 			_, err := newUserRepo.UpdateUser(user)
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"library/pkg/authz"
 	"library/pkg/middleware"
 	"library/pkg/tracing"
 	"library/users/handler"
@@ -19,36 +20,135 @@ func NewRouter(authUser handler.UserAuther, handlerUser handler.Userer) *gin.Eng
 		ginSwagger.WrapHandler(swaggerFiles.Handler),
 	)
 
+	v1.GET("/.well-known/jwks.json", middleware.JWKS)
 	v1.POST("", handlerUser.AddUser)
 	v1.GET("/activate", handlerUser.ActivateAccount)
+	v1.POST("/activate/resend", handlerUser.ResendActivation)
 	v1.POST("/login", authUser.Login)
+	v1.POST("/login/mfa", authUser.LoginMFA)
 	v1.POST("/logout", authUser.Logout)
+	v1.POST("/token/refresh", authUser.RefreshToken)
+	v1.POST("/password/forgot", handlerUser.ForgotPassword)
+	v1.POST("/password/reset", handlerUser.ResetPassword)
 
 	v1.PUT("/:user_id",
 		tracing.TraceMiddleware,
 		middleware.IsAuthorized,
 		middleware.GetToken,
+		authz.RequireSession(),
+		authz.RequireOwnerOr(authz.UsersWriteAny, ""),
 		handlerUser.UpdateUser,
 	)
 	v1.GET("/:user_id",
 		tracing.TraceMiddleware,
 		middleware.IsAuthorized,
 		middleware.GetToken,
+		authz.RequireSession(),
+		authz.RequireOwnerOr(authz.UsersRead, ""),
 		handlerUser.GetUser,
 	)
 	v1.GET("",
 		tracing.TraceMiddleware,
 		middleware.IsAuthorized,
 		middleware.GetToken,
+		authz.RequirePermission(authz.UsersRead),
 		handlerUser.GetAllUsers,
 	)
 	v1.DELETE("/:user_id/:delete_id",
 		tracing.TraceMiddleware,
 		middleware.IsAuthorized,
 		middleware.GetToken,
+		authz.RequireOwner(authz.UsersDelete),
+		authz.RequirePermission(authz.UsersDelete),
 		middleware.GetDeleteParam,
 		handlerUser.DeleteUser,
 	)
 
+	v1.GET("/roles",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequirePermission(authz.UsersRoles),
+		handlerUser.GetRoles,
+	)
+	v1.PUT("/:user_id/role",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequirePermission(authz.UsersRoles),
+		handlerUser.SetUserRole,
+	)
+	v1.POST("/:user_id/mfa",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireSession(),
+		authz.RequireOwner(""),
+		authUser.EnrollMFA,
+	)
+	v1.POST("/:user_id/mfa/confirm",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireSession(),
+		authz.RequireOwner(""),
+		authUser.ConfirmMFA,
+	)
+	v1.POST("/:user_id/api-keys",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireSession(),
+		authz.RequireOwner(""),
+		authUser.CreateAPIKey,
+	)
+	v1.GET("/:user_id/api-keys",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireSession(),
+		authz.RequireOwner(""),
+		authUser.GetAPIKeys,
+	)
+	v1.DELETE("/:user_id/api-keys/:key_id",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireSession(),
+		authz.RequireOwner(""),
+		authUser.RevokeAPIKey,
+	)
+	v1.GET("/:user_id/sessions",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireSession(),
+		authz.RequireOwner(""),
+		authUser.GetSessions,
+	)
+	v1.DELETE("/:user_id/sessions/:session_id",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireSession(),
+		authz.RequireOwner(""),
+		authUser.RevokeSession,
+	)
+	v1.DELETE("/:user_id/sessions",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequireSession(),
+		authz.RequireOwner(""),
+		authUser.RevokeAllSessions,
+	)
+	v1.DELETE("/:user_id/lockout",
+		tracing.TraceMiddleware,
+		middleware.IsAuthorized,
+		middleware.GetToken,
+		authz.RequirePermission(authz.UsersUnlock),
+		authUser.UnlockAccount,
+	)
+
 	return router
 }